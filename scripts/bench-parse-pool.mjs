@@ -0,0 +1,65 @@
+/**
+ * Parse Pool Benchmark
+ * Times a cold index build with 1, 2, 4, ... parse workers up to the core
+ * count, so the speedup over the main thread shows how well parsing scales.
+ * Runs against the built server in dist.
+ *
+ * Usage: npm run bench [-- <dir> [<max workers>]]
+ * (default: this repo's src, up to one worker per core)
+ */
+
+import * as path from 'path';
+import { fileURLToPath } from 'url';
+import { DEFAULT_PARSE_WORKERS, ParsePool } from '../dist/indexes/parse-pool.js';
+import { SymbolIndexer } from '../dist/indexes/symbol-indexer.js';
+
+// Each count is timed this many times and the fastest run kept
+const ROUNDS = 3;
+
+const here = path.dirname(fileURLToPath(import.meta.url));
+const dir = path.resolve(process.argv[2] ?? path.join(here, '..', 'src'));
+const maxWorkers = Number(process.argv[3] ?? DEFAULT_PARSE_WORKERS);
+
+/**
+ * Powers of two below the core count, then the core count itself
+ */
+function workerCounts(max) {
+  const counts = [];
+  for (let n = 1; n < max; n *= 2) counts.push(n);
+  counts.push(max);
+  return counts;
+}
+
+/**
+ * Milliseconds to index dir from nothing, thread start-up included: a fresh
+ * indexer and pool each time, so no parse result is reused
+ */
+async function coldBuild(workers) {
+  const pool = new ParsePool(workers);
+  const indexer = new SymbolIndexer(dir, undefined, pool);
+  const start = process.hrtime.bigint();
+  const result = await indexer.refresh();
+  const ms = Number(process.hrtime.bigint() - start) / 1e6;
+  await pool.close();
+  return { ms, files: result.files, symbols: result.symbols };
+}
+
+const rows = [];
+for (const workers of workerCounts(Math.max(1, Math.floor(maxWorkers) || 1))) {
+  let best;
+  for (let round = 0; round < ROUNDS; round++) {
+    const run = await coldBuild(workers);
+    if (!best || run.ms < best.ms) best = run;
+  }
+  rows.push({ workers, ...best });
+}
+
+// One worker parses on the main thread, the baseline the others are measured against
+const [first] = rows;
+console.log(`Cold index build of ${dir}: ${first.files} file(s), ${first.symbols} symbol(s)\n`);
+console.log('  workers        ms  speedup');
+for (const { workers, ms } of rows) {
+  const speedup = ms > 0 ? (first.ms / ms).toFixed(2) : '-';
+  const columns = [String(workers).padStart(7), ms.toFixed(0).padStart(8), speedup.padStart(6)];
+  console.log(`  ${columns.join('  ')}x`);
+}
@@ -4,8 +4,238 @@
  */
 
 import type { Tool } from '@modelcontextprotocol/sdk/types.js';
+import { SymbolKindSchema } from '../types/index.js';
+import { DOC_MODES, SORT_KEYS, SORT_ORDERS } from '../indexes/symbol-indexer.js';
+import { NAMING_STYLES } from '../validation/naming-checker.js';
+
+// Narrow an analysis to part of the project, e.g. one service in a monorepo
+const SCOPE_PROPERTIES = {
+  include: {
+    type: 'array',
+    items: { type: 'string' },
+    description:
+      'Only analyze files matching one of these globs or path prefixes, relative to the project root (e.g. cmd/**, internal/api); entries starting with ! exclude (e.g. !**/*_test.go)',
+  },
+  exclude: {
+    type: 'array',
+    items: { type: 'string' },
+    description:
+      'Skip files matching these globs or path prefixes, relative to the project root; applied on top of .gitignore and ignore',
+  },
+};
+
+// Project walking options shared by the indexing tools
+const WALK_PROPERTIES = {
+  ignore: {
+    type: 'array',
+    items: { type: 'string' },
+    description: 'Extra glob patterns to skip, in addition to .gitignore (e.g. **/generated/**)',
+  },
+  includeIgnored: {
+    type: 'boolean',
+    description: 'Also walk paths ignored by .gitignore and the defaults (vendor, node_modules, ...)',
+    default: false,
+  },
+  maxFileSizeBytes: {
+    type: 'number',
+    description:
+      'Files larger than this are not parsed and are listed as excluded (default: 2097152). Binary files are always excluded.',
+    default: 2097152,
+  },
+};
+
+// Walk depth limit for the tools that take it
+const DEPTH_PROPERTIES = {
+  maxDepth: {
+    type: 'number',
+    description:
+      'Deepest directory level to walk, counted from path or the project root: 0 = only the files directly in it (default: unlimited). Directories left unwalked are listed as excluded.',
+  },
+};
+
+// Symbol kind filter shared by extraction and search
+const KIND_PROPERTIES = {
+  kinds: {
+    type: 'array',
+    items: { type: 'string', enum: SymbolKindSchema.options },
+    description:
+      'Only symbols of any of these kinds (e.g. ["function", "method"]); "type" matches every type declaration, including structs, interfaces, classes, and enums',
+  },
+};
+
+// Doc comment length, shared by the tools returning symbols
+const DOC_PROPERTIES = {
+  docMode: {
+    type: 'string',
+    enum: [...DOC_MODES],
+    description:
+      'How much of each doc comment to return: summary (first sentence), full, or none (default: full)',
+    default: 'full',
+  },
+};
+
+// Ordering of symbol results, and the complexity to order them by
+const SORT_PROPERTIES = {
+  sortBy: {
+    type: 'string',
+    enum: [...SORT_KEYS],
+    description:
+      'Sort results by name, line (file, then line), kind, or complexity (needs includeComplexity; symbols without one go last). Equal keys keep their usual order (default: unsorted)',
+  },
+  order: {
+    type: 'string',
+    enum: [...SORT_ORDERS],
+    description: 'Direction for sortBy (default: asc)',
+    default: 'asc',
+  },
+  includeComplexity: {
+    type: 'boolean',
+    description:
+      'Attach the cyclomatic complexity of each function and method, as eng_complexity computes it',
+    default: false,
+  },
+};
+
+// Generated code, left out of symbol results unless asked for
+const GENERATED_PROPERTIES = {
+  includeGenerated: {
+    type: 'boolean',
+    description:
+      'Include generated files: a "Code generated ... DO NOT EDIT." header or names like *.pb.go, *_gen.go, *_pb2.py (default: false; the number left out is reported)',
+    default: false,
+  },
+};
+
+// Go build target shared by the symbol-indexing tools
+const BUILD_PROPERTIES = {
+  goos: {
+    type: 'string',
+    description:
+      'Only include Go files that build for this GOOS (e.g. linux, windows); files left out by //go:build lines or _GOOS file suffixes are listed as excluded. Defaults to the host when goarch or buildTags is set.',
+  },
+  goarch: {
+    type: 'string',
+    description: 'Only include Go files that build for this GOARCH (e.g. amd64, arm64)',
+  },
+  buildTags: {
+    type: 'array',
+    items: { type: 'string' },
+    description: 'Extra build tags that count as satisfied (e.g. integration, cgo)',
+  },
+};
+
+// Diff-aware scoping shared by the analysis tools
+const CHANGE_SCOPE_PROPERTIES = {
+  changedFiles: {
+    type: 'array',
+    items: { type: 'string' },
+    description: 'Only analyze these files plus files that directly import them',
+  },
+  gitRange: {
+    type: 'string',
+    description:
+      'Git range (e.g. main...HEAD) to compute changed files from; analyzes everything if git is unavailable',
+  },
+};
+
+// Shared by tools that return lists
+const STREAM_PROPERTIES = {
+  stream: {
+    type: 'boolean',
+    description:
+      'Send results in batches as progress notifications (message: {"batch", "items"}) as they are found; the final response is {"complete": true, "total", "batches"}. Requires a progressToken in the request _meta.',
+    default: false,
+  },
+  batchSize: {
+    type: 'number',
+    description: 'Items per streamed batch (default: 200)',
+    default: 200,
+  },
+};
+
+// Shared by tools whose result lists can be fetched page by page
+const PAGINATION_PROPERTIES = {
+  cursor: {
+    type: 'string',
+    description:
+      'nextCursor from the previous page; fails if the query or the index changed in between',
+  },
+  pageSize: {
+    type: 'number',
+    description:
+      'Results per page (default: 100). Passing cursor or pageSize returns nextCursor while more results remain.',
+  },
+};
+
+// Analyze an unsaved editor buffer instead of files on disk
+const BUFFER_PROPERTIES = {
+  content: {
+    type: 'string',
+    description:
+      'Source text to analyze instead of reading from disk; path then only names the buffer in results (default: <buffer>)',
+  },
+  language: {
+    type: 'string',
+    description:
+      'Language of content (go, typescript, python, rust, java); optional when path has a recognized extension',
+  },
+};
+
+// Shared by tools that return symbols
+const BLAME_PROPERTIES = {
+  includeBlame: {
+    type: 'boolean',
+    description:
+      'Attach git blame (author, commit, date) for each definition line; omitted outside git or on uncommitted lines',
+    default: false,
+  },
+};
+
+// Server-wide tools, not tied to a project root
+const ROOTLESS_TOOLS = new Set([
+  'eng_add_root',
+  'eng_list_roots',
+  'eng_queue_stats',
+  'eng_batch',
+  'eng_remote_symbols',
+  'eng_describe_tool',
+  'eng_server_info',
+]);
+
+const ROOT_PROPERTY = {
+  type: 'string',
+  description:
+    'Project root to run against (see eng_list_roots). Default: the first root; symbol search, find references, and text search cover every root instead.',
+};
+
+const TIMEOUT_PROPERTY = {
+  type: 'number',
+  description:
+    'Stop after this many milliseconds and return what was finished, flagged with _meta.timedOut (default: the server --timeout; 0 = no limit)',
+};
+
+const MAX_RESPONSE_PROPERTY = {
+  type: 'number',
+  description:
+    'Cut results longer than this many bytes: lists lose their last items, and the result is flagged with truncated: true, omitted, and _meta.truncation (default: the server --max-response-bytes, 1 MB; 0 = no limit)',
+};
 
 export function registerCommands(): Tool[] {
+  return listTools().map(tool => ({
+    ...tool,
+    inputSchema: {
+      ...tool.inputSchema,
+      properties: {
+        ...tool.inputSchema.properties,
+        ...(ROOTLESS_TOOLS.has(tool.name) ? {} : { root: ROOT_PROPERTY }),
+        timeoutMs: TIMEOUT_PROPERTY,
+        maxResponseBytes: MAX_RESPONSE_PROPERTY,
+      },
+    },
+  }));
+}
+
+function listTools(): Tool[] {
   return [
     // Lifecycle Commands
     {
@@ -405,5 +635,1394 @@ export function registerCommands(): Tool[] {
         required: ['code'],
       },
     },
+
+    // Code Intelligence
+    {
+      name: 'eng_extract_symbols',
+      description:
+        'Extract symbols (functions, methods, classes, types, constants) from source files. Dispatches on file extension, so mixed-language projects return one consistent symbol list.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File or directory to extract from (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          signaturesOnly: {
+            type: 'boolean',
+            description:
+              'Return only each declaration line (e.g. func (c *Calculator) Add(n float64) *Calculator) with file and line, to map an API in fewer tokens',
+            default: false,
+          },
+          includeDocs: {
+            type: 'boolean',
+            description: 'Set false to drop doc comments; same as docMode none',
+            default: true,
+          },
+          ...DOC_PROPERTIES,
+          decorator: {
+            type: 'string',
+            description:
+              'Only symbols with this decorator or annotation, matched by name without arguments (e.g. Override, @GetMapping, app.route)',
+          },
+          ...KIND_PROPERTIES,
+          ...SORT_PROPERTIES,
+          ...BUFFER_PROPERTIES,
+          ...WALK_PROPERTIES,
+          ...DEPTH_PROPERTIES,
+          ...GENERATED_PROPERTIES,
+          ...BUILD_PROPERTIES,
+          ...CHANGE_SCOPE_PROPERTIES,
+          ...STREAM_PROPERTIES,
+          ...BLAME_PROPERTIES,
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_summarize_file',
+      description:
+        'Compact outline per file: top-level declarations with methods nested under their types, giving only kind, name, and line range (no signatures, docs, or bodies). A lighter alternative to eng_extract_symbols for orientation and navigation trees.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File or directory to outline (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...WALK_PROPERTIES,
+          ...BUILD_PROPERTIES,
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_search_symbols',
+      description:
+        'Fuzzy search over all symbol names. Results are ranked: exact, then prefix, then word-start (e.g. "calc" in NewCalculator), then substring, then subsequence matches.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          query: {
+            type: 'string',
+            description: 'Search text; include a dot to match qualified names (Calculator.Add)',
+          },
+          limit: {
+            type: 'number',
+            description: 'Maximum results to return (default: 20)',
+            default: 20,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...KIND_PROPERTIES,
+          ...SORT_PROPERTIES,
+          ...DOC_PROPERTIES,
+          ...GENERATED_PROPERTIES,
+          ...STREAM_PROPERTIES,
+          ...PAGINATION_PROPERTIES,
+          ...BLAME_PROPERTIES,
+          ...SCOPE_PROPERTIES,
+        },
+        required: ['query'],
+      },
+    },
+    {
+      name: 'eng_search_text',
+      description:
+        'Regex search over raw file contents, grep-style: returns file, line, column, and the matching line. Finds what symbol search cannot, such as string literals, TODOs, and config keys. Honors the same ignore rules as the indexer; binary and oversized files are skipped.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          pattern: {
+            type: 'string',
+            description: 'JavaScript regular expression, e.g. "api[_-]?key" or "TODO\\b"',
+          },
+          path: {
+            type: 'string',
+            description: 'File or directory to search (default: project root)',
+          },
+          glob: {
+            type: 'string',
+            description:
+              'Only search files matching this glob; without a slash it matches at any depth (e.g. *.go, src/**/*.ts)',
+          },
+          ignoreCase: {
+            type: 'boolean',
+            description: 'Case-insensitive matching',
+            default: false,
+          },
+          wholeWord: {
+            type: 'boolean',
+            description: 'Only match at word boundaries',
+            default: false,
+          },
+          maxResults: {
+            type: 'number',
+            description: 'Stop after this many matches (default: 200)',
+            default: 200,
+          },
+          contextBefore: {
+            type: 'number',
+            description:
+              'Lines of context to include before each match, clipped at the start of its file (default: 0, max: 20)',
+            default: 0,
+          },
+          contextAfter: {
+            type: 'number',
+            description:
+              'Lines of context to include after each match, clipped at the end of its file (default: 0, max: 20)',
+            default: 0,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...WALK_PROPERTIES,
+          ...PAGINATION_PROPERTIES,
+          ...SCOPE_PROPERTIES,
+        },
+        required: ['pattern'],
+      },
+    },
+    {
+      name: 'eng_list_markers',
+      description:
+        'Collect TODO, FIXME, HACK, and XXX markers from comments in every supported language, grouped by keyword. Each marker has its file, line, comment text, and author tag from TODO(alice). Markers inside string literals are ignored. groupBy "owner" buckets markers per owner from TODO(name) / FIXME(@handle) tags, with per-keyword counts and an unassigned bucket; blame attributes untagged markers to the git blame author of their line.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File or directory to scan (default: project root)',
+          },
+          keywords: {
+            type: 'array',
+            items: { type: 'string' },
+            description: 'Marker keywords, matched case-sensitively (default: TODO, FIXME, HACK, XXX)',
+          },
+          groupBy: {
+            type: 'string',
+            enum: ['keyword', 'owner'],
+            description: 'Group markers by keyword or by owner (default: keyword)',
+            default: 'keyword',
+          },
+          blame: {
+            type: 'boolean',
+            description:
+              'Attribute markers without an owner tag to the git blame author of the line (default: false)',
+            default: false,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ignore: WALK_PROPERTIES.ignore,
+          includeIgnored: WALK_PROPERTIES.includeIgnored,
+          ...PAGINATION_PROPERTIES,
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_count_lines',
+      description:
+        'Line metrics per file and per language: total, code, comment, and blank lines. Comments are found by the language lexer, so // or /* inside string literals count as code; a line with code and a trailing comment counts as code.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File or directory to count (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ignore: WALK_PROPERTIES.ignore,
+          includeIgnored: WALK_PROPERTIES.includeIgnored,
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_project_tree',
+      description:
+        'Directory hierarchy of the project, each directory annotated with its file count by language and its number of exported symbols (counting everything below it). Honors the same ignore rules as the indexer.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'Directory to start from (default: project root)',
+          },
+          maxDepth: {
+            type: 'number',
+            description:
+              'Deepest directory level to list; deeper directories are folded into their ancestor (default: unlimited)',
+          },
+          walkDepth: {
+            type: 'number',
+            description:
+              'Deepest directory level to walk at all: 0 = only the files directly in path (default: unlimited). Deeper directories are not counted and are listed as not walked.',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ignore: WALK_PROPERTIES.ignore,
+          includeIgnored: WALK_PROPERTIES.includeIgnored,
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_refresh_index',
+      description:
+        'Incrementally refresh the symbol index. Only files whose content changed since the last scan are re-parsed; reports files added, changed, removed, and skipped.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          ...WALK_PROPERTIES,
+          ...DEPTH_PROPERTIES,
+          ...BUILD_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_symbol_delta',
+      description:
+        'Symbols added, changed, and removed since an index version the client saw before, so a client can keep its symbol view current without re-fetching the whole list. Every response carries the new version to pass as since on the next call; without since, or when since is too old, the whole index is returned.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          since: {
+            type: 'string',
+            description:
+              'Index version from a previous delta or notifications/index/changed; omit for the full index',
+          },
+          refresh: {
+            type: 'boolean',
+            description:
+              'Refresh the index before computing the delta (default: true); pass false when --watch keeps it current',
+            default: true,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+      },
+    },
+    {
+      name: 'eng_cache_stats',
+      description:
+        'Show parse cache statistics: hits, misses, evictions, and size. Parse results are cached by content hash with LRU eviction, so identical files parse once and reverted files are not re-parsed. Set the entry limit with the --cache-size server argument.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+      },
+    },
+    {
+      name: 'eng_queue_stats',
+      description:
+        'Show analysis queue statistics: running and queued requests, completed count, and how many gave up with a server busy error. Expensive analyses (call graph, references, scans) share a concurrency limit set by the --max-concurrent and --queue-timeout server arguments; lookups and file reads are never queued.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+      },
+    },
+    {
+      name: 'eng_add_root',
+      description:
+        'Register another project root (e.g. a second repository) with this server. Tools then accept root to target it; symbol search, find references, and text search cover every root when root is omitted.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'Project directory, absolute or relative to the server working directory',
+          },
+          name: {
+            type: 'string',
+            description: 'Name used for root arguments and in results (default: directory name)',
+          },
+        },
+        required: ['path'],
+      },
+    },
+    {
+      name: 'eng_list_roots',
+      description:
+        'List registered project roots (from --root server arguments and eng_add_root). The first is the default for tools called without root.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+      },
+    },
+    {
+      name: 'eng_complexity',
+      description:
+        'Compute cyclomatic complexity per function and method (1 + branch points such as if, for, case, &&, ||). Results are sorted most complex first.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File or directory to analyze (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...BUFFER_PROPERTIES,
+          ...CHANGE_SCOPE_PROPERTIES,
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_find_clones',
+      description:
+        'Find copy-pasted functions: groups of functions and methods whose bodies are identical or near-identical once whitespace, comments, string contents, and local variable and parameter names are normalized away. Near-identical means the Jaccard similarity of the normalized token sequences reaches the threshold. Each group lists its members with file and line range.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File or directory to analyze (default: project root)',
+          },
+          threshold: {
+            type: 'number',
+            description:
+              'Similarity from 0 to 1 at which two functions count as clones; 1 reports identical bodies only (default: 0.8)',
+            default: 0.8,
+          },
+          minLines: {
+            type: 'number',
+            description: 'Ignore functions shorter than this many lines (default: 5)',
+            default: 5,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_check_docs',
+      description:
+        'Lint Go doc comments: report exported functions, methods, types, constants, and variables with no doc comment, or whose comment does not start with the symbol name.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File or directory to check (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...CHANGE_SCOPE_PROPERTIES,
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_check_naming',
+      description:
+        'Check Go symbol names against naming rules per kind and report each violation with the rule it broke and a suggested name. Defaults are idiomatic Go (MixedCaps, consistent initialisms like ID and URL); override them in .engineering/naming.yaml or with rules.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File or directory to check (default: project root)',
+          },
+          rules: {
+            type: 'array',
+            description:
+              'Rules applied over the defaults and .engineering/naming.yaml: a known id updates that rule (enabled: false turns it off), a new id adds one',
+            items: {
+              type: 'object',
+              properties: {
+                id: { type: 'string' },
+                description: { type: 'string' },
+                kinds: { type: 'array', items: { type: 'string', enum: SymbolKindSchema.options } },
+                exported: {
+                  type: 'boolean',
+                  description: 'Only exported (true) or unexported (false) names',
+                },
+                style: { type: 'string', enum: [...NAMING_STYLES] },
+                pattern: { type: 'string', description: 'Regex the whole name must match' },
+                suffix: { type: 'string', description: 'Required suffix, e.g. "er"' },
+                initialisms: {
+                  type: 'boolean',
+                  description: 'Initialisms keep one case: UserID, not UserId',
+                },
+                enabled: { type: 'boolean' },
+              },
+              required: ['id'],
+            },
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...CHANGE_SCOPE_PROPERTIES,
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_list_deprecated',
+      description:
+        'List Go symbols marked deprecated by a "Deprecated:" paragraph in their doc comment, with the deprecation message (e.g. what to use instead). For steering callers away from APIs being phased out and tracking their removal.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File or directory to search (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...CHANGE_SCOPE_PROPERTIES,
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_test_gaps',
+      description:
+        'Find exported Go symbols that no *_test.go file in their package references. Each gap notes whether the symbol is still reached indirectly, through the call graph from tested functions.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'Directory to check (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+      },
+    },
+    {
+      name: 'eng_list_tests',
+      description:
+        'Catalog the tests in Go *_test.go files without running them: Test, Benchmark, Fuzz, and Example functions (and testify suite methods) with file and line, and under each the subtests it starts with t.Run. Table-driven names (t.Run(tt.name, ...) over a slice or map of cases) are expanded to one subtest per case when they are string literals; other names are listed as dynamic.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File or directory to list (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_go_examples',
+      description:
+        'Go Example functions in *_test.go files, each with the symbol it documents (ExampleCalculator_Add documents Calculator.Add, ExampleNewCalculator documents NewCalculator, Example the package), where that symbol is declared, the example body, and the expected output from its // Output: or // Unordered output: comment. Use it to show runnable usage next to a symbol.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File or directory to search (default: project root)',
+          },
+          symbol: {
+            type: 'string',
+            description:
+              'Only examples documenting this symbol, by name or qualified name (Add or Calculator.Add)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_dead_code',
+      description:
+        'Find unexported Go functions, types, and methods that nothing in their package uses. Any mention in code counts as a use, including functions passed as values (e.g. http.HandleFunc("/", healthHandler)). Exported symbols, main, and init are never reported.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'Directory to check (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+      },
+    },
+    {
+      name: 'eng_diagnostics',
+      description:
+        'Run go vet on a Go file, package, or pattern and return structured findings (severity, analyzer, file, line, column, message): printf format mismatches, unreachable code, copied locks, type errors, and the rest of the vet suite. Optionally adds shadowed-variable checks.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description:
+              'Go file, package directory, or pattern such as ./internal/... (default: ./..., the whole module)',
+          },
+          shadow: {
+            type: 'boolean',
+            description:
+              'Also report shadowed variables (requires the shadow analyzer: go install golang.org/x/tools/go/analysis/passes/shadow/cmd/shadow@latest)',
+            default: false,
+          },
+          severity: {
+            type: 'string',
+            enum: ['error', 'warning', 'info'],
+            description:
+              'Minimum severity: error (type errors), warning (vet findings), info (shadowing). Default: info, everything',
+            default: 'info',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+      },
+    },
+    {
+      name: 'eng_diff_symbols',
+      description:
+        'Compare symbols between two git revisions: added, removed, and modified (same name, different signature text). Exported symbols removed or re-signed are flagged as breaking. Reads git objects directly, so neither revision needs to be checked out.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          from: {
+            type: 'string',
+            description: 'Older revision: branch, tag, or commit (e.g. v1.2.0, HEAD~5)',
+          },
+          to: {
+            type: 'string',
+            description: 'Newer revision (default: HEAD)',
+            default: 'HEAD',
+          },
+          path: {
+            type: 'string',
+            description: 'Only compare files under this path (default: project root)',
+          },
+          exportedOnly: {
+            type: 'boolean',
+            description: 'Only report exported symbols, i.e. the public API',
+            default: false,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ignore: WALK_PROPERTIES.ignore,
+          includeIgnored: WALK_PROPERTIES.includeIgnored,
+          ...SCOPE_PROPERTIES,
+        },
+        required: ['from'],
+      },
+    },
+    {
+      name: 'eng_compare_complexity',
+      description:
+        'Flag cyclomatic complexity regressions between two git revisions: functions whose complexity grew by more than maxIncrease, and new functions above newThreshold. Functions are matched by qualified name, so a rename counts as a removal and an addition. passed is false when anything is flagged, for gating CI. Reads git objects directly, so neither revision needs to be checked out.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          from: {
+            type: 'string',
+            description: 'Older revision: branch, tag, or commit (e.g. main, HEAD~1)',
+          },
+          to: {
+            type: 'string',
+            description: 'Newer revision (default: HEAD)',
+            default: 'HEAD',
+          },
+          path: {
+            type: 'string',
+            description: 'Only compare files under this path (default: project root)',
+          },
+          maxIncrease: {
+            type: 'number',
+            description: 'Largest allowed increase for an existing function (default: 0)',
+            default: 0,
+          },
+          newThreshold: {
+            type: 'number',
+            description: 'Highest allowed complexity for a new function (default: 10)',
+            default: 10,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ignore: WALK_PROPERTIES.ignore,
+          includeIgnored: WALK_PROPERTIES.includeIgnored,
+          ...SCOPE_PROPERTIES,
+        },
+        required: ['from'],
+      },
+    },
+    {
+      name: 'eng_churn_risk',
+      description:
+        'Rank files by churn risk: how complex they are (the sum of their functions\' cyclomatic complexity) combined with how often they change (commits touching them within the history window). The score is the weighted geometric mean of the two, each relative to the highest among the files measured, so files that are both complex and frequently changed come first. Use it to pick where reviews and refactoring pay off most.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File or directory to rank (default: project root)',
+          },
+          days: {
+            type: 'number',
+            description: 'History window: count commits from the last N days (default: 90)',
+            default: 90,
+          },
+          complexityWeight: {
+            type: 'number',
+            description:
+              'Weight of complexity against churn, from 0 (churn only) to 1 (complexity only) (default: 0.5)',
+            default: 0.5,
+          },
+          limit: {
+            type: 'number',
+            description: 'Maximum files to return (default: 20)',
+            default: 20,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_api_fingerprint',
+      description:
+        "Hash the exported API of a package into a stable SHA-256 fingerprint, with the normalized signature list behind it. Parameter names, whitespace, declaration order, bodies, constant values, and test files don't count, so two builds with the same public API get the same fingerprint. For release gating: store the fingerprint and compare it in CI to catch accidental API changes.",
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'Package directory or file (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_find_references',
+      description:
+        'Find every definition and usage of a symbol across the project. Matches inside comments and string literals are ignored. Pass file and line to target one specific definition.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          symbol: {
+            type: 'string',
+            description: 'Symbol name, optionally qualified (e.g. CalculateSum, Calculator.Add)',
+          },
+          file: {
+            type: 'string',
+            description: 'File containing the definition, used with line to disambiguate',
+          },
+          line: {
+            type: 'number',
+            description: 'Line inside the definition, used with file to disambiguate',
+          },
+          contextBefore: {
+            type: 'number',
+            description:
+              'Snippet lines before each occurrence, clipped at the start of its file (default: 1, max: 20)',
+            default: 1,
+          },
+          contextAfter: {
+            type: 'number',
+            description:
+              'Snippet lines after each occurrence, clipped at the end of its file (default: 1, max: 20)',
+            default: 1,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...STREAM_PROPERTIES,
+          ...PAGINATION_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_rename_symbol',
+      description:
+        'Plan a rename: returns text edits (file, line, column, old text, new text) for the definition and every reference, skipping comments and strings. Refuses names that collide with a symbol in the same scope. Writes nothing unless apply is true.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          symbol: {
+            type: 'string',
+            description: 'Current name, optionally qualified (e.g. Calculator, Calculator.Add)',
+          },
+          newName: {
+            type: 'string',
+            description: 'New identifier',
+          },
+          file: {
+            type: 'string',
+            description: 'File containing the definition, used with line to disambiguate',
+          },
+          line: {
+            type: 'number',
+            description: 'Line inside the definition, used with file to disambiguate',
+          },
+          apply: {
+            type: 'boolean',
+            description:
+              'Write the edits to disk (default: false, dry run). Files are replaced atomically, and a failed write rolls back the ones already written',
+            default: false,
+          },
+          backup: {
+            type: 'boolean',
+            description: 'With apply, keep each original as <file>.bak (default: false)',
+            default: false,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+        required: ['newName'],
+      },
+    },
+    {
+      name: 'eng_format_code',
+      description:
+        "Format a file or unsaved buffer with the language's canonical formatter (goimports or gofmt for Go, prettier for TypeScript/JavaScript, ruff or black for Python, rustfmt for Rust). Returns the formatted text and whether anything changed; code that doesn't parse returns the formatter's error instead. Writes nothing unless apply is true.",
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File to format',
+          },
+          ...BUFFER_PROPERTIES,
+          apply: {
+            type: 'boolean',
+            description:
+              'Write the formatted file back to disk, atomically (default: false; files only)',
+            default: false,
+          },
+          backup: {
+            type: 'boolean',
+            description: 'With apply, keep the original as <file>.bak (default: false)',
+            default: false,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+      },
+    },
+    {
+      name: 'eng_type_info',
+      description:
+        'Show a Go type with its struct fields, constructors (New... functions returning the type), and methods grouped by pointer or value receiver, each with signature and doc. Fields and methods promoted from embedded types are listed separately with the embedding they come through.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          type: {
+            type: 'string',
+            description: 'Type name (e.g. Calculator)',
+          },
+          file: {
+            type: 'string',
+            description: 'File declaring the type, when several packages define the same name',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+        required: ['type'],
+      },
+    },
+    {
+      name: 'eng_implementations',
+      description:
+        'Find Go types whose method sets structurally satisfy an interface: a project interface (e.g. Store, store.Store) or a common standard library one (e.g. io.Writer, fmt.Stringer, sort.Interface). Embedded interfaces and methods promoted through embedded structs are included; types that need a pointer receiver are reported as *T.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          interface: {
+            type: 'string',
+            description: 'Interface name, optionally package-qualified (e.g. Store, io.Writer)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+        required: ['interface'],
+      },
+    },
+    {
+      name: 'eng_symbol_context',
+      description:
+        'Return the smallest declaration (function, method, type) enclosing a file line, including its doc comment, with start and end lines.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          file: {
+            type: 'string',
+            description: 'File path relative to the project root',
+          },
+          line: {
+            type: 'number',
+            description: 'Line number inside the declaration (1-based)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...BLAME_PROPERTIES,
+        },
+        required: ['file', 'line'],
+      },
+    },
+    {
+      name: 'eng_analyze_function',
+      description:
+        'Everything about one function or method in a single call: its source with doc comment, signature, doc, cyclomatic complexity, parameters, and the functions it calls (Go calls resolved through the package call graph, receiver methods included). Name it by symbol, by a line inside it, or both.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          file: {
+            type: 'string',
+            description: 'File containing the function, relative to the project root',
+          },
+          symbol: {
+            type: 'string',
+            description: 'Function name, optionally qualified (e.g. FetchData, Client.FetchData)',
+          },
+          line: {
+            type: 'number',
+            description: 'Any line inside the function (1-based); picks among same-named ones',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+        required: ['file'],
+      },
+    },
+    {
+      name: 'eng_resolve_symbol',
+      description:
+        "Go to definition: given a file, line, and column of a usage, return the location and signature of the symbol it refers to. Receiver method calls resolve through the receiver's type, so c.Add with c a *Calculator lands on Calculator.Add. An ambiguous name returns every candidate instead of a guess.",
+      inputSchema: {
+        type: 'object',
+        properties: {
+          file: {
+            type: 'string',
+            description: 'File containing the usage, relative to the project root',
+          },
+          line: {
+            type: 'number',
+            description: 'Line of the usage (1-based)',
+          },
+          column: {
+            type: 'number',
+            description: 'Column anywhere inside the identifier (1-based)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+        required: ['file', 'line', 'column'],
+      },
+    },
+    {
+      name: 'eng_hover',
+      description:
+        'Editor hover for the identifier at a file, line, and column: the symbol it declares or refers to, with kind, signature, doc summary, defining location, and whether the position is the definition or a usage. Names resolve as in eng_resolve_symbol. Returns null on whitespace, comments, strings, keywords, and names not defined in the project.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          file: {
+            type: 'string',
+            description: 'File to hover in, relative to the project root',
+          },
+          line: {
+            type: 'number',
+            description: 'Line of the cursor (1-based)',
+          },
+          column: {
+            type: 'number',
+            description: 'Column of the cursor (1-based)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+        required: ['file', 'line', 'column'],
+      },
+    },
+    {
+      name: 'eng_external_definition',
+      description:
+        "Find the source of a Go symbol outside the project, such as http.Get: the standard library under GOROOT, or a dependency in vendor/, a local replace, or the module cache at the version go.mod requires (major-version paths like /v2 and gopkg.in/yaml.v3 included). Returns the file path and the declaration's source. Fails with FILE_NOT_FOUND when the source isn't available locally, e.g. a module that hasn't been downloaded.",
+      inputSchema: {
+        type: 'object',
+        properties: {
+          symbol: {
+            type: 'string',
+            description:
+              'Package-qualified name: pkg.Name or pkg.Type.Method, where pkg is an import path (net/http) or the name it is imported as (http)',
+          },
+          file: {
+            type: 'string',
+            description:
+              "File whose imports and go.mod resolve the package (default: the project's imports and root go.mod)",
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+        required: ['symbol'],
+      },
+    },
+    {
+      name: 'eng_remote_symbols',
+      description:
+        "Extract symbols from a git repository that isn't checked out, e.g. to see a dependency's public API: the server shallow-clones the one commit into a temporary directory, indexes it, and removes the clone after 10 idle minutes, so follow-up calls on the same URL and ref don't fetch again. Clones over maxRepoBytes are refused, and the fetch is stopped as soon as it passes the limit.",
+      inputSchema: {
+        type: 'object',
+        properties: {
+          url: {
+            type: 'string',
+            description:
+              'Repository URL: https://, ssh://, git://, or user@host:path (e.g. https://github.com/spf13/cobra)',
+          },
+          ref: {
+            type: 'string',
+            description: 'Branch, tag, or commit to extract (default: the default branch)',
+          },
+          path: {
+            type: 'string',
+            description: 'Directory or file inside the repository (default: all of it)',
+          },
+          exportedOnly: {
+            type: 'boolean',
+            description: 'Only exported symbols, i.e. the public API',
+            default: false,
+          },
+          signaturesOnly: {
+            type: 'boolean',
+            description: 'Return just each declaration line instead of the full symbol',
+            default: false,
+          },
+          maxRepoBytes: {
+            type: 'number',
+            description: 'Refuse repositories whose clone is larger than this (default: 104857600)',
+            default: 104857600,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...KIND_PROPERTIES,
+          ...DOC_PROPERTIES,
+          ...SCOPE_PROPERTIES,
+        },
+        required: ['url'],
+      },
+    },
+    {
+      name: 'eng_call_graph',
+      description:
+        'Build the call graph of a Go package: caller -> callee edges between its functions, with receiver method calls such as c.Add() resolved to Calculator.Add. Output as text, JSON, or DOT.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'Go package directory (default: project root)',
+          },
+          includeExternal: {
+            type: 'boolean',
+            description: 'Include calls into other packages, e.g. http.Get (default: false)',
+            default: false,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json', 'dot'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+      },
+    },
+    {
+      name: 'eng_list_routes',
+      description:
+        'List the HTTP routes a Go service registers, with method, path pattern, and handler: net/http HandleFunc and Handle (including Go 1.22 "GET /path" patterns), gorilla/mux .Methods(), and Gin, Echo, Chi, and Fiber verb methods, with group and subrouter prefixes applied. Handlers are resolved to their definitions.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File or directory to search (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_imports',
+      description:
+        'List Go imports per file, grouped into standard library, third-party, and intra-module packages (module path from go.mod). For directories, also summarizes external packages by the number of files using them.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'Go file or directory (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+      },
+    },
+    {
+      name: 'eng_unused_imports',
+      description:
+        'Find Go imports a file declares but never uses, and packages it uses without importing them (e.g. strings.ToUpper with no "strings" import), when the name is a known standard library package or one imported elsewhere under path. Blank (_) and dot (.) imports are listed as intentional, never as unused. Package names come from the package clause for module and vendored packages, otherwise from the import path (gopkg.in/yaml.v3 -> yaml).',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'Go file or directory (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+      },
+    },
+    {
+      name: 'eng_validate_references',
+      description:
+        'Check that every identifier Go files use resolves to something: a declaration in the file (parameters, :=, var, const, type, struct fields), a package-level symbol of its package, an import, or a builtin. Reports each unresolved name with file, line, and column, flagging names used as a qualifier (json.Marshal) as likely missing imports. A lightweight name-resolution pass for catching half-finished renames, not a type check: fields and methods after a dot are not checked.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'Go file, package directory, or tree to check (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ...SCOPE_PROPERTIES,
+        },
+      },
+    },
+    {
+      name: 'eng_package_graph',
+      description:
+        'Build the Go package dependency graph: nodes are packages, and an edge A -> B means a file of A imports B. Import cycles among the module\'s own packages are listed, which surfaces layering violations. Test files are left out. Use collapseExternal to fold stdlib and third-party packages into one "external" node.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'Directory to analyze (default: project root)',
+          },
+          collapseExternal: {
+            type: 'boolean',
+            description: 'Represent all stdlib and third-party packages as a single "external" node',
+            default: false,
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json', 'dot'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+      },
+    },
+    {
+      name: 'eng_related_files',
+      description:
+        'Companions of a file found by naming and layout conventions: its tests (x_test.go, test_x.py, x.test.ts, XTest.java, tests/ directories), mocks (mock_x.go, mocks/, __mocks__/), and other source files in the same Go package or directory. For a test or mock, lists the source files it covers instead.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          file: {
+            type: 'string',
+            description: 'Source, test, or mock file',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+          ignore: WALK_PROPERTIES.ignore,
+          includeIgnored: WALK_PROPERTIES.includeIgnored,
+          ...SCOPE_PROPERTIES,
+        },
+        required: ['file'],
+      },
+    },
+    {
+      name: 'eng_detect_language',
+      description:
+        'Detect the language of a file, or count languages under a directory. Uses the extension or well-known file name (Makefile, Dockerfile) first, then the shebang line and content heuristics for extensionless files. The same detection routes files to symbol parsers.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File or directory (default: project root)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+      },
+    },
+    {
+      name: 'eng_read_file',
+      description:
+        'Read a project file, optionally only a line range. Out-of-range lines are clamped, and the total line count is always returned so partial reads can be paged. Content is always UTF-8: a UTF-8 BOM is stripped, UTF-16 files with a BOM and Latin-1 (Windows-1252) files are transcoded, and the encoding found on disk is reported.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          path: {
+            type: 'string',
+            description: 'File path relative to the project root',
+          },
+          startLine: {
+            type: 'number',
+            description: 'First line to return, 1-based (default: 1)',
+          },
+          endLine: {
+            type: 'number',
+            description: 'Last line to return, inclusive (default: end of file)',
+          },
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+        required: ['path'],
+      },
+    },
+    {
+      name: 'eng_batch',
+      description:
+        "Run several tool calls in one request and get their results in the same order, each tagged success or error. Independent calls run in parallel; calls that write files or change server state (eng_scan, eng_refresh_index, eng_rename_symbol, ...) run alone, after the calls before them. A failing call doesn't stop the others. The batch's timeoutMs applies to each call that doesn't set its own, calls that don't set maxResponseBytes share the batch's cap equally, and the whole batch result is held to that cap. At most 50 calls; batches can't be nested.",
+      inputSchema: {
+        type: 'object',
+        properties: {
+          calls: {
+            type: 'array',
+            items: {
+              type: 'object',
+              properties: {
+                tool: { type: 'string', description: 'Tool name, e.g. eng_search_symbols' },
+                arguments: { type: 'object', description: 'Arguments, as for a direct call' },
+              },
+              required: ['tool'],
+            },
+            description: 'Tool invocations, e.g. [{"tool": "eng_hover", "arguments": {...}}]',
+          },
+        },
+        required: ['calls'],
+      },
+    },
+    {
+      name: 'eng_describe_tool',
+      description:
+        'Describe a tool: its input schema plus a JSON Schema for its output (the format=json response, and streamed items when the tool supports stream). Use it to generate client types instead of guessing response shapes.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          tool: {
+            type: 'string',
+            description: 'Tool name, e.g. eng_extract_symbols',
+          },
+        },
+        required: ['tool'],
+      },
+    },
+    {
+      name: 'eng_server_info',
+      description:
+        'Show server version and build commit, supported languages with their parser versions, every available tool, and index stats per root (files, symbols, last full scan or refresh). Use it to feature-detect tools and to include version details in bug reports.',
+      inputSchema: {
+        type: 'object',
+        properties: {
+          format: {
+            type: 'string',
+            enum: ['text', 'json'],
+            description: 'Output format (default: text)',
+            default: 'text',
+          },
+        },
+      },
+    },
   ];
 }
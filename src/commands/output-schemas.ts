@@ -0,0 +1,813 @@
+/**
+ * Tool Output Schemas
+ * JSON Schemas for each tool's result, generated from the zod schemas of what it serializes
+ */
+
+import { z } from 'zod';
+import { zodToJsonSchema } from 'zod-to-json-schema';
+import {
+  CallGraphSchema,
+  ComplexityEntrySchema,
+  ImportHygieneReportSchema,
+  ImportReportSchema,
+  PackageGraphSchema,
+  ParameterSchema,
+  ReferenceEntrySchema,
+  ReferenceValidationSchema,
+  RouteIndexEntrySchema,
+  SymbolEntrySchema,
+  SymbolKindSchema,
+  SymbolSignatureSchema,
+  TextEditSchema,
+} from '../types/index.js';
+import type { DetectionMethod, LanguageReport } from '../core/language-detector.js';
+import type { FileSlice } from '../core/file-reader.js';
+import type { FormatResult } from '../core/code-formatter.js';
+import type { StreamSummary } from '../core/result-stream.js';
+import type { CacheStats } from '../core/lru-cache.js';
+import type { BatchResult } from '../core/batch.js';
+import { ERROR_CODES } from '../core/errors.js';
+import type { ErrorInfo } from '../core/errors.js';
+import type { ConcurrencyStats } from '../core/concurrency-limiter.js';
+import type { ProjectRoot } from '../core/project-roots.js';
+import type { ServerInfo } from '../core/server-info.js';
+import type { GoStructField } from '../parsers/go-parser.js';
+import type { ExcludedFile, SymbolDelta } from '../indexes/symbol-indexer.js';
+import type { MatchType } from '../indexes/symbol-search.js';
+import type { SymbolContext } from '../indexes/symbol-context.js';
+import type { DefinitionResult, HoverResult } from '../indexes/definition-resolver.js';
+import type { GoRoute } from '../indexes/route-finder.js';
+import type { GoSubtest, TestCatalogReport } from '../indexes/test-catalog.js';
+import type { ExampleReport } from '../indexes/example-finder.js';
+import type { FunctionReport } from '../indexes/function-analyzer.js';
+import type { FileOutline, OutlineNode } from '../indexes/file-outline.js';
+import type { SymbolDiff } from '../indexes/symbol-diff.js';
+import type { ComplexityComparison } from '../indexes/complexity-diff.js';
+import type { ChurnRiskReport } from '../indexes/churn-risk.js';
+import type { ExternalDefinition } from '../indexes/external-resolver.js';
+import type { ApiFingerprint } from '../indexes/api-fingerprint.js';
+import type { CloneReport } from '../indexes/clone-detector.js';
+import type { TextSearchResult } from '../indexes/text-search.js';
+import type { Marker, MarkerReport } from '../indexes/marker-scanner.js';
+import type { ProjectTree } from '../indexes/project-tree.js';
+import type { LineCounts, LineReport } from '../indexes/line-counter.js';
+import type { RenamePlan } from '../indexes/rename-planner.js';
+import type { TypeDetails } from '../indexes/type-inspector.js';
+import type { ImplementationReport } from '../indexes/implementation-finder.js';
+import type { FileRole, RelatedFiles } from '../indexes/related-files.js';
+import type { DocViolation } from '../validation/doc-checker.js';
+import type { NamingViolation } from '../validation/naming-checker.js';
+import type { DeprecatedSymbol } from '../validation/deprecation-finder.js';
+import type { TestGapReport } from '../validation/test-gap-detector.js';
+import type { DeadCodeReport } from '../validation/dead-code-detector.js';
+import type { DiagnosticsReport } from '../validation/go-diagnostics.js';
+import { registerCommands } from './index.js';
+
+// Results declared as TypeScript interfaces get a schema here; the type
+// annotations fail the build when an interface drifts from its schema
+
+const ExcludedFileSchema: z.ZodType<ExcludedFile> = z.object({
+  file: z.string(),
+  reason: z.string(), // e.g. "binary", "3.1 MB exceeds 2.0 MB"
+});
+
+// Only present when changedFiles or gitRange narrowed the run
+const ScopeSchema = z.object({
+  files: z.array(z.string()).optional(),
+  changed: z.array(z.string()),
+  dependents: z.array(z.string()),
+  note: z.string(),
+});
+
+const StreamSummarySchema: z.ZodType<StreamSummary> = z.object({
+  complete: z.literal(true),
+  total: z.number(),
+  batches: z.number(),
+});
+
+const MatchTypeSchema: z.ZodType<MatchType> = z.enum([
+  'exact',
+  'prefix',
+  'word',
+  'substring',
+  'subsequence',
+]);
+
+const SymbolMatchSchema = SymbolEntrySchema.extend({
+  match: MatchTypeSchema,
+  score: z.number(),
+});
+
+const DocViolationSchema: z.ZodType<DocViolation> = z.object({
+  file: z.string(),
+  line: z.number(),
+  symbol: z.string(),
+  kind: z.string(),
+  reason: z.string(),
+});
+
+const NamingViolationSchema: z.ZodType<NamingViolation> = z.object({
+  file: z.string(),
+  line: z.number(),
+  symbol: z.string(),
+  kind: z.string(),
+  name: z.string(),
+  rule: z.string(),
+  message: z.string(),
+  suggestion: z.string().optional(),
+});
+
+const DeprecatedSymbolSchema: z.ZodType<DeprecatedSymbol> = z.object({
+  file: z.string(),
+  line: z.number(),
+  symbol: z.string(),
+  kind: z.string(),
+  exported: z.boolean(),
+  message: z.string().optional(),
+});
+
+const GoSubtestSchema: z.ZodType<GoSubtest> = z.lazy(() =>
+  z.object({
+    name: z.string(),
+    fullName: z.string(),
+    line: z.number(),
+    dynamic: z.boolean().optional(),
+    subtests: z.array(GoSubtestSchema).optional(),
+  })
+);
+
+const TestCatalogReportSchema: z.ZodType<TestCatalogReport> = z.object({
+  files: z.number(),
+  tests: z.number(),
+  subtests: z.number(),
+  functions: z.array(
+    z.object({
+      name: z.string(),
+      kind: z.enum(['test', 'benchmark', 'fuzz', 'example']),
+      file: z.string(),
+      line: z.number(),
+      endLine: z.number(),
+      parent: z.string().optional(),
+      subtests: z.array(GoSubtestSchema),
+    })
+  ),
+});
+
+const ExampleReportSchema: z.ZodType<ExampleReport> = z.object({
+  examples: z.number(),
+  unresolved: z.number(),
+  results: z.array(
+    z.object({
+      name: z.string(),
+      file: z.string(),
+      line: z.number(),
+      endLine: z.number(),
+      documents: z.string(),
+      kind: z.enum(['package', 'function', 'type', 'method']),
+      suffix: z.string().optional(),
+      definition: z.object({ file: z.string(), line: z.number() }).optional(),
+      code: z.string(),
+      output: z.string().optional(),
+      unordered: z.boolean().optional(),
+    })
+  ),
+});
+
+const TestGapReportSchema: z.ZodType<TestGapReport> = z.object({
+  gaps: z.array(
+    z.object({
+      file: z.string(),
+      line: z.number(),
+      symbol: z.string(),
+      kind: z.string(),
+      indirect: z.boolean(),
+    })
+  ),
+  checked: z.number(),
+  packagesWithoutTests: z.array(z.string()),
+});
+
+const DeadCodeReportSchema: z.ZodType<DeadCodeReport> = z.object({
+  dead: z.array(
+    z.object({ file: z.string(), line: z.number(), symbol: z.string(), kind: z.string() })
+  ),
+  checked: z.number(),
+});
+
+const DiagnosticsReportSchema: z.ZodType<DiagnosticsReport> = z.object({
+  target: z.string(),
+  diagnostics: z.array(
+    z.object({
+      severity: z.enum(['error', 'warning', 'info']),
+      analyzer: z.string(),
+      file: z.string(),
+      line: z.number(),
+      column: z.number(),
+      message: z.string(),
+    })
+  ),
+});
+
+const RenamePlanSchema: z.ZodType<RenamePlan> = z.object({
+  symbol: z.string(),
+  newName: z.string(),
+  edits: z.array(TextEditSchema),
+  files: z.array(z.string()),
+  applied: z.boolean(),
+  modified: z.array(z.string()),
+  backups: z.array(z.string()).optional(),
+  warnings: z.array(z.string()),
+});
+
+const FormatResultSchema: z.ZodType<FormatResult> = z.object({
+  file: z.string(),
+  language: z.string(),
+  formatter: z.string(),
+  changed: z.boolean(),
+  applied: z.boolean(),
+  modified: z.array(z.string()),
+  backup: z.string().optional(),
+  formatted: z.string().optional(),
+  error: z.string().optional(),
+});
+
+const GoStructFieldSchema: z.ZodType<GoStructField> = z.object({
+  name: z.string(),
+  type: z.string(),
+  tag: z.string().optional(),
+  tags: z.record(z.string()).optional(),
+  embedded: z.boolean(),
+  exported: z.boolean(),
+  line: z.number(),
+  doc: z.string().optional(),
+});
+
+const TypeDetailsSchema: z.ZodType<TypeDetails> = z.object({
+  type: SymbolEntrySchema,
+  fields: z.array(GoStructFieldSchema),
+  methods: z.array(
+    z.object({ symbol: SymbolEntrySchema, receiver: z.enum(['pointer', 'value']) })
+  ),
+  promotedFields: z.array(z.object({ field: GoStructFieldSchema, via: z.array(z.string()) })),
+  promotedMethods: z.array(
+    z.object({
+      symbol: SymbolEntrySchema,
+      receiver: z.enum(['pointer', 'value']),
+      via: z.array(z.string()),
+      pointerOnly: z.boolean(),
+    })
+  ),
+  constructors: z.array(SymbolEntrySchema),
+});
+
+const ImplementationReportSchema: z.ZodType<ImplementationReport> = z.object({
+  interface: z.string(),
+  file: z.string().optional(),
+  line: z.number().optional(),
+  methods: z.array(z.string()),
+  typeSet: z.boolean(),
+  implementations: z.array(
+    z.object({
+      type: z.string(),
+      file: z.string(),
+      line: z.number(),
+      pointer: z.boolean(),
+      methods: z.array(
+        z.object({
+          name: z.string(),
+          file: z.string(),
+          line: z.number(),
+          signature: z.string(),
+          promotedFrom: z.string().optional(),
+        })
+      ),
+    })
+  ),
+});
+
+const OutlineNodeSchema: z.ZodType<OutlineNode> = z.lazy(() =>
+  z.object({
+    name: z.string(),
+    kind: SymbolKindSchema,
+    line: z.number(),
+    endLine: z.number(),
+    children: z.array(OutlineNodeSchema).optional(),
+  })
+);
+
+const FileOutlineSchema: z.ZodType<FileOutline> = z.object({
+  file: z.string(),
+  language: z.string(),
+  outline: z.array(OutlineNodeSchema),
+});
+
+const DiffedSymbolSchema = z.object({
+  name: z.string(),
+  kind: SymbolKindSchema,
+  file: z.string(),
+  line: z.number(),
+  signature: z.string(),
+  parent: z.string().optional(),
+  exported: z.boolean(),
+});
+
+const SymbolDiffSchema: z.ZodType<SymbolDiff> = z.object({
+  from: z.string(),
+  to: z.string(),
+  filesChanged: z.number(),
+  added: z.array(DiffedSymbolSchema),
+  removed: z.array(DiffedSymbolSchema),
+  modified: z.array(
+    DiffedSymbolSchema.omit({ signature: true }).extend({ before: z.string(), after: z.string() })
+  ),
+  breaking: z.number(),
+});
+
+const ComplexityComparisonSchema: z.ZodType<ComplexityComparison> = z.object({
+  from: z.string(),
+  to: z.string(),
+  filesChanged: z.number(),
+  maxIncrease: z.number(),
+  newThreshold: z.number(),
+  regressions: z.array(
+    ComplexityEntrySchema.omit({ complexity: true }).extend({
+      before: z.number(),
+      after: z.number(),
+      increase: z.number(),
+    })
+  ),
+  added: z.array(ComplexityEntrySchema),
+  improved: z.number(),
+  passed: z.boolean(),
+});
+
+const ChurnRiskReportSchema: z.ZodType<ChurnRiskReport> = z.object({
+  days: z.number(),
+  complexityWeight: z.number(),
+  files: z.number(),
+  results: z.array(
+    z.object({
+      file: z.string(),
+      complexity: z.number(),
+      functions: z.number(),
+      commits: z.number(),
+      score: z.number(),
+    })
+  ),
+});
+
+const ExternalDefinitionSchema: z.ZodType<ExternalDefinition> = z.object({
+  symbol: z.string(),
+  package: z.string(),
+  module: z.string().optional(),
+  version: z.string().optional(),
+  source: z.enum(['goroot', 'module-cache', 'vendor', 'replace', 'project']),
+  dir: z.string(),
+  definitions: z.array(
+    z.object({
+      name: z.string(),
+      kind: SymbolKindSchema,
+      parent: z.string().optional(),
+      file: z.string(),
+      line: z.number(),
+      endLine: z.number(),
+      signature: z.string(),
+      doc: z.string().optional(),
+      declaration: z.string(),
+    })
+  ),
+});
+
+const ApiFingerprintSchema: z.ZodType<ApiFingerprint> = z.object({
+  path: z.string(),
+  fingerprint: z.string(),
+  symbols: z.number(),
+  signatures: z.array(z.string()),
+});
+
+const CloneReportSchema: z.ZodType<CloneReport> = z.object({
+  threshold: z.number(),
+  functions: z.number(),
+  groups: z.array(
+    z.object({
+      identical: z.boolean(),
+      similarity: z.number(),
+      lines: z.number(),
+      members: z.array(
+        z.object({
+          symbol: z.string(),
+          kind: SymbolKindSchema,
+          file: z.string(),
+          line: z.number(),
+          endLine: z.number(),
+        })
+      ),
+    })
+  ),
+});
+
+const SymbolContextSchema: z.ZodType<SymbolContext> = z.object({
+  symbol: SymbolEntrySchema,
+  startLine: z.number(),
+  endLine: z.number(),
+  content: z.string(),
+});
+
+const FunctionReportSchema: z.ZodType<FunctionReport> = z.object({
+  symbol: SymbolEntrySchema,
+  startLine: z.number(),
+  endLine: z.number(),
+  source: z.string(),
+  complexity: z.number(),
+  parameters: z.array(ParameterSchema),
+  calls: z.array(z.object({ callee: z.string(), external: z.boolean() })),
+});
+
+const SymbolDeltaSchema: z.ZodType<SymbolDelta> = z.object({
+  since: z.string().optional(),
+  version: z.string(),
+  full: z.boolean(),
+  added: z.array(SymbolEntrySchema),
+  changed: z.array(SymbolEntrySchema),
+  removed: z.array(SymbolEntrySchema),
+});
+
+const GoRouteSchema: z.ZodType<GoRoute> = RouteIndexEntrySchema.extend({
+  handlerFile: z.string().optional(),
+  handlerLine: z.number().optional(),
+});
+
+const DefinitionResultSchema: z.ZodType<DefinitionResult> = z.object({
+  name: z.string(),
+  file: z.string(),
+  line: z.number(),
+  column: z.number(),
+  receiver: z.string().optional(),
+  receiverType: z.string().optional(),
+  external: z.string().optional(),
+  resolved: z.boolean(),
+  definitions: z.array(SymbolEntrySchema),
+});
+
+const HoverResultSchema: z.ZodType<HoverResult> = z.object({
+  name: z.string(),
+  file: z.string(),
+  line: z.number(),
+  column: z.number(),
+  role: z.enum(['definition', 'usage']),
+  receiverType: z.string().optional(),
+  symbol: SymbolEntrySchema,
+  candidates: z.number(),
+});
+
+const FileRoleSchema: z.ZodType<FileRole> = z.enum(['source', 'test', 'mock']);
+
+const RelatedFilesSchema: z.ZodType<RelatedFiles> = z.object({
+  file: z.string(),
+  role: FileRoleSchema,
+  sources: z.array(z.string()),
+  tests: z.array(z.string()),
+  mocks: z.array(z.string()),
+  siblings: z.array(z.string()),
+});
+
+const DetectionMethodSchema: z.ZodType<DetectionMethod> = z.enum([
+  'extension',
+  'filename',
+  'shebang',
+  'content',
+]);
+
+const LanguageReportSchema: z.ZodType<LanguageReport> = z.object({
+  files: z.array(
+    z.object({ file: z.string(), language: z.string(), method: DetectionMethodSchema })
+  ),
+  languages: z.array(z.object({ language: z.string(), files: z.number() })),
+  unknown: z.number(),
+});
+
+const FileSliceSchema: z.ZodType<FileSlice> = z.object({
+  file: z.string(),
+  startLine: z.number(),
+  endLine: z.number(),
+  totalLines: z.number(),
+  truncated: z.boolean(),
+  content: z.string(),
+  encoding: z.enum(['utf-8', 'utf-16le', 'utf-16be', 'windows-1252']),
+  bom: z.boolean(),
+});
+
+const ProjectRootSchema: z.ZodType<ProjectRoot> = z.object({ name: z.string(), path: z.string() });
+
+const ServerInfoSchema: z.ZodType<ServerInfo> = z.object({
+  name: z.string(),
+  version: z.string(),
+  commit: z.string().optional(),
+  node: z.string(),
+  languages: z.array(
+    z.object({
+      language: z.string(),
+      version: z.number(),
+      aliases: z.array(z.string()),
+      extensions: z.array(z.string()),
+    })
+  ),
+  tools: z.array(z.string()),
+  roots: z.array(
+    z.object({
+      name: z.string(),
+      path: z.string(),
+      index: z.object({
+        files: z.number(),
+        symbols: z.number(),
+        excluded: z.number(),
+        indexedAt: z.string().optional(),
+      }),
+    })
+  ),
+});
+
+// Present while more pages follow (cursor or pageSize requests only)
+const NextCursorSchema = z.string().optional();
+
+const TextSearchResultSchema: z.ZodType<TextSearchResult> = z.object({
+  pattern: z.string(),
+  matches: z.array(
+    z.object({
+      file: z.string(),
+      line: z.number(),
+      column: z.number(),
+      startByte: z.number(),
+      endByte: z.number(),
+      text: z.string(),
+      snippet: z.string().optional(),
+      snippetLine: z.number().optional(),
+      root: z.string().optional(),
+    })
+  ),
+  filesSearched: z.number(),
+  truncated: z.boolean(),
+});
+
+const MarkerSchema: z.ZodType<Marker> = z.object({
+  keyword: z.string(),
+  file: z.string(),
+  line: z.number(),
+  author: z.string().optional(),
+  owner: z.string().optional(),
+  ownerSource: z.enum(['tag', 'blame']).optional(),
+  text: z.string(),
+});
+
+const MarkerReportSchema: z.ZodType<MarkerReport> = z.object({
+  total: z.number(),
+  groups: z.array(
+    z.object({
+      keyword: z.string(),
+      count: z.number(),
+      markers: z.array(MarkerSchema),
+    })
+  ),
+  owners: z
+    .array(
+      z.object({
+        owner: z.string().optional(),
+        count: z.number(),
+        keywords: z.record(z.number()),
+        markers: z.array(MarkerSchema),
+      })
+    )
+    .optional(),
+});
+
+const ProjectTreeSchema: z.ZodType<ProjectTree> = z.object({
+  root: z.string(),
+  directories: z.array(
+    z.object({
+      path: z.string(),
+      depth: z.number(),
+      files: z.number(),
+      languages: z.array(z.object({ language: z.string(), files: z.number() })),
+      exportedSymbols: z.number(),
+      collapsed: z.boolean(),
+    })
+  ),
+  tooDeep: z.array(z.string()),
+});
+
+const LineCountsSchema: z.ZodType<LineCounts> = z.object({
+  lines: z.number(),
+  code: z.number(),
+  comments: z.number(),
+  blank: z.number(),
+});
+
+const LineReportSchema: z.ZodType<LineReport> = z.object({
+  total: LineCountsSchema.and(z.object({ files: z.number() })),
+  languages: z.array(LineCountsSchema.and(z.object({ language: z.string(), files: z.number() }))),
+  files: z.array(LineCountsSchema.and(z.object({ file: z.string(), language: z.string() }))),
+});
+
+const CacheStatsSchema: z.ZodType<CacheStats> = z.object({
+  hits: z.number(),
+  misses: z.number(),
+  evictions: z.number(),
+  size: z.number(),
+  maxEntries: z.number(),
+});
+
+const ConcurrencyStatsSchema: z.ZodType<ConcurrencyStats> = z.object({
+  active: z.number(),
+  queued: z.number(),
+  maxConcurrent: z.number(),
+  queueTimeoutMs: z.number(),
+  completed: z.number(),
+  timedOut: z.number(),
+});
+
+const ToolDescriptionSchema = z.object({
+  name: z.string(),
+  description: z.string(),
+  inputSchema: z.record(z.unknown()),
+  outputSchema: z.record(z.unknown()),
+  stream: z
+    .object({ item: z.record(z.unknown()), summary: z.record(z.unknown()) })
+    .optional(),
+});
+
+interface ToolOutput {
+  json: z.ZodTypeAny; // Response text with format=json, or always for JSON-only tools
+  jsonOnly?: boolean | undefined;
+  stream?: { item: z.ZodTypeAny; summary: z.ZodTypeAny } | undefined; // With stream=true
+}
+
+const ErrorInfoSchema: z.ZodType<ErrorInfo> = z.object({
+  code: z.enum(ERROR_CODES),
+  message: z.string(),
+  path: z.string().optional(),
+});
+
+const BatchResultSchema: z.ZodType<BatchResult> = z.object({
+  total: z.number(),
+  succeeded: z.number(),
+  failed: z.number(),
+  results: z.array(
+    z.object({
+      tool: z.string(),
+      status: z.enum(['success', 'error']),
+      text: z.string(),
+      error: ErrorInfoSchema.optional(),
+      timedOut: z.boolean().optional(),
+      truncated: z.boolean().optional(),
+      durationMs: z.number(),
+    })
+  ),
+});
+
+const TOOL_OUTPUTS: Record<string, ToolOutput> = {
+  eng_extract_symbols: {
+    json: z.object({
+      total: z.number(),
+      scope: ScopeSchema.optional(),
+      symbols: z.array(SymbolEntrySchema).or(z.array(SymbolSignatureSchema)), // signaturesOnly
+      excluded: z.array(ExcludedFileSchema),
+      generatedSkipped: z.number(), // Generated files left out without includeGenerated
+    }),
+    stream: {
+      item: SymbolEntrySchema.or(SymbolSignatureSchema),
+      summary: StreamSummarySchema.and(
+        z.object({
+          scope: ScopeSchema.optional(),
+          excluded: z.array(ExcludedFileSchema),
+          generatedSkipped: z.number(),
+        })
+      ),
+    },
+  },
+  eng_summarize_file: { json: z.array(FileOutlineSchema) },
+  eng_search_symbols: {
+    // An object only when cursor or pageSize was passed
+    json: z
+      .array(SymbolMatchSchema)
+      .or(
+        z.object({
+          matches: z.array(SymbolMatchSchema),
+          nextCursor: NextCursorSchema,
+          generatedSkipped: z.number(),
+        })
+      ),
+    stream: { item: SymbolMatchSchema, summary: StreamSummarySchema },
+  },
+  eng_diff_symbols: { json: SymbolDiffSchema },
+  eng_compare_complexity: { json: ComplexityComparisonSchema },
+  eng_churn_risk: { json: ChurnRiskReportSchema },
+  eng_api_fingerprint: { json: ApiFingerprintSchema },
+  eng_find_references: {
+    json: z.object({
+      symbol: z.string(),
+      total: z.number(),
+      references: z.array(ReferenceEntrySchema),
+      nextCursor: NextCursorSchema,
+    }),
+    stream: {
+      item: ReferenceEntrySchema,
+      summary: StreamSummarySchema.and(z.object({ symbol: z.string() })),
+    },
+  },
+  eng_search_text: { json: TextSearchResultSchema.and(z.object({ nextCursor: NextCursorSchema })) },
+  eng_list_markers: { json: MarkerReportSchema.and(z.object({ nextCursor: NextCursorSchema })) },
+  eng_project_tree: { json: ProjectTreeSchema },
+  eng_count_lines: { json: LineReportSchema },
+  eng_symbol_delta: { json: SymbolDeltaSchema },
+  eng_cache_stats: { json: CacheStatsSchema },
+  eng_queue_stats: { json: ConcurrencyStatsSchema },
+  eng_list_roots: { json: z.array(ProjectRootSchema) },
+  eng_complexity: { json: z.array(ComplexityEntrySchema) },
+  eng_find_clones: { json: CloneReportSchema },
+  eng_check_docs: { json: z.array(DocViolationSchema) },
+  eng_check_naming: { json: z.array(NamingViolationSchema) },
+  eng_list_deprecated: { json: z.array(DeprecatedSymbolSchema) },
+  eng_test_gaps: { json: TestGapReportSchema },
+  eng_list_tests: { json: TestCatalogReportSchema },
+  eng_go_examples: { json: ExampleReportSchema },
+  eng_dead_code: { json: DeadCodeReportSchema },
+  eng_diagnostics: { json: DiagnosticsReportSchema },
+  eng_rename_symbol: { json: RenamePlanSchema },
+  eng_format_code: { json: FormatResultSchema },
+  eng_type_info: { json: z.array(TypeDetailsSchema) },
+  eng_implementations: { json: z.array(ImplementationReportSchema) },
+  eng_symbol_context: { json: SymbolContextSchema },
+  eng_analyze_function: { json: FunctionReportSchema },
+  eng_resolve_symbol: { json: DefinitionResultSchema },
+  eng_hover: { json: HoverResultSchema.nullable() },
+  eng_external_definition: { json: ExternalDefinitionSchema },
+  eng_remote_symbols: {
+    json: z.object({
+      url: z.string(),
+      ref: z.string().optional(),
+      commit: z.string(),
+      cached: z.boolean(),
+      sizeBytes: z.number(),
+      total: z.number(),
+      symbols: z.array(SymbolEntrySchema).or(z.array(SymbolSignatureSchema)), // signaturesOnly
+      excluded: z.array(ExcludedFileSchema),
+    }),
+  },
+  eng_call_graph: { json: CallGraphSchema },
+  eng_list_routes: { json: z.array(GoRouteSchema) },
+  eng_imports: { json: ImportReportSchema },
+  eng_unused_imports: { json: ImportHygieneReportSchema },
+  eng_validate_references: { json: ReferenceValidationSchema },
+  eng_package_graph: { json: PackageGraphSchema },
+  eng_related_files: { json: RelatedFilesSchema },
+  eng_detect_language: { json: LanguageReportSchema },
+  eng_read_file: { json: FileSliceSchema },
+  eng_batch: { json: BatchResultSchema, jsonOnly: true },
+  eng_describe_tool: { json: ToolDescriptionSchema, jsonOnly: true },
+  eng_server_info: { json: ServerInfoSchema },
+};
+
+const TEXT_OUTPUT = { type: 'string', description: 'Plain text report' };
+
+export type ToolDescription = z.infer<typeof ToolDescriptionSchema>;
+
+/**
+ * A tool's input schema plus JSON Schemas for its output, or undefined for
+ * an unknown tool
+ */
+export function describeTool(name: string): ToolDescription | undefined {
+  const tool = registerCommands().find(t => t.name === name);
+  if (!tool) {
+    return undefined;
+  }
+
+  const output = TOOL_OUTPUTS[name];
+  const description: ToolDescription = {
+    name,
+    description: tool.description ?? '',
+    inputSchema: tool.inputSchema,
+    outputSchema: output
+      ? {
+          ...toJsonSchema(output.json),
+          description: output.jsonOnly
+            ? 'Response text, as JSON'
+            : 'Response text with format=json; plain text otherwise',
+        }
+      : TEXT_OUTPUT,
+  };
+
+  if (output?.stream) {
+    // Items arrive in progress notifications as {"batch": n, "items": [...]}
+    description.stream = {
+      item: toJsonSchema(output.stream.item),
+      summary: toJsonSchema(output.stream.summary),
+    };
+  }
+
+  return description;
+}
+
+function toJsonSchema(schema: z.ZodTypeAny): Record<string, unknown> {
+  // Inline everything so each schema stands alone
+  return zodToJsonSchema(schema, { $refStrategy: 'none' }) as Record<string, unknown>;
+}
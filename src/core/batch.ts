@@ -0,0 +1,212 @@
+/**
+ * Batch
+ * Runs several tool calls from one request: independent calls in parallel,
+ * calls that change state one at a time and in order, each result kept in
+ * the position of its call
+ */
+
+import type { CallToolResult } from '@modelcontextprotocol/sdk/types.js';
+import { ConcurrencyLimiter } from './concurrency-limiter.js';
+import { ToolError, describeError } from './errors.js';
+import type { ErrorInfo } from './errors.js';
+
+export const MAX_BATCH_CALLS = 50;
+
+// Calls of one batch running at once; expensive ones still wait for the
+// server's analysis slots
+const BATCH_PARALLELISM = 4;
+
+export interface BatchCall {
+  tool: string;
+  arguments?: Record<string, unknown> | undefined;
+}
+
+export interface BatchCallResult {
+  tool: string;
+  status: 'success' | 'error';
+  text: string; // The call's text content, as it would have been returned alone
+  error?: ErrorInfo | undefined;
+  timedOut?: boolean | undefined;
+  truncated?: boolean | undefined;
+  durationMs: number;
+}
+
+export interface BatchResult {
+  total: number;
+  succeeded: number;
+  failed: number;
+  results: BatchCallResult[]; // In call order
+}
+
+/**
+ * The calls of a batch request; throws INVALID_ARGUMENT on anything but a
+ * non-empty list of {tool, arguments?} up to MAX_BATCH_CALLS long
+ */
+export function parseBatchCalls(value: unknown): BatchCall[] {
+  if (!Array.isArray(value) || value.length === 0) {
+    throw new ToolError('INVALID_ARGUMENT', 'calls must be a non-empty list of {tool, arguments}');
+  }
+  if (value.length > MAX_BATCH_CALLS) {
+    throw new ToolError(
+      'INVALID_ARGUMENT',
+      `Too many calls: ${value.length} (at most ${MAX_BATCH_CALLS} per batch)`
+    );
+  }
+
+  return value.map((entry: unknown, i) => {
+    const call = entry as { tool?: unknown; arguments?: unknown } | null;
+    if (typeof call?.tool !== 'string' || call.tool === '') {
+      throw new ToolError('INVALID_ARGUMENT', `calls[${i}] needs a tool name`);
+    }
+    const args = call.arguments;
+    if (args !== undefined && (typeof args !== 'object' || args === null || Array.isArray(args))) {
+      throw new ToolError('INVALID_ARGUMENT', `calls[${i}].arguments must be an object`);
+    }
+    return { tool: call.tool, arguments: args as Record<string, unknown> | undefined };
+  });
+}
+
+/**
+ * Run every call, never letting one failure stop the rest. A call for which
+ * sequential is true waits for the calls before it and holds back the calls
+ * after it; the others overlap.
+ */
+export async function runBatch(
+  calls: BatchCall[],
+  run: (call: BatchCall) => Promise<CallToolResult>,
+  sequential: (tool: string) => boolean
+): Promise<BatchResult> {
+  const limiter = new ConcurrencyLimiter(BATCH_PARALLELISM, 0);
+  const results: BatchCallResult[] = new Array<BatchCallResult>(calls.length);
+  const execute = async (call: BatchCall, index: number): Promise<void> => {
+    results[index] = await runOne(call, run);
+  };
+
+  let running: Array<Promise<void>> = [];
+  for (const [index, call] of calls.entries()) {
+    if (sequential(call.tool)) {
+      await Promise.all(running);
+      running = [];
+      await execute(call, index);
+    } else {
+      running.push(limiter.run(() => execute(call, index)));
+    }
+  }
+  await Promise.all(running);
+
+  const succeeded = results.filter(r => r.status === 'success').length;
+  return { total: calls.length, succeeded, failed: calls.length - succeeded, results };
+}
+
+async function runOne(
+  call: BatchCall,
+  run: (call: BatchCall) => Promise<CallToolResult>
+): Promise<BatchCallResult> {
+  const started = performance.now();
+  const durationMs = (): number => Math.round(performance.now() - started);
+
+  try {
+    const result = await run(call);
+    const meta = result._meta as
+      | { error?: ErrorInfo; timedOut?: boolean; truncation?: unknown }
+      | undefined;
+    const text = result.content.map(item => (item.type === 'text' ? item.text : '')).join('\n');
+    const error: ErrorInfo = meta?.error ?? { code: 'INTERNAL_ERROR', message: text };
+    return {
+      tool: call.tool,
+      status: result.isError ? 'error' : 'success',
+      text,
+      ...(result.isError ? { error } : {}),
+      ...(meta?.timedOut ? { timedOut: true } : {}),
+      ...(meta?.truncation ? { truncated: true } : {}),
+      durationMs: durationMs(),
+    };
+  } catch (error) {
+    const info = describeError(error);
+    return {
+      tool: call.tool,
+      status: 'error',
+      text: info.message,
+      error: info,
+      durationMs: durationMs(),
+    };
+  }
+}
+
+/**
+ * The batch with its longest texts cut short until its JSON fits in maxBytes
+ * (0 = no limit), and the number of lines left out. Every call keeps its
+ * entry, in order; entries cut are flagged truncated. Cutting the batch's
+ * JSON as a whole would drop the last calls' entries instead.
+ */
+export function fitBatch(
+  batch: BatchResult,
+  maxBytes: number
+): { batch: BatchResult; omitted: number } {
+  let results = batch.results;
+  let excess = maxBytes === 0 ? 0 : serializedSize(batch) - maxBytes;
+  // Each pass also pays for the truncated flags the one before added
+  while (excess > 0 && results.some(r => r.text !== '')) {
+    const sizes = results.map(r => escapedSize(r.text));
+    const cap = textCap(sizes, sizes.reduce((sum, size) => sum + size, 0) - excess);
+    results = results.map((result, i) => {
+      if ((sizes[i] ?? 0) <= cap) return result;
+      const { durationMs, ...rest } = result;
+      return { ...rest, text: cutText(result.text, cap), truncated: true, durationMs };
+    });
+    excess = serializedSize({ ...batch, results }) - maxBytes;
+  }
+
+  const omitted = results.reduce(
+    (sum, result, i) => sum + lineCount(batch.results[i]?.text ?? '') - lineCount(result.text),
+    0
+  );
+  return { batch: { ...batch, results }, omitted };
+}
+
+/**
+ * The largest size every text can be cut to so that together they take at
+ * most budget; texts already smaller keep their size
+ */
+function textCap(sizes: number[], budget: number): number {
+  const sorted = [...sizes].sort((a, b) => a - b);
+  let remaining = Math.max(0, budget);
+  for (const [i, size] of sorted.entries()) {
+    const share = Math.floor(remaining / (sorted.length - i));
+    if (size > share) return share;
+    remaining -= size;
+  }
+  return Infinity;
+}
+
+/**
+ * The longest start of text taking at most cap bytes as a JSON string, cut
+ * back to its last line break when it has one
+ */
+function cutText(text: string, cap: number): string {
+  let low = 0;
+  let high = text.length;
+  while (low < high) {
+    const mid = Math.ceil((low + high) / 2);
+    if (escapedSize(text.slice(0, mid)) <= cap) low = mid;
+    else high = mid - 1;
+  }
+  // Never end on half of a surrogate pair
+  if (/[\uD800-\uDBFF]/.test(text[low - 1] ?? '')) low--;
+  const head = text.slice(0, low);
+  const lineEnd = head.lastIndexOf('\n');
+  return lineEnd === -1 ? head : head.slice(0, lineEnd);
+}
+
+function serializedSize(batch: BatchResult): number {
+  return Buffer.byteLength(JSON.stringify(batch, null, 2), 'utf-8');
+}
+
+// Bytes a string takes inside JSON, quotes aside
+function escapedSize(text: string): number {
+  return Buffer.byteLength(JSON.stringify(text), 'utf-8') - 2;
+}
+
+function lineCount(text: string): number {
+  return text === '' ? 0 : text.split('\n').length;
+}
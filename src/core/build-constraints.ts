@@ -0,0 +1,257 @@
+/**
+ * Build Constraints
+ * Evaluates Go //go:build lines and _GOOS/_GOARCH file name suffixes against
+ * a target platform, as go/build does
+ */
+
+import { ToolError } from './errors.js';
+
+export interface BuildContext {
+  goos: string;
+  goarch: string;
+  tags: string[]; // Extra tags satisfied, e.g. integration or cgo
+}
+
+export interface BuildOptions {
+  goos?: string | undefined;
+  goarch?: string | undefined;
+  buildTags?: string[] | undefined;
+}
+
+const KNOWN_OS = new Set([
+  'aix',
+  'android',
+  'darwin',
+  'dragonfly',
+  'freebsd',
+  'hurd',
+  'illumos',
+  'ios',
+  'js',
+  'linux',
+  'nacl',
+  'netbsd',
+  'openbsd',
+  'plan9',
+  'solaris',
+  'wasip1',
+  'windows',
+  'zos',
+]);
+
+const KNOWN_ARCH = new Set([
+  '386',
+  'amd64',
+  'amd64p32',
+  'arm',
+  'armbe',
+  'arm64',
+  'arm64be',
+  'loong64',
+  'mips',
+  'mipsle',
+  'mips64',
+  'mips64le',
+  'mips64p32',
+  'mips64p32le',
+  'ppc',
+  'ppc64',
+  'ppc64le',
+  'riscv',
+  'riscv64',
+  's390',
+  's390x',
+  'sparc',
+  'sparc64',
+  'wasm',
+]);
+
+const UNIX_OS = new Set([
+  'aix',
+  'android',
+  'darwin',
+  'dragonfly',
+  'freebsd',
+  'hurd',
+  'illumos',
+  'ios',
+  'linux',
+  'netbsd',
+  'openbsd',
+  'solaris',
+]);
+
+// Targets that also satisfy another OS's tag and file suffix
+const IMPLIED_OS: Record<string, string> = { android: 'linux', illumos: 'solaris', ios: 'darwin' };
+
+const NODE_PLATFORMS: Record<string, string> = { win32: 'windows', sunos: 'solaris' };
+const NODE_ARCHS: Record<string, string> = { x64: 'amd64', ia32: '386', mipsel: 'mipsle' };
+
+/**
+ * The target platform for goos/goarch/buildTags arguments, or undefined when
+ * none is given and every file is analyzed. An unset GOOS or GOARCH defaults
+ * to the host's, as with the go command.
+ */
+export function buildContext(options: BuildOptions | undefined): BuildContext | undefined {
+  if (!options?.goos && !options?.goarch && !options?.buildTags?.length) {
+    return undefined;
+  }
+
+  const goos = options.goos ?? NODE_PLATFORMS[process.platform] ?? process.platform;
+  const goarch = options.goarch ?? NODE_ARCHS[process.arch] ?? process.arch;
+  if (!KNOWN_OS.has(goos)) {
+    throw new ToolError('INVALID_ARGUMENT', `Unknown GOOS "${goos}"`);
+  }
+  if (!KNOWN_ARCH.has(goarch)) {
+    throw new ToolError('INVALID_ARGUMENT', `Unknown GOARCH "${goarch}"`);
+  }
+  return { goos, goarch, tags: options.buildTags ?? [] };
+}
+
+export function formatBuildContext(context: BuildContext): string {
+  const tags = context.tags.length > 0 ? ` (tags: ${context.tags.join(', ')})` : '';
+  return `${context.goos}/${context.goarch}${tags}`;
+}
+
+/**
+ * The build expression in a Go file's header: the //go:build line, or legacy
+ * // +build lines rewritten in //go:build syntax. Only comments before the
+ * last blank line ahead of the package clause count.
+ */
+export function parseBuildConstraint(content: string): string | undefined {
+  const header: string[] = [];
+  let pending: string[] = [];
+  let inBlock = false;
+
+  for (const raw of content.split('\n')) {
+    const line = raw.trim();
+    if (inBlock) {
+      if (line.includes('*/')) inBlock = false;
+    } else if (line === '') {
+      header.push(...pending);
+      pending = [];
+    } else if (line.startsWith('//')) {
+      pending.push(line);
+    } else if (line.startsWith('/*')) {
+      inBlock = !line.includes('*/');
+    } else {
+      break;
+    }
+  }
+
+  const goBuild = header.find(line => /^\/\/go:build\s/.test(line));
+  if (goBuild) {
+    return goBuild.slice('//go:build'.length).trim();
+  }
+
+  // Spaces separate alternatives, commas join requirements, lines are ANDed
+  const plusBuild = header
+    .filter(line => /^\/\/\s*\+build(\s|$)/.test(line))
+    .map(line => line.replace(/^\/\/\s*\+build/, '').trim())
+    .filter(Boolean)
+    .map(options =>
+      options
+        .split(/\s+/)
+        .map(option => option.split(',').join(' && '))
+        .join(' || ')
+    );
+  if (plusBuild.length === 0) return undefined;
+  return plusBuild.length === 1
+    ? plusBuild[0]
+    : plusBuild.map(e => (e.includes('||') ? `(${e})` : e)).join(' && ');
+}
+
+/**
+ * Why the target platform leaves a Go file out of the build, or undefined
+ * if the file is built. A constraint that doesn't parse is not applied.
+ */
+export function buildExclusionReason(
+  file: string,
+  constraint: string | undefined,
+  context: BuildContext
+): string | undefined {
+  const target = formatBuildContext(context);
+  if (!matchesFileName(file, context)) {
+    return `build constraints exclude it for ${target} (file name suffix)`;
+  }
+  if (constraint !== undefined && evaluate(constraint, tag => matchesTag(tag, context)) === false) {
+    return `build constraints exclude it for ${target} (//go:build ${constraint})`;
+  }
+  return undefined;
+}
+
+/**
+ * *_GOOS, *_GOARCH, and *_GOOS_GOARCH names (before any _test suffix) build
+ * only for that target
+ */
+function matchesFileName(file: string, context: BuildContext): boolean {
+  const base = file.split('/').pop() ?? file;
+  if (!base.endsWith('.go')) return true;
+
+  const name = base.slice(0, -'.go'.length).replace(/_test$/, '');
+  const underscore = name.indexOf('_');
+  if (underscore === -1) return true;
+
+  const parts = name.slice(underscore).split('_');
+  const last = parts[parts.length - 1] ?? '';
+  const beforeLast = parts[parts.length - 2] ?? '';
+  if (parts.length >= 3 && KNOWN_OS.has(beforeLast) && KNOWN_ARCH.has(last)) {
+    return matchesTag(beforeLast, context) && last === context.goarch;
+  }
+  if (KNOWN_OS.has(last)) return matchesTag(last, context);
+  if (KNOWN_ARCH.has(last)) return last === context.goarch;
+  return true;
+}
+
+function matchesTag(tag: string, context: BuildContext): boolean {
+  if (tag === context.goos || tag === context.goarch || context.tags.includes(tag)) {
+    return true;
+  }
+  if (IMPLIED_OS[context.goos] === tag) return true;
+  if (tag === 'unix') return UNIX_OS.has(context.goos);
+  // Assume the gc toolchain at a current release; cgo must be asked for
+  return tag === 'gc' || /^go1\.\d+$/.test(tag);
+}
+
+/**
+ * Evaluate a //go:build expression (!, &&, ||, parentheses), or undefined if
+ * it is malformed
+ */
+function evaluate(expression: string, matches: (tag: string) => boolean): boolean | undefined {
+  const tokens = expression.match(/&&|\|\||[!()]|[\w.]+|\S/g) ?? [];
+  let position = 0;
+
+  const parseOr = (): boolean | undefined => {
+    let value = parseAnd();
+    while (tokens[position] === '||') {
+      position++;
+      const right = parseAnd();
+      value = value === undefined || right === undefined ? undefined : value || right;
+    }
+    return value;
+  };
+  const parseAnd = (): boolean | undefined => {
+    let value = parseNot();
+    while (tokens[position] === '&&') {
+      position++;
+      const right = parseNot();
+      value = value === undefined || right === undefined ? undefined : value && right;
+    }
+    return value;
+  };
+  const parseNot = (): boolean | undefined => {
+    const token = tokens[position++];
+    if (token === '!') {
+      const value = parseNot();
+      return value === undefined ? undefined : !value;
+    }
+    if (token === '(') {
+      const value = parseOr();
+      return tokens[position++] === ')' ? value : undefined;
+    }
+    return token !== undefined && /^[\w.]+$/.test(token) ? matches(token) : undefined;
+  };
+
+  const value = parseOr();
+  return position === tokens.length ? value : undefined;
+}
@@ -0,0 +1,72 @@
+/**
+ * Byte Offsets
+ * Maps string positions and lines to UTF-8 byte offsets for clients that edit by byte
+ */
+
+export class ByteOffsets {
+  private content: string;
+  private lineStarts: number[] = [0]; // String index of each line's first character
+  private lineByteStarts: number[] = [0];
+
+  constructor(content: string) {
+    this.content = content;
+
+    let bytes = 0;
+    for (let i = 0; i < content.length; i++) {
+      const code = content.codePointAt(i) ?? 0;
+      bytes += code < 0x80 ? 1 : code < 0x800 ? 2 : code < 0x10000 ? 3 : 4;
+      if (code > 0xffff) {
+        i++; // Skip the second half of the surrogate pair
+      } else if (code === 0x0a) {
+        this.lineStarts.push(i + 1);
+        this.lineByteStarts.push(bytes);
+      }
+    }
+  }
+
+  /**
+   * Byte offset of a string index
+   */
+  at(index: number): number {
+    const line = this.lineAt(index);
+    const start = this.lineStarts[line] ?? 0;
+    return (this.lineByteStarts[line] ?? 0) + Buffer.byteLength(this.content.slice(start, index));
+  }
+
+  /**
+   * Byte offset of the first non-blank character of a 1-based line
+   */
+  lineStart(line: number): number {
+    const start = this.lineStarts[line - 1] ?? this.content.length;
+    const text = this.lineText(line);
+    return this.at(start + text.length - text.trimStart().length);
+  }
+
+  /**
+   * Byte offset just past the last non-blank character of a 1-based line
+   */
+  lineEnd(line: number): number {
+    const start = this.lineStarts[line - 1] ?? this.content.length;
+    return this.at(start + this.lineText(line).trimEnd().length);
+  }
+
+  private lineText(line: number): string {
+    const start = this.lineStarts[line - 1] ?? this.content.length;
+    const next = this.lineStarts[line];
+    return this.content.slice(start, next === undefined ? undefined : next - 1);
+  }
+
+  private lineAt(index: number): number {
+    let low = 0;
+    let high = this.lineStarts.length - 1;
+    while (low < high) {
+      const mid = (low + high + 1) >> 1;
+      if ((this.lineStarts[mid] ?? 0) <= index) {
+        low = mid;
+      } else {
+        high = mid - 1;
+      }
+    }
+    return low;
+  }
+}
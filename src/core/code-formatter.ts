@@ -0,0 +1,191 @@
+/**
+ * Code Formatter
+ * Runs each language's canonical formatter over a file or buffer via stdin
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { getParser, getParserForFile } from '../parsers/index.js';
+import { ToolError } from './errors.js';
+import { BUFFER_FILE, resolveProjectPath } from './file-reader.js';
+import { runProcess } from './process.js';
+import type { ProcessResult } from './process.js';
+import { isSafeToModify, writeFilesAtomically } from './safety.js';
+
+export interface FormatRequest {
+  path?: string | undefined; // File to format; names the buffer when content is given
+  content?: string | undefined; // Unsaved buffer to format instead of the file
+  language?: string | undefined; // Needed for content without a recognizable path
+  apply?: boolean | undefined; // Write the formatted file back (paths only)
+  backup?: boolean | undefined; // Keep the original as <file>.bak when applying
+}
+
+export interface FormatResult {
+  file: string;
+  language: string;
+  formatter: string;
+  changed: boolean;
+  applied: boolean;
+  modified: string[]; // The file, once written; empty otherwise
+  backup?: string | undefined; // Where the original was kept
+  formatted?: string | undefined; // Omitted when the formatter rejected the input
+  error?: string | undefined; // Parse error reported by the formatter
+}
+
+interface FormatterCommand {
+  command: string;
+  args: (stdinName: string) => string[];
+}
+
+// Tried in order; the first one installed wins
+const FORMATTERS: Record<string, FormatterCommand[]> = {
+  go: [
+    { command: 'goimports', args: () => [] },
+    { command: 'gofmt', args: () => [] },
+  ],
+  typescript: [
+    { command: 'node_modules/.bin/prettier', args: name => ['--stdin-filepath', name] },
+    { command: 'prettier', args: name => ['--stdin-filepath', name] },
+  ],
+  python: [
+    { command: 'ruff', args: name => ['format', '--stdin-filename', name, '-'] },
+    { command: 'black', args: name => ['--quiet', '--stdin-filename', name, '-'] },
+  ],
+  rust: [{ command: 'rustfmt', args: () => ['--edition', '2021', '--emit', 'stdout'] }],
+};
+
+// Stand-in file names for buffers, so prettier and friends pick the right parser
+const BUFFER_NAMES: Record<string, string> = {
+  go: 'buffer.go',
+  typescript: 'buffer.ts',
+  javascript: 'buffer.js',
+  python: 'buffer.py',
+  rust: 'buffer.rs',
+};
+
+export class CodeFormatter {
+  private workingDir: string;
+
+  constructor(workingDir?: string) {
+    this.workingDir = workingDir ?? process.cwd();
+  }
+
+  /**
+   * Format source through the language's formatter. Nothing is written unless
+   * apply is set and the file actually changed.
+   */
+  async format(request: FormatRequest): Promise<FormatResult> {
+    const buffer = request.content !== undefined;
+    if (!buffer && !request.path) {
+      throw new ToolError('INVALID_ARGUMENT', 'Either path or content is required');
+    }
+    if (buffer && request.apply) {
+      throw new ToolError(
+        'INVALID_ARGUMENT',
+        'apply needs a file path; buffers are returned, never written'
+      );
+    }
+
+    const file = request.path ? resolveProjectPath(this.workingDir, request.path) : '';
+    const original =
+      request.content ?? (await fs.readFile(path.join(this.workingDir, file), 'utf-8'));
+
+    const parser = request.language
+      ? getParser(request.language)
+      : getParserForFile(file || 'buffer', original);
+    if (!parser) {
+      throw new ToolError(
+        'UNSUPPORTED_LANGUAGE',
+        `No formatter for ${request.language ?? (path.extname(file) || 'content')}`,
+        file || undefined
+      );
+    }
+
+    const stdinName = file || (BUFFER_NAMES[request.language ?? parser.language] ?? 'buffer');
+    const { formatter, output } = await this.run(parser.language, stdinName, original);
+    const result: FormatResult = {
+      file: file || BUFFER_FILE,
+      language: parser.language,
+      formatter,
+      changed: false,
+      applied: false,
+      modified: [],
+    };
+
+    if (output.code !== 0) {
+      result.error = (output.stderr || output.stdout)
+        .replaceAll(stdinName, result.file)
+        .replace(/<standard input>|<stdin>/g, result.file)
+        .trim();
+      return result;
+    }
+
+    result.formatted = output.stdout;
+    result.changed = output.stdout !== original;
+
+    if (request.apply && result.changed) {
+      const safety = await isSafeToModify(file, this.workingDir);
+      if (!safety.safe) {
+        throw new ToolError(
+          'FAILED_PRECONDITION',
+          `Refusing to write ${file}: ${safety.reason ?? 'unsafe path'}`,
+          file
+        );
+      }
+      const written = await writeFilesAtomically(
+        this.workingDir,
+        [{ file, content: output.stdout }],
+        { backup: request.backup }
+      );
+      result.applied = true;
+      result.modified = written.modified;
+      if (written.backups[0]) result.backup = written.backups[0];
+    }
+
+    return result;
+  }
+
+  private async run(
+    language: string,
+    stdinName: string,
+    input: string
+  ): Promise<{ formatter: string; output: ProcessResult }> {
+    const candidates = FORMATTERS[language] ?? [];
+    for (const candidate of candidates) {
+      const command = candidate.command.includes('/')
+        ? path.join(this.workingDir, candidate.command)
+        : candidate.command;
+      const output = await runProcess(command, candidate.args(stdinName), {
+        cwd: this.workingDir,
+        input,
+      });
+      if (output) {
+        return { formatter: path.basename(candidate.command), output };
+      }
+    }
+
+    const tried = candidates.map(c => path.basename(c.command)).join(', ');
+    throw new ToolError(
+      'FAILED_PRECONDITION',
+      `No ${language} formatter installed (tried ${tried || 'none'})`
+    );
+  }
+
+  formatResult(result: FormatResult): string {
+    if (result.error) {
+      return `${result.formatter} could not format ${result.file}:\n\n${result.error}`;
+    }
+    if (!result.changed) {
+      return `${result.file} is already formatted (${result.formatter}).`;
+    }
+
+    const status = result.applied ? 'formatted and written' : 'formatted (pass apply to write)';
+    const backup = result.backup ? ` (original kept as ${result.backup})` : '';
+    const header = `${result.file} ${status} with ${result.formatter}${backup}`;
+    return `${header}:\n\n${result.formatted ?? ''}`;
+  }
+
+  setWorkingDir(dir: string): void {
+    this.workingDir = dir;
+  }
+}
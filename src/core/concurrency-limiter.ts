@@ -0,0 +1,124 @@
+/**
+ * Concurrency Limiter
+ * Caps how many tasks run at once; extra tasks wait in a FIFO queue
+ */
+
+export const DEFAULT_MAX_CONCURRENT = 2;
+export const DEFAULT_QUEUE_TIMEOUT_MS = 30_000;
+
+export interface ConcurrencyStats {
+  active: number;
+  queued: number; // Waiting for a slot
+  maxConcurrent: number; // 0 = unlimited
+  queueTimeoutMs: number; // 0 = wait indefinitely
+  completed: number;
+  timedOut: number; // Gave up waiting with ServerBusyError
+}
+
+/**
+ * A queued task waited longer than the queue timeout for a slot
+ */
+export class ServerBusyError extends Error {
+  constructor(message: string) {
+    super(message);
+    this.name = 'ServerBusyError';
+  }
+}
+
+interface Waiter {
+  start: () => void;
+  timer?: ReturnType<typeof setTimeout> | undefined;
+}
+
+export class ConcurrencyLimiter {
+  private maxConcurrent: number;
+  private queueTimeoutMs: number;
+  private active = 0;
+  private queue: Waiter[] = [];
+  private completed = 0;
+  private timedOut = 0;
+
+  constructor(maxConcurrent = DEFAULT_MAX_CONCURRENT, queueTimeoutMs = DEFAULT_QUEUE_TIMEOUT_MS) {
+    this.maxConcurrent = Math.max(0, Math.floor(maxConcurrent));
+    this.queueTimeoutMs = Math.max(0, Math.floor(queueTimeoutMs));
+  }
+
+  /**
+   * Run a task once a slot is free. Rejects with ServerBusyError, without
+   * running the task, if no slot frees up within the queue timeout.
+   *
+   * A task that answers before its work is done, such as one that gave up
+   * waiting on it, passes that work to hold: the slot stays taken until the
+   * work settles, so abandoned work still counts against the limit.
+   */
+  async run<T>(task: (hold: (work: Promise<unknown>) => void) => Promise<T>): Promise<T> {
+    await this.acquire();
+    const held: Promise<unknown>[] = [];
+    try {
+      return await task(work => held.push(work));
+    } finally {
+      if (held.length === 0) this.release();
+      else void Promise.allSettled(held).then(() => this.release());
+    }
+  }
+
+  private acquire(): Promise<void> {
+    if (this.maxConcurrent === 0 || this.active < this.maxConcurrent) {
+      this.active++;
+      return Promise.resolve();
+    }
+
+    return new Promise((resolve, reject) => {
+      const waiter: Waiter = {
+        start: (): void => {
+          clearTimeout(waiter.timer);
+          this.active++;
+          resolve();
+        },
+      };
+
+      if (this.queueTimeoutMs > 0) {
+        waiter.timer = setTimeout(() => {
+          this.queue = this.queue.filter(w => w !== waiter);
+          this.timedOut++;
+          reject(
+            new ServerBusyError(
+              `Server busy: ${this.active} analysis task(s) running and ${this.queue.length} ` +
+                `more queued; no slot freed up within ${this.queueTimeoutMs}ms. Retry later.`
+            )
+          );
+        }, this.queueTimeoutMs);
+      }
+      this.queue.push(waiter);
+    });
+  }
+
+  private release(): void {
+    this.active--;
+    this.completed++;
+    this.queue.shift()?.start();
+  }
+
+  stats(): ConcurrencyStats {
+    return {
+      active: this.active,
+      queued: this.queue.length,
+      maxConcurrent: this.maxConcurrent,
+      queueTimeoutMs: this.queueTimeoutMs,
+      completed: this.completed,
+      timedOut: this.timedOut,
+    };
+  }
+
+  formatStats(stats: ConcurrencyStats): string {
+    const limit = stats.maxConcurrent > 0 ? `${stats.maxConcurrent}` : 'unlimited';
+    const timeout = stats.queueTimeoutMs > 0 ? `${stats.queueTimeoutMs}ms` : 'none';
+
+    let output = `Analysis slots: ${stats.active} of ${limit} in use\n\n`;
+    output += `  Queued:        ${stats.queued}\n`;
+    output += `  Completed:     ${stats.completed}\n`;
+    output += `  Timed out:     ${stats.timedOut}\n`;
+    output += `  Queue timeout: ${timeout}\n`;
+    return output;
+  }
+}
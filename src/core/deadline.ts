@@ -0,0 +1,104 @@
+/**
+ * Deadline
+ * Per-invocation time budget, carried through async calls like a Go context
+ * and checked cooperatively by long-running loops
+ */
+
+import { AsyncLocalStorage } from 'async_hooks';
+
+/**
+ * Work was stopped because its invocation ran past the deadline
+ */
+export class TimeoutError extends Error {
+  constructor(timeoutMs: number) {
+    super(`Timed out after ${timeoutMs} ms`);
+    this.name = 'TimeoutError';
+  }
+}
+
+/**
+ * Work was cancelled before its deadline, e.g. at shutdown. It is a
+ * TimeoutError so loops stop the same way and return what they have.
+ */
+export class CancelledError extends TimeoutError {
+  constructor(reason: string) {
+    super(0);
+    this.message = `Cancelled: ${reason}`;
+    this.name = 'CancelledError';
+  }
+}
+
+export class Deadline {
+  readonly timeoutMs: number; // 0 = no time limit, only cancellation
+  private expiresAt: number;
+  private expired = false;
+  private cancelReason: string | undefined;
+
+  constructor(timeoutMs: number) {
+    this.timeoutMs = timeoutMs;
+    this.expiresAt = timeoutMs > 0 ? Date.now() + timeoutMs : Infinity;
+  }
+
+  /**
+   * Throw TimeoutError once the budget is spent. Reads the clock rather than
+   * waiting on a timer, so it works inside synchronous loops that keep the
+   * event loop busy.
+   */
+  check(): void {
+    if (this.cancelReason !== undefined) {
+      this.expired = true;
+      throw new CancelledError(this.cancelReason);
+    }
+    if (this.expired || Date.now() >= this.expiresAt) {
+      this.expired = true;
+      throw new TimeoutError(this.timeoutMs);
+    }
+  }
+
+  /**
+   * Whether a check has failed, i.e. some work was cut short
+   */
+  interrupted(): boolean {
+    return this.expired;
+  }
+
+  /**
+   * End the budget now; the next check throws CancelledError
+   */
+  cancel(reason: string): void {
+    this.cancelReason ??= reason;
+  }
+
+  cancelled(): boolean {
+    return this.cancelReason !== undefined;
+  }
+
+  remainingMs(): number {
+    return Math.max(0, this.expiresAt - Date.now());
+  }
+}
+
+const current = new AsyncLocalStorage<Deadline>();
+
+/**
+ * Run a task with a deadline that checkDeadline() anywhere below it sees
+ */
+export function withDeadline<T>(deadline: Deadline, task: () => T): T {
+  return current.run(deadline, task);
+}
+
+/**
+ * The current invocation's deadline, e.g. to hand its remaining time to a
+ * worker thread, which doesn't share it; undefined outside one
+ */
+export function currentDeadline(): Deadline | undefined {
+  return current.getStore();
+}
+
+/**
+ * Throw TimeoutError if the current invocation is past its deadline; a no-op
+ * outside one
+ */
+export function checkDeadline(): void {
+  current.getStore()?.check();
+}
@@ -0,0 +1,100 @@
+/**
+ * Errors
+ * Stable codes for tool failures, so clients can branch on the kind of
+ * failure instead of matching message text
+ */
+
+import * as path from 'path';
+import { ServerBusyError } from './concurrency-limiter.js';
+import { CancelledError, TimeoutError } from './deadline.js';
+import { ShuttingDownError } from './graceful-shutdown.js';
+
+export const ERROR_CODES = [
+  'INVALID_ARGUMENT', // Missing or malformed tool arguments, including bad regexes
+  'FILE_NOT_FOUND',
+  'SYMBOL_NOT_FOUND', // No symbol by that name, or none defined at the location
+  'PERMISSION_DENIED',
+  'PATH_OUTSIDE_PROJECT',
+  'UNSUPPORTED_LANGUAGE',
+  'PARSE_ERROR', // A parser failed on the file's content
+  'ROOT_NOT_REGISTERED',
+  'FAILED_PRECONDITION', // Project state forbids the call: not initialized, file locked, ...
+  'TIMEOUT',
+  'CANCELLED',
+  'SERVER_BUSY',
+  'SHUTTING_DOWN',
+  'UNKNOWN_TOOL',
+  'INTERNAL_ERROR', // Anything else
+] as const;
+
+export type ErrorCode = (typeof ERROR_CODES)[number];
+
+export interface ErrorInfo {
+  code: ErrorCode;
+  message: string;
+  path?: string | undefined; // Offending file or directory, relative to its project root
+}
+
+/**
+ * An error that knows its code, thrown where the kind of failure is clear
+ */
+export class ToolError extends Error {
+  readonly code: ErrorCode;
+  readonly path: string | undefined;
+
+  constructor(code: ErrorCode, message: string, filePath?: string) {
+    super(message);
+    this.name = 'ToolError';
+    this.code = code;
+    this.path = filePath;
+  }
+}
+
+// Node system error codes
+const ERRNO_CODES: Record<string, ErrorCode> = {
+  ENOENT: 'FILE_NOT_FOUND',
+  ENOTDIR: 'FILE_NOT_FOUND',
+  EACCES: 'PERMISSION_DENIED',
+  EPERM: 'PERMISSION_DENIED',
+};
+
+/**
+ * Code, message, and path of any thrown value. Absolute paths are reported
+ * relative to the root containing them, given the registered root directories.
+ */
+export function describeError(error: unknown, roots: string[] = []): ErrorInfo {
+  const message = error instanceof Error ? error.message : String(error);
+  let code: ErrorCode = 'INTERNAL_ERROR';
+  let filePath: string | undefined;
+
+  if (error instanceof ToolError) {
+    code = error.code;
+    filePath = error.path;
+  } else if (error instanceof CancelledError) {
+    code = 'CANCELLED';
+  } else if (error instanceof TimeoutError) {
+    code = 'TIMEOUT';
+  } else if (error instanceof ServerBusyError) {
+    code = 'SERVER_BUSY';
+  } else if (error instanceof ShuttingDownError) {
+    code = 'SHUTTING_DOWN';
+  } else if (error instanceof SyntaxError) {
+    // User-supplied regular expressions and JSON
+    code = 'INVALID_ARGUMENT';
+  } else if (error instanceof Error) {
+    const system = error as NodeJS.ErrnoException;
+    code = (system.code && ERRNO_CODES[system.code]) || 'INTERNAL_ERROR';
+    filePath = system.path;
+  }
+
+  return { code, message, path: filePath && relativeToRoot(filePath, roots) };
+}
+
+function relativeToRoot(filePath: string, roots: string[]): string {
+  if (!path.isAbsolute(filePath)) return filePath.replace(/\\/g, '/');
+  // The deepest root wins when roots are nested
+  const root = roots
+    .filter(r => filePath === r || filePath.startsWith(r + path.sep))
+    .sort((a, b) => b.length - a.length)[0];
+  return root ? path.relative(root, filePath).replace(/\\/g, '/') || '.' : filePath;
+}
@@ -0,0 +1,177 @@
+/**
+ * File Reader
+ * Reads project files, optionally limited to a line range, as UTF-8 whatever
+ * their encoding on disk
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { ToolError } from './errors.js';
+
+// Leading bytes sniffed for null bytes, as git does
+const BINARY_SNIFF_BYTES = 8000;
+
+const UTF8 = new TextDecoder('utf-8', { fatal: true, ignoreBOM: true });
+// Windows-1252 printables at 0x80-0x9f, where ISO-8859-1 has control codes;
+// Node's windows-1252 decoder treats the range as ISO-8859-1 too
+const WINDOWS_1252_HIGH =
+  '\u20ac\x81\u201a\u0192\u201e\u2026\u2020\u2021\u02c6\u2030\u0160\u2039\u0152\x8d\u017d\x8f' +
+  '\x90\u2018\u2019\u201c\u201d\u2022\u2013\u2014\u02dc\u2122\u0161\u203a\u0153\x9d\u017e\u0178';
+
+// File name reported for source text passed in directly
+export const BUFFER_FILE = '<buffer>';
+
+// Encodings recognized on disk; text is always returned as UTF-8
+export type TextEncoding = 'utf-8' | 'utf-16le' | 'utf-16be' | 'windows-1252';
+
+export interface DecodedText {
+  text: string;
+  encoding: TextEncoding;
+  bom: boolean; // A byte order mark was present and has been stripped
+}
+
+export interface FileSlice {
+  file: string;
+  startLine: number;
+  endLine: number;
+  totalLines: number;
+  truncated: boolean; // True when lines exist outside the returned range
+  content: string;
+  encoding: TextEncoding; // Of the file on disk
+  bom: boolean;
+}
+
+export class FileReader {
+  private workingDir: string;
+
+  constructor(workingDir?: string) {
+    this.workingDir = workingDir ?? process.cwd();
+  }
+
+  /**
+   * Read lines startLine..endLine (1-based, inclusive). Out-of-range values
+   * are clamped; a missing endLine reads to the end of the file.
+   */
+  async read(file: string, startLine?: number, endLine?: number): Promise<FileSlice> {
+    const relativePath = await resolveRealProjectPath(this.workingDir, file);
+    const buffer = await fs.readFile(path.join(this.workingDir, relativePath));
+    const { text: content, encoding, bom } = decodeText(buffer);
+
+    const lines = content.split('\n');
+    if (lines.length > 1 && lines[lines.length - 1] === '') {
+      lines.pop(); // Trailing newline doesn't start a new line
+    }
+
+    const totalLines = lines.length;
+    const start = clamp(Math.floor(startLine ?? 1), 1, totalLines);
+    const end = clamp(Math.floor(endLine ?? totalLines), start, totalLines);
+
+    return {
+      file: relativePath,
+      startLine: start,
+      endLine: end,
+      totalLines,
+      truncated: start > 1 || end < totalLines,
+      content: lines.slice(start - 1, end).join('\n'),
+      encoding,
+      bom,
+    };
+  }
+
+  formatSlice(slice: FileSlice): string {
+    const width = String(slice.endLine).length;
+    const lines = `lines ${slice.startLine}-${slice.endLine} of ${slice.totalLines}`;
+    // Only mention an encoding that needs cleaning up
+    const encoding =
+      slice.encoding !== 'utf-8' || slice.bom
+        ? `, ${slice.encoding}${slice.bom ? ' with BOM' : ''}`
+        : '';
+    let output = `${slice.file} (${lines}${encoding})\n\n`;
+
+    slice.content.split('\n').forEach((text, i) => {
+      output += `${String(slice.startLine + i).padStart(width)} | ${text}\n`;
+    });
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.workingDir = dir;
+  }
+}
+
+/**
+ * Resolve a path against the project root, rejecting paths that escape it
+ * Returns the project-relative path with forward slashes
+ */
+export function resolveProjectPath(workingDir: string, target: string): string {
+  const relativePath = path.relative(workingDir, path.resolve(workingDir, target));
+  // A name like `..foo` is inside the project; only a whole `..` segment leaves it
+  const outside =
+    relativePath === '..' ||
+    relativePath.startsWith('..' + path.sep) ||
+    path.isAbsolute(relativePath);
+  if (outside) {
+    throw new ToolError('PATH_OUTSIDE_PROJECT', `Path is outside the project: ${target}`, target);
+  }
+  return relativePath.replace(/\\/g, '/');
+}
+
+/**
+ * resolveProjectPath, but also following symlinks: a link inside the project
+ * that points outside it is rejected too. The target must exist.
+ */
+export async function resolveRealProjectPath(workingDir: string, target: string): Promise<string> {
+  const relativePath = resolveProjectPath(workingDir, target);
+  const [realRoot, realTarget] = await Promise.all([
+    fs.realpath(workingDir),
+    fs.realpath(path.join(workingDir, relativePath)),
+  ]);
+  const realRelative = path.relative(realRoot, realTarget);
+  if (
+    realRelative === '..' ||
+    realRelative.startsWith('..' + path.sep) ||
+    path.isAbsolute(realRelative)
+  ) {
+    throw new ToolError('PATH_OUTSIDE_PROJECT', `Path is outside the project: ${target}`, target);
+  }
+  return relativePath;
+}
+
+/**
+ * Whether file contents look binary: a null byte near the start
+ */
+export function isBinary(buffer: Buffer): boolean {
+  return buffer.subarray(0, BINARY_SNIFF_BYTES).includes(0);
+}
+
+/**
+ * Text of a file in UTF-8: a byte order mark picks UTF-8 or UTF-16 and is
+ * stripped; without one, bytes that aren't valid UTF-8 are read as Windows-1252,
+ * the usual encoding of legacy Latin-1 files
+ */
+export function decodeText(buffer: Buffer): DecodedText {
+  if (buffer[0] === 0xef && buffer[1] === 0xbb && buffer[2] === 0xbf) {
+    return { text: buffer.subarray(3).toString('utf-8'), encoding: 'utf-8', bom: true };
+  }
+  if (buffer[0] === 0xff && buffer[1] === 0xfe) {
+    return { text: buffer.subarray(2).toString('utf16le'), encoding: 'utf-16le', bom: true };
+  }
+  if (buffer[0] === 0xfe && buffer[1] === 0xff) {
+    // Node has no UTF-16BE decoder without full ICU; swap to little-endian
+    const swapped = Buffer.from(buffer.subarray(2, buffer.length - (buffer.length % 2)));
+    return { text: swapped.swap16().toString('utf16le'), encoding: 'utf-16be', bom: true };
+  }
+  try {
+    return { text: UTF8.decode(buffer), encoding: 'utf-8', bom: false };
+  } catch {
+    const text = buffer
+      .toString('latin1')
+      .replace(/[\x80-\x9f]/g, ch => WINDOWS_1252_HIGH[ch.charCodeAt(0) - 0x80] ?? ch);
+    return { text, encoding: 'windows-1252', bom: false };
+  }
+}
+
+function clamp(value: number, min: number, max: number): number {
+  return Math.min(Math.max(value, min), max);
+}
@@ -0,0 +1,237 @@
+/**
+ * File Walker
+ * Lists project files, honoring .gitignore files and default ignore directories
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { glob } from 'glob';
+
+// Skipped even without a .gitignore entry
+export const DEFAULT_IGNORE = [
+  '**/node_modules/**',
+  '**/vendor/**',
+  '**/dist/**',
+  '**/build/**',
+  '**/.engineering/**',
+];
+
+// Never walked, even when ignored paths are included
+const ALWAYS_IGNORE = ['**/.git/**'];
+
+export interface WalkOptions extends ScopeOptions {
+  cwd?: string; // Directory to walk, relative to the project root (default: root)
+  ignore?: string[] | undefined; // Extra glob patterns to skip
+  includeIgnored?: boolean | undefined; // Disable .gitignore and default ignores
+  maxDepth?: number | undefined; // Directory levels below cwd to descend into: 0 = its own files
+  onDepthLimit?: ((dir: string) => void) | undefined; // Each directory maxDepth left unwalked
+}
+
+// Globs or path prefixes relative to the project root, on top of the ignore rules
+export interface ScopeOptions {
+  include?: string[] | undefined; // Only matching files; "!glob" entries exclude
+  exclude?: string[] | undefined;
+}
+
+interface IgnoreRule {
+  base: string; // Directory of the .gitignore, relative to the project root
+  regex: RegExp;
+  negate: boolean;
+  dirOnly: boolean;
+}
+
+/**
+ * Glob files under the project, returning paths relative to the project root
+ */
+export async function walkFiles(
+  workingDir: string,
+  patterns: string[],
+  options: WalkOptions = {}
+): Promise<string[]> {
+  const cwd = options.cwd ?? '';
+  const ignore = [
+    ...ALWAYS_IGNORE,
+    ...(options.includeIgnored ? [] : DEFAULT_IGNORE),
+    ...(options.ignore ?? []),
+  ];
+
+  const toProjectPath = (f: string): string =>
+    path.posix.join(cwd.replace(/\\/g, '/'), f.replace(/\\/g, '/'));
+  const { maxDepth } = options;
+
+  const files = (
+    await glob(patterns, {
+      cwd: path.join(workingDir, cwd),
+      nodir: true,
+      dot: false,
+      ignore,
+      // glob counts cwd itself as depth 0, so its files are at depth 1
+      ...(maxDepth !== undefined ? { maxDepth: maxDepth + 1 } : {}),
+    })
+  )
+    .map(toProjectPath)
+    .filter(scopeMatcher(options));
+
+  const rules = options.includeIgnored ? [] : await loadGitignoreRules(workingDir, ignore);
+
+  // The directories one level past the limit are the ones never entered
+  if (maxDepth !== undefined && options.onDepthLimit) {
+    const skipped = await glob('*/'.repeat(maxDepth + 1), {
+      cwd: path.join(workingDir, cwd),
+      dot: false,
+      ignore,
+    });
+    for (const dir of skipped.map(toProjectPath).sort()) {
+      if (!isIgnored(dir, rules, true)) options.onDepthLimit(dir);
+    }
+  }
+
+  return files.filter(f => !isIgnored(f, rules)).sort();
+}
+
+/**
+ * Whether a project-relative path matches any of the glob patterns, for file
+ * lists that don't come from the filesystem (e.g. a git tree)
+ */
+export function matchesAnyGlob(file: string, patterns: string[]): boolean {
+  return patterns.some(pattern => new RegExp(`^${globToRegex(pattern)}$`).test(file));
+}
+
+/**
+ * Predicate for include/exclude scoping. A file must match some include
+ * pattern (when there are any) and no exclude pattern. Patterns are globs
+ * relative to the project root; one without wildcards is a path prefix, so
+ * cmd/api covers everything under it.
+ */
+export function scopeMatcher(scope: ScopeOptions): (file: string) => boolean {
+  const include = scope.include ?? [];
+  const toRegex = (pattern: string): RegExp => {
+    const normalized = pattern.replace(/^\.\//, '').replace(/\/+$/, '');
+    const body = globToRegex(normalized);
+    return new RegExp(/[*?[]/.test(normalized) ? `^${body}$` : `^${body}(?:/.*)?$`);
+  };
+  const included = include.filter(p => !p.startsWith('!')).map(toRegex);
+  const excluded = [
+    ...include.filter(p => p.startsWith('!')).map(p => p.slice(1)),
+    ...(scope.exclude ?? []),
+  ].map(toRegex);
+
+  return file =>
+    (included.length === 0 || included.some(r => r.test(file))) &&
+    !excluded.some(r => r.test(file));
+}
+
+async function loadGitignoreRules(workingDir: string, ignore: string[]): Promise<IgnoreRule[]> {
+  const ignoreFiles = await glob('**/.gitignore', { cwd: workingDir, dot: true, ignore });
+  const rules: IgnoreRule[] = [];
+
+  // Shallow files first so deeper .gitignore rules take precedence
+  ignoreFiles.sort((a, b) => a.split(/[\\/]/).length - b.split(/[\\/]/).length);
+
+  for (const file of ignoreFiles) {
+    try {
+      const content = await fs.readFile(path.join(workingDir, file), 'utf-8');
+      const base = path.posix.dirname(file.replace(/\\/g, '/'));
+      rules.push(...parseGitignore(content, base === '.' ? '' : base));
+    } catch {
+      // Skip files that can't be read
+    }
+  }
+
+  return rules;
+}
+
+function parseGitignore(content: string, base = ''): IgnoreRule[] {
+  const rules: IgnoreRule[] = [];
+
+  for (const rawLine of content.split('\n')) {
+    let line = rawLine.replace(/\r$/, '').replace(/(?<!\\)\s+$/, '');
+    if (line === '' || line.startsWith('#')) continue;
+
+    const negate = line.startsWith('!');
+    if (negate) line = line.slice(1);
+    line = line.replace(/^\\([#!])/, '$1');
+
+    const dirOnly = line.endsWith('/');
+    if (dirOnly) line = line.slice(0, -1);
+
+    // A slash anywhere but the end anchors the pattern to the .gitignore directory
+    const anchored = line.includes('/');
+    if (line.startsWith('/')) line = line.slice(1);
+
+    const body = globToRegex(line);
+    rules.push({
+      base,
+      regex: new RegExp(anchored ? `^${body}$` : `(?:^|/)${body}$`),
+      negate,
+      dirOnly,
+    });
+  }
+
+  return rules;
+}
+
+/**
+ * Whether a file (or any of its parent directories) is ignored. Later rules
+ * override earlier ones, as in git. With isDirectory, the path itself is a
+ * directory, so directory-only rules apply to it too.
+ */
+function isIgnored(file: string, rules: IgnoreRule[], isDirectory = false): boolean {
+  let ignored = false;
+
+  for (const rule of rules) {
+    if (rule.base && !file.startsWith(`${rule.base}/`)) continue;
+    const relative = rule.base ? file.slice(rule.base.length + 1) : file;
+    const parts = relative.split('/');
+
+    for (let i = 1; i <= parts.length; i++) {
+      const isDir = i < parts.length || isDirectory;
+      if (rule.dirOnly && !isDir) continue;
+      if (rule.regex.test(parts.slice(0, i).join('/'))) {
+        ignored = !rule.negate;
+        break;
+      }
+    }
+  }
+
+  return ignored;
+}
+
+function globToRegex(pattern: string): string {
+  let out = '';
+
+  for (let i = 0; i < pattern.length; i++) {
+    const ch = pattern[i] ?? '';
+    if (ch === '*') {
+      if (pattern[i + 1] === '*') {
+        // "**/" matches any number of directories, a trailing "**" everything
+        const slash = pattern[i + 2] === '/';
+        out += slash ? '(?:.*/)?' : '.*';
+        i += slash ? 2 : 1;
+      } else {
+        out += '[^/]*';
+      }
+    } else if (ch === '?') {
+      out += '[^/]';
+    } else if (ch === '[') {
+      const close = pattern.indexOf(']', i + 1);
+      if (close === -1) {
+        out += '\\[';
+      } else {
+        out += '[' + pattern.slice(i + 1, close).replace(/^!/, '^').replace(/\\/g, '\\\\') + ']';
+        i = close;
+      }
+    } else if (ch === '\\' && i + 1 < pattern.length) {
+      out += escapeRegExp(pattern[i + 1] ?? '');
+      i++;
+    } else {
+      out += escapeRegExp(ch);
+    }
+  }
+
+  return out;
+}
+
+function escapeRegExp(text: string): string {
+  return text.replace(/[.*+?^${}()|[\]\\/]/g, '\\$&');
+}
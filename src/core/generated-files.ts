@@ -0,0 +1,38 @@
+/**
+ * Generated Files
+ * Recognizes generated source by the "Code generated ... DO NOT EDIT." header
+ * (golang.org/s/generatedcode, in any comment style) and by the file names
+ * code generators use
+ */
+
+import * as path from 'path';
+
+// "// Code generated by protoc-gen-go. DO NOT EDIT." and the same after #, --, or /* *
+const GENERATED_HEADER = /^\s*(?:\/\/|#|--|\/?\*+)\s*Code generated .* DO NOT EDIT\.?/m;
+
+// "// @generated" (Buck, Relay, Thrift) and "<auto-generated>" (.NET)
+const GENERATED_MARKER = /^\s*(?:\/\/\/?|#|\/?\*+)\s*(?:@generated\b|<auto-generated)/m;
+
+const GENERATED_NAMES = [
+  /\.pb(?:\.gw)?\.(?:go|cc|h|cpp|ts|js|swift|dart)$/, // protoc: api.pb.go, api.pb.h
+  /_pb2(?:_grpc)?\.pyi?$/, // api_pb2.py
+  /_pb\.(?:js|d\.ts)$/, // api_pb.js, api_grpc_pb.d.ts
+  /[._](?:gen|generated)\.\w+$/, // models_gen.go, schema.generated.ts
+  /^zz_generated\./, // Kubernetes deepcopy
+  /\.(?:g|freezed)\.dart$/,
+  /\.designer\.cs$/i,
+];
+
+// The header must precede the first declaration, so only the start is read
+const HEADER_SCAN_BYTES = 8 * 1024;
+
+/**
+ * Whether a file is generated, from its name or the header of its content
+ */
+export function isGeneratedFile(file: string, content: string): boolean {
+  const name = path.basename(file);
+  if (GENERATED_NAMES.some(pattern => pattern.test(name))) return true;
+
+  const head = content.slice(0, HEADER_SCAN_BYTES);
+  return GENERATED_HEADER.test(head) || GENERATED_MARKER.test(head);
+}
@@ -0,0 +1,231 @@
+/**
+ * Git
+ * Thin wrapper around the git CLI
+ */
+
+import { spawn } from 'child_process';
+import { ToolError } from './errors.js';
+
+export interface GitResult {
+  code: number;
+  stdout: string;
+  stderr: string;
+}
+
+/**
+ * Run git with arguments passed directly (no shell), so user-supplied refs
+ * can't inject commands. Rejects if git can't be started.
+ */
+export async function runGit(workingDir: string, args: string[]): Promise<GitResult> {
+  // Decoded once at the end, so multi-byte characters split across chunks survive
+  const { code, stdout, stderr } = await runGitRaw(workingDir, args);
+  return { code, stdout: stdout.toString('utf-8'), stderr: stderr.toString('utf-8') };
+}
+
+function runGitRaw(
+  workingDir: string,
+  args: string[]
+): Promise<{ code: number; stdout: Buffer; stderr: Buffer }> {
+  return new Promise((resolve, reject) => {
+    const proc = spawn('git', args, { cwd: workingDir });
+
+    const stdout: Buffer[] = [];
+    const stderr: Buffer[] = [];
+
+    proc.stdout.on('data', (data: Buffer) => {
+      stdout.push(data);
+    });
+
+    proc.stderr.on('data', (data: Buffer) => {
+      stderr.push(data);
+    });
+
+    proc.on('error', reject);
+    proc.on('close', code => {
+      resolve({ code: code ?? 1, stdout: Buffer.concat(stdout), stderr: Buffer.concat(stderr) });
+    });
+  });
+}
+
+/**
+ * Files changed in a ref range such as "main...HEAD", relative to workingDir
+ * Returns null when git is unavailable or the range is invalid
+ */
+export async function getChangedFiles(workingDir: string, range: string): Promise<string[] | null> {
+  if (range.startsWith('-')) {
+    throw new ToolError('INVALID_ARGUMENT', `Invalid git range: ${range}`);
+  }
+
+  try {
+    const result = await runGit(workingDir, ['diff', '--name-only', '--relative', range, '--']);
+    if (result.code !== 0) {
+      return null;
+    }
+
+    return result.stdout
+      .split('\n')
+      .map(line => line.trim())
+      .filter(line => line.length > 0);
+  } catch {
+    // git not installed
+    return null;
+  }
+}
+
+export interface TreeEntry {
+  file: string; // Relative to workingDir
+  blob: string; // Object id; equal ids mean identical content
+}
+
+/**
+ * Commit id a ref (branch, tag, sha, HEAD~3) points to. Throws if it doesn't
+ * resolve to a commit, or if workingDir isn't in a repository.
+ */
+export async function resolveCommit(workingDir: string, ref: string): Promise<string> {
+  if (ref.startsWith('-')) {
+    throw new ToolError('INVALID_ARGUMENT', `Invalid git ref: ${ref}`);
+  }
+
+  const result = await runGit(workingDir, ['rev-parse', '--verify', '--quiet', `${ref}^{commit}`]);
+  if (result.code !== 0) {
+    throw new ToolError('INVALID_ARGUMENT', `Unknown git ref: ${ref}`);
+  }
+  return result.stdout.trim();
+}
+
+/**
+ * Files in a commit's tree under workingDir, read from the object database
+ * without touching the checkout
+ */
+export async function listTree(workingDir: string, commit: string): Promise<TreeEntry[]> {
+  const result = await runGit(workingDir, ['ls-tree', '-r', '-z', commit]);
+  if (result.code !== 0) {
+    throw new Error(`git ls-tree ${commit} failed: ${result.stderr.trim()}`);
+  }
+
+  // "<mode> <type> <id>\t<path>", NUL-separated so paths need no unquoting
+  const entries: TreeEntry[] = [];
+  for (const record of result.stdout.split('\0')) {
+    const match = /^\d+ blob ([0-9a-f]+)\t(.+)$/s.exec(record);
+    if (match?.[1] && match[2]) entries.push({ file: match[2], blob: match[1] });
+  }
+  return entries;
+}
+
+/**
+ * Content of a blob by object id
+ */
+export async function readBlob(workingDir: string, blob: string): Promise<Buffer> {
+  const result = await runGitRaw(workingDir, ['cat-file', 'blob', blob]);
+  if (result.code !== 0) {
+    throw new Error(`git cat-file blob ${blob} failed: ${result.stderr.toString().trim()}`);
+  }
+  return result.stdout;
+}
+
+export interface BlameLine {
+  author: string;
+  commit: string;
+  date: string; // ISO 8601 author date
+}
+
+/**
+ * Last commit touching each line of a file, keyed by 1-based line number.
+ * Uncommitted lines are left out; returns null when git can't blame the
+ * file (not a repository, untracked file, git missing).
+ */
+export async function getBlame(
+  workingDir: string,
+  file: string
+): Promise<Map<number, BlameLine> | null> {
+  try {
+    const result = await runGit(workingDir, ['blame', '--porcelain', '--', file]);
+    if (result.code !== 0) {
+      return null;
+    }
+    return parseBlamePorcelain(result.stdout);
+  } catch {
+    // git not installed
+    return null;
+  }
+}
+
+/**
+ * Porcelain output lists each commit's headers only on its first hunk, so
+ * track them per commit and resolve every line afterwards
+ */
+function parseBlamePorcelain(output: string): Map<number, BlameLine> {
+  const commits = new Map<string, { author: string; time: number }>();
+  const lineCommits = new Map<number, string>();
+  let current = '';
+
+  for (const line of output.split('\n')) {
+    const header = /^([0-9a-f]{40}) \d+ (\d+)/.exec(line);
+    if (header?.[1] && header[2]) {
+      current = header[1];
+      lineCommits.set(Number(header[2]), current);
+      if (!commits.has(current)) commits.set(current, { author: '', time: 0 });
+      continue;
+    }
+
+    const commit = commits.get(current);
+    if (!commit) continue;
+    if (line.startsWith('author ')) {
+      commit.author = line.slice('author '.length);
+    } else if (line.startsWith('author-time ')) {
+      commit.time = Number(line.slice('author-time '.length));
+    }
+  }
+
+  const blame = new Map<number, BlameLine>();
+  for (const [lineNumber, sha] of lineCommits) {
+    const commit = commits.get(sha);
+    if (!commit || /^0+$/.test(sha)) continue;
+    blame.set(lineNumber, {
+      author: commit.author,
+      commit: sha,
+      date: new Date(commit.time * 1000).toISOString(),
+    });
+  }
+
+  return blame;
+}
+
+/**
+ * Number of commits since a date ("90 days ago", "2024-01-01") that touched
+ * each file under workingDir, keyed by path relative to workingDir. Merge
+ * commits count only through the commits they bring in. Returns null when
+ * there's no history to read (not a repository, no commits, git missing).
+ */
+export async function getChangeCounts(
+  workingDir: string,
+  since: string
+): Promise<Map<string, number> | null> {
+  try {
+    const result = await runGit(workingDir, [
+      '-c',
+      'core.quotePath=false',
+      'log',
+      `--since=${since}`,
+      '--format=',
+      '--name-only',
+      '--relative',
+      '--no-renames',
+      '--',
+      '.',
+    ]);
+    if (result.code !== 0) {
+      return null;
+    }
+
+    const counts = new Map<string, number>();
+    for (const line of result.stdout.split('\n')) {
+      const file = line.trim();
+      if (file) counts.set(file, (counts.get(file) ?? 0) + 1);
+    }
+    return counts;
+  } catch {
+    // git not installed
+    return null;
+  }
+}
@@ -0,0 +1,88 @@
+/**
+ * Graceful Shutdown
+ * Tracks in-flight tool calls so shutdown can refuse new ones, wait for the
+ * running ones, and cancel whatever outlasts the grace period
+ */
+
+export const DEFAULT_GRACE_PERIOD_MS = 10_000;
+
+// Once cancelled, how long calls get to send back their partial results
+const CANCEL_SETTLE_MS = 1000;
+
+/**
+ * A call arrived after shutdown began
+ */
+export class ShuttingDownError extends Error {
+  constructor() {
+    super('Server is shutting down; not accepting new tool calls');
+    this.name = 'ShuttingDownError';
+  }
+}
+
+export interface DrainResult {
+  drained: number; // Finished within the grace period
+  cancelled: number;
+}
+
+export class InFlightTracker {
+  private calls = new Set<AbortController>();
+  private draining = false;
+  private onIdle: (() => void) | undefined;
+
+  get size(): number {
+    return this.calls.size;
+  }
+
+  /**
+   * Run a call, unless shutdown has begun. The signal aborts if the call is
+   * still running when the grace period ends.
+   */
+  async run<T>(task: (signal: AbortSignal) => Promise<T>): Promise<T> {
+    if (this.draining) {
+      throw new ShuttingDownError();
+    }
+
+    const controller = new AbortController();
+    this.calls.add(controller);
+    try {
+      return await task(controller.signal);
+    } finally {
+      this.calls.delete(controller);
+      if (this.calls.size === 0) this.onIdle?.();
+    }
+  }
+
+  /**
+   * Refuse new calls and wait up to gracePeriodMs for running ones, then
+   * abort the rest and give them a moment to answer
+   */
+  async drain(gracePeriodMs: number): Promise<DrainResult> {
+    this.draining = true;
+    const total = this.calls.size;
+    await this.idle(gracePeriodMs);
+
+    const cancelled = this.calls.size;
+    for (const controller of this.calls) {
+      controller.abort();
+    }
+    await this.idle(CANCEL_SETTLE_MS);
+
+    return { drained: total - cancelled, cancelled };
+  }
+
+  private idle(timeoutMs: number): Promise<void> {
+    if (this.calls.size === 0) {
+      return Promise.resolve();
+    }
+
+    return new Promise(resolve => {
+      const done = (): void => {
+        clearTimeout(timer);
+        this.onIdle = undefined;
+        resolve();
+      };
+      const timer = setTimeout(done, timeoutMs);
+      this.onIdle = done;
+    });
+  }
+}
@@ -0,0 +1,211 @@
+/**
+ * HTTP Transport
+ * Streamable HTTP endpoint serving one MCP session per client, plus a health
+ * probe for orchestration, optionally behind a bearer token
+ */
+
+import * as http from 'http';
+import { createHash, randomUUID, timingSafeEqual } from 'crypto';
+import type { Server } from '@modelcontextprotocol/sdk/server/index.js';
+import { StreamableHTTPServerTransport } from '@modelcontextprotocol/sdk/server/streamableHttp.js';
+import { isInitializeRequest } from '@modelcontextprotocol/sdk/types.js';
+
+export const DEFAULT_HTTP_HOST = '127.0.0.1';
+export const DEFAULT_HTTP_PORT = 3000;
+
+export const MCP_PATH = '/mcp';
+export const HEALTH_PATH = '/health';
+
+// Largest request body read before a session exists, as the SDK caps its own
+export const MAX_BODY_BYTES = 4 * 1024 * 1024;
+const TOO_LARGE = Symbol('too large');
+
+export interface HttpOptions {
+  host?: string | undefined;
+  port?: number | undefined;
+  authToken?: string | undefined; // When set, every request needs Authorization: Bearer <token>
+}
+
+export interface HealthStatus {
+  status: 'ok';
+  sessions: number;
+  uptimeSeconds: number;
+}
+
+export class HttpTransportServer {
+  private createServer: () => Server;
+  private sessions = new Map<string, StreamableHTTPServerTransport>();
+  private startedAt = Date.now();
+  private authTokenHash: Buffer | undefined;
+  private httpServer: http.Server | undefined;
+
+  /**
+   * createServer builds a fresh MCP server for each client that initializes
+   */
+  constructor(createServer: () => Server) {
+    this.createServer = createServer;
+  }
+
+  async listen(options: HttpOptions = {}): Promise<{ host: string; port: number }> {
+    const host = options.host ?? DEFAULT_HTTP_HOST;
+    this.authTokenHash = options.authToken ? sha256(options.authToken) : undefined;
+    const httpServer = (this.httpServer = http.createServer((req, res) => {
+      this.handle(req, res).catch((error: unknown) => {
+        if (!res.headersSent) {
+          sendJsonRpcError(res, 500, -32603, `Internal error: ${String(error)}`);
+        } else {
+          res.end();
+        }
+      });
+    }));
+
+    await new Promise<void>((resolve, reject) => {
+      httpServer.once('error', reject);
+      httpServer.listen(options.port ?? DEFAULT_HTTP_PORT, host, () => resolve());
+    });
+    const address = httpServer.address();
+    return { host, port: typeof address === 'object' && address ? address.port : 0 };
+  }
+
+  /**
+   * End every session and stop listening, dropping idle keep-alive connections
+   */
+  async close(): Promise<void> {
+    await Promise.all([...this.sessions.values()].map(t => t.close().catch(() => undefined)));
+    const httpServer = this.httpServer;
+    if (!httpServer) return;
+    await new Promise<void>(resolve => {
+      httpServer.close(() => resolve());
+      httpServer.closeAllConnections();
+    });
+  }
+
+  health(): HealthStatus {
+    return {
+      status: 'ok',
+      sessions: this.sessions.size,
+      uptimeSeconds: Math.floor((Date.now() - this.startedAt) / 1000),
+    };
+  }
+
+  private async handle(req: http.IncomingMessage, res: http.ServerResponse): Promise<void> {
+    const { pathname } = new URL(req.url ?? '/', 'http://localhost');
+
+    if (!this.authorized(req)) {
+      res.writeHead(401, { 'Content-Type': 'text/plain', 'WWW-Authenticate': 'Bearer' });
+      res.end('Unauthorized');
+      return;
+    }
+
+    if (pathname === HEALTH_PATH) {
+      res.writeHead(req.method === 'GET' ? 200 : 405, { 'Content-Type': 'application/json' });
+      res.end(req.method === 'GET' ? JSON.stringify(this.health()) : '');
+      return;
+    }
+    if (pathname !== MCP_PATH) {
+      res.writeHead(404, { 'Content-Type': 'text/plain' });
+      res.end(`Not found. MCP is served at ${MCP_PATH}`);
+      return;
+    }
+
+    // Requests within a session (including GET for the SSE stream and DELETE
+    // to end it) go to that session's transport
+    const sessionId = req.headers['mcp-session-id'];
+    if (typeof sessionId === 'string') {
+      const transport = this.sessions.get(sessionId);
+      if (!transport) {
+        sendJsonRpcError(res, 404, -32001, `Session not found: ${sessionId}`);
+        return;
+      }
+      await transport.handleRequest(req, res);
+      return;
+    }
+
+    // Anything else must start a session
+    const body = req.method === 'POST' ? await readJson(req) : undefined;
+    if (body === TOO_LARGE) {
+      // The rest of the body is left unread, so the connection can't be reused
+      res.setHeader('Connection', 'close');
+      sendJsonRpcError(res, 413, -32000, `Request body over ${MAX_BODY_BYTES} bytes`);
+      return;
+    }
+    if (!isInitializeRequest(body)) {
+      sendJsonRpcError(res, 400, -32000, 'No session: send an initialize request first');
+      return;
+    }
+
+    const transport: StreamableHTTPServerTransport = new StreamableHTTPServerTransport({
+      sessionIdGenerator: () => randomUUID(),
+      onsessioninitialized: id => {
+        this.sessions.set(id, transport);
+      },
+    });
+    transport.onclose = () => {
+      if (transport.sessionId) this.sessions.delete(transport.sessionId);
+    };
+
+    await this.createServer().connect(transport);
+    await transport.handleRequest(req, res, body);
+  }
+
+  /**
+   * Whether the request carries the configured bearer token. Both sides are
+   * hashed to equal length first so the comparison takes constant time.
+   */
+  private authorized(req: http.IncomingMessage): boolean {
+    if (!this.authTokenHash) return true;
+    const match = /^Bearer\s+(\S+)\s*$/i.exec(req.headers.authorization ?? '');
+    return match?.[1] !== undefined && timingSafeEqual(sha256(match[1]), this.authTokenHash);
+  }
+}
+
+function sha256(text: string): Buffer {
+  return createHash('sha256').update(text).digest();
+}
+
+/**
+ * The parsed JSON body, undefined if it isn't JSON, or TOO_LARGE once it
+ * passes MAX_BODY_BYTES, at which point reading stops
+ */
+function readJson(req: http.IncomingMessage): Promise<unknown> {
+  if (Number(req.headers['content-length']) > MAX_BODY_BYTES) {
+    return Promise.resolve(TOO_LARGE);
+  }
+
+  return new Promise((resolve, reject) => {
+    const chunks: Buffer[] = [];
+    let size = 0;
+    const stop = (): void => {
+      req.off('data', onData).off('end', onEnd).off('error', reject);
+    };
+    const onData = (chunk: Buffer): void => {
+      size += chunk.length;
+      if (size > MAX_BODY_BYTES) {
+        stop();
+        req.pause();
+        resolve(TOO_LARGE);
+        return;
+      }
+      chunks.push(chunk);
+    };
+    const onEnd = (): void => {
+      stop();
+      try {
+        resolve(JSON.parse(Buffer.concat(chunks).toString('utf-8')));
+      } catch {
+        resolve(undefined);
+      }
+    };
+    req.on('data', onData).on('end', onEnd).on('error', reject);
+  });
+}
+
+function sendJsonRpcError(
+  res: http.ServerResponse,
+  status: number,
+  code: number,
+  message: string
+): void {
+  res.writeHead(status, { 'Content-Type': 'application/json' });
+  res.end(JSON.stringify({ jsonrpc: '2.0', error: { code, message }, id: null }));
+}
@@ -0,0 +1,232 @@
+/**
+ * Language Detector
+ * Identifies a file's language from its extension, name, shebang, or content
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { walkFiles } from './file-walker.js';
+import { resolveProjectPath } from './file-reader.js';
+
+export type DetectionMethod = 'extension' | 'filename' | 'shebang' | 'content';
+
+export interface LanguageDetection {
+  language: string;
+  method: DetectionMethod;
+}
+
+export interface FileLanguage extends LanguageDetection {
+  file: string;
+}
+
+export interface LanguageReport {
+  files: FileLanguage[];
+  languages: Array<{ language: string; files: number }>;
+  unknown: number; // Files no rule matched
+}
+
+const EXTENSIONS: Record<string, string> = {
+  '.go': 'go',
+  '.ts': 'typescript',
+  '.tsx': 'typescript',
+  '.mts': 'typescript',
+  '.cts': 'typescript',
+  '.js': 'javascript',
+  '.jsx': 'javascript',
+  '.mjs': 'javascript',
+  '.cjs': 'javascript',
+  '.py': 'python',
+  '.pyi': 'python',
+  '.rs': 'rust',
+  '.java': 'java',
+  '.kt': 'kotlin',
+  '.kts': 'kotlin',
+  '.swift': 'swift',
+  '.c': 'c',
+  '.h': 'c',
+  '.cpp': 'cpp',
+  '.cc': 'cpp',
+  '.cxx': 'cpp',
+  '.hpp': 'cpp',
+  '.hh': 'cpp',
+  '.ino': 'cpp',
+  '.cs': 'csharp',
+  '.rb': 'ruby',
+  '.php': 'php',
+  '.sh': 'shell',
+  '.bash': 'shell',
+  '.zsh': 'shell',
+  '.sql': 'sql',
+  '.graphql': 'graphql',
+  '.gql': 'graphql',
+  '.proto': 'protobuf',
+  '.mk': 'makefile',
+};
+
+// Well-known extensionless (or fixed-name) files
+const FILENAMES: Record<string, string> = {
+  makefile: 'makefile',
+  gnumakefile: 'makefile',
+  dockerfile: 'dockerfile',
+  containerfile: 'dockerfile',
+  jenkinsfile: 'groovy',
+  gemfile: 'ruby',
+  rakefile: 'ruby',
+  vagrantfile: 'ruby',
+  'cmakelists.txt': 'cmake',
+  build: 'starlark',
+  'build.bazel': 'starlark',
+  workspace: 'starlark',
+};
+
+// Interpreter named by a shebang -> language
+const INTERPRETERS: Array<[RegExp, string]> = [
+  [/^python[\d.]*$/, 'python'],
+  [/^(?:node|nodejs|bun)$/, 'javascript'],
+  [/^(?:deno|ts-node|tsx)$/, 'typescript'],
+  [/^(?:ba|z|k|da)?sh$/, 'shell'],
+  [/^ruby$/, 'ruby'],
+  [/^php$/, 'php'],
+  [/^perl$/, 'perl'],
+];
+
+// Last-resort content signatures, checked in order
+const CONTENT_HEURISTICS: Array<[RegExp, string]> = [
+  [/^package\s+\w+\s*$[\s\S]*^(?:func|import|type)\b/m, 'go'],
+  [/^<\?php/, 'php'],
+  [/^(?:from\s+[\w.]+\s+import|import\s+\w+|def\s+\w+\s*\(.*\)\s*(?:->.*)?:)/m, 'python'],
+  [/^(?:export\s+)?(?:interface|type)\s+\w+.*[={]/m, 'typescript'],
+  [/^(?:const|let)\s+\w+\s*=\s*require\(/m, 'javascript'],
+  [/^[\w.-]+\s*:(?!=).*$\n\t/m, 'makefile'],
+];
+
+/**
+ * Detect a file's language. Content is only consulted when the extension and
+ * file name are inconclusive; pass it to avoid a read, or omit to read lazily.
+ */
+export async function detectLanguage(
+  file: string,
+  content?: string,
+  workingDir = process.cwd()
+): Promise<LanguageDetection | null> {
+  const fast = detectLanguageFromName(file);
+  if (fast) return fast;
+
+  let text = content;
+  if (text === undefined) {
+    try {
+      const handle = await fs.open(path.resolve(workingDir, file), 'r');
+      try {
+        const buffer = Buffer.alloc(4096);
+        const { bytesRead } = await handle.read(buffer, 0, buffer.length, 0);
+        text = buffer.subarray(0, bytesRead).toString('utf-8');
+      } finally {
+        await handle.close();
+      }
+    } catch {
+      return null;
+    }
+  }
+
+  return detectLanguageFromContent(text);
+}
+
+export function detectLanguageFromName(file: string): LanguageDetection | null {
+  const base = path.basename(file).toLowerCase();
+  const byName = FILENAMES[base];
+  if (byName) return { language: byName, method: 'filename' };
+
+  const byExtension = EXTENSIONS[path.extname(base)];
+  if (byExtension) return { language: byExtension, method: 'extension' };
+
+  return null;
+}
+
+export function detectLanguageFromContent(content: string): LanguageDetection | null {
+  const shebang = /^#!\s*(\S+)(?:\s+(\S+))?/.exec(content);
+  if (shebang) {
+    // "#!/usr/bin/env python3" names the interpreter in the second word
+    const program = path.basename(shebang[1] ?? '');
+    const interpreter = program === 'env' ? (shebang[2] ?? '') : program;
+    for (const [pattern, language] of INTERPRETERS) {
+      if (pattern.test(interpreter)) return { language, method: 'shebang' };
+    }
+  }
+
+  const head = content.slice(0, 4096);
+  for (const [pattern, language] of CONTENT_HEURISTICS) {
+    if (pattern.test(head)) return { language, method: 'content' };
+  }
+
+  return null;
+}
+
+export class LanguageDetector {
+  private workingDir: string;
+
+  constructor(workingDir?: string) {
+    this.workingDir = workingDir ?? process.cwd();
+  }
+
+  async detect(target = '.'): Promise<LanguageReport> {
+    const relativeTarget = resolveProjectPath(this.workingDir, target);
+    const stat = await fs.stat(path.join(this.workingDir, relativeTarget));
+    const files = stat.isFile()
+      ? [relativeTarget]
+      : await walkFiles(this.workingDir, ['**/*'], { cwd: relativeTarget });
+
+    const report: LanguageReport = { files: [], languages: [], unknown: 0 };
+    const counts = new Map<string, number>();
+    for (const file of files) {
+      const detection = await detectLanguage(file, undefined, this.workingDir);
+      if (!detection) {
+        report.unknown++;
+        continue;
+      }
+      report.files.push({ file, ...detection });
+      counts.set(detection.language, (counts.get(detection.language) ?? 0) + 1);
+    }
+
+    report.languages = [...counts.entries()]
+      .map(([language, count]) => ({ language, files: count }))
+      .sort((a, b) => b.files - a.files || a.language.localeCompare(b.language));
+
+    return report;
+  }
+
+  formatReport(report: LanguageReport): string {
+    if (report.files.length === 1 && report.unknown === 0) {
+      const [only] = report.files;
+      return only ? `${only.file}: ${only.language} (by ${only.method})` : '';
+    }
+
+    if (report.files.length === 0) {
+      return report.unknown > 0
+        ? `No recognized languages (${report.unknown} unknown file(s)).`
+        : 'No files found.';
+    }
+
+    let output = `Languages (${report.files.length} file(s)):\n`;
+    for (const { language, files } of report.languages) {
+      output += `  ${language.padEnd(12)} ${files}\n`;
+    }
+    if (report.unknown > 0) {
+      output += `  ${'unknown'.padEnd(12)} ${report.unknown}\n`;
+    }
+
+    // Files not identified by extension are the ones worth double-checking
+    const inferred = report.files.filter(f => f.method !== 'extension');
+    if (inferred.length > 0) {
+      output += '\nDetected without a known extension:\n';
+      for (const f of inferred) {
+        output += `  ${f.file}: ${f.language} (by ${f.method})\n`;
+      }
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.workingDir = dir;
+  }
+}
@@ -0,0 +1,111 @@
+/**
+ * Logger
+ * Leveled diagnostics on stderr, as text or JSON lines. Never stdout: on the
+ * stdio transport that stream carries the protocol.
+ */
+
+export const LOG_LEVELS = ['debug', 'info', 'warn', 'error'] as const;
+
+export type LogLevel = (typeof LOG_LEVELS)[number];
+
+export const LOG_FORMATS = ['text', 'json'] as const;
+
+export type LogFormat = (typeof LOG_FORMATS)[number];
+
+export type LogFields = Record<string, unknown>;
+
+export interface LoggerOptions {
+  level?: LogLevel | undefined;
+  format?: LogFormat | undefined;
+  write?: ((line: string) => void) | undefined; // Defaults to stderr
+}
+
+export const DEFAULT_LOG_LEVEL: LogLevel = 'info';
+
+export class Logger {
+  private level: LogLevel = DEFAULT_LOG_LEVEL;
+  private format: LogFormat = 'text';
+  private write: (line: string) => void = line => process.stderr.write(line);
+
+  constructor(options: LoggerOptions = {}) {
+    this.configure(options);
+  }
+
+  /**
+   * Change level, format, or destination; options left out keep their value
+   */
+  configure(options: LoggerOptions): void {
+    if (options.level) this.level = options.level;
+    if (options.format) this.format = options.format;
+    if (options.write) this.write = options.write;
+  }
+
+  isEnabled(level: LogLevel): boolean {
+    return LOG_LEVELS.indexOf(level) >= LOG_LEVELS.indexOf(this.level);
+  }
+
+  debug(message: string, fields?: LogFields): void {
+    this.log('debug', message, fields);
+  }
+
+  info(message: string, fields?: LogFields): void {
+    this.log('info', message, fields);
+  }
+
+  warn(message: string, fields?: LogFields): void {
+    this.log('warn', message, fields);
+  }
+
+  error(message: string, fields?: LogFields): void {
+    this.log('error', message, fields);
+  }
+
+  log(level: LogLevel, message: string, fields: LogFields = {}): void {
+    if (!this.isEnabled(level)) return;
+    const time = new Date().toISOString();
+
+    if (this.format === 'json') {
+      const entry = { time, level, msg: message, ...normalize(fields) };
+      this.write(`${JSON.stringify(entry)}\n`);
+      return;
+    }
+
+    // text: 2024-05-01T12:00:00.000Z INFO tool call tool=eng_symbols durationMs=12
+    const pairs = Object.entries(normalize(fields))
+      .filter(([, value]) => value !== undefined)
+      .map(([key, value]) => `${key}=${formatValue(value)}`);
+    this.write(`${[time, level.toUpperCase(), message, ...pairs].join(' ')}\n`);
+  }
+}
+
+// The server's logger, configured from --log-level and --log-format at startup
+export const logger = new Logger();
+
+/**
+ * Whether a string names a level, for validating configuration
+ */
+export function isLogLevel(value: string): value is LogLevel {
+  return (LOG_LEVELS as readonly string[]).includes(value);
+}
+
+export function isLogFormat(value: string): value is LogFormat {
+  return (LOG_FORMATS as readonly string[]).includes(value);
+}
+
+/**
+ * Errors as their message, since JSON.stringify turns them into {}
+ */
+function normalize(fields: LogFields): LogFields {
+  const normalized: LogFields = {};
+  for (const [key, value] of Object.entries(fields)) {
+    normalized[key] = value instanceof Error ? value.message : value;
+  }
+  return normalized;
+}
+
+function formatValue(value: unknown): string {
+  if (typeof value === 'string') {
+    return /^[^\s"=]+$/.test(value) ? value : JSON.stringify(value);
+  }
+  return typeof value === 'object' ? JSON.stringify(value) : String(value);
+}
@@ -0,0 +1,65 @@
+/**
+ * LRU Cache
+ * Bounded in-memory map that evicts the least recently used entry
+ */
+
+export interface CacheStats {
+  hits: number;
+  misses: number;
+  evictions: number;
+  size: number;
+  maxEntries: number;
+}
+
+export class LruCache<K, V> {
+  // Map iteration order is insertion order, so the first key is the oldest
+  private entries = new Map<K, V>();
+  private maxEntries: number;
+  private hits = 0;
+  private misses = 0;
+  private evictions = 0;
+
+  /**
+   * A maxEntries of 0 disables the cache: every lookup misses
+   */
+  constructor(maxEntries: number) {
+    this.maxEntries = Math.max(0, Math.floor(maxEntries));
+  }
+
+  get(key: K): V | undefined {
+    const value = this.entries.get(key);
+    if (value === undefined) {
+      this.misses++;
+      return undefined;
+    }
+
+    this.hits++;
+    this.entries.delete(key);
+    this.entries.set(key, value);
+    return value;
+  }
+
+  set(key: K, value: V): void {
+    if (this.maxEntries === 0) {
+      return;
+    }
+
+    this.entries.delete(key);
+    this.entries.set(key, value);
+    for (const oldest of this.entries.keys()) {
+      if (this.entries.size <= this.maxEntries) break;
+      this.entries.delete(oldest);
+      this.evictions++;
+    }
+  }
+
+  stats(): CacheStats {
+    return {
+      hits: this.hits,
+      misses: this.misses,
+      evictions: this.evictions,
+      size: this.entries.size,
+      maxEntries: this.maxEntries,
+    };
+  }
+}
@@ -0,0 +1,197 @@
+/**
+ * Metrics
+ * Per-tool call counts, errors, and latency histograms, plus index and parse
+ * cache gauges, in the Prometheus text format on an HTTP endpoint of their
+ * own, whichever transport serves MCP
+ */
+
+import * as http from 'http';
+
+export const DEFAULT_METRICS_HOST = '127.0.0.1';
+export const METRICS_PATH = '/metrics';
+
+// Upper bounds in seconds, from cached lookups to whole-project scans
+const LATENCY_BUCKETS = [0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120];
+
+export type CallOutcome = 'ok' | 'error' | 'timeout' | 'cancelled';
+
+export interface RootGauges {
+  root: string;
+  files: number; // Indexed files
+  symbols: number;
+  cacheHits: number; // Parse cache lookups since start that hit
+  cacheMisses: number;
+}
+
+export interface ServerGauges {
+  inFlight: number; // Tool calls running now
+  queued: number; // Calls waiting for an analysis slot
+  roots: RootGauges[];
+}
+
+export interface MetricsOptions {
+  host?: string | undefined;
+  port: number; // 0 = any free port
+}
+
+interface Histogram {
+  buckets: number[]; // Per LATENCY_BUCKETS entry, not cumulative
+  sum: number;
+  count: number;
+}
+
+export class ToolMetrics {
+  private calls = new Map<string, number>(); // Keyed by tool\0outcome
+  private errors = new Map<string, number>(); // Keyed by tool\0code
+  private latency = new Map<string, Histogram>();
+  private httpServer: http.Server | undefined;
+  private tools: Set<string>;
+
+  /**
+   * knownTools are the names kept as label values; any other name a client
+   * sends is counted as "unknown", so made-up names can't grow the series
+   */
+  constructor(knownTools: Iterable<string>) {
+    this.tools = new Set(knownTools);
+  }
+
+  record(name: string, durationMs: number, outcome: CallOutcome, code?: string): void {
+    const tool = this.tools.has(name) ? name : 'unknown';
+    increment(this.calls, `${tool}\0${outcome}`);
+    if (outcome !== 'ok') increment(this.errors, `${tool}\0${code ?? outcome.toUpperCase()}`);
+
+    const histogram = this.latency.get(tool) ?? {
+      buckets: LATENCY_BUCKETS.map(() => 0),
+      sum: 0,
+      count: 0,
+    };
+    const seconds = durationMs / 1000;
+    const bucket = LATENCY_BUCKETS.findIndex(bound => seconds <= bound);
+    if (bucket !== -1) histogram.buckets[bucket] = (histogram.buckets[bucket] ?? 0) + 1;
+    histogram.sum += seconds;
+    histogram.count++;
+    this.latency.set(tool, histogram);
+  }
+
+  /**
+   * Every metric in the Prometheus text exposition format (version 0.0.4)
+   */
+  render(gauges: ServerGauges): string {
+    const lines: string[] = [];
+    const family = (name: string, type: string, help: string): void => {
+      lines.push(`# HELP ${name} ${help}`, `# TYPE ${name} ${type}`);
+    };
+
+    family('mcp_tool_calls_total', 'counter', 'Tool calls by tool and outcome');
+    for (const [key, count] of sorted(this.calls)) {
+      const [tool = '', outcome = ''] = key.split('\0');
+      lines.push(`mcp_tool_calls_total${labels({ tool, outcome })} ${count}`);
+    }
+
+    family('mcp_tool_errors_total', 'counter', 'Failed tool calls by tool and error code');
+    for (const [key, count] of sorted(this.errors)) {
+      const [tool = '', code = ''] = key.split('\0');
+      lines.push(`mcp_tool_errors_total${labels({ tool, code })} ${count}`);
+    }
+
+    family('mcp_tool_duration_seconds', 'histogram', 'Tool call latency');
+    for (const [tool, histogram] of sorted(this.latency)) {
+      let cumulative = 0;
+      LATENCY_BUCKETS.forEach((bound, i) => {
+        cumulative += histogram.buckets[i] ?? 0;
+        const le = String(bound);
+        lines.push(`mcp_tool_duration_seconds_bucket${labels({ tool, le })} ${cumulative}`);
+      });
+      lines.push(
+        `mcp_tool_duration_seconds_bucket${labels({ tool, le: '+Inf' })} ${histogram.count}`,
+        `mcp_tool_duration_seconds_sum${labels({ tool })} ${histogram.sum}`,
+        `mcp_tool_duration_seconds_count${labels({ tool })} ${histogram.count}`
+      );
+    }
+
+    family('mcp_tool_calls_in_flight', 'gauge', 'Tool calls running now');
+    lines.push(`mcp_tool_calls_in_flight ${gauges.inFlight}`);
+    family('mcp_analysis_queue_length', 'gauge', 'Calls waiting for an analysis slot');
+    lines.push(`mcp_analysis_queue_length ${gauges.queued}`);
+
+    const perRoot = (
+      name: string,
+      type: string,
+      help: string,
+      value: (root: RootGauges) => number
+    ): void => {
+      family(name, type, help);
+      for (const root of gauges.roots) {
+        lines.push(`${name}${labels({ root: root.root })} ${value(root)}`);
+      }
+    };
+    perRoot('mcp_index_files', 'gauge', 'Files in the symbol index', r => r.files);
+    perRoot('mcp_index_symbols', 'gauge', 'Symbols in the symbol index', r => r.symbols);
+    perRoot('mcp_parse_cache_hits_total', 'counter', 'Parse cache hits', r => r.cacheHits);
+    perRoot('mcp_parse_cache_misses_total', 'counter', 'Parse cache misses', r => r.cacheMisses);
+    perRoot('mcp_parse_cache_hit_ratio', 'gauge', 'Parse cache hits per lookup', r => {
+      const lookups = r.cacheHits + r.cacheMisses;
+      return lookups > 0 ? r.cacheHits / lookups : 0;
+    });
+
+    return lines.join('\n') + '\n';
+  }
+
+  /**
+   * Serve GET /metrics; collect is called on each scrape for the gauges
+   */
+  async listen(
+    options: MetricsOptions,
+    collect: () => ServerGauges
+  ): Promise<{ host: string; port: number }> {
+    const host = options.host ?? DEFAULT_METRICS_HOST;
+    const httpServer = (this.httpServer = http.createServer((req, res) => {
+      const { pathname } = new URL(req.url ?? '/', 'http://localhost');
+      if (pathname !== METRICS_PATH) {
+        res.writeHead(404, { 'Content-Type': 'text/plain' });
+        res.end(`Not found. Metrics are served at ${METRICS_PATH}`);
+        return;
+      }
+      if (req.method !== 'GET') {
+        res.writeHead(405, { Allow: 'GET' });
+        res.end();
+        return;
+      }
+      res.writeHead(200, { 'Content-Type': 'text/plain; version=0.0.4; charset=utf-8' });
+      res.end(this.render(collect()));
+    }));
+
+    await new Promise<void>((resolve, reject) => {
+      httpServer.once('error', reject);
+      httpServer.listen(options.port, host, () => resolve());
+    });
+    const address = httpServer.address();
+    return { host, port: typeof address === 'object' && address ? address.port : 0 };
+  }
+
+  async close(): Promise<void> {
+    const httpServer = this.httpServer;
+    if (!httpServer) return;
+    await new Promise<void>(resolve => {
+      httpServer.close(() => resolve());
+      httpServer.closeAllConnections();
+    });
+  }
+}
+
+function increment(counts: Map<string, number>, key: string): void {
+  counts.set(key, (counts.get(key) ?? 0) + 1);
+}
+
+function sorted<V>(map: Map<string, V>): Array<[string, V]> {
+  return [...map.entries()].sort(([a], [b]) => a.localeCompare(b));
+}
+
+// Label values escape backslashes, quotes, and line breaks
+function labels(values: Record<string, string>): string {
+  const pairs = Object.entries(values).map(([name, value]) => {
+    const escaped = value.replace(/[\\"\n]/g, ch => (ch === '\n' ? '\\n' : `\\${ch}`));
+    return `${name}="${escaped}"`;
+  });
+  return `{${pairs.join(',')}}`;
+}
@@ -0,0 +1,117 @@
+/**
+ * Pagination
+ * Opaque cursors for fetching long result lists in pages
+ */
+
+import * as crypto from 'crypto';
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { ToolError } from './errors.js';
+
+export const DEFAULT_PAGE_SIZE = 100;
+
+export interface PageOptions {
+  cursor?: string | undefined; // nextCursor from the previous page
+  pageSize?: number | undefined;
+}
+
+export interface Page<T> {
+  items: T[];
+  nextCursor?: string | undefined; // Set when more results follow
+}
+
+export interface PageWindow {
+  offset: number;
+  pageSize: number;
+}
+
+interface CursorState {
+  offset: number;
+  query: string; // Fingerprint of the query and index state the cursor belongs to
+}
+
+/**
+ * Whether the caller asked for paged results
+ */
+export function isPaginated(options: PageOptions | undefined): boolean {
+  return options?.cursor !== undefined || options?.pageSize !== undefined;
+}
+
+/**
+ * Short stable hash of whatever determines a result list: the query
+ * arguments and, for index-backed tools, the index fingerprint
+ */
+export function queryFingerprint(...parts: unknown[]): string {
+  return crypto.createHash('sha1').update(JSON.stringify(parts)).digest('hex').slice(0, 16);
+}
+
+/**
+ * Short hash of the size and modification time of each file, for tools that
+ * read files directly rather than through the index; a file that is missing
+ * or unreadable counts as its own state
+ */
+export async function fileStateFingerprint(workingDir: string, files: string[]): Promise<string> {
+  const hash = crypto.createHash('sha1');
+  for (const file of [...files].sort()) {
+    const stat = await fs.stat(path.join(workingDir, file)).catch(() => undefined);
+    hash.update(`${file}:${stat ? `${stat.size}:${stat.mtimeMs}` : '-'}\n`);
+  }
+  return hash.digest('hex').slice(0, 16);
+}
+
+/**
+ * Offset and size of the requested page. A cursor issued for a different
+ * query, or before the index changed, is rejected rather than silently
+ * returning shifted results.
+ */
+export function pageWindow(options: PageOptions, fingerprint: string): PageWindow {
+  const pageSize = Math.max(1, Math.floor(options.pageSize ?? DEFAULT_PAGE_SIZE));
+  if (options.cursor === undefined) {
+    return { offset: 0, pageSize };
+  }
+
+  const state = decodeCursor(options.cursor);
+  if (state.query !== fingerprint) {
+    throw new ToolError(
+      'FAILED_PRECONDITION',
+      'Cursor is stale: the query, the index, or the files changed since it was issued; start again without a cursor'
+    );
+  }
+  return { offset: state.offset, pageSize };
+}
+
+/**
+ * One page of a complete result list
+ */
+export function paginate<T>(items: T[], options: PageOptions, fingerprint: string): Page<T> {
+  const { offset, pageSize } = pageWindow(options, fingerprint);
+  const end = offset + pageSize;
+  const page: Page<T> = { items: items.slice(offset, end) };
+  if (end < items.length) {
+    page.nextCursor = encodeCursor({ offset: end, query: fingerprint });
+  }
+  return page;
+}
+
+/**
+ * Trailer for text output pointing at the next page
+ */
+export function formatNextCursor(nextCursor: string | undefined): string {
+  return nextCursor ? `\n\nMore results: pass cursor=${nextCursor}` : '';
+}
+
+function encodeCursor(state: CursorState): string {
+  return Buffer.from(JSON.stringify(state)).toString('base64url');
+}
+
+function decodeCursor(cursor: string): CursorState {
+  try {
+    const state = JSON.parse(Buffer.from(cursor, 'base64url').toString('utf-8')) as CursorState;
+    if (Number.isInteger(state.offset) && state.offset >= 0 && typeof state.query === 'string') {
+      return state;
+    }
+  } catch {
+    // Fall through to the error below
+  }
+  throw new ToolError('INVALID_ARGUMENT', `Invalid cursor: ${cursor}`);
+}
@@ -0,0 +1,75 @@
+/**
+ * Process
+ * Runs external tools (formatters, linters, git clones) without a shell
+ */
+
+import { spawn } from 'child_process';
+import * as path from 'path';
+
+// Tools that hang (waiting on a lock, a network fetch) are killed
+export const DEFAULT_PROCESS_TIMEOUT_MS = 30_000;
+
+export interface ProcessResult {
+  code: number;
+  stdout: string;
+  stderr: string;
+}
+
+export interface ProcessOptions {
+  cwd: string;
+  input?: string | undefined; // Written to stdin, which is then closed
+  timeoutMs?: number | undefined;
+  env?: Record<string, string> | undefined; // Added to the server's environment
+  signal?: AbortSignal | undefined; // Kills the process when aborted
+}
+
+/**
+ * Run a command with arguments passed directly, so user input can't inject
+ * shell syntax. Resolves undefined when the command isn't installed.
+ */
+export function runProcess(
+  command: string,
+  args: string[],
+  options: ProcessOptions
+): Promise<ProcessResult | undefined> {
+  const timeoutMs = options.timeoutMs ?? DEFAULT_PROCESS_TIMEOUT_MS;
+
+  return new Promise((resolve, reject) => {
+    const proc = spawn(command, args, {
+      cwd: options.cwd,
+      env: options.env ? { ...process.env, ...options.env } : process.env,
+      signal: options.signal,
+    });
+
+    let stdout = '';
+    let stderr = '';
+
+    const timeout = setTimeout(() => {
+      proc.kill();
+      reject(new Error(`${path.basename(command)} timed out after ${timeoutMs}ms`));
+    }, timeoutMs);
+
+    proc.stdout.on('data', (data: Buffer) => {
+      stdout += data.toString();
+    });
+
+    proc.stderr.on('data', (data: Buffer) => {
+      stderr += data.toString();
+    });
+
+    proc.on('error', (error: NodeJS.ErrnoException) => {
+      clearTimeout(timeout);
+      if (error.code === 'ENOENT') resolve(undefined);
+      else reject(error);
+    });
+
+    proc.on('close', code => {
+      clearTimeout(timeout);
+      resolve({ code: code ?? 1, stdout, stderr });
+    });
+
+    // A tool that exits early (bad flags) closes stdin; the exit code reports it
+    proc.stdin.on('error', () => undefined);
+    proc.stdin.end(options.input ?? '');
+  });
+}
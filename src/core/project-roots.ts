@@ -0,0 +1,115 @@
+/**
+ * Project Roots
+ * Named project directories served by one server instance
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { ToolError } from './errors.js';
+
+export interface ProjectRoot {
+  name: string;
+  path: string; // Absolute directory
+}
+
+// Root names appear in tool arguments and results
+const ROOT_NAME = /^[\w.-]+$/;
+
+export class ProjectRoots<T> {
+  private roots = new Map<string, { root: ProjectRoot; project: T }>();
+  private create: (root: ProjectRoot) => T;
+
+  constructor(create: (root: ProjectRoot) => T) {
+    this.create = create;
+  }
+
+  /**
+   * Register a directory under a name (default: its basename) and build its
+   * project with the factory given to the constructor
+   */
+  async add(dir: string, name?: string): Promise<ProjectRoot> {
+    const rootPath = path.resolve(dir);
+    const rootName = name ?? path.basename(rootPath);
+
+    if (!ROOT_NAME.test(rootName)) {
+      throw new ToolError(
+        'INVALID_ARGUMENT',
+        `Invalid root name "${rootName}": use letters, digits, ".", "_", or "-"`
+      );
+    }
+    if (this.roots.has(rootName)) {
+      throw new ToolError('INVALID_ARGUMENT', `Root "${rootName}" is already registered`);
+    }
+    const existing = this.list().find(r => r.path === rootPath);
+    if (existing) {
+      throw new ToolError(
+        'INVALID_ARGUMENT',
+        `${rootPath} is already registered as "${existing.name}"`,
+        rootPath
+      );
+    }
+
+    const stat = await fs.stat(rootPath).catch(() => undefined);
+    if (!stat?.isDirectory()) {
+      throw new ToolError('FILE_NOT_FOUND', `Not a directory: ${rootPath}`, rootPath);
+    }
+
+    const root = { name: rootName, path: rootPath };
+    this.roots.set(rootName, { root, project: this.create(root) });
+    return root;
+  }
+
+  get(name: string): T {
+    const entry = this.roots.get(name);
+    if (!entry) {
+      const known = [...this.roots.keys()].join(', ');
+      throw new ToolError(
+        'ROOT_NOT_REGISTERED',
+        `Unknown root "${name}". Registered roots: ${known}`
+      );
+    }
+    return entry.project;
+  }
+
+  /**
+   * The first registered root, used when a tool is not given one
+   */
+  primary(): T {
+    const first = this.roots.values().next();
+    if (first.done) {
+      throw new ToolError('ROOT_NOT_REGISTERED', 'No project roots registered');
+    }
+    return first.value.project;
+  }
+
+  /**
+   * The named root, or every root in registration order
+   */
+  select(name?: string): T[] {
+    return name ? [this.get(name)] : [...this.roots.values()].map(entry => entry.project);
+  }
+
+  list(): ProjectRoot[] {
+    return [...this.roots.values()].map(entry => entry.root);
+  }
+
+  formatRoots(): string {
+    const roots = this.list();
+    let output = `${roots.length} project root(s):\n\n`;
+    roots.forEach((root, i) => {
+      output += `  ${root.name}  ${root.path}${i === 0 ? '  (default)' : ''}\n`;
+    });
+    return output.trimEnd();
+  }
+}
+
+/**
+ * --root value: "name=dir", or a bare directory named after its basename
+ */
+export function parseRootSpec(spec: string): { path: string; name?: string } {
+  const separator = spec.indexOf('=');
+  if (separator > 0 && ROOT_NAME.test(spec.slice(0, separator))) {
+    return { name: spec.slice(0, separator), path: spec.slice(separator + 1) };
+  }
+  return { path: spec };
+}
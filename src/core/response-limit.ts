@@ -0,0 +1,155 @@
+/**
+ * Response Limit
+ * Caps the size of tool results: lists are cut short and flagged instead of
+ * returning more than a client can take
+ */
+
+import type { CallToolResult } from '@modelcontextprotocol/sdk/types.js';
+
+export const DEFAULT_MAX_RESPONSE_BYTES = 1024 * 1024;
+
+export interface Truncation {
+  truncated: true;
+  omitted: number; // List items (JSON) or lines (text) left out
+  maxResponseBytes: number;
+}
+
+// Room kept for the note appended to truncated text
+const NOTE_BYTES = 200;
+
+/**
+ * The result, or a copy that fits in maxBytes of text (0 = no limit). JSON
+ * output loses items from the end of its largest lists, and an object gains
+ * truncated: true and omitted; text output loses trailing lines and gains a
+ * note. Either way _meta.truncation says how much was left out. Errors pass
+ * through untouched.
+ */
+export function limitResponse(result: CallToolResult, maxBytes: number): CallToolResult {
+  if (maxBytes === 0 || result.isError) return result;
+
+  const texts = result.content.map(item => (item.type === 'text' ? item.text : ''));
+  const total = texts.reduce((sum, text) => sum + byteLength(text), 0);
+  if (total <= maxBytes) return result;
+
+  // Cut the largest text; the others (scope notes, cursors) are small
+  let largest = 0;
+  texts.forEach((text, i) => {
+    if (byteLength(text) > byteLength(texts[largest] ?? '')) largest = i;
+  });
+  const text = texts[largest] ?? '';
+  const budget = Math.max(0, maxBytes - (total - byteLength(text)));
+
+  const cut = truncateJson(text, budget) ?? truncateText(text, budget - NOTE_BYTES);
+  const content = result.content.map((item, i) =>
+    i === largest ? { type: 'text' as const, text: cut.text } : item
+  );
+  if (cut.note) content.push({ type: 'text', text: cut.note });
+
+  const truncation: Truncation = {
+    truncated: true,
+    omitted: cut.omitted,
+    maxResponseBytes: maxBytes,
+  };
+  return { ...result, content, _meta: { ...result._meta, truncation } };
+}
+
+interface Cut {
+  text: string;
+  omitted: number;
+  note?: string | undefined;
+}
+
+/**
+ * JSON text with items dropped from the end of its largest arrays until it
+ * fits; undefined when the text isn't JSON or no list can make it fit
+ */
+function truncateJson(text: string, budget: number): Cut | undefined {
+  const trimmed = text.trimStart();
+  if (!trimmed.startsWith('{') && !trimmed.startsWith('[')) return undefined;
+  let value: unknown;
+  try {
+    value = JSON.parse(text);
+  } catch {
+    return undefined;
+  }
+
+  let omitted = 0;
+  const serialize = (): string => {
+    if (omitted > 0 && isObject(value)) {
+      value.truncated = true;
+      value.omitted = omitted;
+    }
+    return JSON.stringify(value, null, 2);
+  };
+
+  let output = serialize();
+  while (byteLength(output) > budget) {
+    const list = largestArray(value);
+    if (!list) return undefined;
+
+    // Binary search for the most items of this list that fit
+    const items = [...list];
+    const before = omitted;
+    let low = 0;
+    let high = items.length - 1;
+    while (low < high) {
+      const keep = Math.ceil((low + high) / 2);
+      list.splice(0, list.length, ...items.slice(0, keep));
+      omitted = before + items.length - keep;
+      if (byteLength(serialize()) <= budget) low = keep;
+      else high = keep - 1;
+    }
+    list.splice(0, list.length, ...items.slice(0, low));
+    omitted = before + items.length - low;
+    output = serialize();
+  }
+
+  return { text: output, omitted };
+}
+
+/**
+ * The non-empty array with the longest serialization anywhere in a JSON value
+ */
+function largestArray(value: unknown): unknown[] | undefined {
+  let best: unknown[] | undefined;
+  let bestSize = 0;
+  const visit = (node: unknown): void => {
+    if (Array.isArray(node)) {
+      const size = node.length > 0 ? byteLength(JSON.stringify(node)) : 0;
+      if (size > bestSize) {
+        best = node;
+        bestSize = size;
+      }
+      node.forEach(visit);
+    } else if (isObject(node)) {
+      Object.values(node).forEach(visit);
+    }
+  };
+  visit(value);
+  return best;
+}
+
+/**
+ * Text cut at the last line break that fits, with a note on what was left out
+ */
+function truncateText(text: string, budget: number): Cut {
+  const head = Buffer.from(text, 'utf-8').subarray(0, Math.max(0, budget)).toString('utf-8');
+  // A split multi-byte character decodes as U+FFFD; a line break before it is safe
+  const lineEnd = head.lastIndexOf('\n');
+  const kept = lineEnd === -1 ? '' : head.slice(0, lineEnd);
+  const omitted = text.slice(kept.length).split('\n').length - (kept ? 1 : 0);
+
+  return {
+    text: kept,
+    omitted,
+    note: `Truncated: ${omitted} line(s) omitted to stay under the response limit; narrow the path or query, or page with pageSize`,
+  };
+}
+
+function isObject(value: unknown): value is Record<string, unknown> {
+  return typeof value === 'object' && value !== null && !Array.isArray(value);
+}
+
+function byteLength(text: string): number {
+  return Buffer.byteLength(text, 'utf-8');
+}
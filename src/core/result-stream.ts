@@ -0,0 +1,70 @@
+/**
+ * Result Stream
+ * Sends list results to the client in batches over MCP progress notifications
+ */
+
+import type { ProgressToken, ServerNotification } from '@modelcontextprotocol/sdk/types.js';
+
+export type NotificationSender = (notification: ServerNotification) => Promise<void>;
+
+export interface StreamSummary {
+  complete: true;
+  total: number;
+  batches: number;
+}
+
+export const DEFAULT_BATCH_SIZE = 200;
+
+/**
+ * Buffers items and sends each full batch as a progress notification whose
+ * message is JSON: {"batch": n, "items": [...]}. The tool's final response
+ * carries the summary from end(), marking the stream complete.
+ */
+export class ResultStream<T> {
+  private sendNotification: NotificationSender;
+  private progressToken: ProgressToken;
+  private batchSize: number;
+  private pending: T[] = [];
+  private total = 0;
+  private batches = 0;
+
+  constructor(
+    sendNotification: NotificationSender,
+    progressToken: ProgressToken,
+    batchSize = DEFAULT_BATCH_SIZE
+  ) {
+    this.sendNotification = sendNotification;
+    this.progressToken = progressToken;
+    this.batchSize = Math.max(1, batchSize);
+  }
+
+  async push(items: T[]): Promise<void> {
+    this.pending.push(...items);
+    while (this.pending.length >= this.batchSize) {
+      await this.flush(this.batchSize);
+    }
+  }
+
+  async end(): Promise<StreamSummary> {
+    if (this.pending.length > 0) {
+      await this.flush(this.pending.length);
+    }
+    return { complete: true, total: this.total, batches: this.batches };
+  }
+
+  private async flush(count: number): Promise<void> {
+    const items = this.pending.splice(0, count);
+    this.batches++;
+    this.total += items.length;
+
+    // progress must increase with every notification; the running count does
+    await this.sendNotification({
+      method: 'notifications/progress',
+      params: {
+        progressToken: this.progressToken,
+        progress: this.total,
+        message: JSON.stringify({ batch: this.batches, items }),
+      },
+    });
+  }
+}
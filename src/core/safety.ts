@@ -3,8 +3,10 @@
  * Prevents dangerous auto-modifications
  */
 
+import * as crypto from 'crypto';
 import * as fs from 'fs/promises';
 import * as path from 'path';
+import { ToolError } from './errors.js';
 
 /**
  * Protected paths that should never be auto-modified
@@ -143,7 +145,7 @@ export class AtomicFileWriter {
     const fullPath = path.join(this.workingDir, filePath);
 
     try {
-      await fs.writeFile(fullPath, content, 'utf-8');
+      await writeFileAtomic(fullPath, content);
       if (isNewFile) {
         this.createdFiles.push(fullPath);
       }
@@ -162,7 +164,7 @@ export class AtomicFileWriter {
     // Restore backups
     for (const backup of this.backups) {
       try {
-        await fs.writeFile(backup.path, backup.originalContent, 'utf-8');
+        await writeFileAtomic(backup.path, backup.originalContent);
         restored.push(backup.path);
         // Remove backup file
         try {
@@ -209,3 +211,139 @@ export class AtomicFileWriter {
     return this.backups.map(b => path.relative(this.workingDir, b.backupPath));
   }
 }
+
+export interface FileChange {
+  file: string; // Relative to the working directory
+  content: string;
+}
+
+export interface WriteResult {
+  modified: string[]; // Files whose content changed, in the order given
+  backups: string[]; // <file>.bak copies of the originals, when asked for
+}
+
+/**
+ * Replace a file by renaming a temp file beside it over it, so readers see
+ * the old content or the new, never a partial write. A file that exists
+ * keeps its permissions.
+ */
+export async function writeFileAtomic(fullPath: string, content: string): Promise<void> {
+  const temp = tempPath(fullPath);
+  try {
+    const mode = await fs.stat(fullPath).then(s => s.mode & 0o7777, () => undefined);
+    await fs.writeFile(temp, content, 'utf-8');
+    if (mode !== undefined) await fs.chmod(temp, mode);
+    await fs.rename(temp, fullPath);
+  } catch (error) {
+    await fs.rm(temp, { force: true });
+    throw error;
+  }
+}
+
+/**
+ * Write several existing files all or nothing. Every new content is staged
+ * in a temp file first, so a full disk or a permission problem fails before
+ * any file is touched; if moving one into place still fails, the files
+ * already replaced get their original content back and the backups made
+ * along the way are removed. Files whose content wouldn't change are left
+ * alone. A backup never replaces an existing file: when <file>.bak is taken,
+ * it goes to <file>.bak.1, .bak.2, ...
+ */
+export async function writeFilesAtomically(
+  workingDir: string,
+  changes: FileChange[],
+  options: { backup?: boolean | undefined } = {}
+): Promise<WriteResult> {
+  const staged: Array<{ file: string; fullPath: string; temp: string; original: string }> = [];
+  const backups: string[] = [];
+  const backupOf = new Map<string, string>(); // File -> its backup, relative to workingDir
+  const discard = async (): Promise<void> => {
+    await Promise.all(staged.map(s => fs.rm(s.temp, { force: true })));
+  };
+
+  try {
+    for (const change of changes) {
+      const fullPath = path.join(workingDir, change.file);
+      const original = await fs.readFile(fullPath, 'utf-8');
+      if (original === change.content) continue;
+
+      const temp = tempPath(fullPath);
+      staged.push({ file: change.file, fullPath, temp, original });
+      await fs.writeFile(temp, change.content, 'utf-8');
+      await fs.chmod(temp, (await fs.stat(fullPath)).mode & 0o7777);
+    }
+    if (options.backup) {
+      for (const { file, fullPath, original } of staged) {
+        const backup = `${file}${await writeBackup(fullPath, original)}`;
+        backups.push(backup);
+        backupOf.set(file, backup);
+      }
+    }
+  } catch (error) {
+    await discard();
+    await removeBackups(workingDir, backups);
+    throw error;
+  }
+
+  for (const [i, { file, temp, fullPath }] of staged.entries()) {
+    try {
+      await fs.rename(temp, fullPath);
+    } catch (error) {
+      await discard();
+      const failed: string[] = [];
+      for (const replaced of staged.slice(0, i)) {
+        try {
+          await writeFileAtomic(replaced.fullPath, replaced.original);
+        } catch {
+          failed.push(replaced.file);
+        }
+      }
+      // A file that couldn't be restored keeps its .bak, the only copy of its original
+      await removeBackups(
+        workingDir,
+        backups.filter(b => !failed.some(f => backupOf.get(f) === b))
+      );
+      const rollback =
+        failed.length > 0
+          ? `could not restore ${failed.join(', ')}`
+          : `restored ${i} file(s) already written`;
+      throw new ToolError(
+        'INTERNAL_ERROR',
+        `Failed to write ${file} (${rollback}): ${String(error)}`,
+        file
+      );
+    }
+  }
+
+  return { modified: staged.map(s => s.file), backups };
+}
+
+/**
+ * Write content to the first of <fullPath>.bak, .bak.1, .bak.2, ... that
+ * doesn't exist yet, so a backup the user keeps is never overwritten, and
+ * return the suffix used
+ */
+async function writeBackup(fullPath: string, content: string): Promise<string> {
+  for (let n = 0; ; n++) {
+    const suffix = n === 0 ? '.bak' : `.bak.${n}`;
+    try {
+      await fs.writeFile(`${fullPath}${suffix}`, content, { encoding: 'utf-8', flag: 'wx' });
+      return suffix;
+    } catch (error) {
+      if ((error as NodeJS.ErrnoException).code !== 'EEXIST') throw error;
+    }
+  }
+}
+
+async function removeBackups(workingDir: string, backups: string[]): Promise<void> {
+  await Promise.all(backups.map(b => fs.rm(path.join(workingDir, b), { force: true })));
+}
+
+/**
+ * A hidden, unique file name in the same directory, so the final rename
+ * stays on one filesystem
+ */
+function tempPath(fullPath: string): string {
+  const name = `.${path.basename(fullPath)}.${crypto.randomBytes(4).toString('hex')}.tmp`;
+  return path.join(path.dirname(fullPath), name);
+}
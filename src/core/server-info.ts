@@ -0,0 +1,110 @@
+/**
+ * Server Info
+ * Version, build, parsers, and tools of this server, for client feature
+ * detection and bug reports
+ */
+
+import { readFileSync } from 'fs';
+import { fileURLToPath } from 'url';
+import type { IndexStats } from '../indexes/symbol-indexer.js';
+import { getParsers } from '../parsers/index.js';
+import { runGit } from './git.js';
+import type { ProjectRoot } from './project-roots.js';
+
+export const SERVER_NAME = 'mcp-engineering-server';
+
+// Package root, from src/core or dist/core
+const PACKAGE_DIR = fileURLToPath(new URL('../..', import.meta.url));
+
+export const SERVER_VERSION = readVersion();
+
+export interface ParserInfo {
+  language: string;
+  version: number;
+  aliases: string[];
+  extensions: string[];
+}
+
+export interface RootInfo extends ProjectRoot {
+  index: IndexStats;
+}
+
+export interface ServerInfo {
+  name: string;
+  version: string;
+  commit?: string | undefined; // Unset when built outside a git checkout
+  node: string;
+  languages: ParserInfo[];
+  tools: string[];
+  roots: RootInfo[];
+}
+
+let commit: Promise<string | undefined> | undefined;
+
+export async function getServerInfo(tools: string[], roots: RootInfo[]): Promise<ServerInfo> {
+  return {
+    name: SERVER_NAME,
+    version: SERVER_VERSION,
+    commit: await buildCommit(),
+    node: process.version,
+    languages: getParsers().map(p => ({
+      language: p.language,
+      version: p.version,
+      aliases: p.aliases ?? [],
+      extensions: p.extensions,
+    })),
+    tools,
+    roots,
+  };
+}
+
+/**
+ * Commit the server runs from: MCP_BUILD_COMMIT when packaging set it,
+ * otherwise HEAD of the checkout it's installed in. Looked up once.
+ */
+function buildCommit(): Promise<string | undefined> {
+  commit ??= (async () => {
+    if (process.env.MCP_BUILD_COMMIT) return process.env.MCP_BUILD_COMMIT;
+    try {
+      const result = await runGit(PACKAGE_DIR, ['rev-parse', 'HEAD']);
+      return result.code === 0 ? result.stdout.trim() : undefined;
+    } catch {
+      // git isn't installed
+      return undefined;
+    }
+  })();
+  return commit;
+}
+
+function readVersion(): string {
+  try {
+    const manifest = JSON.parse(readFileSync(`${PACKAGE_DIR}/package.json`, 'utf-8')) as {
+      version?: string;
+    };
+    return manifest.version ?? '0.0.0';
+  } catch {
+    return '0.0.0';
+  }
+}
+
+export function formatServerInfo(info: ServerInfo): string {
+  let output = `${info.name} ${info.version}`;
+  output += `${info.commit ? ` (${info.commit.slice(0, 12)})` : ''}, Node ${info.node}\n\n`;
+
+  output += `Languages (${info.languages.length}):\n`;
+  for (const parser of info.languages) {
+    const aliases = parser.aliases.length > 0 ? ` (also ${parser.aliases.join(', ')})` : '';
+    output += `  ${parser.language} v${parser.version}${aliases}  ${parser.extensions.join(' ')}\n`;
+  }
+
+  output += `\nIndex:\n`;
+  for (const root of info.roots) {
+    const { files, symbols, excluded, indexedAt } = root.index;
+    output += `  ${root.name}  ${files} file(s), ${symbols} symbol(s), ${excluded} excluded, `;
+    output += `last indexed ${indexedAt ?? 'never'}\n`;
+  }
+
+  output += `\nTools (${info.tools.length}):\n`;
+  for (const tool of info.tools) output += `  ${tool}\n`;
+  return output.trimEnd();
+}
@@ -60,11 +60,30 @@ async function setupCommands(): Promise<void> {
   }
 }
 import { StdioServerTransport } from '@modelcontextprotocol/sdk/server/stdio.js';
+import { HttpTransportServer, MCP_PATH } from './core/http-transport.js';
+import { METRICS_PATH, ToolMetrics } from './core/metrics.js';
+import type { CallOutcome, ServerGauges } from './core/metrics.js';
 import { CallToolRequestSchema, ListToolsRequestSchema } from '@modelcontextprotocol/sdk/types.js';
+import type {
+  CallToolRequest,
+  CallToolResult,
+  ProgressToken,
+} from '@modelcontextprotocol/sdk/types.js';
 
 import { registerCommands } from './commands/index.js';
+import { describeTool } from './commands/output-schemas.js';
+import {
+  SERVER_NAME,
+  SERVER_VERSION,
+  formatServerInfo,
+  getServerInfo,
+} from './core/server-info.js';
+import { ToolError, describeError } from './core/errors.js';
+import type { ErrorCode, ErrorInfo } from './core/errors.js';
 import { ProjectDetector } from './core/project-detector.js';
 import { ConfigManager } from './core/config.js';
+import { BUFFER_FILE, FileReader } from './core/file-reader.js';
+import { CodeFormatter } from './core/code-formatter.js';
 import { SecurityScanner } from './security/scanner.js';
 import { FunctionIndexer } from './indexes/function-indexer.js';
 import { DuplicateDetector } from './indexes/duplicate-detector.js';
@@ -73,50 +92,683 @@ import { HardwareIndexer } from './indexes/hardware-indexer.js';
 import { DependencyAnalyzer } from './indexes/dependency-graph.js';
 import { RefactorAnalyzer } from './indexes/refactor-analyzer.js';
 import { SimilarityAnalyzer } from './indexes/similarity.js';
+import {
+  SymbolIndexer,
+  docTrimmer,
+  hasDecorator,
+  kindFilter,
+  symbolSorter,
+  toSignature,
+} from './indexes/symbol-indexer.js';
+import { DEFAULT_PARSE_WORKERS, ParsePool } from './indexes/parse-pool.js';
+import { ReferenceFinder } from './indexes/reference-finder.js';
+import type { ReferenceResult } from './indexes/reference-finder.js';
+import { RenamePlanner } from './indexes/rename-planner.js';
+import { TypeInspector } from './indexes/type-inspector.js';
+import { ImplementationFinder } from './indexes/implementation-finder.js';
+import { ComplexityAnalyzer } from './indexes/complexity-analyzer.js';
+import { CloneDetector } from './indexes/clone-detector.js';
+import { CallGraphBuilder } from './indexes/call-graph.js';
+import { RouteFinder } from './indexes/route-finder.js';
+import { TestCatalog } from './indexes/test-catalog.js';
+import { ExampleFinder } from './indexes/example-finder.js';
+import { ReferenceValidator } from './indexes/reference-validator.js';
+import {
+  DEFAULT_SEARCH_LIMIT,
+  searchSymbols,
+  rankMatches,
+  formatMatches,
+  type SymbolMatch,
+} from './indexes/symbol-search.js';
+import { DEFAULT_MAX_RESULTS, TextSearcher } from './indexes/text-search.js';
+import type { TextSearchOptions, TextSearchResult } from './indexes/text-search.js';
+import { MarkerScanner } from './indexes/marker-scanner.js';
+import { ProjectTreeBuilder } from './indexes/project-tree.js';
+import { LineCounter } from './indexes/line-counter.js';
+import { ChangeScope } from './indexes/change-scope.js';
+import { SymbolContextResolver } from './indexes/symbol-context.js';
+import { DefinitionResolver } from './indexes/definition-resolver.js';
+import { ExternalResolver } from './indexes/external-resolver.js';
+import { RemoteRepoCache } from './indexes/remote-symbols.js';
+import { FunctionAnalyzer } from './indexes/function-analyzer.js';
+import { FileOutliner } from './indexes/file-outline.js';
+import { SymbolDiffer } from './indexes/symbol-diff.js';
+import { ComplexityComparer } from './indexes/complexity-diff.js';
+import { ChurnRiskAnalyzer } from './indexes/churn-risk.js';
+import { ApiFingerprinter } from './indexes/api-fingerprint.js';
+import { IndexWatcher } from './indexes/index-watcher.js';
+import { ImportAnalyzer } from './indexes/import-analyzer.js';
+import { RelatedFileFinder } from './indexes/related-files.js';
+import { LanguageDetector } from './core/language-detector.js';
+import { ResultStream } from './core/result-stream.js';
+import { DEFAULT_MAX_RESPONSE_BYTES, limitResponse } from './core/response-limit.js';
+import type { Truncation } from './core/response-limit.js';
+import { DEFAULT_LOG_LEVEL, LOG_LEVELS, isLogFormat, isLogLevel, logger } from './core/logger.js';
+import { ConcurrencyLimiter, ServerBusyError } from './core/concurrency-limiter.js';
+import { fitBatch, parseBatchCalls, runBatch } from './core/batch.js';
+import type { BatchCall } from './core/batch.js';
+import { Deadline, withDeadline } from './core/deadline.js';
+import {
+  DEFAULT_GRACE_PERIOD_MS,
+  InFlightTracker,
+  ShuttingDownError,
+} from './core/graceful-shutdown.js';
+import type { NotificationSender } from './core/result-stream.js';
+import {
+  formatNextCursor,
+  isPaginated,
+  paginate,
+  pageWindow,
+  queryFingerprint,
+} from './core/pagination.js';
+import type { PageOptions } from './core/pagination.js';
+import { ProjectRoots, parseRootSpec } from './core/project-roots.js';
+import type { ProjectRoot } from './core/project-roots.js';
+import type { ChangeScopeOptions, ResolvedScope } from './indexes/change-scope.js';
+import type { ScopeOptions } from './core/file-walker.js';
+import { buildContext } from './core/build-constraints.js';
+import type { BuildOptions } from './core/build-constraints.js';
 import { ValidationPipeline } from './validation/pipeline.js';
 import { ReviewChecker } from './validation/review-checker.js';
+import { DocCommentChecker } from './validation/doc-checker.js';
+import { NamingChecker, type NamingRule } from './validation/naming-checker.js';
+import { DeprecationFinder } from './validation/deprecation-finder.js';
+import { TestGapDetector } from './validation/test-gap-detector.js';
+import { DeadCodeDetector } from './validation/dead-code-detector.js';
+import { GoDiagnostics } from './validation/go-diagnostics.js';
+import type { DiagnosticSeverity } from './validation/go-diagnostics.js';
 import { FeatureManager } from './features/manager.js';
 import { ContextManager } from './sessions/context-manager.js';
 import { SessionCoordinator } from './sessions/coordinator.js';
+import type { ReferenceEntry, SymbolEntry } from './types/index.js';
+
+// --log-level debug|info|warn|error and --log-format text|json: diagnostics on
+// stderr, including a line per tool call at info and its arguments at debug
+const [logLevel = DEFAULT_LOG_LEVEL] = stringOptions(args, '--log-level');
+const [logFormat = 'text'] = stringOptions(args, '--log-format');
+if (!isLogLevel(logLevel)) {
+  console.error(`Invalid --log-level: ${logLevel} (expected ${LOG_LEVELS.join(', ')})`);
+  process.exit(1);
+}
+if (!isLogFormat(logFormat)) {
+  console.error(`Invalid --log-format: ${logFormat} (expected text or json)`);
+  process.exit(1);
+}
+logger.configure({ level: logLevel, format: logFormat });
+
+// Servers with a connected client: the stdio one, or one per HTTP session
+const connectedServers = new Set<Server>();
+
+// --parse-workers <n>: worker threads that parse files during indexing, shared
+// by every root (default: one per core; 0 or 1 parses on the main thread)
+const parsePool = new ParsePool(integerOption(args, '--parse-workers') ?? DEFAULT_PARSE_WORKERS);
+
+// Shallow clones for eng_remote_symbols, removed after a few idle minutes or at shutdown
+const remoteRepos = new RemoteRepoCache(undefined, parsePool);
+
+// Every component is bound to one project root; each root gets its own set
+// (and its own symbol index)
+function createProject(root: ProjectRoot) {
+  const dir = root.path;
+  const symbolIndexer = new SymbolIndexer(dir, integerOption(args, '--cache-size'), parsePool);
+  return {
+    root,
+    projectDetector: new ProjectDetector(dir),
+    configManager: new ConfigManager(dir),
+    fileReader: new FileReader(dir),
+    codeFormatter: new CodeFormatter(dir),
+    securityScanner: new SecurityScanner(dir),
+    functionIndexer: new FunctionIndexer(dir),
+    duplicateDetector: new DuplicateDetector(dir),
+    routeIndexer: new RouteIndexer(dir),
+    hardwareIndexer: new HardwareIndexer(dir),
+    dependencyAnalyzer: new DependencyAnalyzer(dir),
+    refactorAnalyzer: new RefactorAnalyzer(dir),
+    similarityAnalyzer: new SimilarityAnalyzer(dir),
+    symbolIndexer,
+    referenceFinder: new ReferenceFinder(symbolIndexer),
+    renamePlanner: new RenamePlanner(symbolIndexer),
+    typeInspector: new TypeInspector(symbolIndexer),
+    implementationFinder: new ImplementationFinder(symbolIndexer),
+    complexityAnalyzer: new ComplexityAnalyzer(symbolIndexer),
+    cloneDetector: new CloneDetector(symbolIndexer),
+    callGraphBuilder: new CallGraphBuilder(symbolIndexer),
+    routeFinder: new RouteFinder(symbolIndexer),
+    testCatalog: new TestCatalog(symbolIndexer),
+    exampleFinder: new ExampleFinder(symbolIndexer),
+    textSearcher: new TextSearcher(dir),
+    markerScanner: new MarkerScanner(symbolIndexer),
+    projectTreeBuilder: new ProjectTreeBuilder(symbolIndexer),
+    lineCounter: new LineCounter(symbolIndexer),
+    changeScope: new ChangeScope(dir),
+    symbolContextResolver: new SymbolContextResolver(symbolIndexer),
+    definitionResolver: new DefinitionResolver(symbolIndexer),
+    externalResolver: new ExternalResolver(symbolIndexer),
+    functionAnalyzer: new FunctionAnalyzer(symbolIndexer),
+    fileOutliner: new FileOutliner(symbolIndexer),
+    symbolDiffer: new SymbolDiffer(symbolIndexer),
+    complexityComparer: new ComplexityComparer(symbolIndexer),
+    churnRiskAnalyzer: new ChurnRiskAnalyzer(symbolIndexer),
+    apiFingerprinter: new ApiFingerprinter(symbolIndexer),
+    importAnalyzer: new ImportAnalyzer(dir),
+    referenceValidator: new ReferenceValidator(symbolIndexer),
+    relatedFileFinder: new RelatedFileFinder(dir),
+    languageDetector: new LanguageDetector(dir),
+    validationPipeline: new ValidationPipeline(dir),
+    reviewChecker: new ReviewChecker(dir),
+    docCommentChecker: new DocCommentChecker(symbolIndexer),
+    namingChecker: new NamingChecker(symbolIndexer),
+    deprecationFinder: new DeprecationFinder(symbolIndexer),
+    testGapDetector: new TestGapDetector(symbolIndexer),
+    deadCodeDetector: new DeadCodeDetector(symbolIndexer),
+    goDiagnostics: new GoDiagnostics(dir),
+    featureManager: new FeatureManager(dir),
+    contextManager: new ContextManager(dir),
+    sessionCoordinator: new SessionCoordinator(dir),
+    indexWatcher: new IndexWatcher(symbolIndexer, async (changes, result) => {
+      await broadcast({
+        method: 'notifications/index/changed',
+        params: {
+          root: root.name,
+          changes,
+          files: result.files,
+          symbols: result.symbols,
+          version: symbolIndexer.getVersion(),
+        },
+      });
+    }),
+  };
+}
+
+type Project = ReturnType<typeof createProject>;
+
+// --root name=dir (repeatable) registers project roots; without any, the
+// working directory is the only root
+const roots = new ProjectRoots(createProject);
+for (const spec of stringOptions(args, '--root')) {
+  const { path: dir, name } = parseRootSpec(spec);
+  await roots.add(dir, name).catch((error: unknown) => {
+    console.error(`Invalid --root ${spec}: ${String(error)}`);
+    process.exit(1);
+  });
+}
+if (roots.list().length === 0) {
+  await roots.add(process.cwd());
+}
+
+// --watch: push notifications/index/changed as files are saved, renamed, or deleted
+const watching = args.includes('--watch');
+
+const analysisLimiter = new ConcurrencyLimiter(
+  integerOption(args, '--max-concurrent'),
+  integerOption(args, '--queue-timeout')
+);
+
+// --timeout <ms>: time budget per tool call (0 = none); a call's timeoutMs overrides it
+const defaultTimeoutMs = integerOption(args, '--timeout') ?? 0;
+
+// --max-response-bytes <n>: results over this many bytes of text are cut short
+// and flagged (0 = no limit); a call's maxResponseBytes overrides it
+const defaultMaxResponseBytes =
+  integerOption(args, '--max-response-bytes') ?? DEFAULT_MAX_RESPONSE_BYTES;
+
+// Time past the deadline for cooperative work to return its partial result
+// before the call is abandoned
+const TIMEOUT_GRACE_MS = 1000;
+
+// --grace-period <ms>: on SIGTERM or SIGINT, how long in-flight calls get to finish
+const gracePeriodMs = integerOption(args, '--grace-period') ?? DEFAULT_GRACE_PERIOD_MS;
+const inFlight = new InFlightTracker();
+
+// --metrics-port <n>: serve Prometheus metrics at http://<--metrics-host>:<n>/metrics,
+// alongside either transport; off unless given
+const metricsPort = integerOption(args, '--metrics-port');
+const metrics =
+  metricsPort === undefined
+    ? undefined
+    : new ToolMetrics(registerCommands().map(tool => tool.name));
+
+// Whole-project analyses share the limiter's slots; lookups and reads never queue
+const EXPENSIVE_TOOLS = new Set([
+  'eng_scan',
+  'eng_security',
+  'eng_validate',
+  'eng_pipeline',
+  'eng_review',
+  'eng_duplicates',
+  'eng_routes',
+  'eng_hardware',
+  'eng_deps',
+  'eng_refactor',
+  'eng_index_function',
+  'eng_index_similar',
+  'eng_extract_symbols',
+  'eng_summarize_file',
+  'eng_search_symbols',
+  'eng_search_text',
+  'eng_list_markers',
+  'eng_project_tree',
+  'eng_count_lines',
+  'eng_refresh_index',
+  'eng_symbol_delta',
+  'eng_complexity',
+  'eng_find_clones',
+  'eng_check_docs',
+  'eng_check_naming',
+  'eng_list_deprecated',
+  'eng_test_gaps',
+  'eng_list_tests',
+  'eng_go_examples',
+  'eng_dead_code',
+  'eng_diagnostics',
+  'eng_diff_symbols',
+  'eng_compare_complexity',
+  'eng_churn_risk',
+  'eng_api_fingerprint',
+  'eng_find_references',
+  'eng_rename_symbol',
+  'eng_format_code',
+  'eng_implementations',
+  'eng_analyze_function',
+  'eng_resolve_symbol',
+  'eng_hover',
+  'eng_type_info',
+  'eng_symbol_context',
+  'eng_remote_symbols',
+  'eng_call_graph',
+  'eng_list_routes',
+  'eng_imports',
+  'eng_unused_imports',
+  'eng_validate_references',
+  'eng_package_graph',
+  'eng_related_files',
+]);
+
+// Tools that write to the project or change server state; in a batch they run
+// alone, after the calls before them
+const STATEFUL_TOOLS = new Set([
+  'eng_init',
+  'eng_scan',
+  'eng_security',
+  'eng_start',
+  'eng_validate',
+  'eng_done',
+  'eng_session_checkpoint',
+  'eng_session_resume',
+  'eng_session_start',
+  'eng_session_switch',
+  'eng_session_sync',
+  'eng_lock',
+  'eng_unlock',
+  'eng_knowledge',
+  'eng_pipeline',
+  'eng_deps',
+  'eng_review',
+  'eng_refresh_index',
+  'eng_add_root',
+  'eng_rename_symbol',
+  'eng_format_code',
+]);
 
-const server = new Server(
-  {
-    name: 'mcp-engineering-server',
-    version: '0.1.0',
-  },
-  {
-    capabilities: {
-      tools: {},
+/**
+ * An MCP server with the tool handlers registered, for one client. Projects,
+ * indexes, and the analysis limiter are shared by every client.
+ */
+function createServer(): Server {
+  const server = new Server(
+    {
+      name: SERVER_NAME,
+      version: SERVER_VERSION,
     },
+    {
+      capabilities: {
+        tools: {},
+      },
+    }
+  );
+
+  // Register tool handlers
+  server.setRequestHandler(ListToolsRequestSchema, () => {
+    return {
+      tools: registerCommands(),
+    };
+  });
+
+  server.setRequestHandler(CallToolRequestSchema, async (request, extra) => {
+    const tool = request.params.name;
+    const started = performance.now();
+    logger.debug('tool call started', { tool, arguments: logArguments(request) });
+    try {
+      const result = await handleToolCall(request, extra);
+      logToolCall(tool, started, result);
+      return result;
+    } catch (error) {
+      logger.error('tool call failed', { tool, durationMs: elapsedMs(started), error });
+      metrics?.record(tool, elapsedMs(started), 'error', 'INTERNAL_ERROR');
+      throw error;
+    }
+  });
+
+  connectedServers.add(server);
+  server.onclose = () => {
+    connectedServers.delete(server);
+  };
+  return server;
+}
+
+/**
+ * Send a notification to every connected client; a client that has gone away
+ * doesn't stop the others from hearing about it
+ */
+async function broadcast(notification: Parameters<Server['notification']>[0]): Promise<void> {
+  await Promise.all(
+    [...connectedServers].map(server => server.notification(notification).catch(() => undefined))
+  );
+}
+
+/**
+ * Run a tool call: queued behind the limiter if it's expensive, under its
+ * time budget, and with its response cut to size
+ */
+async function handleToolCall(
+  request: CallToolRequest,
+  extra: { sendNotification: NotificationSender }
+): Promise<CallToolResult> {
+  const requested = (request.params.arguments as { maxResponseBytes?: unknown } | undefined)
+    ?.maxResponseBytes;
+  if (
+    requested !== undefined &&
+    (typeof requested !== 'number' || !Number.isInteger(requested) || requested < 0)
+  ) {
+    return errorResult('INVALID_ARGUMENT', `Invalid maxResponseBytes: ${String(requested)}`);
   }
-);
+  const maxBytes = requested ?? defaultMaxResponseBytes;
+  if (request.params.name === 'eng_batch') {
+    return callBatch(request, extra, maxBytes);
+  }
+
+  try {
+    const result = await inFlight.run(signal => {
+      if (!EXPENSIVE_TOOLS.has(request.params.name)) {
+        return callWithTimeout(request, extra, signal);
+      }
+      // The deadline starts once a slot is free, not while queued
+      return analysisLimiter.run(hold => callWithTimeout(request, extra, signal, hold));
+    });
+    return limitResponse(result, maxBytes);
+  } catch (error) {
+    if (error instanceof ServerBusyError || error instanceof ShuttingDownError) {
+      return errorResult(describeError(error).code, error.message);
+    }
+    throw error;
+  }
+}
+
+/**
+ * eng_batch: each call goes through handleToolCall as if sent on its own, so
+ * it gets its own analysis slot, time budget, and response cap. The batch's
+ * timeoutMs is the default for its calls, and calls that don't set
+ * maxResponseBytes share maxBytes equally. The batch as a whole is held to
+ * maxBytes by cutting its calls' texts, never by dropping their entries.
+ */
+async function callBatch(
+  request: CallToolRequest,
+  extra: { sendNotification: NotificationSender },
+  maxBytes: number
+): Promise<CallToolResult> {
+  const argsObj = request.params.arguments as { calls?: unknown; timeoutMs?: number } | undefined;
+  let calls: BatchCall[];
+  try {
+    calls = parseBatchCalls(argsObj?.calls);
+  } catch (error) {
+    return toolError('Batch failed', error);
+  }
+
+  const batch = await runBatch(
+    calls,
+    async call => {
+      if (call.tool === 'eng_batch') {
+        return errorResult('INVALID_ARGUMENT', 'eng_batch calls cannot be nested');
+      }
+      const defaults: Record<string, unknown> = {
+        maxResponseBytes: maxBytes === 0 ? 0 : Math.max(1, Math.floor(maxBytes / calls.length)),
+      };
+      if (argsObj?.timeoutMs !== undefined) defaults.timeoutMs = argsObj.timeoutMs;
+      const started = performance.now();
+      const callArgs = { ...defaults, ...call.arguments };
+      const params = { ...request.params, name: call.tool, arguments: callArgs };
+      const result = await handleToolCall({ ...request, params }, extra);
+      logToolCall(call.tool, started, result);
+      return result;
+    },
+    tool => STATEFUL_TOOLS.has(tool)
+  );
+
+  // Escaping and indentation, and calls that set their own cap, can still
+  // add up to more than the batch's
+  const fitted = fitBatch(batch, maxBytes);
+  const truncation: Truncation = {
+    truncated: true,
+    omitted: fitted.omitted,
+    maxResponseBytes: maxBytes,
+  };
+  const cut = fitted.batch.results.some((result, i) => result.text !== batch.results[i]?.text);
+  return {
+    content: [{ type: 'text', text: JSON.stringify(fitted.batch, null, 2) }],
+    ...(cut ? { _meta: { truncation } } : {}),
+  };
+}
+
+/**
+ * One info line per tool call: its name, duration, and how it ended. The
+ * same goes into the metrics when they are served.
+ */
+function logToolCall(tool: string, started: number, result: CallToolResult): void {
+  const meta = result._meta as
+    | { error?: ErrorInfo; timedOut?: boolean; cancelled?: boolean; truncation?: unknown }
+    | undefined;
+  let outcome: CallOutcome = result.isError ? 'error' : 'ok';
+  if (meta?.timedOut) outcome = 'timeout';
+  else if (meta?.cancelled) outcome = 'cancelled';
+  const durationMs = elapsedMs(started);
+  logger.info('tool call', {
+    tool,
+    durationMs,
+    outcome,
+    code: meta?.error?.code,
+    truncated: meta?.truncation ? true : undefined,
+  });
+  metrics?.record(tool, durationMs, outcome, meta?.error?.code);
+}
 
-// Initialize core components
-const projectDetector = new ProjectDetector();
-const configManager = new ConfigManager();
-const securityScanner = new SecurityScanner();
-const functionIndexer = new FunctionIndexer();
-const duplicateDetector = new DuplicateDetector();
-const routeIndexer = new RouteIndexer();
-const hardwareIndexer = new HardwareIndexer();
-const dependencyAnalyzer = new DependencyAnalyzer();
-const refactorAnalyzer = new RefactorAnalyzer();
-const similarityAnalyzer = new SimilarityAnalyzer();
-const validationPipeline = new ValidationPipeline();
-const reviewChecker = new ReviewChecker();
-const featureManager = new FeatureManager();
-const contextManager = new ContextManager();
-const sessionCoordinator = new SessionCoordinator();
-
-// Register tool handlers
-server.setRequestHandler(ListToolsRequestSchema, () => {
+/**
+ * Gauges for a metrics scrape: calls running and queued, and each root's
+ * index size and parse cache counters
+ */
+function serverGauges(): ServerGauges {
   return {
-    tools: registerCommands(),
+    inFlight: inFlight.size,
+    queued: analysisLimiter.stats().queued,
+    roots: roots.select().map(project => {
+      const index = project.symbolIndexer.getIndexStats();
+      const cache = project.symbolIndexer.getCacheStats();
+      return {
+        root: project.root.name,
+        files: index.files,
+        symbols: index.symbols,
+        cacheHits: cache.hits,
+        cacheMisses: cache.misses,
+      };
+    }),
   };
-});
+}
+
+/**
+ * Arguments for a debug line, with long strings such as unsaved buffers
+ * replaced by their length
+ */
+function logArguments(request: CallToolRequest): Record<string, unknown> {
+  const logged: Record<string, unknown> = {};
+  for (const [key, value] of Object.entries(request.params.arguments ?? {})) {
+    logged[key] =
+      typeof value === 'string' && value.length > 200 ? `<${value.length} chars>` : value;
+  }
+  return logged;
+}
+
+function elapsedMs(started: number): number {
+  return Math.round(performance.now() - started);
+}
+
+/**
+ * Run a tool under its time budget. Parsers and scans check the deadline and
+ * stop with what they have; work that doesn't check is abandoned after a
+ * grace period. Either way the result carries _meta.timedOut. Aborting the
+ * signal cancels the deadline early, and the result carries _meta.cancelled.
+ * hold is given the work, so an analysis slot isn't freed before it settles.
+ */
+async function callWithTimeout(
+  request: CallToolRequest,
+  extra: { sendNotification: NotificationSender },
+  signal: AbortSignal,
+  hold?: (work: Promise<unknown>) => void
+): Promise<CallToolResult> {
+  const requested = (request.params.arguments as { timeoutMs?: unknown } | undefined)?.timeoutMs;
+  if (
+    requested !== undefined &&
+    (typeof requested !== 'number' || !Number.isInteger(requested) || requested < 0)
+  ) {
+    return errorResult('INVALID_ARGUMENT', `Invalid timeoutMs: ${String(requested)}`);
+  }
+  const timeoutMs = requested ?? defaultTimeoutMs;
+
+  const deadline = new Deadline(timeoutMs);
+  const cancel = (): void => deadline.cancel('the server is shutting down');
+  signal.addEventListener('abort', cancel);
+  if (signal.aborted) cancel();
+
+  let timer: ReturnType<typeof setTimeout> | undefined;
+  const abandoned = new Promise<CallToolResult>(resolve => {
+    if (timeoutMs === 0) return;
+    timer = setTimeout(
+      () =>
+        resolve({
+          content: [
+            {
+              type: 'text',
+              text: `${request.params.name} timed out after ${timeoutMs} ms without a result`,
+            },
+          ],
+          isError: true,
+          _meta: {
+            timedOut: true,
+            error: { code: 'TIMEOUT', message: `Timed out after ${timeoutMs} ms` },
+          },
+        }),
+      timeoutMs + TIMEOUT_GRACE_MS
+    );
+  });
+
+  try {
+    // Work abandoned at the timeout keeps running until it next checks the deadline
+    const work = withDeadline(deadline, () => callTool(request, extra));
+    hold?.(work);
+    const result = await Promise.race([work, abandoned]);
+    if (!deadline.interrupted() || result._meta?.timedOut) {
+      return result;
+    }
+    if (deadline.cancelled()) {
+      return {
+        ...result,
+        content: [
+          ...result.content,
+          { type: 'text', text: 'Cancelled: the server is shutting down; the result is partial' },
+        ],
+        _meta: { ...result._meta, cancelled: true },
+      };
+    }
+    return {
+      ...result,
+      content: [
+        ...result.content,
+        { type: 'text', text: `Timed out after ${timeoutMs} ms; the result is partial` },
+      ],
+      _meta: { ...result._meta, timedOut: true },
+    };
+  } finally {
+    clearTimeout(timer);
+    signal.removeEventListener('abort', cancel);
+  }
+}
 
-server.setRequestHandler(CallToolRequestSchema, async request => {
+async function callTool(
+  request: CallToolRequest,
+  extra: { sendNotification: NotificationSender }
+): Promise<CallToolResult> {
   const { name, arguments: args } = request.params;
+  const progressToken = request.params._meta?.progressToken;
+  const rootName = (args as { root?: string } | undefined)?.root;
+
+  let project: Project;
+  try {
+    project = rootName ? roots.get(rootName) : roots.primary();
+  } catch (error) {
+    return errorResult(describeError(error).code, String(error));
+  }
+  const {
+    projectDetector,
+    configManager,
+    fileReader,
+    codeFormatter,
+    securityScanner,
+    functionIndexer,
+    duplicateDetector,
+    routeIndexer,
+    hardwareIndexer,
+    dependencyAnalyzer,
+    refactorAnalyzer,
+    similarityAnalyzer,
+    symbolIndexer,
+    referenceFinder,
+    renamePlanner,
+    typeInspector,
+    implementationFinder,
+    complexityAnalyzer,
+    cloneDetector,
+    callGraphBuilder,
+    routeFinder,
+    testCatalog,
+    exampleFinder,
+    textSearcher,
+    markerScanner,
+    projectTreeBuilder,
+    lineCounter,
+    changeScope,
+    symbolContextResolver,
+    definitionResolver,
+    externalResolver,
+    functionAnalyzer,
+    fileOutliner,
+    symbolDiffer,
+    complexityComparer,
+    churnRiskAnalyzer,
+    apiFingerprinter,
+    importAnalyzer,
+    referenceValidator,
+    relatedFileFinder,
+    languageDetector,
+    validationPipeline,
+    reviewChecker,
+    docCommentChecker,
+    namingChecker,
+    deprecationFinder,
+    testGapDetector,
+    deadCodeDetector,
+    goDiagnostics,
+    featureManager,
+    contextManager,
+    sessionCoordinator,
+  } = project;
 
   // Tool dispatch logic
   switch (name) {
@@ -138,7 +790,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
         // Detect project type
         const projectType = await projectDetector.detect();
         const projectName =
-          (args as { name?: string } | undefined)?.name ?? path.basename(process.cwd());
+          (args as { name?: string } | undefined)?.name ?? path.basename(project.root.path);
 
         // Initialize config and directory structure
         await configManager.initialize(projectName, projectType);
@@ -156,10 +808,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           ],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Init failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Init failed', error);
       }
     }
 
@@ -167,10 +816,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
       try {
         // Ensure initialized
         if (!(await configManager.exists())) {
-          return {
-            content: [{ type: 'text', text: 'Project not initialized. Run eng_init first.' }],
-            isError: true,
-          };
+          return errorResult('FAILED_PRECONDITION', 'Project not initialized. Run eng_init first.');
         }
 
         const functions = await functionIndexer.scan();
@@ -188,10 +834,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           ],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Scan failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Scan failed', error);
       }
     }
 
@@ -267,30 +910,22 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           content: [{ type: 'text', text: report }],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Security scan failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Security scan failed', error);
       }
     }
 
     case 'eng_start': {
       try {
         if (!(await configManager.exists())) {
-          return {
-            content: [{ type: 'text', text: 'Project not initialized. Run eng_init first.' }],
-            isError: true,
-          };
+          return errorResult('FAILED_PRECONDITION', 'Project not initialized. Run eng_init first.');
         }
 
         const featureName = (args as { feature?: string } | undefined)?.feature;
         if (!featureName) {
-          return {
-            content: [
-              { type: 'text', text: 'Feature name required. Usage: eng_start --feature <name>' },
-            ],
-            isError: true,
-          };
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'Feature name required. Usage: eng_start --feature <name>'
+          );
         }
 
         // Check for active feature
@@ -358,20 +993,14 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           content: [{ type: 'text', text: resultText }],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Start failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Start failed', error);
       }
     }
 
     case 'eng_validate': {
       try {
         if (!(await configManager.exists())) {
-          return {
-            content: [{ type: 'text', text: 'Project not initialized. Run eng_init first.' }],
-            isError: true,
-          };
+          return errorResult('FAILED_PRECONDITION', 'Project not initialized. Run eng_init first.');
         }
 
         const results: string[] = [];
@@ -412,10 +1041,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           ],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Validation failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Validation failed', error);
       }
     }
 
@@ -423,25 +1049,17 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
       try {
         const activeFeature = await featureManager.getActiveFeature();
         if (!activeFeature) {
-          return {
-            content: [{ type: 'text', text: 'No active feature. Start one with eng_start.' }],
-            isError: true,
-          };
+          return errorResult('FAILED_PRECONDITION', 'No active feature. Start one with eng_start.');
         }
 
         // Run security check before completing
         const findings = await securityScanner.scan();
         const criticalFindings = findings.filter(f => f.severity === 'critical');
         if (criticalFindings.length > 0) {
-          return {
-            content: [
-              {
-                type: 'text',
-                text: `Cannot complete: ${criticalFindings.length} critical security issue(s) found.\nRun eng_security to see details, fix them first.`,
-              },
-            ],
-            isError: true,
-          };
+          return errorResult(
+            'FAILED_PRECONDITION',
+            `Cannot complete: ${criticalFindings.length} critical security issue(s) found.\nRun eng_security to see details, fix them first.`
+          );
         }
 
         const { archivePath, knowledgeExtracted } =
@@ -467,10 +1085,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           content: [{ type: 'text', text: resultText }],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Done failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Done failed', error);
       }
     }
 
@@ -491,10 +1106,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           ],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Checkpoint failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Checkpoint failed', error);
       }
     }
 
@@ -541,10 +1153,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           ],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Resume failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Resume failed', error);
       }
     }
 
@@ -552,12 +1161,10 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
       try {
         const query = (args as { query?: string } | undefined)?.query;
         if (!query) {
-          return {
-            content: [
-              { type: 'text', text: 'Search query required. Usage: eng_search --query <term>' },
-            ],
-            isError: true,
-          };
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'Search query required. Usage: eng_search --query <term>'
+          );
         }
 
         // Search functions
@@ -584,10 +1191,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           ],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Search failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Search failed', error);
       }
     }
 
@@ -596,10 +1200,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
       try {
         const id = (args as { id?: 'A' | 'B' | 'C' } | undefined)?.id;
         if (!id || !['A', 'B', 'C'].includes(id)) {
-          return {
-            content: [{ type: 'text', text: 'Session ID required (A, B, or C).' }],
-            isError: true,
-          };
+          return errorResult('INVALID_ARGUMENT', 'Session ID required (A, B, or C).');
         }
 
         const info = await sessionCoordinator.startSession(id);
@@ -617,10 +1218,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           ],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Session start failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Session start failed', error);
       }
     }
 
@@ -655,10 +1253,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           content: [{ type: 'text', text: report }],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Status failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Status failed', error);
       }
     }
 
@@ -666,10 +1261,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
       try {
         const id = (args as { id?: 'A' | 'B' | 'C' } | undefined)?.id;
         if (!id || !['A', 'B', 'C'].includes(id)) {
-          return {
-            content: [{ type: 'text', text: 'Session ID required (A, B, or C).' }],
-            isError: true,
-          };
+          return errorResult('INVALID_ARGUMENT', 'Session ID required (A, B, or C).');
         }
 
         const info = await sessionCoordinator.switchSession(id);
@@ -686,10 +1278,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           ],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Switch failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Switch failed', error);
       }
     }
 
@@ -724,10 +1313,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           content: [{ type: 'text', text: report }],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Sync failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Sync failed', error);
       }
     }
 
@@ -735,35 +1321,25 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
       try {
         const file = (args as { file?: string } | undefined)?.file;
         if (!file) {
-          return {
-            content: [{ type: 'text', text: 'File path required.' }],
-            isError: true,
-          };
+          return errorResult('INVALID_ARGUMENT', 'File path required.');
         }
 
         const success = await sessionCoordinator.lockFile(file);
         if (!success) {
           const locks = await sessionCoordinator.getLocks();
           const existing = locks.find(l => l.file === file);
-          return {
-            content: [
-              {
-                type: 'text',
-                text: `Cannot lock: ${file} is locked by session ${existing?.session ?? 'unknown'}`,
-              },
-            ],
-            isError: true,
-          };
+          return errorResult(
+            'FAILED_PRECONDITION',
+            `Cannot lock: ${file} is locked by session ${existing?.session ?? 'unknown'}`,
+            file
+          );
         }
 
         return {
           content: [{ type: 'text', text: `✓ Locked: ${file}` }],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Lock failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Lock failed', error);
       }
     }
 
@@ -771,10 +1347,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
       try {
         const file = (args as { file?: string } | undefined)?.file;
         if (!file) {
-          return {
-            content: [{ type: 'text', text: 'File path required.' }],
-            isError: true,
-          };
+          return errorResult('INVALID_ARGUMENT', 'File path required.');
         }
 
         await sessionCoordinator.unlockFile(file);
@@ -783,10 +1356,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           content: [{ type: 'text', text: `✓ Unlocked: ${file}` }],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Unlock failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Unlock failed', error);
       }
     }
 
@@ -812,10 +1382,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           ],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Duplicate scan failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Duplicate scan failed', error);
       }
     }
 
@@ -856,10 +1423,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           content: [{ type: 'text', text: report }],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Route scan failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Route scan failed', error);
       }
     }
 
@@ -881,10 +1445,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           content: [{ type: 'text', text: report }],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Hardware scan failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Hardware scan failed', error);
       }
     }
 
@@ -944,10 +1505,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           content: [{ type: 'text', text: report }],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Knowledge query failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Knowledge query failed', error);
       }
     }
 
@@ -972,10 +1530,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           content: [{ type: 'text', text: result.summary }],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Pipeline failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Pipeline failed', error);
       }
     }
 
@@ -995,10 +1550,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           ],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Dependency analysis failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Dependency analysis failed', error);
       }
     }
 
@@ -1046,10 +1598,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           content: [{ type: 'text', text: output }],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Refactor analysis failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Refactor analysis failed', error);
       }
     }
 
@@ -1067,10 +1616,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           ],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Review failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Review failed', error);
       }
     }
 
@@ -1133,10 +1679,7 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
           content: [{ type: 'text', text: report }],
         };
       } catch (error) {
-        return {
-          content: [{ type: 'text', text: `Function search failed: ${String(error)}` }],
-          isError: true,
-        };
+        return toolError('Function search failed', error);
       }
     }
 
@@ -1145,51 +1688,2252 @@ server.setRequestHandler(CallToolRequestSchema, async request => {
         const code = (args as { code?: string } | undefined)?.code;
 
         if (!code) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'Code snippet required. Usage: eng_index_similar --code "<your code>"'
+          );
+        }
+
+        const result = await similarityAnalyzer.findSimilar(code);
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text: similarityAnalyzer.formatResult(result),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Similarity search failed', error);
+      }
+    }
+
+    case 'eng_extract_symbols': {
+      try {
+        const argsObj = args as
+          | ({
+              path?: string;
+              format?: 'text' | 'json';
+              ignore?: string[];
+              includeIgnored?: boolean;
+              include?: string[];
+              exclude?: string[];
+              maxFileSizeBytes?: number;
+              maxDepth?: number;
+              includeBlame?: boolean;
+              signaturesOnly?: boolean;
+              includeDocs?: boolean;
+              docMode?: string;
+              decorator?: string;
+              kinds?: string[];
+              sortBy?: string;
+              order?: string;
+              includeComplexity?: boolean;
+              includeGenerated?: boolean;
+            } & BufferOptions &
+              BuildOptions &
+              ChangeScopeOptions &
+              StreamOptions)
+          | undefined;
+        const stream = openStream<object>(extra.sendNotification, progressToken, argsObj);
+        // includeDocs: false predates docMode and means none
+        const trimDoc = docTrimmer(
+          argsObj?.docMode ?? (argsObj?.includeDocs === false ? 'none' : undefined)
+        );
+        const present = (list: SymbolEntry[]): object[] => {
+          const trimmed = trimDoc ? list.map(trimDoc) : list;
+          return argsObj?.signaturesOnly ? trimmed.map(s => toSignature(s)) : trimmed;
+        };
+        const ofKind = kindFilter(argsObj?.kinds);
+        const sorter = symbolSorter(argsObj?.sortBy, argsObj?.order, argsObj?.includeComplexity);
+        // Sorting needs every symbol, so sorted results stream as one batch at the end
+        const streamed = sorter ? undefined : stream;
+        // A buffer is shown as given, even standing in for a generated file
+        const skippedGenerated = new Set<string>();
+        const handWritten =
+          argsObj?.includeGenerated || argsObj?.content !== undefined
+            ? undefined
+            : generatedFilter(symbolIndexer, skippedGenerated);
+        const filtered = (list: SymbolEntry[]): SymbolEntry[] =>
+          list.filter(
+            s =>
+              (!argsObj?.decorator || hasDecorator(s, argsObj.decorator)) &&
+              (ofKind?.(s) ?? true) &&
+              (handWritten?.(s) ?? true)
+          );
+
+        // An unsaved buffer has no history to blame and nothing to scope or index
+        const buffer = argsObj?.content !== undefined;
+        const build = buildContext(argsObj);
+        const scope = await changeScope.resolve(buffer ? {} : { ...argsObj });
+        const withBlame = async (list: SymbolEntry[]): Promise<SymbolEntry[]> =>
+          argsObj?.includeBlame && !buffer ? symbolIndexer.withBlame(list) : list;
+        const finish = async (list: SymbolEntry[]): Promise<SymbolEntry[]> => {
+          let annotated = await withBlame(list);
+          if (argsObj?.includeComplexity) {
+            annotated = await complexityAnalyzer.withComplexity(
+              annotated,
+              argsObj.content !== undefined
+                ? { content: argsObj.content, language: argsObj.language }
+                : undefined
+            );
+          }
+          return sorter ? [...annotated].sort(sorter) : annotated;
+        };
+        let scanned: SymbolEntry[];
+        if (argsObj?.content !== undefined) {
+          scanned = filtered(
+            symbolIndexer.extractSource(
+              argsObj.content,
+              argsObj.path ?? BUFFER_FILE,
+              argsObj.language
+            )
+          );
+          await streamed?.push(present(await finish(scanned)));
+        } else {
+          scanned = filtered(
+            await symbolIndexer.scan(argsObj?.path, {
+              only: scope.files,
+              ignore: argsObj?.ignore,
+              includeIgnored: argsObj?.includeIgnored,
+              include: argsObj?.include,
+              exclude: argsObj?.exclude,
+              maxFileSizeBytes: argsObj?.maxFileSizeBytes,
+              maxDepth: argsObj?.maxDepth,
+              build,
+              onSymbols: streamed
+                ? async batch => streamed.push(present(await finish(filtered(batch))))
+                : undefined,
+            })
+          );
+        }
+        // Streamed batches were finished as they went; the rest is finished once, here
+        const symbols = streamed ? scanned : await finish(scanned);
+        if (stream && !streamed) {
+          await stream.push(present(symbols));
+        }
+        const excluded = buffer ? [] : symbolIndexer.getExcluded();
+
+        // Persist the index only for full-project extraction
+        if (
+          !buffer &&
+          !argsObj?.path &&
+          !scope.files &&
+          !build &&
+          !argsObj?.ignore &&
+          !argsObj?.includeIgnored &&
+          !argsObj?.include &&
+          !argsObj?.exclude &&
+          argsObj?.maxDepth === undefined
+        ) {
+          await symbolIndexer.saveIndex();
+        }
+
+        if (stream) {
+          const summary = await stream.end();
           return {
             content: [
               {
                 type: 'text',
-                text: 'Code snippet required. Usage: eng_index_similar --code "<your code>"',
+                text: JSON.stringify(
+                  {
+                    ...summary,
+                    ...(scope.note ? { scope } : {}),
+                    excluded,
+                    generatedSkipped: skippedGenerated.size,
+                  },
+                  null,
+                  2
+                ),
               },
             ],
-            isError: true,
           };
         }
 
-        const result = await similarityAnalyzer.findSimilar(code);
-
         return {
           content: [
             {
               type: 'text',
-              text: similarityAnalyzer.formatResult(result),
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(
+                      {
+                        total: symbols.length,
+                        ...(scope.note ? { scope } : {}),
+                        symbols: present(symbols),
+                        excluded,
+                        generatedSkipped: skippedGenerated.size,
+                      },
+                      null,
+                      2
+                    )
+                  : withScopeNote(
+                      scope,
+                      symbolIndexer.formatSymbols(symbols, argsObj?.signaturesOnly, !!sorter)
+                    ) +
+                    (excluded.length > 0 ? `\n\n${symbolIndexer.formatExcluded(excluded)}` : '') +
+                    formatGeneratedNote(skippedGenerated.size),
             },
           ],
         };
       } catch (error) {
+        return toolError('Symbol extraction failed', error);
+      }
+    }
+
+    case 'eng_summarize_file': {
+      try {
+        const argsObj = args as
+          | ({
+              path?: string;
+              format?: 'text' | 'json';
+              ignore?: string[];
+              includeIgnored?: boolean;
+              include?: string[];
+              exclude?: string[];
+              maxFileSizeBytes?: number;
+            } & BuildOptions)
+          | undefined;
+        const outlines = await fileOutliner.outline(argsObj?.path, {
+          ignore: argsObj?.ignore,
+          includeIgnored: argsObj?.includeIgnored,
+          include: argsObj?.include,
+          exclude: argsObj?.exclude,
+          maxFileSizeBytes: argsObj?.maxFileSizeBytes,
+          build: buildContext(argsObj),
+        });
+
         return {
-          content: [{ type: 'text', text: `Similarity search failed: ${String(error)}` }],
-          isError: true,
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(outlines, null, 2)
+                  : fileOutliner.formatOutlines(outlines),
+            },
+          ],
         };
+      } catch (error) {
+        return toolError('File summary failed', error);
       }
     }
 
-    default:
-      return {
-        content: [
+    case 'eng_search_symbols': {
+      try {
+        const argsObj = args as
+          | ({
+              query?: string;
+              limit?: number;
+              format?: 'text' | 'json';
+              includeBlame?: boolean;
+              kinds?: string[];
+              sortBy?: string;
+              order?: string;
+              includeComplexity?: boolean;
+              docMode?: string;
+              includeGenerated?: boolean;
+            } & StreamOptions &
+              PageOptions &
+              ScopeOptions)
+          | undefined;
+        if (!argsObj?.query) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'Search query required. Usage: eng_search_symbols --query <term>'
+          );
+        }
+
+        const query = argsObj.query;
+        const ofKind = kindFilter(argsObj.kinds);
+        const sorter = symbolSorter(argsObj.sortBy, argsObj.order, argsObj.includeComplexity);
+        const trimDoc = docTrimmer(argsObj.docMode);
+        const stream = openStream<object>(extra.sendNotification, progressToken, argsObj);
+        // Paged results run through every match unless limit is given explicitly
+        const paged = !stream && isPaginated(argsObj);
+        const limit = paged ? (argsObj.limit ?? Infinity) : (argsObj.limit ?? DEFAULT_SEARCH_LIMIT);
+        // sortBy orders every match, and limit then keeps the first of them
+        const keep = sorter ? Infinity : limit;
+        // Without a root, every root is searched and ranked together. Each
+        // file's symbols are matched as they're scanned, and only the best
+        // matches so far are held, not every symbol of every root.
+        const targets = roots.select(rootName);
+        const tagRoots = roots.list().length > 1;
+        const skippedGenerated = new Set<string>();
+        let matches: SymbolMatch[] = [];
+        for (const target of targets) {
+          const root = target.root.name;
+          const handWritten = argsObj.includeGenerated
+            ? undefined
+            : generatedFilter(target.symbolIndexer, skippedGenerated, tagRoots ? root : undefined);
+          await target.symbolIndexer.scan('.', {
+            include: argsObj.include,
+            exclude: argsObj.exclude,
+            onSymbols: async batch => {
+              const kept = batch.filter(s => (ofKind?.(s) ?? true) && (handWritten?.(s) ?? true));
+              const found = searchSymbols(kept, query, keep);
+              matches.push(
+                ...(tagRoots ? found.map(m => ({ ...m, symbol: { ...m.symbol, root } })) : found)
+              );
+              if (matches.length > 2 * keep) matches = rankMatches(matches, keep);
+            },
+          });
+        }
+        matches = rankMatches(matches, keep);
+        if (argsObj.includeComplexity) {
+          const measured = await withComplexityByRoot(targets, matches.map(m => m.symbol));
+          matches = matches.map((m, i) => ({ ...m, symbol: measured[i] ?? m.symbol }));
+        }
+        if (sorter) {
+          matches = [...matches].sort((a, b) => sorter(a.symbol, b.symbol)).slice(0, limit);
+        }
+        const page = paged
+          ? paginate(
+              matches,
+              argsObj,
+              queryFingerprint(
+                argsObj.query,
+                argsObj.limit,
+                argsObj.include,
+                argsObj.exclude,
+                argsObj.kinds,
+                argsObj.sortBy,
+                argsObj.order,
+                argsObj.includeComplexity,
+                argsObj.includeGenerated,
+                targets.map(target => target.symbolIndexer.fingerprint())
+              )
+            )
+          : undefined;
+        if (page) {
+          matches = page.items;
+        }
+        if (argsObj.includeBlame) {
+          const blamed = await withBlameByRoot(matches.map(m => m.symbol));
+          matches = matches.map((m, i) => ({ ...m, symbol: blamed[i] ?? m.symbol }));
+        }
+        if (trimDoc) {
+          matches = matches.map(m => ({ ...m, symbol: trimDoc(m.symbol) }));
+        }
+
+        if (stream) {
+          await stream.push(matches.map(m => ({ ...m.symbol, match: m.match, score: m.score })));
+          return {
+            content: [{ type: 'text', text: JSON.stringify(await stream.end(), null, 2) }],
+          };
+        }
+
+        const entries = matches.map(m => ({ ...m.symbol, match: m.match, score: m.score }));
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj.format === 'json'
+                  ? JSON.stringify(
+                      page
+                        ? {
+                            matches: entries,
+                            nextCursor: page.nextCursor,
+                            generatedSkipped: skippedGenerated.size,
+                          }
+                        : entries,
+                      null,
+                      2
+                    )
+                  : formatMatches(argsObj.query, matches) +
+                    formatNextCursor(page?.nextCursor) +
+                    formatGeneratedNote(skippedGenerated.size),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Symbol search failed', error);
+      }
+    }
+
+    case 'eng_search_text': {
+      try {
+        const argsObj = args as
+          | ({
+              pattern?: string;
+              path?: string;
+              glob?: string;
+              ignoreCase?: boolean;
+              wholeWord?: boolean;
+              maxResults?: number;
+              format?: 'text' | 'json';
+              ignore?: string[];
+              includeIgnored?: boolean;
+              include?: string[];
+              exclude?: string[];
+              maxFileSizeBytes?: number;
+              contextBefore?: number;
+              contextAfter?: number;
+            } & PageOptions)
+          | undefined;
+        if (!argsObj?.pattern) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'Search pattern required. Usage: eng_search_text --pattern <regex>'
+          );
+        }
+
+        // Without a root, every root is searched; a path is resolved in one
+        // root (default: the first)
+        const targets = argsObj.path && !rootName ? [project] : roots.select(rootName);
+
+        if (!isPaginated(argsObj)) {
+          const result = await searchTextAcross(targets, { ...argsObj, pattern: argsObj.pattern });
+          return {
+            content: [
+              {
+                type: 'text',
+                text:
+                  argsObj.format === 'json'
+                    ? JSON.stringify(result, null, 2)
+                    : textSearcher.formatResult(result),
+              },
+            ],
+          };
+        }
+
+        // Pages replace maxResults: search just far enough to know whether
+        // another page follows
+        const fingerprint = queryFingerprint(
+          rootName,
+          argsObj.pattern,
+          argsObj.path,
+          argsObj.glob,
+          argsObj.ignoreCase,
+          argsObj.wholeWord,
+          argsObj.ignore,
+          argsObj.includeIgnored,
+          argsObj.include,
+          argsObj.exclude,
+          argsObj.maxFileSizeBytes,
+          await Promise.all(targets.map(target => target.textSearcher.fileState(argsObj)))
+        );
+        const { offset, pageSize } = pageWindow(argsObj, fingerprint);
+        const result = await searchTextAcross(targets, {
+          ...argsObj,
+          pattern: argsObj.pattern,
+          maxResults: offset + pageSize + 1,
+        });
+        const page = paginate(result.matches, argsObj, fingerprint);
+        const paged = { ...result, matches: page.items, truncated: page.nextCursor !== undefined };
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj.format === 'json'
+                  ? JSON.stringify({ ...paged, nextCursor: page.nextCursor }, null, 2)
+                  : textSearcher.formatResult({ ...paged, truncated: false }) +
+                    formatNextCursor(page.nextCursor),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Text search failed', error);
+      }
+    }
+
+    case 'eng_list_markers': {
+      try {
+        const argsObj = args as
+          | ({
+              path?: string;
+              keywords?: string[];
+              groupBy?: 'keyword' | 'owner';
+              blame?: boolean;
+              format?: 'text' | 'json';
+              ignore?: string[];
+              includeIgnored?: boolean;
+              include?: string[];
+              exclude?: string[];
+            } & PageOptions)
+          | undefined;
+        const keywords = argsObj?.keywords?.length ? argsObj.keywords : undefined;
+        let report = await markerScanner.scan(argsObj?.path, keywords, {
+          ignore: argsObj?.ignore,
+          includeIgnored: argsObj?.includeIgnored,
+          include: argsObj?.include,
+          exclude: argsObj?.exclude,
+          blame: argsObj?.blame,
+        });
+        if (argsObj?.groupBy === 'owner') {
+          report.owners = markerScanner.groupByOwner(report.groups.flatMap(g => g.markers));
+        }
+
+        // Markers are paged in report order; each group keeps its full count
+        let nextCursor: string | undefined;
+        if (argsObj && isPaginated(argsObj)) {
+          const page = paginate(
+            (report.owners ?? report.groups).flatMap(group => group.markers),
+            argsObj,
+            queryFingerprint(
+              argsObj.path,
+              keywords,
+              argsObj.groupBy,
+              argsObj.blame,
+              argsObj.ignore,
+              argsObj.includeIgnored,
+              argsObj.include,
+              argsObj.exclude,
+              await markerScanner.fileState(argsObj.path, {
+                ignore: argsObj.ignore,
+                includeIgnored: argsObj.includeIgnored,
+                include: argsObj.include,
+                exclude: argsObj.exclude,
+              })
+            )
+          );
+          nextCursor = page.nextCursor;
+          const onPage = new Set(page.items);
+          report = {
+            total: report.total,
+            groups: report.groups
+              .map(group => ({ ...group, markers: group.markers.filter(m => onPage.has(m)) }))
+              .filter(group => group.markers.length > 0),
+            ...(report.owners && {
+              owners: report.owners
+                .map(group => ({ ...group, markers: group.markers.filter(m => onPage.has(m)) }))
+                .filter(group => group.markers.length > 0),
+            }),
+          };
+        }
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify({ ...report, nextCursor }, null, 2)
+                  : markerScanner.formatReport(report) + formatNextCursor(nextCursor),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Marker scan failed', error);
+      }
+    }
+
+    case 'eng_count_lines': {
+      try {
+        const argsObj = args as
+          | {
+              path?: string;
+              format?: 'text' | 'json';
+              ignore?: string[];
+              includeIgnored?: boolean;
+              include?: string[];
+              exclude?: string[];
+            }
+          | undefined;
+        const report = await lineCounter.count(argsObj?.path, {
+          ignore: argsObj?.ignore,
+          includeIgnored: argsObj?.includeIgnored,
+          include: argsObj?.include,
+          exclude: argsObj?.exclude,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(report, null, 2)
+                  : lineCounter.formatReport(report),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Line count failed', error);
+      }
+    }
+
+    case 'eng_project_tree': {
+      try {
+        const argsObj = args as
+          | {
+              path?: string;
+              maxDepth?: number;
+              walkDepth?: number;
+              format?: 'text' | 'json';
+              ignore?: string[];
+              includeIgnored?: boolean;
+              include?: string[];
+              exclude?: string[];
+            }
+          | undefined;
+        const tree = await projectTreeBuilder.build(argsObj?.path, {
+          maxDepth: argsObj?.maxDepth,
+          walkDepth: argsObj?.walkDepth,
+          ignore: argsObj?.ignore,
+          includeIgnored: argsObj?.includeIgnored,
+          include: argsObj?.include,
+          exclude: argsObj?.exclude,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(tree, null, 2)
+                  : projectTreeBuilder.formatTree(tree),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Project tree failed', error);
+      }
+    }
+
+    case 'eng_refresh_index': {
+      try {
+        const argsObj = args as
+          | ({
+              ignore?: string[];
+              includeIgnored?: boolean;
+              maxFileSizeBytes?: number;
+              maxDepth?: number;
+            } & BuildOptions)
+          | undefined;
+        const result = await symbolIndexer.refresh({
+          ignore: argsObj?.ignore,
+          includeIgnored: argsObj?.includeIgnored,
+          maxFileSizeBytes: argsObj?.maxFileSizeBytes,
+          maxDepth: argsObj?.maxDepth,
+          build: buildContext(argsObj),
+        });
+        // A depth-limited index is partial, so the saved one is kept
+        if (argsObj?.maxDepth === undefined) await symbolIndexer.saveIndex();
+
+        return {
+          content: [{ type: 'text', text: symbolIndexer.formatRefresh(result) }],
+        };
+      } catch (error) {
+        return toolError('Index refresh failed', error);
+      }
+    }
+
+    case 'eng_symbol_delta': {
+      try {
+        const argsObj = args as
+          | { since?: string; refresh?: boolean; format?: 'text' | 'json' }
+          | undefined;
+        if (argsObj?.refresh !== false) {
+          await symbolIndexer.refresh();
+        }
+        const delta = symbolIndexer.delta(argsObj?.since);
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(delta, null, 2)
+                  : symbolIndexer.formatDelta(delta),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Symbol delta failed', error);
+      }
+    }
+
+    case 'eng_cache_stats': {
+      try {
+        const argsObj = args as { format?: 'text' | 'json' } | undefined;
+        const stats = symbolIndexer.getCacheStats();
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(stats, null, 2)
+                  : symbolIndexer.formatCacheStats(stats),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Cache stats failed', error);
+      }
+    }
+
+    case 'eng_queue_stats': {
+      try {
+        const argsObj = args as { format?: 'text' | 'json' } | undefined;
+        const stats = analysisLimiter.stats();
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(stats, null, 2)
+                  : analysisLimiter.formatStats(stats),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Queue stats failed', error);
+      }
+    }
+
+    case 'eng_add_root': {
+      try {
+        const argsObj = args as { path?: string; name?: string } | undefined;
+        if (!argsObj?.path) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'Root directory required. Usage: eng_add_root --path <dir> [--name <name>]'
+          );
+        }
+
+        const root = await roots.add(argsObj.path, argsObj.name);
+        if (watching) {
+          await roots.get(root.name).indexWatcher.start();
+        }
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text: `✓ Added root "${root.name}" (${root.path})\n\n${roots.formatRoots()}`,
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Add root failed', error);
+      }
+    }
+
+    case 'eng_list_roots': {
+      const argsObj = args as { format?: 'text' | 'json' } | undefined;
+      return {
+        content: [
           {
             type: 'text',
-            text: `Unknown command: ${name}`,
+            text:
+              argsObj?.format === 'json'
+                ? JSON.stringify(roots.list(), null, 2)
+                : roots.formatRoots(),
           },
         ],
-        isError: true,
       };
-  }
-});
+    }
 
-async function main(): Promise<void> {
-  const transport = new StdioServerTransport();
-  await server.connect(transport);
-}
+    case 'eng_complexity': {
+      try {
+        const argsObj = args as
+          | ({ path?: string; format?: 'text' | 'json' } & BufferOptions &
+              ChangeScopeOptions &
+              ScopeOptions)
+          | undefined;
+        const buffer = argsObj?.content !== undefined;
+        const scope = await changeScope.resolve(buffer ? {} : { ...argsObj });
+        const entries =
+          argsObj?.content !== undefined
+            ? complexityAnalyzer.analyzeSource(
+                argsObj.content,
+                argsObj.path ?? BUFFER_FILE,
+                argsObj.language
+              )
+            : await complexityAnalyzer.analyze(argsObj?.path, scope.files, {
+                include: argsObj?.include,
+                exclude: argsObj?.exclude,
+              });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(entries, null, 2)
+                  : withScopeNote(scope, complexityAnalyzer.formatResult(entries)),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Complexity analysis failed', error);
+      }
+    }
+
+    case 'eng_find_clones': {
+      try {
+        const argsObj = args as
+          | ({
+              path?: string;
+              threshold?: number;
+              minLines?: number;
+              format?: 'text' | 'json';
+            } & ScopeOptions)
+          | undefined;
+        const report = await cloneDetector.detect(argsObj?.path, {
+          threshold: argsObj?.threshold,
+          minLines: argsObj?.minLines,
+          include: argsObj?.include,
+          exclude: argsObj?.exclude,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(report, null, 2)
+                  : cloneDetector.formatReport(report),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Clone detection failed', error);
+      }
+    }
+
+    case 'eng_check_docs': {
+      try {
+        const argsObj = args as
+          | ({ path?: string; format?: 'text' | 'json' } & ChangeScopeOptions & ScopeOptions)
+          | undefined;
+        const scope = await changeScope.resolve({ ...argsObj });
+        const violations = await docCommentChecker.check(argsObj?.path, scope.files, {
+          include: argsObj?.include,
+          exclude: argsObj?.exclude,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(violations, null, 2)
+                  : withScopeNote(scope, docCommentChecker.formatResult(violations)),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Doc comment check failed', error);
+      }
+    }
+
+    case 'eng_check_naming': {
+      try {
+        const argsObj = args as
+          | ({ path?: string; format?: 'text' | 'json'; rules?: NamingRule[] } &
+              ChangeScopeOptions &
+              ScopeOptions)
+          | undefined;
+        const scope = await changeScope.resolve({ ...argsObj });
+        const violations = await namingChecker.check(
+          argsObj?.path,
+          scope.files,
+          { include: argsObj?.include, exclude: argsObj?.exclude },
+          argsObj?.rules
+        );
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(violations, null, 2)
+                  : withScopeNote(scope, namingChecker.formatResult(violations)),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Naming check failed', error);
+      }
+    }
+
+    case 'eng_list_deprecated': {
+      try {
+        const argsObj = args as
+          | ({ path?: string; format?: 'text' | 'json' } & ChangeScopeOptions & ScopeOptions)
+          | undefined;
+        const scope = await changeScope.resolve({ ...argsObj });
+        const deprecated = await deprecationFinder.find(argsObj?.path, scope.files, {
+          include: argsObj?.include,
+          exclude: argsObj?.exclude,
+        });
 
-main().catch(console.error);
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(deprecated, null, 2)
+                  : withScopeNote(scope, deprecationFinder.formatResult(deprecated)),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Deprecation search failed', error);
+      }
+    }
+
+    case 'eng_test_gaps': {
+      try {
+        const argsObj = args as { path?: string; format?: 'text' | 'json' } | undefined;
+        const report = await testGapDetector.detect(argsObj?.path);
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(report, null, 2)
+                  : testGapDetector.formatReport(report),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Test gap detection failed', error);
+      }
+    }
+
+    case 'eng_list_tests': {
+      try {
+        const argsObj = args as
+          | ({ path?: string; format?: 'text' | 'json' } & ScopeOptions)
+          | undefined;
+        const report = await testCatalog.list(argsObj?.path, {
+          include: argsObj?.include,
+          exclude: argsObj?.exclude,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(report, null, 2)
+                  : testCatalog.formatReport(report),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Test listing failed', error);
+      }
+    }
+
+    case 'eng_go_examples': {
+      try {
+        const argsObj = args as
+          | ({ path?: string; symbol?: string; format?: 'text' | 'json' } & ScopeOptions)
+          | undefined;
+        const report = await exampleFinder.find(argsObj?.path, {
+          symbol: argsObj?.symbol,
+          include: argsObj?.include,
+          exclude: argsObj?.exclude,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(report, null, 2)
+                  : exampleFinder.formatReport(report),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Example extraction failed', error);
+      }
+    }
+
+    case 'eng_dead_code': {
+      try {
+        const argsObj = args as { path?: string; format?: 'text' | 'json' } | undefined;
+        const report = await deadCodeDetector.detect(argsObj?.path);
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(report, null, 2)
+                  : deadCodeDetector.formatReport(report),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Dead code detection failed', error);
+      }
+    }
+
+    case 'eng_diagnostics': {
+      try {
+        const argsObj = args as
+          | {
+              path?: string;
+              shadow?: boolean;
+              severity?: DiagnosticSeverity;
+              format?: 'text' | 'json';
+            }
+          | undefined;
+        const report = await goDiagnostics.run({ ...argsObj });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(report, null, 2)
+                  : goDiagnostics.formatReport(report),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Diagnostics failed', error);
+      }
+    }
+
+    case 'eng_diff_symbols': {
+      try {
+        const argsObj = args as
+          | {
+              from?: string;
+              to?: string;
+              path?: string;
+              exportedOnly?: boolean;
+              format?: 'text' | 'json';
+              ignore?: string[];
+              includeIgnored?: boolean;
+              include?: string[];
+              exclude?: string[];
+            }
+          | undefined;
+        if (!argsObj?.from) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'Revision required. Usage: eng_diff_symbols --from <ref> [--to <ref>]'
+          );
+        }
+
+        const diff = await symbolDiffer.diff(argsObj.from, argsObj.to, {
+          path: argsObj.path,
+          exportedOnly: argsObj.exportedOnly,
+          ignore: argsObj.ignore,
+          includeIgnored: argsObj.includeIgnored,
+          include: argsObj.include,
+          exclude: argsObj.exclude,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj.format === 'json'
+                  ? JSON.stringify(diff, null, 2)
+                  : symbolDiffer.formatDiff(diff),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Symbol diff failed', error);
+      }
+    }
+
+    case 'eng_compare_complexity': {
+      try {
+        const argsObj = args as
+          | {
+              from?: string;
+              to?: string;
+              path?: string;
+              maxIncrease?: number;
+              newThreshold?: number;
+              format?: 'text' | 'json';
+              ignore?: string[];
+              includeIgnored?: boolean;
+              include?: string[];
+              exclude?: string[];
+            }
+          | undefined;
+        if (!argsObj?.from) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'Revision required. Usage: eng_compare_complexity --from <ref> [--to <ref>]'
+          );
+        }
+        for (const name of ['maxIncrease', 'newThreshold'] as const) {
+          const value = argsObj[name];
+          if (value !== undefined && !(Number.isInteger(value) && value >= 0)) {
+            return errorResult('INVALID_ARGUMENT', `${name} must be a non-negative integer`);
+          }
+        }
+
+        const comparison = await complexityComparer.compare(argsObj.from, argsObj.to, {
+          path: argsObj.path,
+          maxIncrease: argsObj.maxIncrease,
+          newThreshold: argsObj.newThreshold,
+          ignore: argsObj.ignore,
+          includeIgnored: argsObj.includeIgnored,
+          include: argsObj.include,
+          exclude: argsObj.exclude,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj.format === 'json'
+                  ? JSON.stringify(comparison, null, 2)
+                  : complexityComparer.formatComparison(comparison),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Complexity comparison failed', error);
+      }
+    }
+
+    case 'eng_churn_risk': {
+      try {
+        const argsObj = args as
+          | ({
+              path?: string;
+              days?: number;
+              complexityWeight?: number;
+              limit?: number;
+              format?: 'text' | 'json';
+            } & ScopeOptions)
+          | undefined;
+        for (const name of ['days', 'limit'] as const) {
+          const value = argsObj?.[name];
+          if (value !== undefined && !(Number.isInteger(value) && value > 0)) {
+            return errorResult('INVALID_ARGUMENT', `${name} must be a positive integer`);
+          }
+        }
+        const weight = argsObj?.complexityWeight;
+        if (weight !== undefined && !(weight >= 0 && weight <= 1)) {
+          return errorResult('INVALID_ARGUMENT', 'complexityWeight must be between 0 and 1');
+        }
+
+        const report = await churnRiskAnalyzer.analyze(argsObj?.path, {
+          days: argsObj?.days,
+          complexityWeight: weight,
+          limit: argsObj?.limit,
+          include: argsObj?.include,
+          exclude: argsObj?.exclude,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(report, null, 2)
+                  : churnRiskAnalyzer.formatReport(report),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Churn risk analysis failed', error);
+      }
+    }
+
+    case 'eng_api_fingerprint': {
+      try {
+        const argsObj = args as
+          | ({ path?: string; format?: 'text' | 'json' } & ScopeOptions)
+          | undefined;
+        const result = await apiFingerprinter.fingerprint(argsObj?.path, {
+          include: argsObj?.include,
+          exclude: argsObj?.exclude,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(result, null, 2)
+                  : apiFingerprinter.formatFingerprint(result),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('API fingerprint failed', error);
+      }
+    }
+
+    case 'eng_find_references': {
+      try {
+        const argsObj = args as
+          | ({
+              symbol?: string;
+              file?: string;
+              line?: number;
+              contextBefore?: number;
+              contextAfter?: number;
+              format?: 'text' | 'json';
+            } & StreamOptions &
+              PageOptions)
+          | undefined;
+        const stream = openStream<ReferenceEntry>(extra.sendNotification, progressToken, argsObj);
+        const query = {
+          symbol: argsObj?.symbol,
+          file: argsObj?.file,
+          line: argsObj?.line,
+          contextBefore: argsObj?.contextBefore,
+          contextAfter: argsObj?.contextAfter,
+        };
+
+        // Without a root, usages are collected from every root; file and line
+        // pin a definition inside one root (default: the first)
+        const targets = query.file && !rootName ? [project] : roots.select(rootName);
+        const tagRoots = roots.list().length > 1;
+        const result: ReferenceResult = { symbol: '', definitions: [], references: [] };
+        for (const target of targets) {
+          const withRoot = (references: ReferenceEntry[]): ReferenceEntry[] =>
+            tagRoots ? references.map(r => ({ ...r, root: target.root.name })) : references;
+          const found = await target.referenceFinder.find(
+            query,
+            stream ? batch => stream.push(withRoot(batch)) : undefined
+          );
+          result.symbol ||= found.symbol;
+          result.definitions.push(...found.definitions);
+          result.references.push(...withRoot(found.references));
+        }
+
+        if (stream) {
+          const summary = await stream.end();
+          return {
+            content: [
+              {
+                type: 'text',
+                text: JSON.stringify({ symbol: result.symbol, ...summary }, null, 2),
+              },
+            ],
+          };
+        }
+
+        // The finder scans first, so the fingerprint reflects the index it searched
+        const page =
+          argsObj && isPaginated(argsObj)
+            ? paginate(
+                result.references,
+                argsObj,
+                queryFingerprint(
+                  result.symbol,
+                  argsObj.file,
+                  argsObj.line,
+                  targets.map(target => target.symbolIndexer.fingerprint())
+                )
+              )
+            : undefined;
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(
+                      {
+                        symbol: result.symbol,
+                        total: result.references.length,
+                        references: page?.items ?? result.references,
+                        nextCursor: page?.nextCursor,
+                      },
+                      null,
+                      2
+                    )
+                  : page
+                    ? referenceFinder.formatResult({ ...result, references: page.items }) +
+                      formatNextCursor(page.nextCursor)
+                    : referenceFinder.formatResult(result),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Find references failed', error);
+      }
+    }
+
+    case 'eng_rename_symbol': {
+      try {
+        const argsObj = args as
+          | {
+              symbol?: string;
+              newName?: string;
+              file?: string;
+              line?: number;
+              apply?: boolean;
+              backup?: boolean;
+              format?: 'text' | 'json';
+            }
+          | undefined;
+        if (!argsObj?.newName || (!argsObj.symbol && !argsObj.file)) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'Symbol and new name required. Usage: eng_rename_symbol --symbol <name> --newName <name>'
+          );
+        }
+
+        const plan = await renamePlanner.plan({
+          symbol: argsObj.symbol,
+          newName: argsObj.newName,
+          file: argsObj.file,
+          line: argsObj.line,
+          apply: argsObj.apply,
+          backup: argsObj.backup,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj.format === 'json'
+                  ? JSON.stringify(plan, null, 2)
+                  : renamePlanner.formatPlan(plan),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Rename failed', error);
+      }
+    }
+
+    case 'eng_format_code': {
+      try {
+        const argsObj = args as
+          | ({
+              path?: string;
+              apply?: boolean;
+              backup?: boolean;
+              format?: 'text' | 'json';
+            } & BufferOptions)
+          | undefined;
+        if (!argsObj?.path && argsObj?.content === undefined) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'File path or content required. Usage: eng_format_code --path <file>'
+          );
+        }
+
+        const result = await codeFormatter.format({ ...argsObj });
+
+        // Unparseable code is an error, never mangled output
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(result, null, 2)
+                  : codeFormatter.formatResult(result),
+            },
+          ],
+          ...(result.error
+            ? {
+                isError: true,
+                _meta: { error: { code: 'PARSE_ERROR', message: result.error, path: result.file } },
+              }
+            : {}),
+        };
+      } catch (error) {
+        return toolError('Formatting failed', error);
+      }
+    }
+
+    case 'eng_type_info': {
+      try {
+        const argsObj = args as
+          | { type?: string; file?: string; format?: 'text' | 'json' }
+          | undefined;
+        if (!argsObj?.type) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'Type name required. Usage: eng_type_info --type <name>'
+          );
+        }
+
+        const details = await typeInspector.inspect(argsObj.type, argsObj.file);
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj.format === 'json'
+                  ? JSON.stringify(details, null, 2)
+                  : typeInspector.formatDetails(argsObj.type, details),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Type lookup failed', error);
+      }
+    }
+
+    case 'eng_implementations': {
+      try {
+        const argsObj = args as { interface?: string; format?: 'text' | 'json' } | undefined;
+        if (!argsObj?.interface) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'Interface name required. Usage: eng_implementations --interface <name>'
+          );
+        }
+
+        const reports = await implementationFinder.find(argsObj.interface);
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj.format === 'json'
+                  ? JSON.stringify(reports, null, 2)
+                  : implementationFinder.formatReports(argsObj.interface, reports),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Implementation search failed', error);
+      }
+    }
+
+    case 'eng_symbol_context': {
+      try {
+        const argsObj = args as
+          | { file?: string; line?: number; format?: 'text' | 'json'; includeBlame?: boolean }
+          | undefined;
+        if (!argsObj?.file || argsObj.line === undefined) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'File and line required. Usage: eng_symbol_context --file <path> --line <n>'
+          );
+        }
+
+        const context = await symbolContextResolver.resolve(argsObj.file, argsObj.line);
+        if (argsObj.includeBlame) {
+          const [blamed] = await symbolIndexer.withBlame([context.symbol]);
+          if (blamed) context.symbol = blamed;
+        }
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj.format === 'json'
+                  ? JSON.stringify(context, null, 2)
+                  : symbolContextResolver.formatContext(context),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Symbol context failed', error);
+      }
+    }
+
+    case 'eng_analyze_function': {
+      try {
+        const argsObj = args as
+          | { file?: string; symbol?: string; line?: number; format?: 'text' | 'json' }
+          | undefined;
+        if (!argsObj?.file || (!argsObj.symbol && argsObj.line === undefined)) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'File and a symbol or line required. Usage: eng_analyze_function --file <path> --symbol <name>'
+          );
+        }
+
+        const report = await functionAnalyzer.analyze({
+          file: argsObj.file,
+          symbol: argsObj.symbol,
+          line: argsObj.line,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj.format === 'json'
+                  ? JSON.stringify(report, null, 2)
+                  : functionAnalyzer.formatReport(report),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Function analysis failed', error);
+      }
+    }
+
+    case 'eng_resolve_symbol': {
+      try {
+        const argsObj = args as
+          | { file?: string; line?: number; column?: number; format?: 'text' | 'json' }
+          | undefined;
+        if (!argsObj?.file || argsObj.line === undefined || argsObj.column === undefined) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'File, line, and column required. Usage: eng_resolve_symbol --file <path> --line <n> --column <n>'
+          );
+        }
+
+        const result = await definitionResolver.resolve(argsObj.file, argsObj.line, argsObj.column);
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj.format === 'json'
+                  ? JSON.stringify(result, null, 2)
+                  : definitionResolver.formatResult(result),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Symbol resolution failed', error);
+      }
+    }
+
+    case 'eng_hover': {
+      try {
+        const argsObj = args as
+          | { file?: string; line?: number; column?: number; format?: 'text' | 'json' }
+          | undefined;
+        if (!argsObj?.file || argsObj.line === undefined || argsObj.column === undefined) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'File, line, and column required. Usage: eng_hover --file <path> --line <n> --column <n>'
+          );
+        }
+
+        const hover = await definitionResolver.hover(argsObj.file, argsObj.line, argsObj.column);
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj.format === 'json'
+                  ? JSON.stringify(hover, null, 2)
+                  : definitionResolver.formatHover(hover),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Hover failed', error);
+      }
+    }
+
+    case 'eng_external_definition': {
+      try {
+        const argsObj = args as
+          | { symbol?: string; file?: string; format?: 'text' | 'json' }
+          | undefined;
+        if (!argsObj?.symbol) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'Symbol required. Usage: eng_external_definition --symbol <pkg.Name> [--file <path>]'
+          );
+        }
+
+        const result = await externalResolver.resolve(argsObj.symbol, argsObj.file);
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj.format === 'json'
+                  ? JSON.stringify(result, null, 2)
+                  : externalResolver.formatDefinition(result),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('External definition lookup failed', error);
+      }
+    }
+
+    case 'eng_remote_symbols': {
+      try {
+        const argsObj = args as
+          | ({
+              url?: string;
+              ref?: string;
+              path?: string;
+              kinds?: string[];
+              exportedOnly?: boolean;
+              signaturesOnly?: boolean;
+              docMode?: string;
+              maxRepoBytes?: number;
+              format?: 'text' | 'json';
+            } & ScopeOptions)
+          | undefined;
+        if (!argsObj?.url) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'Repository URL required. Usage: eng_remote_symbols --url <git url> [--ref <ref>]'
+          );
+        }
+        const maxRepoBytes = argsObj.maxRepoBytes;
+        if (maxRepoBytes !== undefined && (!Number.isInteger(maxRepoBytes) || maxRepoBytes < 1)) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            `maxRepoBytes must be a positive integer, got ${maxRepoBytes}`
+          );
+        }
+
+        const ofKind = kindFilter(argsObj.kinds);
+        const trimDoc = docTrimmer(argsObj.docMode);
+        const result = await remoteRepos.extract(argsObj.url, {
+          ref: argsObj.ref,
+          path: argsObj.path,
+          include: argsObj.include,
+          exclude: argsObj.exclude,
+          maxRepoBytes,
+        });
+        const symbols = result.symbols.filter(
+          s => (ofKind?.(s) ?? true) && (!argsObj.exportedOnly || s.exported)
+        );
+
+        if (argsObj.format !== 'json') {
+          return {
+            content: [
+              {
+                type: 'text',
+                text: remoteRepos.formatResult({ ...result, symbols }, argsObj.signaturesOnly),
+              },
+            ],
+          };
+        }
+
+        const trimmed = trimDoc ? symbols.map(trimDoc) : symbols;
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(
+                {
+                  url: result.url,
+                  ref: result.ref,
+                  commit: result.commit,
+                  cached: result.cached,
+                  sizeBytes: result.sizeBytes,
+                  total: symbols.length,
+                  symbols: argsObj.signaturesOnly ? trimmed.map(s => toSignature(s)) : trimmed,
+                  excluded: result.excluded,
+                },
+                null,
+                2
+              ),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Remote symbol extraction failed', error);
+      }
+    }
+
+    case 'eng_call_graph': {
+      try {
+        const argsObj = args as
+          | { path?: string; includeExternal?: boolean; format?: 'text' | 'json' | 'dot' }
+          | undefined;
+        const graph = await callGraphBuilder.build(argsObj?.path, {
+          includeExternal: argsObj?.includeExternal,
+        });
+
+        let text: string;
+        if (argsObj?.format === 'json') {
+          text = JSON.stringify(graph, null, 2);
+        } else if (argsObj?.format === 'dot') {
+          text = callGraphBuilder.formatDot(graph);
+        } else {
+          text = callGraphBuilder.formatResult(graph);
+        }
+
+        return {
+          content: [{ type: 'text', text }],
+        };
+      } catch (error) {
+        return toolError('Call graph failed', error);
+      }
+    }
+
+    case 'eng_list_routes': {
+      try {
+        const argsObj = args as
+          | ({ path?: string; format?: 'text' | 'json' } & ScopeOptions)
+          | undefined;
+        const routes = await routeFinder.find(argsObj?.path, {
+          include: argsObj?.include,
+          exclude: argsObj?.exclude,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(routes, null, 2)
+                  : routeFinder.formatRoutes(routes),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Route listing failed', error);
+      }
+    }
+
+    case 'eng_imports': {
+      try {
+        const argsObj = args as { path?: string; format?: 'text' | 'json' } | undefined;
+        const report = await importAnalyzer.analyze(argsObj?.path);
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(report, null, 2)
+                  : importAnalyzer.formatReport(report),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Import analysis failed', error);
+      }
+    }
+
+    case 'eng_unused_imports': {
+      try {
+        const argsObj = args as { path?: string; format?: 'text' | 'json' } | undefined;
+        const report = await importAnalyzer.unusedImports(argsObj?.path);
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(report, null, 2)
+                  : importAnalyzer.formatUnusedImports(report),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Unused import check failed', error);
+      }
+    }
+
+    case 'eng_validate_references': {
+      try {
+        const argsObj = args as
+          | ({ path?: string; format?: 'text' | 'json' } & ScopeOptions)
+          | undefined;
+        const report = await referenceValidator.validate(argsObj?.path, {
+          include: argsObj?.include,
+          exclude: argsObj?.exclude,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(report, null, 2)
+                  : referenceValidator.formatReport(report),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Reference validation failed', error);
+      }
+    }
+
+    case 'eng_package_graph': {
+      try {
+        const argsObj = args as
+          | { path?: string; collapseExternal?: boolean; format?: 'text' | 'json' | 'dot' }
+          | undefined;
+        const graph = await importAnalyzer.packageGraph(argsObj?.path, {
+          collapseExternal: argsObj?.collapseExternal,
+        });
+
+        let text: string;
+        if (argsObj?.format === 'json') {
+          text = JSON.stringify(graph, null, 2);
+        } else if (argsObj?.format === 'dot') {
+          text = importAnalyzer.formatDot(graph);
+        } else {
+          text = importAnalyzer.formatGraph(graph);
+        }
+
+        return {
+          content: [{ type: 'text', text }],
+        };
+      } catch (error) {
+        return toolError('Package graph failed', error);
+      }
+    }
+
+    case 'eng_related_files': {
+      try {
+        const argsObj = args as
+          | {
+              file?: string;
+              format?: 'text' | 'json';
+              ignore?: string[];
+              includeIgnored?: boolean;
+              include?: string[];
+              exclude?: string[];
+            }
+          | undefined;
+        if (!argsObj?.file) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'File required. Usage: eng_related_files --file <path>'
+          );
+        }
+
+        const related = await relatedFileFinder.find(argsObj.file, {
+          ignore: argsObj.ignore,
+          includeIgnored: argsObj.includeIgnored,
+          include: argsObj.include,
+          exclude: argsObj.exclude,
+        });
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj.format === 'json'
+                  ? JSON.stringify(related, null, 2)
+                  : relatedFileFinder.formatRelated(related),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Related file lookup failed', error);
+      }
+    }
+
+    case 'eng_detect_language': {
+      try {
+        const argsObj = args as { path?: string; format?: 'text' | 'json' } | undefined;
+        const report = await languageDetector.detect(argsObj?.path);
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(report, null, 2)
+                  : languageDetector.formatReport(report),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Language detection failed', error);
+      }
+    }
+
+    case 'eng_read_file': {
+      try {
+        const argsObj = args as
+          | { path?: string; startLine?: number; endLine?: number; format?: 'text' | 'json' }
+          | undefined;
+        if (!argsObj?.path) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'File path required. Usage: eng_read_file --path <file>'
+          );
+        }
+
+        const slice = await fileReader.read(argsObj.path, argsObj.startLine, argsObj.endLine);
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj.format === 'json'
+                  ? JSON.stringify(slice, null, 2)
+                  : fileReader.formatSlice(slice),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Read file failed', error);
+      }
+    }
+
+    case 'eng_server_info': {
+      try {
+        const argsObj = args as { format?: 'text' | 'json' } | undefined;
+        const info = await getServerInfo(
+          registerCommands().map(tool => tool.name),
+          roots.select().map(p => ({ ...p.root, index: p.symbolIndexer.getIndexStats() }))
+        );
+
+        return {
+          content: [
+            {
+              type: 'text',
+              text:
+                argsObj?.format === 'json'
+                  ? JSON.stringify(info, null, 2)
+                  : formatServerInfo(info),
+            },
+          ],
+        };
+      } catch (error) {
+        return toolError('Server info failed', error);
+      }
+    }
+
+    case 'eng_describe_tool': {
+      try {
+        const argsObj = args as { tool?: string } | undefined;
+        if (!argsObj?.tool) {
+          return errorResult(
+            'INVALID_ARGUMENT',
+            'Tool name required. Usage: eng_describe_tool --tool <name>'
+          );
+        }
+
+        const description = describeTool(argsObj.tool);
+        if (!description) {
+          return errorResult('INVALID_ARGUMENT', `Unknown tool: ${argsObj.tool}`);
+        }
+
+        return {
+          content: [{ type: 'text', text: JSON.stringify(description, null, 2) }],
+        };
+      } catch (error) {
+        return toolError('Describe tool failed', error);
+      }
+    }
+
+    default:
+      return errorResult('UNKNOWN_TOOL', `Unknown command: ${name}`);
+  }
+}
+
+/**
+ * A failed call: the text for people, and _meta.error with a stable code,
+ * the message, and the offending path for clients to branch on
+ */
+function errorResult(code: ErrorCode, text: string, path?: string): CallToolResult {
+  const error: ErrorInfo = { code, message: text, path };
+  return { content: [{ type: 'text', text }], isError: true, _meta: { error } };
+}
+
+/**
+ * errorResult for a thrown error, coded by what was thrown; paths are
+ * reported relative to their root
+ */
+function toolError(summary: string, error: unknown): CallToolResult {
+  const info = describeError(error, roots.list().map(r => r.path));
+  return {
+    content: [{ type: 'text', text: `${summary}: ${String(error)}` }],
+    isError: true,
+    _meta: { error: info },
+  };
+}
+
+/**
+ * Blame for symbols gathered across roots, each from its own root's
+ * repository; symbols without a root are the primary root's
+ */
+async function withBlameByRoot(symbols: SymbolEntry[]): Promise<SymbolEntry[]> {
+  const blamed = [...symbols];
+  const primary = roots.primary().root.name;
+  for (const project of roots.select()) {
+    const indexes = symbols.flatMap((symbol, i) =>
+      (symbol.root ?? primary) === project.root.name ? [i] : []
+    );
+    if (indexes.length === 0) continue;
+
+    const result = await project.symbolIndexer.withBlame(
+      indexes.map(i => blamed[i] as SymbolEntry)
+    );
+    indexes.forEach((index, i) => {
+      const entry = result[i];
+      if (entry) blamed[index] = entry;
+    });
+  }
+  return blamed;
+}
+
+/**
+ * Complexity for symbols from the given roots; symbols carry no root tag when
+ * only one root is registered
+ */
+async function withComplexityByRoot(
+  targets: Project[],
+  symbols: SymbolEntry[]
+): Promise<SymbolEntry[]> {
+  const measured = [...symbols];
+  for (const target of targets) {
+    const indexes = symbols.flatMap((symbol, i) =>
+      symbol.root === undefined || symbol.root === target.root.name ? [i] : []
+    );
+    if (indexes.length === 0) continue;
+
+    const result = await target.complexityAnalyzer.withComplexity(
+      indexes.map(i => measured[i] as SymbolEntry)
+    );
+    indexes.forEach((index, i) => {
+      const entry = result[i];
+      if (entry) measured[index] = entry;
+    });
+  }
+  return measured;
+}
+
+/**
+ * One text search over several roots, with maxResults shared between them
+ */
+async function searchTextAcross(
+  targets: Project[],
+  options: TextSearchOptions
+): Promise<TextSearchResult> {
+  const maxResults = Math.max(1, options.maxResults ?? DEFAULT_MAX_RESULTS);
+  const tagRoots = roots.list().length > 1;
+  const combined: TextSearchResult = {
+    pattern: options.pattern,
+    matches: [],
+    filesSearched: 0,
+    truncated: false,
+  };
+
+  for (const target of targets) {
+    // Once the budget is spent, look for one more match only to report truncation
+    const remaining = maxResults - combined.matches.length;
+    const result = await target.textSearcher.search({
+      ...options,
+      maxResults: Math.max(1, remaining),
+    });
+    combined.filesSearched += result.filesSearched;
+    const matches = result.matches.slice(0, remaining);
+    combined.matches.push(
+      ...(tagRoots ? matches.map(match => ({ ...match, root: target.root.name })) : matches)
+    );
+    if (result.truncated || result.matches.length > remaining) {
+      combined.truncated = true;
+      break;
+    }
+  }
+
+  return combined;
+}
+
+function withScopeNote(scope: ResolvedScope, text: string): string {
+  return scope.note ? `${scope.note}\n\n${text}` : text;
+}
+
+/**
+ * Symbol filter dropping generated files, adding each file it drops to
+ * skipped (prefixed with the root name when several roots are searched)
+ */
+function generatedFilter(
+  indexer: SymbolIndexer,
+  skipped: Set<string>,
+  root?: string
+): (symbol: SymbolEntry) => boolean {
+  return symbol => {
+    if (!indexer.isGenerated(symbol.file)) return true;
+    skipped.add(root ? `${root}:${symbol.file}` : symbol.file);
+    return false;
+  };
+}
+
+function formatGeneratedNote(skipped: number): string {
+  return skipped > 0
+    ? `\n\nLeft out ${skipped} generated file(s); pass includeGenerated to include them.`
+    : '';
+}
+
+// Source text standing in for a file on disk, e.g. an unsaved editor buffer
+interface BufferOptions {
+  content?: string;
+  language?: string;
+}
+
+interface StreamOptions {
+  stream?: boolean;
+  batchSize?: number;
+}
+
+/**
+ * Batch stream for a list tool, or undefined unless streaming was requested.
+ * Progress notifications need a token from the client to be routed.
+ */
+function openStream<T>(
+  sendNotification: NotificationSender,
+  progressToken: ProgressToken | undefined,
+  options: StreamOptions | undefined
+): ResultStream<T> | undefined {
+  if (!options?.stream) {
+    return undefined;
+  }
+  if (progressToken === undefined) {
+    throw new ToolError(
+      'INVALID_ARGUMENT',
+      'Streaming requires a progressToken in the request _meta'
+    );
+  }
+  return new ResultStream<T>(sendNotification, progressToken, options.batchSize);
+}
+
+/**
+ * Non-negative integer from --flag <n> or --flag=<n>: --cache-size (0 disables
+ * the parse cache), --max-concurrent (0 = unlimited), --queue-timeout in ms
+ * (0 = wait indefinitely), --port and --metrics-port (0 = any free port), --timeout
+ * in ms (0 = none), --grace-period in ms, --parse-workers (0 or 1 parses on the
+ * main thread), --max-response-bytes (0 = no limit)
+ */
+function integerOption(argv: string[], name: string): number | undefined {
+  const index = argv.findIndex(a => a === name || a.startsWith(`${name}=`));
+  if (index === -1) {
+    return undefined;
+  }
+
+  const flag = argv[index] ?? '';
+  const value = flag.includes('=') ? flag.slice(flag.indexOf('=') + 1) : argv[index + 1];
+  const number = Number(value);
+  if (!value || !Number.isInteger(number) || number < 0) {
+    console.error(`Invalid ${name}: ${value ?? '(missing)'}`);
+    process.exit(1);
+  }
+  return number;
+}
+
+/**
+ * Every value of a repeatable --flag <value> or --flag=<value>
+ */
+function stringOptions(argv: string[], name: string): string[] {
+  const values: string[] = [];
+  argv.forEach((flag, index) => {
+    if (flag.startsWith(`${name}=`)) {
+      values.push(flag.slice(name.length + 1));
+    } else if (flag === name) {
+      const value = argv[index + 1];
+      if (!value || value.startsWith('--')) {
+        console.error(`Invalid ${name}: (missing)`);
+        process.exit(1);
+      }
+      values.push(value);
+    }
+  });
+  return values;
+}
+
+let httpTransport: HttpTransportServer | undefined;
+
+async function main(): Promise<void> {
+  // --transport http serves many clients at --host/--port; stdio serves the one that spawned us
+  const [transport = 'stdio'] = stringOptions(args, '--transport');
+  if (transport === 'http') {
+    const [host] = stringOptions(args, '--host');
+    // The environment keeps the token out of process listings
+    const [authToken = process.env.MCP_AUTH_TOKEN] = stringOptions(args, '--auth-token');
+    httpTransport = new HttpTransportServer(createServer);
+    const address = await httpTransport.listen({
+      host,
+      port: integerOption(args, '--port'),
+      authToken,
+    });
+    logger.info(`MCP server listening on http://${address.host}:${address.port}${MCP_PATH}`);
+    logger.info(
+      authToken
+        ? 'Bearer token authentication enabled'
+        : 'Authentication disabled: set MCP_AUTH_TOKEN or --auth-token to require a bearer token'
+    );
+  } else if (transport === 'stdio') {
+    await createServer().connect(new StdioServerTransport());
+  } else {
+    console.error(`Invalid --transport: ${transport} (expected stdio or http)`);
+    process.exit(1);
+  }
+
+  if (metrics && metricsPort !== undefined) {
+    const [metricsHost] = stringOptions(args, '--metrics-host');
+    const address = await metrics.listen({ host: metricsHost, port: metricsPort }, serverGauges);
+    logger.info(`Metrics served at http://${address.host}:${address.port}${METRICS_PATH}`);
+  }
+
+  if (watching) {
+    for (const project of roots.select()) {
+      await project.indexWatcher.start();
+    }
+  }
+
+  process.once('SIGTERM', () => void shutdown('SIGTERM'));
+  process.once('SIGINT', () => void shutdown('SIGINT'));
+}
+
+/**
+ * Stop taking tool calls, let running ones finish within the grace period,
+ * cancel the rest, and exit: non-zero if anything had to be cancelled. A
+ * second signal exits at once.
+ */
+async function shutdown(signal: NodeJS.Signals): Promise<void> {
+  process.once(signal, () => process.exit(1));
+  logger.info(
+    `${signal} received: waiting up to ${gracePeriodMs} ms for ${inFlight.size} in-flight call(s)`
+  );
+
+  const { drained, cancelled } = await inFlight.drain(gracePeriodMs);
+  logger.info(`Shutdown: ${drained} call(s) drained, ${cancelled} cancelled`);
+
+  for (const project of roots.select()) {
+    project.indexWatcher.stop();
+  }
+  await Promise.all([...connectedServers].map(server => server.close().catch(() => undefined)));
+  await httpTransport?.close().catch(() => undefined);
+  await metrics?.close().catch(() => undefined);
+  await remoteRepos.clear().catch(() => undefined);
+  process.exit(cancelled > 0 ? 1 : 0);
+}
+
+main().catch((error: unknown) => {
+  logger.error('Server failed', { error });
+  process.exit(1);
+});
@@ -0,0 +1,314 @@
+/**
+ * API Fingerprint
+ * Stable hash of a package's exported API: signatures normalized so that
+ * parameter names, whitespace, declaration order, and bodies don't count
+ */
+
+import * as crypto from 'crypto';
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { SymbolEntry } from '../types/index.js';
+import { checkDeadline } from '../core/deadline.js';
+import { resolveProjectPath } from '../core/file-reader.js';
+import type { ScopeOptions } from '../core/file-walker.js';
+import { parseGoParameters, parseGoResults, parseGoStructFields } from '../parsers/go-parser.js';
+import { GO_SYNTAX, SourceText } from '../parsers/source.js';
+import { SymbolIndexer, qualifiedName } from './symbol-indexer.js';
+import { receiverKind } from './type-inspector.js';
+
+export interface ApiFingerprint {
+  path: string;
+  fingerprint: string; // SHA-256 of the sorted signatures, hex
+  symbols: number; // Exported symbols contributing, not counting fields and interface methods
+  signatures: string[]; // Sorted; "<package>: " prefixed when the path spans several
+}
+
+// Tests aren't part of the API a package exports
+const TEST_FILES = [
+  /_test\.go$/,
+  /[.-](?:test|spec)\.[cm]?[jt]sx?$/,
+  /(?:^|\/)test_[^/]*\.py$/,
+];
+
+// "name?: " and "name: " in TypeScript and Rust parameter lists
+const TYPED_NAME = /(?:\bmut\s+)?[A-Za-z_$][\w$]*\??\s*:(?!:)\s*/g;
+
+export class ApiFingerprinter {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  async fingerprint(target = '.', scope: ScopeOptions = {}): Promise<ApiFingerprint> {
+    const workingDir = this.symbolIndexer.getWorkingDir();
+    const root = resolveProjectPath(workingDir, target);
+    const symbols = await this.symbolIndexer.scan(target, scope);
+    // A scan cut short by the deadline would hash a partial API
+    checkDeadline();
+
+    const exported = symbols.filter(s => s.exported && !isTestFile(s.file) && !onHiddenType(s));
+    const packages = new Set(exported.map(s => packageOf(root, s)));
+    const signatures: string[] = [];
+
+    for (const symbol of exported) {
+      const prefix = packages.size > 1 ? `${packageOf(root, symbol)}: ` : '';
+      for (const signature of await this.normalize(symbol)) {
+        signatures.push(prefix + signature);
+      }
+    }
+    signatures.sort();
+
+    return {
+      path: root || '.',
+      fingerprint: crypto.createHash('sha256').update(signatures.join('\n')).digest('hex'),
+      symbols: exported.length,
+      signatures,
+    };
+  }
+
+  /**
+   * Normalized lines for a symbol: its own signature, then for Go structs and
+   * interfaces one per exported field or method
+   */
+  private async normalize(symbol: SymbolEntry): Promise<string[]> {
+    if (symbol.language !== 'go') {
+      return [`${symbol.kind} ${qualifiedName(symbol)} ${normalizeSignature(symbol)}`.trimEnd()];
+    }
+
+    if (symbol.kind === 'function' || symbol.kind === 'method') {
+      return [goFunction(symbol)];
+    }
+    if (symbol.kind === 'const' || symbol.kind === 'var') {
+      // Values aren't API; an explicit type is
+      return [collapse(symbol.signature.replace(/\s*=[\s\S]*$/, ''))];
+    }
+    if (symbol.kind !== 'struct' && symbol.kind !== 'interface') {
+      return [goType(symbol.signature)];
+    }
+
+    const lines = [collapse(symbol.signature)];
+    const body = await this.readBody(symbol);
+    if (!body) return lines;
+
+    if (symbol.kind === 'struct') {
+      for (const field of parseGoStructFields(body.source, body.open)) {
+        if (!field.exported) continue;
+        const embedded = field.embedded ? ' (embedded)' : '';
+        lines.push(`field ${symbol.name}.${field.name} ${goType(field.type)}${embedded}`);
+      }
+    } else {
+      lines.push(...goInterfaceMembers(body.source, body.open, symbol.name));
+    }
+    return lines;
+  }
+
+  /**
+   * Masked source of a Go type's file and the offset of its struct or
+   * interface brace
+   */
+  private async readBody(
+    symbol: SymbolEntry
+  ): Promise<{ source: SourceText; open: number } | undefined> {
+    const fullPath = path.join(this.symbolIndexer.getWorkingDir(), symbol.file);
+    let content: string;
+    try {
+      content = await fs.readFile(fullPath, 'utf-8');
+    } catch {
+      // Skip files that can't be read
+      return undefined;
+    }
+
+    const source = new SourceText(content, GO_SYNTAX);
+    const start = source.lineStart(symbol.line);
+    const declaration = source.masked.slice(start, source.lineStart(symbol.endLine + 1));
+    const brace = /\b(?:struct|interface)\s*\{/.exec(declaration);
+    if (!brace) return undefined;
+    return { source, open: start + brace.index + brace[0].length - 1 };
+  }
+
+  formatFingerprint(result: ApiFingerprint): string {
+    let output = `API fingerprint of ${result.path}: ${result.fingerprint}\n`;
+    output += `${result.symbols} exported symbol(s), ${result.signatures.length} signature(s)\n`;
+    if (result.signatures.length > 0) {
+      output += '\n';
+      for (const signature of result.signatures) output += `  ${signature}\n`;
+    }
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+function isTestFile(file: string): boolean {
+  return TEST_FILES.some(pattern => pattern.test(file));
+}
+
+/**
+ * Exported Go methods of unexported types are unreachable from other packages
+ */
+function onHiddenType(symbol: SymbolEntry): boolean {
+  return (
+    symbol.language === 'go' && symbol.kind === 'method' && !/^[A-Z]/.test(symbol.parent ?? '')
+  );
+}
+
+/**
+ * A Go package is its directory; elsewhere each file is its own module
+ */
+function packageOf(root: string, symbol: SymbolEntry): string {
+  const unit = symbol.language === 'go' ? path.posix.dirname(symbol.file) : symbol.file;
+  const relative = root ? path.posix.relative(root, unit) : unit;
+  return relative || '.';
+}
+
+/**
+ * "func (c *Client) Do(ctx context.Context, req *Request) (resp *Response, err error)"
+ * -> "func (*Client) Do(context.Context, *Request) (*Response, error)"
+ */
+function goFunction(symbol: SymbolEntry): string {
+  const receiver =
+    symbol.kind === 'method'
+      ? `(${receiverKind(symbol.signature) === 'pointer' ? '*' : ''}${symbol.parent ?? ''}) `
+      : '';
+  const typeParams = symbol.typeParams?.length
+    ? `[${symbol.typeParams.map(p => `${p.name} ${collapse(p.constraint)}`).join(', ')}]`
+    : '';
+  const params = (symbol.params ?? [])
+    .map(p => `${p.variadic ? '...' : ''}${goType(p.type)}`)
+    .join(', ');
+  const results = goResults(symbol.returns ?? []);
+  return `func ${receiver}${symbol.name}${typeParams}(${params})${results}`;
+}
+
+function goResults(results: Array<{ type: string }>): string {
+  if (results.length === 0) return '';
+  const types = results.map(r => goType(r.type));
+  return results.length === 1 ? ` ${types[0]}` : ` (${types.join(', ')})`;
+}
+
+/**
+ * A Go type with parameter names dropped from any func types inside it:
+ * "func(ctx context.Context) error" -> "func(context.Context) error"
+ */
+function goType(type: string): string {
+  const text = collapse(type);
+  const func = /\bfunc\s*\(/.exec(text);
+  if (!func) return text;
+
+  const open = func.index + func[0].length - 1;
+  const close = matchingParen(text, open);
+  if (close === -1) return text;
+
+  const params = parseGoParameters(text.slice(open + 1, close))
+    .map(p => `${p.variadic ? '...' : ''}${goType(p.type)}`)
+    .join(', ');
+  let rest = text.slice(close + 1).trim();
+  let results = '';
+  if (rest.startsWith('(')) {
+    const end = matchingParen(rest, 0);
+    if (end !== -1) {
+      results = goResults(parseGoResults(rest.slice(0, end + 1)));
+      rest = rest.slice(end + 1).trim();
+    }
+  } else if (rest !== '') {
+    // A single unnamed result runs to the end: func() map[string]func(int) error
+    results = ` ${goType(rest)}`;
+    rest = '';
+  }
+  return `${text.slice(0, func.index)}func(${params})${results}${rest ? ` ${rest}` : ''}`;
+}
+
+/**
+ * Method and embedded-type lines of a Go interface body; type-set lines such
+ * as "~int | ~float64" are kept as written
+ */
+function goInterfaceMembers(source: SourceText, open: number, typeName: string): string[] {
+  const close = source.findMatching(open);
+  if (close === -1) return [];
+
+  const members: string[] = [];
+  let depth = 0;
+  let start = open + 1;
+  for (let i = open + 1; i <= close; i++) {
+    const ch = source.masked[i] ?? '';
+    if ('({['.includes(ch)) depth++;
+    else if (')}]'.includes(ch) && i < close) depth--;
+    if (i !== close && (depth > 0 || (ch !== '\n' && ch !== ';'))) continue;
+
+    const entry = collapse(source.masked.slice(start, i));
+    start = i + 1;
+    if (entry === '') continue;
+
+    const method = /^([A-Za-z_]\w*)\s*\(/.exec(entry);
+    if (!method?.[1]) {
+      members.push(`embed ${typeName} ${entry}`);
+    } else if (/^[A-Z]/.test(method[1])) {
+      const paramsClose = matchingParen(entry, method[0].length - 1);
+      if (paramsClose === -1) continue;
+      const params = parseGoParameters(entry.slice(method[0].length, paramsClose))
+        .map(p => `${p.variadic ? '...' : ''}${goType(p.type)}`)
+        .join(', ');
+      const results = goResults(parseGoResults(entry.slice(paramsClose + 1)));
+      members.push(`method ${typeName}.${method[1]}(${params})${results}`);
+    }
+  }
+  return members;
+}
+
+/**
+ * Signature of a non-Go symbol with parameter names removed where types
+ * carry the meaning: "name: T" (TypeScript, Rust) and "T name" (Java, C++).
+ * Python keeps its names, since callers may pass arguments by keyword.
+ */
+function normalizeSignature(symbol: SymbolEntry): string {
+  const text = collapse(symbol.signature);
+  const open = text.indexOf('(');
+  const close = open === -1 ? -1 : matchingParen(text, open);
+  if (close === -1 || symbol.language === 'python') return text;
+
+  const params = splitParams(text.slice(open + 1, close)).map(param => {
+    const withoutDefault = param.replace(/\s*=(?![>=])[\s\S]*$/, '');
+    if (symbol.language === 'typescript' || symbol.language === 'rust') {
+      return withoutDefault.replace(TYPED_NAME, '');
+    }
+    // Java, C, C++: the trailing identifier names the parameter when a type precedes it
+    return withoutDefault.replace(/([\w>\]*&.])\s+[A-Za-z_]\w*(\s*(?:\[\s*\])*)$/, '$1$2');
+  });
+  return `${text.slice(0, open)}(${params.join(', ')})${text.slice(close + 1)}`;
+}
+
+/**
+ * Split a parameter list on commas outside brackets and generics
+ */
+export function splitParams(list: string): string[] {
+  const params: string[] = [];
+  let depth = 0;
+  let start = 0;
+  for (let i = 0; i < list.length; i++) {
+    const ch = list[i] ?? '';
+    if ('([{<'.includes(ch)) depth++;
+    else if (')]}>'.includes(ch) && list[i - 1] !== '=') depth--;
+    else if (ch === ',' && depth === 0) {
+      params.push(list.slice(start, i));
+      start = i + 1;
+    }
+  }
+  params.push(list.slice(start));
+  return params.map(p => p.trim()).filter(p => p !== '');
+}
+
+function matchingParen(text: string, open: number): number {
+  let depth = 0;
+  for (let i = open; i < text.length; i++) {
+    if (text[i] === '(') depth++;
+    else if (text[i] === ')' && --depth === 0) return i;
+  }
+  return -1;
+}
+
+function collapse(text: string): string {
+  return text.replace(/\s+/g, ' ').trim();
+}
@@ -0,0 +1,265 @@
+/**
+ * Call Graph
+ * Builds caller -> callee edges between the functions of a Go package
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { CallEdge, CallGraph, SymbolEntry } from '../types/index.js';
+import { ToolError } from '../core/errors.js';
+import { resolveProjectPath } from '../core/file-reader.js';
+import { GO_SYNTAX, SourceText } from '../parsers/source.js';
+import { parseGoImports } from '../parsers/go-parser.js';
+import { SymbolIndexer, qualifiedName } from './symbol-indexer.js';
+
+const CALL_PATTERN = /(?:([A-Za-z_]\w*)\s*\.\s*)?([A-Za-z_]\w*)\s*(?:\[[^\]]*\])?\s*\(/g;
+const PARAM_PATTERN = /([A-Za-z_]\w*)\s+\*?([A-Za-z_][\w.]*)/g;
+const ASSIGN_PATTERN =
+  /\b([A-Za-z_]\w*)\s*(?::=|=)\s*&?([A-Za-z_]\w*)\s*(?:\[[^\]]*\])?\s*[({]/g;
+const VAR_PATTERN = /\bvar\s+([A-Za-z_]\w*)\s+\*?([A-Za-z_]\w*)/g;
+
+const KEYWORDS = new Set(['func', 'if', 'for', 'switch', 'select', 'return', 'go', 'defer', 'range']);
+const BUILTINS = new Set([
+  'append',
+  'cap',
+  'clear',
+  'close',
+  'complex',
+  'copy',
+  'delete',
+  'imag',
+  'len',
+  'make',
+  'max',
+  'min',
+  'new',
+  'panic',
+  'print',
+  'println',
+  'real',
+  'recover',
+]);
+
+export interface CallGraphOptions {
+  includeExternal?: boolean | undefined;
+}
+
+export class CallGraphBuilder {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  /**
+   * Build the call graph of the Go package in a directory (or containing a file)
+   */
+  async build(target = '.', options: CallGraphOptions = {}): Promise<CallGraph> {
+    const workingDir = this.symbolIndexer.getWorkingDir();
+    let packageDir = resolveProjectPath(workingDir, target);
+    if ((await fs.stat(path.join(workingDir, packageDir))).isFile()) {
+      packageDir = path.posix.dirname(packageDir);
+    }
+    const dirOf = (file: string): string => path.posix.dirname(file);
+    const normalizedDir = packageDir === '' ? '.' : packageDir;
+
+    const symbols = (await this.symbolIndexer.scan(packageDir || '.')).filter(
+      s => s.language === 'go' && dirOf(s.file) === normalizedDir
+    );
+    if (symbols.length === 0) {
+      throw new ToolError('SYMBOL_NOT_FOUND', `No Go symbols found in ${normalizedDir}`);
+    }
+
+    const functions = symbols.filter(s => s.kind === 'function' || s.kind === 'method');
+    const known = new Set(functions.map(qualifiedName));
+    const constructors = goConstructors(symbols);
+
+    const edges = new Map<string, CallEdge>();
+    const byFile = new Map<string, SymbolEntry[]>();
+    for (const fn of functions) {
+      byFile.set(fn.file, [...(byFile.get(fn.file) ?? []), fn]);
+    }
+
+    for (const [file, fileFunctions] of byFile) {
+      let content: string;
+      try {
+        content = await fs.readFile(path.join(workingDir, file), 'utf-8');
+      } catch {
+        // Skip files that can't be read
+        continue;
+      }
+
+      const source = new SourceText(content, GO_SYNTAX);
+      const imports = new Map(
+        parseGoImports(source)
+          .filter(i => i.name !== '_' && i.name !== '.')
+          .map(i => [i.name, i.path])
+      );
+
+      for (const fn of fileFunctions) {
+        const caller = qualifiedName(fn);
+        const body = source.masked.slice(
+          source.lineStart(fn.line),
+          source.lineStart(fn.endLine + 1)
+        );
+        const variables = goVariableTypes(fn, body, constructors);
+
+        for (const call of this.findCalls(fn, body)) {
+          const callee = this.resolveCall(call, variables, imports, known, functions);
+          if (!callee || (callee.external && !options.includeExternal)) continue;
+
+          const key = `${caller}->${callee.name}`;
+          if (!edges.has(key)) {
+            edges.set(key, { caller, callee: callee.name, external: callee.external });
+          }
+        }
+      }
+    }
+
+    const edgeList = [...edges.values()].sort(
+      (a, b) => a.caller.localeCompare(b.caller) || a.callee.localeCompare(b.callee)
+    );
+    const nodes = new Set([...known, ...edgeList.map(e => e.callee)]);
+
+    return { package: normalizedDir, nodes: [...nodes].sort(), edges: edgeList };
+  }
+
+  /**
+   * Call expressions in a function, minus the function's own name
+   */
+  private findCalls(fn: SymbolEntry, body: string): { receiver?: string; name: string }[] {
+    const calls: { receiver?: string; name: string }[] = [];
+    const ownName = new RegExp(`^func\\s*(?:\\([^)]*\\)\\s*)?${fn.name}\\b`);
+    let skippedOwn = !ownName.test(body.trimStart());
+
+    CALL_PATTERN.lastIndex = 0;
+    let match;
+    while ((match = CALL_PATTERN.exec(body)) !== null) {
+      const receiver = match[1];
+      const name = match[2] ?? '';
+      if (!skippedOwn && !receiver && name === fn.name) {
+        skippedOwn = true;
+        continue;
+      }
+      if (receiver === undefined && (KEYWORDS.has(name) || BUILTINS.has(name))) continue;
+      // Chained calls (x.A().B()) have no resolvable receiver
+      if (receiver === undefined && body[match.index - 1] === '.') continue;
+
+      calls.push(receiver === undefined ? { name } : { receiver, name });
+    }
+
+    return calls;
+  }
+
+  private resolveCall(
+    call: { receiver?: string; name: string },
+    variables: Map<string, string>,
+    imports: Map<string, string>,
+    known: Set<string>,
+    functions: SymbolEntry[]
+  ): { name: string; external: boolean } | null {
+    if (call.receiver === undefined) {
+      return known.has(call.name) ? { name: call.name, external: false } : null;
+    }
+
+    const receiverType = variables.get(call.receiver);
+    if (receiverType && known.has(`${receiverType}.${call.name}`)) {
+      return { name: `${receiverType}.${call.name}`, external: false };
+    }
+
+    if (imports.has(call.receiver) && !variables.has(call.receiver)) {
+      return { name: `${call.receiver}.${call.name}`, external: true };
+    }
+
+    // Unknown receiver: resolve only when exactly one package method has this name
+    const candidates = functions.filter(f => f.kind === 'method' && f.name === call.name);
+    if (candidates.length === 1 && candidates[0]) {
+      return { name: qualifiedName(candidates[0]), external: false };
+    }
+
+    return null;
+  }
+
+  formatResult(graph: CallGraph): string {
+    if (graph.edges.length === 0) {
+      return `No calls found in package ${graph.package} (${graph.nodes.length} function(s)).`;
+    }
+
+    let output = `Call graph for ${graph.package}: ${graph.edges.length} edge(s)\n\n`;
+    for (const edge of graph.edges) {
+      output += `  ${edge.caller} -> ${edge.callee}${edge.external ? ' (external)' : ''}\n`;
+    }
+
+    return output.trimEnd();
+  }
+
+  formatDot(graph: CallGraph): string {
+    const external = new Set(graph.edges.filter(e => e.external).map(e => e.callee));
+    let output = `digraph ${JSON.stringify(graph.package)} {\n`;
+    output += '  rankdir=LR;\n';
+    output += '  node [shape=box];\n';
+
+    for (const node of graph.nodes) {
+      const style = external.has(node) ? ' [style=dashed]' : '';
+      output += `  ${JSON.stringify(node)}${style};\n`;
+    }
+    for (const edge of graph.edges) {
+      output += `  ${JSON.stringify(edge.caller)} -> ${JSON.stringify(edge.callee)};\n`;
+    }
+
+    return output + '}';
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+/**
+ * Functions returning a type this package declares, by name: NewCalculator -> Calculator
+ */
+export function goConstructors(symbols: SymbolEntry[]): Map<string, string> {
+  const constructors = new Map<string, string>();
+  for (const fn of symbols) {
+    const result = /\)\s*\(?\*?([A-Za-z_]\w*)[^)]*$/.exec(fn.signature)?.[1];
+    if (fn.kind === 'function' && result && symbols.some(s => s.name === result)) {
+      constructors.set(fn.name, result);
+    }
+  }
+  return constructors;
+}
+
+/**
+ * Best-effort types of local identifiers: the method receiver, typed
+ * parameters, `var x T`, and `x := NewT()` / `x := &T{}` assignments
+ */
+export function goVariableTypes(
+  fn: SymbolEntry,
+  body: string,
+  constructors: Map<string, string>
+): Map<string, string> {
+  const types = new Map<string, string>();
+  const header = fn.signature.replace(/^func\s*/, '');
+
+  // Receiver and parameter groups: "(c *Calculator)", "(a, b int, calc *Calculator)"
+  for (const group of header.match(/\(([^()]*)\)/g) ?? []) {
+    PARAM_PATTERN.lastIndex = 0;
+    let match;
+    while ((match = PARAM_PATTERN.exec(group)) !== null) {
+      if (match[1] && match[2]) types.set(match[1], match[2]);
+    }
+  }
+
+  for (const pattern of [VAR_PATTERN, ASSIGN_PATTERN]) {
+    pattern.lastIndex = 0;
+    let match;
+    while ((match = pattern.exec(body)) !== null) {
+      const name = match[1];
+      const value = match[2];
+      if (!name || !value) continue;
+      types.set(name, constructors.get(value) ?? value);
+    }
+  }
+
+  return types;
+}
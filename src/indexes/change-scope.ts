@@ -0,0 +1,64 @@
+/**
+ * Change Scope
+ * Restricts analysis to changed files plus their direct dependents
+ */
+
+import { getChangedFiles } from '../core/git.js';
+import { DependencyAnalyzer } from './dependency-graph.js';
+
+export interface ChangeScopeOptions {
+  changedFiles?: string[] | undefined;
+  gitRange?: string | undefined;
+}
+
+export interface ResolvedScope {
+  files: string[] | undefined; // undefined = analyze everything
+  changed: string[];
+  dependents: string[];
+  note: string;
+}
+
+export class ChangeScope {
+  private workingDir: string;
+  private dependencyAnalyzer: DependencyAnalyzer;
+
+  constructor(workingDir?: string) {
+    this.workingDir = workingDir ?? process.cwd();
+    this.dependencyAnalyzer = new DependencyAnalyzer(this.workingDir);
+  }
+
+  async resolve(options: ChangeScopeOptions): Promise<ResolvedScope> {
+    let changed = options.changedFiles?.map(f => f.replace(/\\/g, '/').replace(/^\.\//, ''));
+
+    if (!changed && options.gitRange) {
+      const fromGit = await getChangedFiles(this.workingDir, options.gitRange);
+      if (!fromGit) {
+        return {
+          files: undefined,
+          changed: [],
+          dependents: [],
+          note: `git diff ${options.gitRange} unavailable; analyzed all files`,
+        };
+      }
+      changed = fromGit;
+    }
+
+    if (!changed) {
+      return { files: undefined, changed: [], dependents: [], note: '' };
+    }
+
+    const dependents = await this.dependencyAnalyzer.findDirectDependents(changed);
+
+    return {
+      files: [...changed, ...dependents],
+      changed,
+      dependents,
+      note: `Scope: ${changed.length} changed file(s) + ${dependents.length} direct dependent(s)`,
+    };
+  }
+
+  setWorkingDir(dir: string): void {
+    this.workingDir = dir;
+    this.dependencyAnalyzer.setWorkingDir(dir);
+  }
+}
@@ -0,0 +1,131 @@
+/**
+ * Churn Risk
+ * Files ranked by how complex they are and how often they change: code that
+ * is both hard to follow and edited often is where review and refactoring
+ * effort pays off most
+ */
+
+import type { ScopeOptions } from '../core/file-walker.js';
+import { ToolError } from '../core/errors.js';
+import { getChangeCounts } from '../core/git.js';
+import { ComplexityAnalyzer } from './complexity-analyzer.js';
+import { SymbolIndexer } from './symbol-indexer.js';
+
+export const DEFAULT_CHURN_DAYS = 90;
+export const DEFAULT_COMPLEXITY_WEIGHT = 0.5;
+export const DEFAULT_CHURN_LIMIT = 20;
+
+export interface ChurnRiskEntry {
+  file: string;
+  complexity: number; // Sum over the file's functions and methods
+  functions: number;
+  commits: number; // Commits touching the file within the window
+  score: number; // 0 to 1, relative to the files measured
+}
+
+export interface ChurnRiskReport {
+  days: number;
+  complexityWeight: number;
+  files: number; // Files measured, before the limit
+  results: ChurnRiskEntry[];
+}
+
+export interface ChurnRiskOptions extends ScopeOptions {
+  days?: number | undefined; // History window, counted back from now
+  complexityWeight?: number | undefined; // 1 ranks by complexity alone, 0 by churn alone
+  limit?: number | undefined;
+}
+
+export class ChurnRiskAnalyzer {
+  private symbolIndexer: SymbolIndexer;
+  private complexityAnalyzer: ComplexityAnalyzer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+    this.complexityAnalyzer = new ComplexityAnalyzer(this.symbolIndexer);
+  }
+
+  /**
+   * Score the files with functions under target by the weighted geometric
+   * mean of their complexity and commit count, each relative to the highest
+   * among them, so only files high on both rank high. Riskiest first.
+   */
+  async analyze(target = '.', options: ChurnRiskOptions = {}): Promise<ChurnRiskReport> {
+    const days = options.days ?? DEFAULT_CHURN_DAYS;
+    const weight = options.complexityWeight ?? DEFAULT_COMPLEXITY_WEIGHT;
+    const commits = await getChangeCounts(this.symbolIndexer.getWorkingDir(), `${days} days ago`);
+    if (!commits) {
+      throw new ToolError(
+        'FAILED_PRECONDITION',
+        'No git history to read: not a git repository, or no commits yet'
+      );
+    }
+
+    const byFile = new Map<string, ChurnRiskEntry>();
+    const entries = await this.complexityAnalyzer.analyze(target, undefined, {
+      include: options.include,
+      exclude: options.exclude,
+    });
+    for (const entry of entries) {
+      const file = byFile.get(entry.file) ?? {
+        file: entry.file,
+        complexity: 0,
+        functions: 0,
+        commits: commits.get(entry.file) ?? 0,
+        score: 0,
+      };
+      file.complexity += entry.complexity;
+      file.functions++;
+      byFile.set(entry.file, file);
+    }
+
+    const files = [...byFile.values()];
+    const maxComplexity = Math.max(1, ...files.map(f => f.complexity));
+    const maxCommits = Math.max(1, ...files.map(f => f.commits));
+    for (const file of files) {
+      const score =
+        (file.complexity / maxComplexity) ** weight * (file.commits / maxCommits) ** (1 - weight);
+      file.score = Math.round(score * 1000) / 1000;
+    }
+    files.sort(
+      (a, b) =>
+        b.score - a.score ||
+        b.commits - a.commits ||
+        b.complexity - a.complexity ||
+        a.file.localeCompare(b.file)
+    );
+
+    return {
+      days,
+      complexityWeight: weight,
+      files: files.length,
+      results: files.slice(0, options.limit ?? DEFAULT_CHURN_LIMIT),
+    };
+  }
+
+  formatReport(report: ChurnRiskReport): string {
+    if (report.files === 0) {
+      return 'No functions found.';
+    }
+
+    const shown =
+      report.results.length < report.files
+        ? `top ${report.results.length} of ${report.files}`
+        : `${report.files}`;
+    let output = `Churn risk for ${shown} file(s), commits in the last ${report.days} day(s), `;
+    output += `complexity weight ${report.complexityWeight}:\n\n`;
+    output += '  score  complexity  commits  file\n';
+    for (const entry of report.results) {
+      const score = entry.score.toFixed(3).padStart(5);
+      const complexity = String(entry.complexity).padStart(10);
+      const commits = String(entry.commits).padStart(7);
+      output += `  ${score}  ${complexity}  ${commits}  ${entry.file}\n`;
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
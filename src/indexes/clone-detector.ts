@@ -0,0 +1,330 @@
+/**
+ * Clone Detector
+ * Groups functions whose bodies match once comments, whitespace, and local
+ * names are normalized away, exactly or above a similarity threshold
+ */
+
+import * as crypto from 'crypto';
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { SymbolEntry, SymbolKind } from '../types/index.js';
+import { checkDeadline } from '../core/deadline.js';
+import type { ScopeOptions } from '../core/file-walker.js';
+import { getParserForFile } from '../parsers/index.js';
+import { SourceText } from '../parsers/source.js';
+import { SymbolIndexer, qualifiedName } from './symbol-indexer.js';
+
+export const DEFAULT_CLONE_THRESHOLD = 0.8;
+export const DEFAULT_CLONE_MIN_LINES = 5;
+
+export interface CloneMember {
+  symbol: string;
+  kind: SymbolKind;
+  file: string;
+  line: number;
+  endLine: number;
+}
+
+export interface CloneGroup {
+  identical: boolean; // Every member has the same normalized body
+  similarity: number; // Lowest similarity between members linked into the group, 0-1
+  lines: number; // Length of the longest member
+  members: CloneMember[];
+}
+
+export interface CloneReport {
+  threshold: number;
+  functions: number; // Functions compared, after the minLines cut
+  groups: CloneGroup[];
+}
+
+export interface CloneOptions extends ScopeOptions {
+  threshold?: number | undefined; // 1 = identical bodies only
+  minLines?: number | undefined; // Shorter functions are left out
+}
+
+interface NormalizedBody {
+  member: CloneMember;
+  language: string;
+  hash: string;
+  shingles: Set<string>;
+}
+
+// Kept as written when normalizing: renaming them would make "if x" equal "for x"
+const KEYWORDS = new Set([
+  // Shared by the C family, Go, Rust, Python, and JavaScript
+  ...['if', 'else', 'for', 'while', 'do', 'switch', 'case', 'default', 'break', 'continue'],
+  ...['return', 'goto', 'try', 'catch', 'finally', 'throw', 'throws', 'new', 'delete'],
+  ...['true', 'false', 'null', 'nil', 'undefined', 'this', 'self', 'super', 'void'],
+  // Declarations and modifiers
+  ...['var', 'let', 'const', 'func', 'function', 'fn', 'def', 'class', 'struct', 'enum'],
+  ...['interface', 'type', 'impl', 'trait', 'static', 'final', 'mut', 'pub', 'async', 'await'],
+  ...['yield', 'lambda', 'in', 'of', 'is', 'not', 'and', 'or', 'as', 'typeof', 'instanceof'],
+  ...['with', 'pass', 'raise', 'except', 'elif', 'from', 'import', 'global', 'nonlocal'],
+  ...['go', 'defer', 'select', 'chan', 'map', 'range', 'fallthrough', 'match', 'loop', 'where'],
+  ...['move', 'ref', 'unsafe', 'sizeof', 'auto', 'extern', 'volatile', 'synchronized'],
+  // Built-in types
+  ...['int', 'int8', 'int16', 'int32', 'int64', 'uint', 'uint8', 'uint16', 'uint32', 'uint64'],
+  ...['float', 'float32', 'float64', 'double', 'long', 'short', 'char', 'byte', 'rune'],
+  ...['bool', 'boolean', 'string', 'str', 'error', 'any', 'number', 'object', 'usize', 'isize'],
+  ...['i8', 'i16', 'i32', 'i64', 'u8', 'u16', 'u32', 'u64', 'f32', 'f64', 'unsigned', 'signed'],
+]);
+
+// Tokens per shingle when comparing bodies that aren't identical
+const SHINGLE_SIZE = 4;
+
+export class CloneDetector {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  async detect(target = '.', options: CloneOptions = {}): Promise<CloneReport> {
+    const threshold = Math.min(1, Math.max(0, options.threshold ?? DEFAULT_CLONE_THRESHOLD));
+    const minLines = Math.max(1, options.minLines ?? DEFAULT_CLONE_MIN_LINES);
+    const symbols = await this.symbolIndexer.scan(target, {
+      include: options.include,
+      exclude: options.exclude,
+    });
+
+    const byFile = new Map<string, SymbolEntry[]>();
+    for (const symbol of symbols) {
+      if (symbol.kind !== 'function' && symbol.kind !== 'method') continue;
+      if (symbol.endLine - symbol.line + 1 < minLines) continue;
+      byFile.set(symbol.file, [...(byFile.get(symbol.file) ?? []), symbol]);
+    }
+
+    const bodies: NormalizedBody[] = [];
+    for (const [file, functions] of byFile) {
+      bodies.push(...(await this.normalizeFile(file, functions)));
+    }
+
+    return { threshold, functions: bodies.length, groups: group(bodies, threshold) };
+  }
+
+  private async normalizeFile(file: string, functions: SymbolEntry[]): Promise<NormalizedBody[]> {
+    let content: string;
+    try {
+      content = await fs.readFile(path.join(this.symbolIndexer.getWorkingDir(), file), 'utf-8');
+    } catch {
+      // Skip files that can't be read
+      return [];
+    }
+
+    const parser = getParserForFile(file, content);
+    if (!parser) {
+      return [];
+    }
+
+    const source = new SourceText(content, parser.syntax);
+    const bodies: NormalizedBody[] = [];
+    for (const fn of functions) {
+      checkDeadline();
+      const body =
+        parser.language === 'python' ? indentedBody(source, fn) : bracedBody(source, fn);
+      if (body === undefined) continue;
+
+      const tokens = normalizeTokens(body);
+      if (tokens.length === 0) continue;
+      bodies.push({
+        member: {
+          symbol: qualifiedName(fn),
+          kind: fn.kind,
+          file,
+          line: fn.line,
+          endLine: fn.endLine,
+        },
+        language: parser.language,
+        hash: crypto.createHash('sha1').update(tokens.join(' ')).digest('hex'),
+        shingles: shingles(tokens),
+      });
+    }
+    return bodies;
+  }
+
+  formatReport(report: CloneReport): string {
+    if (report.groups.length === 0) {
+      return `No cloned functions found among ${report.functions} function(s).`;
+    }
+
+    const percent = (n: number): string => `${Math.round(n * 100)}%`;
+    let output = `Found ${report.groups.length} clone group(s) among ${report.functions} `;
+    output += `function(s) (threshold ${percent(report.threshold)}):\n`;
+
+    for (const g of report.groups) {
+      const match = g.identical ? 'identical' : `${percent(g.similarity)} similar`;
+      output += `\n${g.members.length} functions, ${match}, up to ${g.lines} lines:\n`;
+      for (const m of g.members) {
+        output += `  ${m.kind} ${m.symbol}  ${m.file}:${m.line}-${m.endLine}\n`;
+      }
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+/**
+ * Masked text inside the braces of a function whose body closes on its last
+ * line; undefined for declarations without a body
+ */
+function bracedBody(source: SourceText, fn: SymbolEntry): string | undefined {
+  const start = source.lineStart(fn.line);
+  const end = source.lineStart(fn.endLine + 1);
+  for (let open = source.masked.indexOf('{', start); open !== -1 && open < end; ) {
+    const close = source.findMatching(open);
+    if (close === -1) return undefined;
+    // Braces closing earlier belong to the signature, e.g. func(x struct{}) {
+    if (source.lineOf(close) === fn.endLine) return source.masked.slice(open + 1, close);
+    open = source.masked.indexOf('{', open + 1);
+  }
+  return undefined;
+}
+
+/**
+ * Masked lines after a Python signature, which ends at the first line whose
+ * code ends in a colon
+ */
+function indentedBody(source: SourceText, fn: SymbolEntry): string | undefined {
+  for (let line = fn.line; line < fn.endLine; line++) {
+    if (source.lineText(line, true).trimEnd().endsWith(':')) {
+      return source.masked.slice(source.lineStart(line + 1), source.lineStart(fn.endLine + 1));
+    }
+  }
+  return undefined;
+}
+
+/**
+ * Tokens of masked code with local names replaced by their order of first
+ * appearance ($0, $1, ...). Keywords, built-in types, calls, member names
+ * after . or ::, and capitalized names (types, exported identifiers) are kept,
+ * since they carry what the code does rather than what a variable is called.
+ */
+function normalizeTokens(code: string): string[] {
+  const locals = new Map<string, string>();
+  const tokens: string[] = [];
+  const pattern = /[A-Za-z_$][\w$]*|\d[\w.]*|->|::|\S/g;
+  let match;
+
+  while ((match = pattern.exec(code)) !== null) {
+    const token = match[0];
+    const previous = tokens[tokens.length - 1];
+    const next = /^\s*(\S)/.exec(code.slice(pattern.lastIndex, pattern.lastIndex + 64))?.[1];
+    const isLocal =
+      /^[a-z_$]/.test(token) &&
+      !KEYWORDS.has(token) &&
+      previous !== '.' &&
+      previous !== '->' &&
+      previous !== '::' &&
+      next !== '(';
+
+    if (!isLocal) {
+      tokens.push(token);
+      continue;
+    }
+    let placeholder = locals.get(token);
+    if (placeholder === undefined) {
+      placeholder = `$${locals.size}`;
+      locals.set(token, placeholder);
+    }
+    tokens.push(placeholder);
+  }
+  return tokens;
+}
+
+function shingles(tokens: string[]): Set<string> {
+  const set = new Set<string>();
+  for (let i = 0; i + SHINGLE_SIZE <= Math.max(tokens.length, SHINGLE_SIZE); i++) {
+    set.add(tokens.slice(i, i + SHINGLE_SIZE).join(' '));
+  }
+  return set;
+}
+
+function jaccard(a: Set<string>, b: Set<string>): number {
+  const [small, large] = a.size <= b.size ? [a, b] : [b, a];
+  let shared = 0;
+  for (const shingle of small) {
+    if (large.has(shingle)) shared++;
+  }
+  return shared / (a.size + b.size - shared);
+}
+
+/**
+ * Link bodies that are identical or at least threshold similar (Jaccard
+ * similarity of token shingles) and return the connected groups, largest
+ * first
+ */
+function group(bodies: NormalizedBody[], threshold: number): CloneGroup[] {
+  const parent = bodies.map((_, i) => i);
+  const lowest = bodies.map(() => 1);
+  const find = (i: number): number => {
+    let root = i;
+    while ((parent[root] ?? root) !== root) root = parent[root] ?? root;
+    return root;
+  };
+  const link = (a: number, b: number, similarity: number): void => {
+    const [rootA, rootB] = [find(a), find(b)];
+    const low = Math.min(lowest[rootA] ?? 1, lowest[rootB] ?? 1, similarity);
+    parent[rootB] = rootA;
+    lowest[rootA] = low;
+  };
+
+  // Identical bodies first; they need no pairwise comparison
+  const byHash = new Map<string, number>();
+  bodies.forEach((body, i) => {
+    const key = `${body.language}\0${body.hash}`;
+    const first = byHash.get(key);
+    if (first === undefined) byHash.set(key, i);
+    else link(first, i, 1);
+  });
+
+  if (threshold < 1) {
+    // Jaccard similarity can't reach the threshold when one shingle set is
+    // much smaller than the other, so only neighbours in size order compete
+    const order = [...byHash.values()].sort(
+      (a, b) => (bodies[a]?.shingles.size ?? 0) - (bodies[b]?.shingles.size ?? 0)
+    );
+    for (let x = 0; x < order.length; x++) {
+      const a = bodies[order[x] ?? 0];
+      if (!a) continue;
+      for (let y = x + 1; y < order.length; y++) {
+        checkDeadline();
+        const b = bodies[order[y] ?? 0];
+        if (!b) continue;
+        if (a.shingles.size < threshold * b.shingles.size) break;
+        if (a.language !== b.language) continue;
+        const similarity = jaccard(a.shingles, b.shingles);
+        if (similarity >= threshold) link(order[x] ?? 0, order[y] ?? 0, similarity);
+      }
+    }
+  }
+
+  const groups = new Map<number, number[]>();
+  bodies.forEach((_, i) => {
+    const root = find(i);
+    groups.set(root, [...(groups.get(root) ?? []), i]);
+  });
+
+  const result: CloneGroup[] = [];
+  for (const [root, indexes] of groups) {
+    if (indexes.length < 2) continue;
+    const members = indexes.flatMap(i => bodies[i] ?? []);
+    const hashes = new Set(members.map(m => m.hash));
+    result.push({
+      identical: hashes.size === 1,
+      similarity: hashes.size === 1 ? 1 : (lowest[root] ?? 1),
+      lines: Math.max(...members.map(m => m.member.endLine - m.member.line + 1)),
+      members: members
+        .map(m => m.member)
+        .sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line),
+    });
+  }
+
+  return result.sort(
+    (a, b) => b.members.length * b.lines - a.members.length * a.lines || b.lines - a.lines
+  );
+}
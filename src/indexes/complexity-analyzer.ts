@@ -0,0 +1,183 @@
+/**
+ * Complexity Analyzer
+ * Computes cyclomatic complexity per function by counting branch points
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { ComplexityEntry, SymbolEntry } from '../types/index.js';
+import { getParser, getParserForFile } from '../parsers/index.js';
+import type { SymbolParser } from '../parsers/index.js';
+import { SourceText } from '../parsers/source.js';
+import { ToolError } from '../core/errors.js';
+import type { ScopeOptions } from '../core/file-walker.js';
+import { SymbolIndexer, qualifiedName } from './symbol-indexer.js';
+
+// Each match adds one decision point; patterns run on comment/string-masked text
+const BRANCH_PATTERNS: Record<string, RegExp[]> = {
+  go: [/\b(?:if|for|case)\b/g, /&&|\|\|/g],
+  typescript: [
+    /\b(?:if|for|while|case|catch)\b/g,
+    /&&|\|\||\?\?/g,
+    /(?<=\s)\?(?=\s)/g, // Ternary
+  ],
+};
+
+export class ComplexityAnalyzer {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  /**
+   * Complexity of every function and method under a path, most complex first
+   */
+  async analyze(
+    target = '.',
+    only?: string[],
+    scope: ScopeOptions = {}
+  ): Promise<ComplexityEntry[]> {
+    const symbols = await this.symbolIndexer.scan(target, { ...scope, only });
+    const byFile = new Map<string, SymbolEntry[]>();
+
+    for (const symbol of symbols) {
+      if (symbol.kind !== 'function' && symbol.kind !== 'method') continue;
+      const existing = byFile.get(symbol.file) ?? [];
+      existing.push(symbol);
+      byFile.set(symbol.file, existing);
+    }
+
+    const entries: ComplexityEntry[] = [];
+    for (const [file, functions] of byFile) {
+      entries.push(...(await this.analyzeFile(file, functions)));
+    }
+
+    return entries.sort(
+      (a, b) => b.complexity - a.complexity || a.file.localeCompare(b.file) || a.line - b.line
+    );
+  }
+
+  /**
+   * The symbols with each function's and method's complexity attached; other
+   * symbols are returned as they are. A buffer stands in for a file's content.
+   */
+  async withComplexity(
+    symbols: SymbolEntry[],
+    buffer?: { content: string; language?: string | undefined }
+  ): Promise<SymbolEntry[]> {
+    const byFile = new Map<string, SymbolEntry[]>();
+    for (const symbol of symbols) {
+      if (symbol.kind !== 'function' && symbol.kind !== 'method') continue;
+      byFile.set(symbol.file, [...(byFile.get(symbol.file) ?? []), symbol]);
+    }
+
+    const measured = new Map<SymbolEntry, number>();
+    for (const [file, functions] of byFile) {
+      let entries: ComplexityEntry[];
+      if (buffer) {
+        const { content, language } = buffer;
+        const parser = language ? getParser(language) : getParserForFile(file, content);
+        entries = parser ? this.measure(content, file, parser, functions) : [];
+      } else {
+        entries = await this.analyzeFile(file, functions);
+      }
+      entries.forEach((entry, i) => {
+        const fn = functions[i];
+        if (fn) measured.set(fn, entry.complexity);
+      });
+    }
+
+    return symbols.map(symbol => {
+      const complexity = measured.get(symbol);
+      return complexity === undefined ? symbol : { ...symbol, complexity };
+    });
+  }
+
+  private async analyzeFile(file: string, functions: SymbolEntry[]): Promise<ComplexityEntry[]> {
+    let content: string;
+    try {
+      content = await fs.readFile(path.join(this.symbolIndexer.getWorkingDir(), file), 'utf-8');
+    } catch {
+      // Skip files that can't be read
+      return [];
+    }
+
+    const parser = getParserForFile(file, content);
+    if (!parser) {
+      return [];
+    }
+
+    return this.measure(content, file, parser, functions);
+  }
+
+  /**
+   * Complexity of every function in source text that isn't on disk, such as
+   * an unsaved editor buffer
+   */
+  analyzeSource(content: string, file: string, language?: string): ComplexityEntry[] {
+    const parser = language ? getParser(language) : getParserForFile(file, content);
+    if (!parser) {
+      throw new ToolError(
+        'UNSUPPORTED_LANGUAGE',
+        `Unsupported language: ${language ?? path.extname(file)}`,
+        file
+      );
+    }
+
+    const functions = this.symbolIndexer
+      .extractSource(content, file, language)
+      .filter(s => s.kind === 'function' || s.kind === 'method');
+    return this.measure(content, file, parser, functions).sort(
+      (a, b) => b.complexity - a.complexity || a.line - b.line
+    );
+  }
+
+  private measure(
+    content: string,
+    file: string,
+    parser: SymbolParser,
+    functions: SymbolEntry[]
+  ): ComplexityEntry[] {
+    const source = new SourceText(content, parser.syntax);
+    const patterns = BRANCH_PATTERNS[parser.language] ?? [];
+
+    return functions.map(fn => {
+      const body = source.masked.slice(source.lineStart(fn.line), source.lineStart(fn.endLine + 1));
+      return {
+        function: qualifiedName(fn),
+        file,
+        line: fn.line,
+        complexity: 1 + countMatches(body, patterns),
+      };
+    });
+  }
+
+  formatResult(entries: ComplexityEntry[]): string {
+    if (entries.length === 0) {
+      return 'No functions found.';
+    }
+
+    const width = Math.max(...entries.map(e => e.function.length));
+    let output = `Cyclomatic complexity for ${entries.length} function(s):\n\n`;
+
+    for (const entry of entries) {
+      const complexity = String(entry.complexity).padStart(3);
+      output += `  ${complexity}  ${entry.function.padEnd(width)}  ${entry.file}:${entry.line}\n`;
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+function countMatches(text: string, patterns: RegExp[]): number {
+  let count = 0;
+  for (const pattern of patterns) {
+    count += text.match(pattern)?.length ?? 0;
+  }
+  return count;
+}
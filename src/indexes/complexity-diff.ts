@@ -0,0 +1,173 @@
+/**
+ * Complexity Diff
+ * Functions whose cyclomatic complexity grew between two git revisions, and
+ * new functions that start out too complex, for gating a change in CI
+ */
+
+import * as path from 'path';
+import type { ComplexityEntry } from '../types/index.js';
+import { getParserForFile } from '../parsers/index.js';
+import { ComplexityAnalyzer } from './complexity-analyzer.js';
+import { SymbolDiffer, type SymbolDiffOptions } from './symbol-diff.js';
+import { SymbolIndexer } from './symbol-indexer.js';
+
+export const DEFAULT_MAX_INCREASE = 0;
+export const DEFAULT_NEW_THRESHOLD = 10;
+
+export interface ComplexityRegression {
+  function: string;
+  file: string; // Location in the newer revision
+  line: number;
+  before: number;
+  after: number;
+  increase: number;
+}
+
+export interface ComplexityComparison {
+  from: string;
+  to: string;
+  filesChanged: number;
+  maxIncrease: number;
+  newThreshold: number;
+  regressions: ComplexityRegression[]; // Grew by more than maxIncrease
+  added: ComplexityEntry[]; // New functions above newThreshold
+  improved: number; // Functions whose complexity went down
+  passed: boolean;
+}
+
+export interface ComplexityComparisonOptions extends SymbolDiffOptions {
+  maxIncrease?: number | undefined;
+  newThreshold?: number | undefined;
+}
+
+export class ComplexityComparer {
+  private symbolIndexer: SymbolIndexer;
+  private symbolDiffer: SymbolDiffer;
+  private complexityAnalyzer: ComplexityAnalyzer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+    this.symbolDiffer = new SymbolDiffer(this.symbolIndexer);
+    this.complexityAnalyzer = new ComplexityAnalyzer(this.symbolIndexer);
+  }
+
+  /**
+   * Compare per-function complexity in the files that differ between two
+   * revisions. Functions are matched by qualified name, so a renamed function
+   * counts as removed and added.
+   */
+  async compare(
+    from: string,
+    to = 'HEAD',
+    options: ComplexityComparisonOptions = {}
+  ): Promise<ComplexityComparison> {
+    const maxIncrease = options.maxIncrease ?? DEFAULT_MAX_INCREASE;
+    const newThreshold = options.newThreshold ?? DEFAULT_NEW_THRESHOLD;
+    const changed = await this.symbolDiffer.changedSources(from, to, options);
+
+    const before = new Map<string, ComplexityEntry[]>();
+    const after = new Map<string, ComplexityEntry[]>();
+    for (const { file, before: oldContent, after: newContent } of changed) {
+      this.measure(file, oldContent, before);
+      this.measure(file, newContent, after);
+    }
+
+    const result: ComplexityComparison = {
+      from,
+      to,
+      filesChanged: changed.length,
+      maxIncrease,
+      newThreshold,
+      regressions: [],
+      added: [],
+      improved: 0,
+      passed: true,
+    };
+    for (const [key, current] of after) {
+      const olds = before.get(key) ?? [];
+      // Same-named functions (overloads, init in Go) pair up in source order
+      current.forEach((entry, i) => {
+        const old = olds[i];
+        if (!old) {
+          if (entry.complexity > newThreshold) result.added.push(entry);
+        } else if (entry.complexity - old.complexity > maxIncrease) {
+          result.regressions.push({
+            function: entry.function,
+            file: entry.file,
+            line: entry.line,
+            before: old.complexity,
+            after: entry.complexity,
+            increase: entry.complexity - old.complexity,
+          });
+        } else if (entry.complexity < old.complexity) {
+          result.improved++;
+        }
+      });
+    }
+
+    result.regressions.sort(
+      (a, b) => b.increase - a.increase || a.file.localeCompare(b.file) || a.line - b.line
+    );
+    result.added.sort(
+      (a, b) => b.complexity - a.complexity || a.file.localeCompare(b.file) || a.line - b.line
+    );
+    result.passed = result.regressions.length === 0 && result.added.length === 0;
+    return result;
+  }
+
+  /**
+   * Add a file's functions to groups keyed like symbol diffs: by qualified
+   * name within the file, or within the package directory in Go
+   */
+  private measure(
+    file: string,
+    content: string | undefined,
+    groups: Map<string, ComplexityEntry[]>
+  ): void {
+    if (content === undefined) return;
+    const parser = getParserForFile(file, content);
+    if (!parser) return;
+
+    const scope = parser.language === 'go' ? path.posix.dirname(file) : file;
+    const entries = this.complexityAnalyzer
+      .analyzeSource(content, file, parser.language)
+      .sort((a, b) => a.line - b.line);
+    for (const entry of entries) {
+      const key = `${parser.language}\0${scope}\0${entry.function}`;
+      const group = groups.get(key) ?? [];
+      group.push(entry);
+      groups.set(key, group);
+    }
+  }
+
+  formatComparison(comparison: ComplexityComparison): string {
+    const { regressions, added } = comparison;
+    let output = `Complexity ${comparison.from} -> ${comparison.to}: `;
+    output += `${regressions.length} regression(s), ${added.length} new complex function(s), `;
+    output += `${comparison.improved} improved in ${comparison.filesChanged} changed file(s)\n`;
+
+    if (regressions.length > 0) {
+      output += `\nIncreased by more than ${comparison.maxIncrease}:\n`;
+      const width = Math.max(...regressions.map(r => r.function.length));
+      for (const r of regressions) {
+        const change = `${r.before} -> ${r.after} (+${r.increase})`.padEnd(16);
+        output += `  ${change}  ${r.function.padEnd(width)}  ${r.file}:${r.line}\n`;
+      }
+    }
+    if (added.length > 0) {
+      output += `\nNew above ${comparison.newThreshold}:\n`;
+      const width = Math.max(...added.map(e => e.function.length));
+      for (const e of added) {
+        const complexity = String(e.complexity).padStart(3);
+        output += `  ${complexity}  ${e.function.padEnd(width)}  ${e.file}:${e.line}\n`;
+      }
+    }
+
+    output += `\n${comparison.passed ? 'PASS' : 'FAIL'}`;
+    return output;
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
@@ -0,0 +1,458 @@
+/**
+ * Definition Resolver
+ * Go to definition: finds the symbol an identifier at a file position refers
+ * to, resolving receiver method calls through the receiver's type. Hover
+ * summarizes that symbol for an editor.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { SymbolEntry } from '../types/index.js';
+import { ToolError } from '../core/errors.js';
+import { resolveProjectPath } from '../core/file-reader.js';
+import { getParserForFile } from '../parsers/index.js';
+import { parseGoImports } from '../parsers/go-parser.js';
+import { SourceText } from '../parsers/source.js';
+import { goConstructors, goVariableTypes } from './call-graph.js';
+import { SymbolIndexer, docSummary, qualifiedName } from './symbol-indexer.js';
+
+export interface DefinitionResult {
+  name: string; // Identifier at the position
+  file: string;
+  line: number;
+  column: number;
+  receiver?: string | undefined; // What the name is accessed on: "c" in c.Add(2)
+  receiverType?: string | undefined; // The receiver's inferred type: Calculator
+  external?: string | undefined; // Import path when the receiver is a package outside the project
+  resolved: boolean; // Exactly one definition; otherwise definitions lists the candidates
+  definitions: SymbolEntry[];
+}
+
+export interface HoverResult {
+  name: string;
+  file: string;
+  line: number;
+  column: number; // Start of the identifier
+  role: 'definition' | 'usage'; // Whether the position is the symbol's own declaration
+  receiverType?: string | undefined;
+  symbol: SymbolEntry; // Doc cut to its summary
+  candidates: number; // Definitions the name could mean; above 1, symbol is the first
+}
+
+// Identifier characters, including $ for JavaScript
+const IDENTIFIER_CHAR = /[\w$]/;
+
+// "recv." "recv?." "recv::" "recv->" directly before the name
+const RECEIVER_PATTERN = /([A-Za-z_$][\w$]*)\s*(?:\?\.|\.|::|->)\s*$/;
+
+// A member access whose receiver isn't a plain identifier: a().b, x[0].b
+const MEMBER_ACCESS = /(?:\?\.|\.|::|->)\s*$/;
+
+const SELF_RECEIVERS = new Set(['this', 'self', 'cls', 'Self']);
+
+// Reserved words of each parser's language, which never name a symbol, so
+// hovering one shows nothing. Soft keywords (Python's match and type, type
+// and of in TypeScript) are left out, since they are also ordinary names.
+const KEYWORDS: Record<string, Set<string>> = {
+  go: words(`break case chan const continue default defer else fallthrough for func go goto if
+    import interface map package range return select struct switch type var true false nil`),
+  typescript: words(`break case catch class const continue debugger default delete do else enum
+    export extends false finally for function if implements import in instanceof interface let
+    new null package private protected public return static switch throw true try typeof var
+    void while with yield await`),
+  python: words(`False None True and as assert async await break class continue def del elif
+    else except finally for from global if import in is lambda nonlocal not or pass raise return
+    try while with yield`),
+  java: words(`abstract assert boolean break byte case catch char class const continue default do
+    double else enum extends final finally float for goto if implements import instanceof int
+    interface long native new package private protected public return short static strictfp
+    switch synchronized throw throws transient try void volatile while true false null`),
+  kotlin: words(`as break class continue do else false for fun if in interface is null object
+    package return throw true try typealias typeof val var when while`),
+  swift: words(`associatedtype class deinit enum extension fileprivate func import init inout
+    internal let open operator private protocol public rethrows static struct subscript
+    typealias var break case continue default defer do else fallthrough for guard if in repeat
+    return switch where while as catch false is nil throw throws true try`),
+  rust: words(`as async await break const continue crate dyn else enum extern false fn for if impl
+    in let loop match mod move mut pub ref return static struct trait true type unsafe use where
+    while`),
+  cpp: words(`alignas alignof asm auto bool break case catch char class const constexpr const_cast
+    continue decltype default delete do double dynamic_cast else enum explicit extern false float
+    for friend goto if inline int long mutable namespace new noexcept nullptr operator private
+    protected public register reinterpret_cast return short signed sizeof static static_assert
+    static_cast struct switch template throw true try typedef typeid typename union unsigned
+    using virtual void volatile while`),
+  graphql: words(`query mutation subscription fragment on type input enum interface union scalar
+    schema extend implements directive true false null`),
+};
+
+// Languages where a bare name inside a method can mean a member of its class
+const IMPLICIT_THIS = new Set(['java', 'kotlin', 'swift', 'cpp']);
+
+export class DefinitionResolver {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  /**
+   * Resolve the identifier at a 1-based line and column. An ambiguous name
+   * returns every candidate with resolved false rather than a guess.
+   */
+  async resolve(file: string, line: number, column: number): Promise<DefinitionResult> {
+    const { relativePath, source } = await this.readSource(file);
+    const usage = identifierAt(source, line, column);
+    if (!usage) {
+      throw new ToolError(
+        'INVALID_ARGUMENT',
+        `No identifier at ${relativePath}:${line}:${column} (comments and strings are not resolved)`,
+        relativePath
+      );
+    }
+    return this.resolveUsage(await this.symbolIndexer.scan(), relativePath, source, line, usage);
+  }
+
+  /**
+   * Resolve the identifiers at several positions against one scan, reading
+   * each file once, e.g. every occurrence of a name. Positions with no
+   * identifier resolve to undefined.
+   */
+  async resolveAll(
+    positions: Array<{ file: string; line: number; column: number }>
+  ): Promise<Array<DefinitionResult | undefined>> {
+    const symbols = await this.symbolIndexer.scan();
+    const sources = new Map<string, { relativePath: string; source: SourceText }>();
+    const results: Array<DefinitionResult | undefined> = [];
+    for (const { file, line, column } of positions) {
+      let read = sources.get(file);
+      if (!read) {
+        read = await this.readSource(file);
+        sources.set(file, read);
+      }
+      const usage = identifierAt(read.source, line, column);
+      results.push(
+        usage && this.resolveUsage(symbols, read.relativePath, read.source, line, usage)
+      );
+    }
+    return results;
+  }
+
+  /**
+   * What an editor hover shows for the identifier at a 1-based line and
+   * column: the symbol it declares or refers to, with its signature and doc
+   * summary. Null on whitespace, comments, strings, keywords, and names with
+   * no definition in the project.
+   */
+  async hover(file: string, line: number, column: number): Promise<HoverResult | null> {
+    const { relativePath, source, language } = await this.readSource(file);
+    const usage = identifierAt(source, line, column);
+    // After a dot it's a member name, even one spelled like a keyword: m.delete()
+    const member = source.masked[(usage?.offset ?? 0) - 1] === '.';
+    if (!usage || (!member && KEYWORDS[language]?.has(usage.name))) return null;
+
+    const symbols = await this.symbolIndexer.scan();
+    const result = this.resolveUsage(symbols, relativePath, source, line, usage);
+    const [symbol] = result.definitions;
+    if (!symbol) return null;
+
+    const declared = symbol.file === relativePath && symbol.line === line;
+    return {
+      name: result.name,
+      file: relativePath,
+      line,
+      column: result.column,
+      role: result.resolved && declared ? 'definition' : 'usage',
+      receiverType: result.receiverType,
+      symbol: symbol.doc ? { ...symbol, doc: docSummary(symbol.doc) } : symbol,
+      candidates: result.definitions.length,
+    };
+  }
+
+  private async readSource(
+    file: string
+  ): Promise<{ relativePath: string; source: SourceText; language: string }> {
+    const workingDir = this.symbolIndexer.getWorkingDir();
+    const relativePath = resolveProjectPath(workingDir, file);
+    const content = await fs.readFile(path.join(workingDir, relativePath), 'utf-8');
+    const parser = getParserForFile(relativePath, content);
+    if (!parser) {
+      throw new ToolError(
+        'UNSUPPORTED_LANGUAGE',
+        `Unsupported language: ${path.basename(relativePath)}`,
+        relativePath
+      );
+    }
+    return {
+      relativePath,
+      source: new SourceText(content, parser.syntax),
+      language: parser.language,
+    };
+  }
+
+  private resolveUsage(
+    symbols: SymbolEntry[],
+    relativePath: string,
+    source: SourceText,
+    line: number,
+    usage: { name: string; offset: number; column: number }
+  ): DefinitionResult {
+    const result: DefinitionResult = {
+      name: usage.name,
+      file: relativePath,
+      line,
+      column: usage.column,
+      resolved: false,
+      definitions: [],
+    };
+
+    // The name at its own declaration
+    const declared = symbols.filter(
+      s => s.file === relativePath && s.line === line && s.name === usage.name
+    );
+    if (declared.length === 1) {
+      return { ...result, resolved: true, definitions: declared };
+    }
+
+    const enclosing = symbols
+      .filter(
+        s =>
+          s.file === relativePath &&
+          (s.kind === 'function' || s.kind === 'method') &&
+          s.line <= line &&
+          line <= s.endLine
+      )
+      .sort((a, b) => a.endLine - a.line - (b.endLine - b.line))[0];
+
+    const before = source.masked.slice(source.lineStart(line), usage.offset);
+    const receiver = RECEIVER_PATTERN.exec(before)?.[1];
+    let definitions: SymbolEntry[];
+    if (receiver) {
+      result.receiver = receiver;
+      definitions = this.resolveMember(result, receiver, source, symbols, enclosing);
+    } else if (MEMBER_ACCESS.test(before)) {
+      // Receiver is an expression: any member with this name
+      definitions = symbols.filter(s => s.parent && s.name === usage.name);
+    } else {
+      definitions = this.resolveBare(usage.name, relativePath, symbols, enclosing);
+    }
+
+    definitions.sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+    return { ...result, resolved: definitions.length === 1, definitions };
+  }
+
+  /**
+   * recv.name: a member of the receiver's type when it can be inferred, a
+   * declaration of an imported Go package, or failing those any member with
+   * the name
+   */
+  private resolveMember(
+    result: DefinitionResult,
+    receiver: string,
+    source: SourceText,
+    symbols: SymbolEntry[],
+    enclosing: SymbolEntry | undefined
+  ): SymbolEntry[] {
+    const { name, file } = result;
+    const members = symbols.filter(s => s.parent && s.name === name);
+    const language = getParserForFile(file)?.language;
+
+    if (language === 'go') {
+      const imported = parseGoImports(source).find(i => i.name === receiver);
+      const receiverType = enclosing
+        ? this.goReceiverType(receiver, enclosing, source, symbols)
+        : undefined;
+      if (imported && !receiverType) {
+        const inPackage = symbols.filter(
+          s => s.language === 'go' && !s.parent && s.name === name && inGoPackage(s, imported.path)
+        );
+        if (inPackage.length === 0) result.external = imported.path;
+        return inPackage;
+      }
+      if (receiverType) {
+        result.receiverType = receiverType;
+        const typed = members.filter(s => s.parent === receiverType && s.language === 'go');
+        if (typed.length > 0) return typed;
+      }
+    } else {
+      const receiverType = SELF_RECEIVERS.has(receiver)
+        ? enclosing?.parent
+        : symbols.some(s => s.name === receiver && isType(s))
+          ? receiver // Static access or a method expression: Calculator.Add
+          : enclosing && localType(source, enclosing, receiver);
+      if (receiverType) {
+        result.receiverType = receiverType;
+        const typed = members.filter(s => s.parent === receiverType);
+        if (typed.length > 0) return typed;
+      }
+    }
+
+    // Receiver type unknown or not in the project: every method with the name
+    return members.filter(s => s.language === language);
+  }
+
+  /**
+   * Type of a Go identifier inside a function: receiver, parameter, or local
+   * declared with var, a composite literal, or a constructor call
+   */
+  private goReceiverType(
+    receiver: string,
+    enclosing: SymbolEntry,
+    source: SourceText,
+    symbols: SymbolEntry[]
+  ): string | undefined {
+    const dir = path.posix.dirname(enclosing.file);
+    const inPackage = symbols.filter(
+      s => s.language === 'go' && path.posix.dirname(s.file) === dir
+    );
+    const body = source.masked.slice(
+      source.lineStart(enclosing.line),
+      source.lineStart(enclosing.endLine + 1)
+    );
+    const type = goVariableTypes(enclosing, body, goConstructors(inPackage)).get(receiver);
+    // pkg.Type: the parsers record its methods under the bare type name
+    return type?.split('.').pop();
+  }
+
+  /**
+   * A bare name: the innermost scope that declares it wins, from the enclosing
+   * class (Java, C++), to the file, to the Go package, to the whole project
+   */
+  private resolveBare(
+    name: string,
+    file: string,
+    symbols: SymbolEntry[],
+    enclosing: SymbolEntry | undefined
+  ): SymbolEntry[] {
+    const named = symbols.filter(s => s.name === name);
+    const language = getParserForFile(file)?.language;
+    const topLevel = named.filter(s => !s.parent && s.language === language);
+    const dir = path.posix.dirname(file);
+
+    const scopes: SymbolEntry[][] = [
+      IMPLICIT_THIS.has(language ?? '') && enclosing?.parent
+        ? named.filter(s => s.parent === enclosing.parent)
+        : [],
+      topLevel.filter(s => s.file === file),
+      language === 'go' ? topLevel.filter(s => path.posix.dirname(s.file) === dir) : [],
+      topLevel,
+      named.filter(s => s.language === language),
+    ];
+    return scopes.find(scope => scope.length > 0) ?? [];
+  }
+
+  formatResult(result: DefinitionResult): string {
+    const usage = result.receiver ? `${result.receiver}.${result.name}` : result.name;
+    const via = result.receiverType ? ` (${result.receiver} is ${result.receiverType})` : '';
+    const at = `${result.file}:${result.line}:${result.column}`;
+
+    if (result.definitions.length === 0) {
+      if (result.external) {
+        return `${usage} at ${at} is defined outside the project, in package ${result.external}.`;
+      }
+      return `No definition of ${usage} found in the project${via}.`;
+    }
+
+    let output = result.resolved
+      ? `${usage} at ${at} resolves to${via}:\n\n`
+      : `${usage} at ${at} is ambiguous${via}: ${result.definitions.length} candidate(s)\n\n`;
+    for (const symbol of result.definitions) {
+      output += `${symbol.kind} ${qualifiedName(symbol)} (${symbol.file}:${symbol.line})\n`;
+      output += `  ${symbol.signature}\n`;
+      if (symbol.doc) output += `  ${symbol.doc.split('\n')[0]}\n`;
+      output += '\n';
+    }
+    return output.trimEnd();
+  }
+
+  formatHover(hover: HoverResult | null): string {
+    if (!hover) return 'Nothing to show at this position.';
+
+    const { symbol } = hover;
+    const role = hover.role === 'definition' ? 'definition' : 'usage';
+    const defined = `${symbol.file}:${symbol.line}`;
+    let output = `${symbol.kind} ${qualifiedName(symbol)} (${role}, defined at ${defined})\n`;
+    output += `  ${symbol.signature}\n`;
+    if (symbol.doc) output += `  ${symbol.doc}\n`;
+    if (hover.candidates > 1) {
+      const others = hover.candidates - 1;
+      output += `\n${others} other definition(s) share the name; eng_resolve_symbol lists them\n`;
+    }
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+/**
+ * The identifier covering a 1-based line and column, outside comments and strings
+ */
+function identifierAt(
+  source: SourceText,
+  line: number,
+  column: number
+): { name: string; offset: number; column: number } | undefined {
+  if (line < 1 || line > source.lineCount) return undefined;
+  const lineStart = source.lineStart(line);
+  const text = source.lineText(line, true);
+  let start = Math.min(Math.max(column - 1, 0), text.length);
+  // A cursor just past the name still counts: c.Add|(2)
+  if (!IDENTIFIER_CHAR.test(text[start] ?? '') && IDENTIFIER_CHAR.test(text[start - 1] ?? '')) {
+    start--;
+  }
+  if (!IDENTIFIER_CHAR.test(text[start] ?? '')) return undefined;
+
+  let end = start;
+  while (start > 0 && IDENTIFIER_CHAR.test(text[start - 1] ?? '')) start--;
+  while (end < text.length && IDENTIFIER_CHAR.test(text[end] ?? '')) end++;
+  const name = text.slice(start, end);
+  if (/^\d/.test(name)) return undefined;
+  return { name, offset: lineStart + start, column: start + 1 };
+}
+
+/**
+ * Declared type of a local in a non-Go function: "c: Calculator",
+ * "c = new Calculator(", "Calculator c", "c = Calculator(" (Python), or
+ * "c = Calculator::new(" (Rust)
+ */
+function localType(source: SourceText, enclosing: SymbolEntry, name: string): string | undefined {
+  const body = source.masked.slice(
+    source.lineStart(enclosing.line),
+    source.lineStart(enclosing.endLine + 1)
+  );
+  // $ is legal in JavaScript identifiers
+  const local = escapeRegExp(name);
+  const patterns = [
+    new RegExp(`(?<![\\w$.])${local}\\s*\\??:\\s*&?(?:mut\\s+)?([A-Z][\\w$]*)`),
+    new RegExp(`(?<![\\w$.])${local}\\s*=\\s*(?:new\\s+)?([A-Z][\\w$]*)\\s*(?:::\\s*\\w+\\s*)?\\(`),
+    new RegExp(`\\b([A-Z]\\w*)(?:<[^<>;]*>)?\\s*[*&]?\\s+${local}\\s*[;=,)({]`),
+  ];
+  for (const pattern of patterns) {
+    const type = pattern.exec(body)?.[1];
+    if (type) return type;
+  }
+  return undefined;
+}
+
+function isType(symbol: SymbolEntry): boolean {
+  return ['class', 'struct', 'interface', 'type', 'enum'].includes(symbol.kind);
+}
+
+/**
+ * Whether a Go symbol is in the package an import path names: the path ends
+ * with the symbol's directory
+ */
+function inGoPackage(symbol: SymbolEntry, importPath: string): boolean {
+  const dir = path.posix.dirname(symbol.file);
+  return dir !== '.' && (importPath === dir || importPath.endsWith(`/${dir}`));
+}
+
+function escapeRegExp(text: string): string {
+  return text.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+}
+
+function words(text: string): Set<string> {
+  return new Set(text.trim().split(/\s+/));
+}
@@ -59,9 +59,17 @@ const FILE_EXTENSIONS: Record<string, string> = {
   '.cs': 'csharp',
 };
 
+interface ScannedImports {
+  mtimeMs: number;
+  size: number;
+  paths: string[]; // As written, in pattern order
+}
+
 export class DependencyAnalyzer {
   private workingDir: string;
   private graph: DependencyGraph;
+  // Import paths by file, read again only when its size or mtime changes
+  private scanned = new Map<string, ScannedImports>();
 
   constructor(workingDir?: string) {
     this.workingDir = workingDir ?? process.cwd();
@@ -74,6 +82,53 @@ export class DependencyAnalyzer {
   }
 
   async analyze(): Promise<DependencyReport> {
+    await this.loadNodes();
+
+    // Build reverse dependencies (importedBy)
+    this.buildReverseDependencies();
+
+    // Detect circular dependencies
+    this.detectCircularDependencies();
+
+    // Find entry points and orphans
+    this.findEntryPointsAndOrphans();
+
+    return this.generateReport();
+  }
+
+  /**
+   * Files that directly import any of the given files. Go imports name
+   * packages, so a Go file depends on every file in an imported package dir.
+   * Only each file's imports are needed, so unchanged files aren't re-read
+   * and no cycles or entry points are worked out.
+   */
+  async findDirectDependents(files: string[]): Promise<string[]> {
+    await this.loadNodes();
+
+    const targets = new Set(files.map(moduleKey));
+    const goFiles = files.filter(f => f.endsWith('.go'));
+    const goPackages = [...new Set(goFiles.map(f => path.dirname(f)))];
+    const dependents = new Set<string>();
+
+    for (const [file, node] of this.graph.nodes) {
+      const dependsOnChange = node.imports.some(
+        imported =>
+          targets.has(moduleKey(imported)) ||
+          (file.endsWith('.go') &&
+            goPackages.some(pkg => imported === pkg || imported.endsWith(`/${pkg}`)))
+      );
+      if (dependsOnChange && !files.includes(file)) {
+        dependents.add(file);
+      }
+    }
+
+    return [...dependents].sort();
+  }
+
+  /**
+   * A fresh graph of every source file and its imports, without importedBy
+   */
+  private async loadNodes(): Promise<void> {
     this.graph = {
       nodes: new Map(),
       circular: [],
@@ -101,17 +156,10 @@ export class DependencyAnalyzer {
     for (const file of files) {
       await this.parseFile(file);
     }
-
-    // Build reverse dependencies (importedBy)
-    this.buildReverseDependencies();
-
-    // Detect circular dependencies
-    this.detectCircularDependencies();
-
-    // Find entry points and orphans
-    this.findEntryPointsAndOrphans();
-
-    return this.generateReport();
+    const listed = new Set(files);
+    for (const file of this.scanned.keys()) {
+      if (!listed.has(file)) this.scanned.delete(file);
+    }
   }
 
   private async parseFile(filePath: string): Promise<void> {
@@ -125,27 +173,34 @@ export class DependencyAnalyzer {
 
     try {
       const fullPath = path.join(this.workingDir, filePath);
-      const content = await fs.readFile(fullPath, 'utf-8');
+      const stat = await fs.stat(fullPath);
+      let scanned = this.scanned.get(filePath);
+      if (!scanned || scanned.mtimeMs !== stat.mtimeMs || scanned.size !== stat.size) {
+        const content = await fs.readFile(fullPath, 'utf-8');
+        const paths: string[] = [];
+        for (const pattern of patterns) {
+          pattern.lastIndex = 0;
+          let match;
+
+          while ((match = pattern.exec(content)) !== null) {
+            if (match[1]) paths.push(match[1]);
+          }
+        }
+        scanned = { mtimeMs: stat.mtimeMs, size: stat.size, paths };
+        this.scanned.set(filePath, scanned);
+      }
 
       const imports: string[] = [];
       const externalDeps: string[] = [];
 
-      for (const pattern of patterns) {
-        pattern.lastIndex = 0;
-        let match;
-
-        while ((match = pattern.exec(content)) !== null) {
-          const importPath = match[1];
-          if (!importPath) continue;
-
-          // Determine if internal or external
-          if (this.isExternalImport(importPath, language)) {
-            externalDeps.push(importPath);
-          } else {
-            const resolved = this.resolveImport(importPath, filePath, language);
-            if (resolved) {
-              imports.push(resolved);
-            }
+      for (const importPath of scanned.paths) {
+        // Determine if internal or external
+        if (this.isExternalImport(importPath, language)) {
+          externalDeps.push(importPath);
+        } else {
+          const resolved = this.resolveImport(importPath, filePath, language);
+          if (resolved) {
+            imports.push(resolved);
           }
         }
       }
@@ -410,6 +465,7 @@ export class DependencyAnalyzer {
 
   setWorkingDir(dir: string): void {
     this.workingDir = dir;
+    this.scanned.clear();
     this.graph = {
       nodes: new Map(),
       circular: [],
@@ -418,3 +474,14 @@ export class DependencyAnalyzer {
     };
   }
 }
+
+/**
+ * Path with source extension and trailing /index removed, so "./a/b.js",
+ * "a/b.ts", and "a/b/index.ts" imports can be compared
+ */
+function moduleKey(file: string): string {
+  return file
+    .replace(/\\/g, '/')
+    .replace(/\.(?:d\.)?[cm]?[jt]sx?$|\.py$/, '')
+    .replace(/\/index$/, '');
+}
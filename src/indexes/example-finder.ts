@@ -0,0 +1,288 @@
+/**
+ * Example Finder
+ * Go Example functions from _test.go files with the symbol each documents,
+ * matched by go test's naming convention (ExampleCalculator_Add documents
+ * Calculator.Add), their code, and the output they promise
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { SymbolEntry } from '../types/index.js';
+import type { ScopeOptions } from '../core/file-walker.js';
+import { GO_SYNTAX, SourceText, cleanComment } from '../parsers/source.js';
+import type { CommentRange } from '../parsers/source.js';
+import { SymbolIndexer } from './symbol-indexer.js';
+
+export type ExampleTargetKind = 'package' | 'function' | 'type' | 'method';
+
+export interface GoExample {
+  name: string;
+  file: string;
+  line: number;
+  endLine: number;
+  documents: string; // Calculator.Add, Calculator, NewCalculator, or the package name
+  kind: ExampleTargetKind;
+  suffix?: string | undefined; // "second" of ExampleCalculator_Add_second
+  definition?: { file: string; line: number } | undefined; // Unset when nothing by that name exists
+  code: string; // The body, dedented, without the output comment
+  output?: string | undefined; // Expected output; unset when the example is only compiled
+  unordered?: boolean | undefined; // An "Unordered output:" comment
+}
+
+export interface ExampleReport {
+  examples: number;
+  unresolved: number; // Examples naming a symbol the package doesn't declare
+  results: GoExample[];
+}
+
+export interface ExampleOptions extends ScopeOptions {
+  symbol?: string | undefined; // Only examples documenting this name or qualified name
+}
+
+// Example followed by anything but a lower-case letter, as go test requires
+const EXAMPLE_NAME = /^Example(?![a-z])/;
+
+// Output: or Unordered output:, in any case, as go test matches it
+const OUTPUT_MARKER = /^(unordered\s+)?output:/i;
+
+const PACKAGE_CLAUSE = /^\s*package\s+([A-Za-z_]\w*)/m;
+
+const GO_TYPE_KINDS = new Set(['struct', 'interface', 'type']);
+
+interface ExampleName {
+  kind: ExampleTargetKind;
+  type?: string | undefined; // The type or function before the first _
+  method?: string | undefined;
+  suffix?: string | undefined;
+}
+
+export class ExampleFinder {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  async find(target = '.', options: ExampleOptions = {}): Promise<ExampleReport> {
+    const examples = (
+      await this.symbolIndexer.scan(target, { include: options.include, exclude: options.exclude })
+    ).filter(isExample);
+
+    // Each directory's own declarations, test files left out, to resolve names against
+    const declarations = new Map<string, SymbolEntry[]>();
+    for (const dir of new Set(examples.map(s => path.posix.dirname(s.file)))) {
+      const symbols = await this.symbolIndexer.scan(dir, { maxDepth: 0 });
+      declarations.set(
+        dir,
+        symbols.filter(s => s.language === 'go' && !s.file.endsWith('_test.go'))
+      );
+    }
+
+    const results: GoExample[] = [];
+    for (const file of new Set(examples.map(s => s.file))) {
+      let source: SourceText;
+      try {
+        const content = await fs.readFile(
+          path.join(this.symbolIndexer.getWorkingDir(), file),
+          'utf-8'
+        );
+        source = new SourceText(content, GO_SYNTAX);
+      } catch {
+        // Skip files that can't be read
+        continue;
+      }
+      const packageName = (PACKAGE_CLAUSE.exec(source.masked)?.[1] ?? '').replace(/_test$/, '');
+      const declared = declarations.get(path.posix.dirname(file)) ?? [];
+
+      for (const symbol of examples.filter(s => s.file === file)) {
+        const parsed = parseExampleName(symbol.name);
+        const definition = resolve(parsed, declared);
+        const documents =
+          parsed.kind === 'package'
+            ? packageName
+            : parsed.method
+              ? `${parsed.type}.${parsed.method}`
+              : (parsed.type ?? '');
+        // ExampleFoo is a function or a type, whichever the package declares
+        const kind =
+          parsed.kind === 'function' && definition && GO_TYPE_KINDS.has(definition.kind)
+            ? 'type'
+            : parsed.kind;
+
+        const example: GoExample = {
+          name: symbol.name,
+          file,
+          line: symbol.line,
+          endLine: symbol.endLine,
+          documents,
+          kind,
+          ...exampleBody(source, symbol),
+        };
+        if (parsed.suffix) example.suffix = parsed.suffix;
+        if (definition) example.definition = { file: definition.file, line: definition.line };
+        results.push(example);
+      }
+    }
+
+    const wanted = options.symbol;
+    const matching = wanted
+      ? results.filter(e => e.documents === wanted || e.documents.split('.').pop() === wanted)
+      : results;
+    matching.sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+
+    return {
+      examples: matching.length,
+      unresolved: matching.filter(e => !e.definition && e.kind !== 'package').length,
+      results: matching,
+    };
+  }
+
+  formatReport(report: ExampleReport): string {
+    if (report.examples === 0) {
+      return 'No Go examples found.';
+    }
+
+    let output = `${report.examples} example(s)`;
+    if (report.unresolved > 0) output += `, ${report.unresolved} naming no declared symbol`;
+    output += ':\n';
+    for (const example of report.results) {
+      const of = example.kind === 'package' ? `package ${example.documents}` : example.documents;
+      const where = example.definition
+        ? ` (${example.definition.file}:${example.definition.line})`
+        : example.kind === 'package'
+          ? ''
+          : ' (not declared)';
+      output += `\n${example.name} documents ${of}${where}\n`;
+      output += `  ${example.file}:${example.line}\n\n`;
+      output += indent(example.code === '' ? '// (empty)' : example.code) + '\n';
+      if (example.output !== undefined) {
+        output += `\n  ${example.unordered ? 'Unordered output' : 'Output'}:\n`;
+        output += indent(example.output === '' ? '(none)' : example.output) + '\n';
+      }
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+function isExample(symbol: SymbolEntry): boolean {
+  return (
+    symbol.language === 'go' &&
+    symbol.file.endsWith('_test.go') &&
+    symbol.kind === 'function' &&
+    !symbol.parent &&
+    EXAMPLE_NAME.test(symbol.name)
+  );
+}
+
+/**
+ * What an example's name says it documents: Example (the package), ExampleF
+ * (a function or type), ExampleT_M (a method), each optionally followed by a
+ * suffix starting with a lower-case letter, e.g. ExampleT_M_second
+ */
+function parseExampleName(name: string): ExampleName {
+  const rest = name.replace(EXAMPLE_NAME, '');
+  if (rest === '' || rest.startsWith('_')) {
+    return { kind: 'package', suffix: rest.slice(1) || undefined };
+  }
+
+  const [type, ...parts] = rest.split('_');
+  const method = parts[0] && /^[A-Z]/.test(parts[0]) ? parts.shift() : undefined;
+  const suffix = parts.join('_') || undefined;
+  return method ? { kind: 'method', type, method, suffix } : { kind: 'function', type, suffix };
+}
+
+function resolve(parsed: ExampleName, declared: SymbolEntry[]): SymbolEntry | undefined {
+  if (parsed.kind === 'package') return undefined;
+  if (parsed.method) {
+    return declared.find(
+      s => s.name === parsed.method && s.parent === parsed.type && s.kind === 'method'
+    );
+  }
+  return declared.find(
+    s =>
+      s.name === parsed.type &&
+      !s.parent &&
+      (s.kind === 'function' || GO_TYPE_KINDS.has(s.kind))
+  );
+}
+
+/**
+ * The code between an example's braces, and the output its last comment
+ * promises. go test only checks an output comment that ends the body.
+ */
+function exampleBody(
+  source: SourceText,
+  symbol: SymbolEntry
+): Pick<GoExample, 'code' | 'output' | 'unordered'> {
+  const open = source.masked.indexOf('{', source.lineStart(symbol.line));
+  const close = open === -1 ? -1 : source.findMatching(open);
+  if (close === -1) return { code: '' };
+
+  const comments = source.comments.filter(c => c.start > open && c.end <= close);
+  const group = trailingCommentGroup(source, comments, close);
+  const marker = group.findIndex(c => OUTPUT_MARKER.test(cleanComment(c)));
+  if (marker === -1) {
+    return { code: dedent(source.content.slice(open + 1, close)) };
+  }
+
+  const outputComments = group.slice(marker);
+  const first = outputComments[0] as CommentRange;
+  const last = outputComments[outputComments.length - 1] as CommentRange;
+  const lines = outputComments.map(cleanComment);
+  const match = OUTPUT_MARKER.exec(lines[0] ?? '');
+  lines[0] = (lines[0] ?? '').slice(match?.[0].length ?? 0);
+  const code = source.content.slice(open + 1, first.start) + source.content.slice(last.end, close);
+
+  return {
+    code: dedent(code),
+    output: lines.join('\n').trim(),
+    ...(match?.[1] ? { unordered: true } : {}),
+  };
+}
+
+/**
+ * The comments on consecutive lines that end a body, with only whitespace
+ * between the last of them and the closing brace
+ */
+function trailingCommentGroup(
+  source: SourceText,
+  comments: CommentRange[],
+  close: number
+): CommentRange[] {
+  const group: CommentRange[] = [];
+  let end = close;
+  for (let i = comments.length - 1; i >= 0; i--) {
+    const comment = comments[i] as CommentRange;
+    if (source.content.slice(comment.end, end).trim() !== '') break;
+    const next = group[0];
+    if (next && comment.endLine + 1 < next.line) break;
+    group.unshift(comment);
+    end = comment.start;
+  }
+  return group;
+}
+
+/**
+ * Lines without their common indentation, blank leading and trailing lines
+ * dropped
+ */
+function dedent(text: string): string {
+  const lines = text.replace(/^\s*\n/, '').trimEnd().split('\n');
+  const indents = lines
+    .filter(line => line.trim() !== '')
+    .map(line => /^[ \t]*/.exec(line)?.[0].length ?? 0);
+  const common = indents.length > 0 ? Math.min(...indents) : 0;
+  return lines.map(line => line.slice(common).trimEnd()).join('\n');
+}
+
+function indent(text: string): string {
+  return text
+    .split('\n')
+    .map(line => (line === '' ? '' : `    ${line}`))
+    .join('\n');
+}
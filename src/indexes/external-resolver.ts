@@ -0,0 +1,421 @@
+/**
+ * External Resolver
+ * Finds the source of a Go symbol outside the project: the standard library
+ * under GOROOT, or a dependency in vendor/, a local replace, or the module
+ * cache at the version go.mod requires
+ */
+
+import * as fs from 'fs/promises';
+import * as os from 'os';
+import * as path from 'path';
+import type { SymbolEntry } from '../types/index.js';
+import { ToolError } from '../core/errors.js';
+import { resolveProjectPath } from '../core/file-reader.js';
+import { walkFiles } from '../core/file-walker.js';
+import { runProcess } from '../core/process.js';
+import { parseGoImports } from '../parsers/go-parser.js';
+import { GO_SYNTAX, SourceText } from '../parsers/source.js';
+import { isStdlib } from './import-analyzer.js';
+import { SymbolIndexer } from './symbol-indexer.js';
+
+export type ExternalSource = 'goroot' | 'module-cache' | 'vendor' | 'replace' | 'project';
+
+export interface ExternalDeclaration {
+  name: string;
+  kind: SymbolEntry['kind'];
+  parent?: string | undefined;
+  file: string; // Absolute path
+  line: number;
+  endLine: number;
+  signature: string;
+  doc?: string | undefined;
+  declaration: string; // Source text of the declaration, body included
+}
+
+export interface ExternalDefinition {
+  symbol: string; // As asked
+  package: string; // Import path
+  module?: string | undefined; // Module providing the package, outside the standard library
+  version?: string | undefined;
+  source: ExternalSource;
+  dir: string; // Absolute directory of the package
+  definitions: ExternalDeclaration[]; // One per file when build constraints split it
+}
+
+interface GoModFile {
+  dir: string; // Absolute directory holding go.mod
+  module?: string | undefined;
+  requires: Map<string, string>; // Module path -> version
+  replaces: Map<string, { path: string; version?: string | undefined }>;
+}
+
+interface PackageLocation {
+  source: ExternalSource;
+  dir: string;
+  module?: string | undefined;
+  version?: string | undefined;
+}
+
+const IDENTIFIER = /^[A-Za-z_]\w*$/;
+
+export class ExternalResolver {
+  private symbolIndexer: SymbolIndexer;
+  private goEnv: Promise<{ goroot?: string; modcache: string }> | undefined;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  /**
+   * Declarations of pkg.Name or pkg.Type.Method, where pkg is an import path
+   * (net/http, gopkg.in/yaml.v3) or the name a project file imports it as
+   * (http, yaml). from is the file whose imports and go.mod decide the
+   * package; without it, the project's imports and root go.mod do.
+   */
+  async resolve(symbol: string, from?: string): Promise<ExternalDefinition> {
+    const workingDir = this.symbolIndexer.getWorkingDir();
+    const fromFile = from === undefined ? undefined : resolveProjectPath(workingDir, from);
+    const goMod = await findGoMod(path.join(workingDir, fromFile ? path.dirname(fromFile) : '.'));
+
+    let lastError: ToolError | undefined;
+    for (const { pkg, member } of await this.candidates(symbol, fromFile)) {
+      let location: PackageLocation;
+      try {
+        location = await this.locate(pkg, goMod);
+      } catch (error) {
+        if (!(error instanceof ToolError)) throw error;
+        lastError ??= error;
+        continue;
+      }
+      const definitions = await this.declarations(location.dir, member);
+      if (definitions.length === 0) {
+        // The package exists, so this split of the name is the one meant
+        lastError = new ToolError(
+          'FILE_NOT_FOUND',
+          `No declaration of ${member.join('.')} in ${pkg} (${location.dir})`
+        );
+        continue;
+      }
+      return {
+        symbol,
+        package: pkg,
+        ...(location.module ? { module: location.module } : {}),
+        ...(location.version ? { version: location.version } : {}),
+        source: location.source,
+        dir: location.dir,
+        definitions,
+      };
+    }
+    throw lastError ?? new ToolError('INVALID_ARGUMENT', `Not a package symbol: ${symbol}`);
+  }
+
+  /**
+   * Ways to split a symbol into package and member, most specific package
+   * first: gopkg.in/yaml.v3.Marshal could be package gopkg.in/yaml.v3 or
+   * gopkg.in/yaml with member v3.Marshal
+   */
+  private async candidates(
+    symbol: string,
+    fromFile: string | undefined
+  ): Promise<Array<{ pkg: string; member: string[] }>> {
+    const slash = symbol.lastIndexOf('/');
+    const head = symbol.slice(0, slash + 1);
+    const parts = symbol.slice(slash + 1).split('.');
+    const found: Array<{ pkg: string; member: string[] }> = [];
+
+    for (let split = parts.length - 1; split >= 1; split--) {
+      const member = parts.slice(split);
+      if (member.length > 2 || !member.every(part => IDENTIFIER.test(part))) continue;
+      const pkg = head + parts.slice(0, split).join('.');
+      if (!head) {
+        // A bare name is an import alias, or a standard library path like fmt
+        for (const importPath of await this.importsNamed(pkg, fromFile)) {
+          found.push({ pkg: importPath, member });
+        }
+      }
+      found.push({ pkg, member });
+    }
+    return found;
+  }
+
+  /**
+   * Import paths the file, or else any Go file of the project, imports
+   * under a name; more than one is ambiguous
+   */
+  private async importsNamed(name: string, fromFile: string | undefined): Promise<string[]> {
+    const workingDir = this.symbolIndexer.getWorkingDir();
+    const files = fromFile ? [fromFile] : await walkFiles(workingDir, ['**/*.go']);
+    const paths = new Set<string>();
+    for (const file of files) {
+      let content: string;
+      try {
+        content = await fs.readFile(path.join(workingDir, file), 'utf-8');
+      } catch {
+        continue;
+      }
+      for (const imported of parseGoImports(new SourceText(content, GO_SYNTAX))) {
+        if (imported.name === name) paths.add(imported.path);
+      }
+    }
+    if (paths.size > 1) {
+      throw new ToolError(
+        'INVALID_ARGUMENT',
+        `${name} is imported as more than one package (${[...paths].sort().join(', ')}); pass the import path or the file using it`
+      );
+    }
+    return [...paths];
+  }
+
+  /**
+   * Directory of a package: GOROOT for the standard library; otherwise
+   * vendor/, a replace directive, or the module cache, by the module that
+   * go.mod requires for it
+   */
+  private async locate(pkg: string, goMod: GoModFile | undefined): Promise<PackageLocation> {
+    // Import paths are joined onto GOROOT and vendor, so they must not climb out
+    const segments = pkg.split('/');
+    if (path.isAbsolute(pkg) || pkg.includes('\\') || segments.some(s => s === '..' || s === '.')) {
+      throw new ToolError('INVALID_ARGUMENT', `Not an import path: ${pkg}`);
+    }
+
+    if (isStdlib(pkg)) {
+      const { goroot } = await this.env();
+      if (!goroot) {
+        throw new ToolError(
+          'FAILED_PRECONDITION',
+          `Can't find the standard library for ${pkg}: Go isn't installed and GOROOT isn't set`
+        );
+      }
+      const dir = contained(path.join(goroot, 'src'), pkg);
+      return existing({ source: 'goroot', dir }, pkg);
+    }
+    if (!goMod) {
+      throw new ToolError('FAILED_PRECONDITION', `No go.mod found to resolve ${pkg}`);
+    }
+
+    if (goMod.module && within(pkg, goMod.module)) {
+      const dir = path.join(goMod.dir, pkg.slice(goMod.module.length));
+      return existing({ source: 'project', dir, module: goMod.module }, pkg);
+    }
+    const vendored = contained(path.join(goMod.dir, 'vendor'), pkg);
+    if (await isDirectory(vendored)) return { source: 'vendor', dir: vendored };
+
+    const module = [...goMod.requires.keys()]
+      .filter(m => within(pkg, m))
+      .sort((a, b) => b.length - a.length)[0];
+    if (!module) {
+      throw new ToolError(
+        'FILE_NOT_FOUND',
+        `${pkg} isn't provided by any module go.mod requires`,
+        path.relative(this.symbolIndexer.getWorkingDir(), path.join(goMod.dir, 'go.mod'))
+      );
+    }
+    const subpath = pkg.slice(module.length);
+    const version = goMod.requires.get(module) ?? '';
+    const replacement = goMod.replaces.get(`${module}@${version}`) ?? goMod.replaces.get(module);
+    if (replacement && /^\.{0,2}\//.test(replacement.path)) {
+      const dir = path.join(path.resolve(goMod.dir, replacement.path), subpath);
+      return existing({ source: 'replace', dir, module }, pkg);
+    }
+
+    const target = replacement?.version
+      ? { module: replacement.path, version: replacement.version }
+      : { module, version };
+    const { modcache } = await this.env();
+    const dir = path.join(
+      modcache,
+      `${escapeModulePath(target.module)}@${escapeModulePath(target.version)}`,
+      subpath
+    );
+    if (!(await isDirectory(dir))) {
+      throw new ToolError(
+        'FILE_NOT_FOUND',
+        `Source of ${target.module}@${target.version} isn't in the module cache (${modcache}); run go mod download`
+      );
+    }
+    return { source: 'module-cache', dir, ...target };
+  }
+
+  /**
+   * Declarations of a member in the package's non-test files
+   */
+  private async declarations(dir: string, member: string[]): Promise<ExternalDeclaration[]> {
+    const [first, second] = member;
+    const entries = await fs.readdir(dir, { withFileTypes: true });
+    const definitions: ExternalDeclaration[] = [];
+
+    for (const entry of entries.sort((a, b) => a.name.localeCompare(b.name))) {
+      if (!entry.isFile() || !entry.name.endsWith('.go') || entry.name.endsWith('_test.go')) {
+        continue;
+      }
+      const file = path.join(dir, entry.name);
+      const content = await fs.readFile(file, 'utf-8');
+      const symbols = this.symbolIndexer
+        .extractSource(content, file, 'go')
+        .filter(s =>
+          second === undefined
+            ? !s.parent && s.name === first
+            : s.parent === first && s.name === second
+        );
+      if (symbols.length === 0) continue;
+
+      const lines = content.split('\n');
+      for (const symbol of symbols) {
+        const definition: ExternalDeclaration = {
+          name: symbol.name,
+          kind: symbol.kind,
+          file,
+          line: symbol.line,
+          endLine: symbol.endLine,
+          signature: symbol.signature,
+          declaration: lines.slice(symbol.line - 1, symbol.endLine).join('\n'),
+        };
+        if (symbol.parent) definition.parent = symbol.parent;
+        if (symbol.doc) definition.doc = symbol.doc;
+        definitions.push(definition);
+      }
+    }
+    return definitions;
+  }
+
+  /**
+   * GOROOT and GOMODCACHE from go env, or from the environment and Go's
+   * defaults when the go command isn't installed
+   */
+  private env(): Promise<{ goroot?: string; modcache: string }> {
+    this.goEnv ??= (async () => {
+      const output = await runProcess('go', ['env', 'GOROOT', 'GOMODCACHE'], {
+        cwd: this.symbolIndexer.getWorkingDir(),
+      }).catch(() => undefined);
+      // go env prints an empty line for a variable it can't determine
+      const [goroot, modcache] = (output?.code === 0 ? output.stdout.split('\n') : [])
+        .map(line => line.trim())
+        .map(value => (value === '' ? undefined : value));
+      const gopath = process.env.GOPATH?.split(path.delimiter)[0] ?? path.join(os.homedir(), 'go');
+      const root = goroot ?? process.env.GOROOT;
+      return {
+        ...(root ? { goroot: root } : {}),
+        modcache: modcache ?? process.env.GOMODCACHE ?? path.join(gopath, 'pkg', 'mod'),
+      };
+    })();
+    return this.goEnv;
+  }
+
+  formatDefinition(result: ExternalDefinition): string {
+    const origin = result.module
+      ? `${result.module}${result.version ? `@${result.version}` : ''}, ${result.source}`
+      : result.source;
+    let output = `${result.symbol} -> ${result.package} (${origin})\n`;
+    for (const definition of result.definitions) {
+      output += `\n${definition.file}:${definition.line}\n`;
+      if (definition.doc) {
+        output += definition.doc
+          .split('\n')
+          .map(line => `// ${line}`.trimEnd())
+          .join('\n');
+        output += '\n';
+      }
+      output += `${definition.declaration}\n`;
+    }
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+    this.goEnv = undefined;
+  }
+}
+
+/**
+ * The nearest go.mod at or above a directory, parsed for its module path,
+ * requirements, and replacements
+ */
+async function findGoMod(dir: string): Promise<GoModFile | undefined> {
+  let current = dir;
+  for (;;) {
+    try {
+      const content = await fs.readFile(path.join(current, 'go.mod'), 'utf-8');
+      return parseGoMod(content, current);
+    } catch {
+      const parent = path.dirname(current);
+      if (parent === current) return undefined;
+      current = parent;
+    }
+  }
+}
+
+function parseGoMod(content: string, dir: string): GoModFile {
+  const goMod: GoModFile = { dir, requires: new Map(), replaces: new Map() };
+  let block: string | undefined;
+  for (const raw of content.split('\n')) {
+    const line = raw.replace(/\/\/.*$/, '').trim();
+    if (!line) continue;
+    if (block && line === ')') {
+      block = undefined;
+      continue;
+    }
+    const opened = /^(module|require|replace|exclude|retract|tool|godebug)\s*\($/.exec(line);
+    if (opened) {
+      block = opened[1];
+      continue;
+    }
+
+    const directive = block ?? line.split(/\s+/)[0];
+    const spec = (block ? line : line.slice(directive?.length ?? 0)).trim();
+    const fields = spec.split(/\s+/).map(field => field.replace(/^"|"$/g, ''));
+    if (directive === 'module' && fields[0]) {
+      goMod.module = fields[0];
+    } else if (directive === 'require' && fields[0] && fields[1]) {
+      goMod.requires.set(fields[0], fields[1]);
+    } else if (directive === 'replace') {
+      const arrow = fields.indexOf('=>');
+      const [from, fromVersion] = fields.slice(0, arrow);
+      const [to, toVersion] = fields.slice(arrow + 1);
+      if (arrow === -1 || !from || !to) continue;
+      const key = fromVersion ? `${from}@${fromVersion}` : from;
+      goMod.replaces.set(key, toVersion ? { path: to, version: toVersion } : { path: to });
+    }
+  }
+  return goMod;
+}
+
+/**
+ * Module cache path escaping: each uppercase letter becomes ! and its
+ * lowercase, since the cache may live on a case-insensitive file system
+ */
+export function escapeModulePath(modulePath: string): string {
+  return modulePath.replace(/[A-Z]/g, letter => `!${letter.toLowerCase()}`);
+}
+
+function within(pkg: string, module: string): boolean {
+  return pkg === module || pkg.startsWith(`${module}/`);
+}
+
+/**
+ * base/pkg, checked to still be under base
+ */
+function contained(base: string, pkg: string): string {
+  const dir = path.resolve(base, pkg);
+  const relative = path.relative(base, dir);
+  const outside =
+    relative === '..' || relative.startsWith('..' + path.sep) || path.isAbsolute(relative);
+  if (!relative || outside) {
+    throw new ToolError('INVALID_ARGUMENT', `Import path ${pkg} resolves outside ${base}`);
+  }
+  return dir;
+}
+
+async function existing(location: PackageLocation, pkg: string): Promise<PackageLocation> {
+  if (!(await isDirectory(location.dir))) {
+    throw new ToolError('FILE_NOT_FOUND', `No source for package ${pkg} (${location.dir})`);
+  }
+  return location;
+}
+
+async function isDirectory(dir: string): Promise<boolean> {
+  try {
+    return (await fs.stat(dir)).isDirectory();
+  } catch {
+    return false;
+  }
+}
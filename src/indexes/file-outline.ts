@@ -0,0 +1,119 @@
+/**
+ * File Outline
+ * Compact per-file tree of declarations: names, kinds, and lines, with
+ * methods nested under their types
+ */
+
+import type { SymbolEntry, SymbolKind } from '../types/index.js';
+import { SymbolIndexer, qualifiedName } from './symbol-indexer.js';
+import type { ScanOptions } from './symbol-indexer.js';
+
+export interface OutlineNode {
+  name: string;
+  kind: SymbolKind;
+  line: number;
+  endLine: number;
+  children?: OutlineNode[] | undefined;
+}
+
+export interface FileOutline {
+  file: string;
+  language: string;
+  outline: OutlineNode[];
+}
+
+// Kinds that never contain other declarations in an outline
+const LEAF_KINDS = new Set<SymbolKind>(['function', 'method', 'const', 'var', 'package']);
+
+export class FileOutliner {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  /**
+   * One outline per file under a file or directory, in walk order
+   */
+  async outline(target = '.', options: Omit<ScanOptions, 'only'> = {}): Promise<FileOutline[]> {
+    const symbols = await this.symbolIndexer.scan(target, options);
+    const byFile = new Map<string, SymbolEntry[]>();
+    for (const symbol of symbols) {
+      const fileSymbols = byFile.get(symbol.file) ?? [];
+      fileSymbols.push(symbol);
+      byFile.set(symbol.file, fileSymbols);
+    }
+
+    return [...byFile.entries()].map(([file, fileSymbols]) => ({
+      file,
+      language: fileSymbols[0]?.language ?? '',
+      outline: buildOutline(fileSymbols),
+    }));
+  }
+
+  formatOutlines(outlines: FileOutline[]): string {
+    if (outlines.length === 0) {
+      return 'No symbols found.';
+    }
+
+    let output = '';
+    for (const { file, language, outline } of outlines) {
+      output += `${file} (${language})\n`;
+      const visit = (nodes: OutlineNode[], depth: number): void => {
+        for (const node of nodes) {
+          const indent = '  '.repeat(depth);
+          output += `${indent}${node.kind} ${node.name}  ${node.line}-${node.endLine}\n`;
+          if (node.children) visit(node.children, depth + 1);
+        }
+      };
+      visit(outline, 1);
+      output += '\n';
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+/**
+ * Nest each symbol under the type its parent names. Go methods may come
+ * before their type, so containers are collected first; a method whose type
+ * is declared in another file stays at the top level under its qualified name
+ */
+function buildOutline(symbols: SymbolEntry[]): OutlineNode[] {
+  const entries = [...symbols]
+    .sort((a, b) => a.line - b.line)
+    .map(symbol => {
+      const node: OutlineNode = {
+        name: symbol.name,
+        kind: symbol.kind,
+        line: symbol.line,
+        endLine: symbol.endLine,
+      };
+      return { symbol, node };
+    });
+
+  // Java nests by qualified name (Outer.Inner), Python by the simple class name
+  const containers = new Map<string, OutlineNode>();
+  for (const { symbol, node } of entries) {
+    if (LEAF_KINDS.has(symbol.kind)) continue;
+    for (const key of [qualifiedName(symbol), symbol.name]) {
+      if (!containers.has(key)) containers.set(key, node);
+    }
+  }
+
+  const outline: OutlineNode[] = [];
+  for (const { symbol, node } of entries) {
+    const parent = symbol.parent === undefined ? undefined : containers.get(symbol.parent);
+    if (parent && parent !== node) {
+      (parent.children ??= []).push(node);
+    } else {
+      node.name = qualifiedName(symbol);
+      outline.push(node);
+    }
+  }
+  return outline;
+}
@@ -0,0 +1,277 @@
+/**
+ * Function Analyzer
+ * Everything about one function in a single report: source, signature, doc,
+ * complexity, parameters, and the functions it calls
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { Parameter, SymbolEntry } from '../types/index.js';
+import { ToolError } from '../core/errors.js';
+import { resolveProjectPath } from '../core/file-reader.js';
+import { getParserForFile } from '../parsers/index.js';
+import { SourceText } from '../parsers/source.js';
+import { splitParams } from './api-fingerprint.js';
+import { CallGraphBuilder } from './call-graph.js';
+import { ComplexityAnalyzer } from './complexity-analyzer.js';
+import { SymbolContextResolver } from './symbol-context.js';
+import { SymbolIndexer, qualifiedName } from './symbol-indexer.js';
+
+export interface FunctionQuery {
+  file: string;
+  symbol?: string | undefined; // Name, optionally qualified: FetchData, Client.FetchData
+  line?: number | undefined; // Any line inside the function
+}
+
+export interface FunctionCall {
+  callee: string; // Qualified when resolved: Calculator.Add; otherwise as written: http.Get
+  external: boolean; // Not defined in the project (Go: outside the package)
+}
+
+export interface FunctionReport {
+  symbol: SymbolEntry;
+  startLine: number; // Includes the doc comment and decorators
+  endLine: number;
+  source: string;
+  complexity: number;
+  parameters: Parameter[];
+  calls: FunctionCall[];
+}
+
+const FUNCTION_KINDS = new Set(['function', 'method']);
+
+// name( or recv.name( outside Go, where the call graph resolves calls instead
+const CALL_PATTERN = /(?:([A-Za-z_$][\w$]*)\s*(?:\?\.|\.|::|->)\s*)?([A-Za-z_$][\w$]*)\s*(?:<[^<>()]*>)?\s*\(/g;
+
+// Words that take parentheses without being calls
+const NOT_CALLS = new Set([
+  'if',
+  'for',
+  'while',
+  'switch',
+  'catch',
+  'return',
+  'function',
+  'typeof',
+  'sizeof',
+  'new',
+  'await',
+  'yield',
+  'super',
+  'elif',
+  'with',
+]);
+
+export class FunctionAnalyzer {
+  private symbolIndexer: SymbolIndexer;
+  private contextResolver: SymbolContextResolver;
+  private complexityAnalyzer: ComplexityAnalyzer;
+  private callGraphBuilder: CallGraphBuilder;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+    this.contextResolver = new SymbolContextResolver(this.symbolIndexer);
+    this.complexityAnalyzer = new ComplexityAnalyzer(this.symbolIndexer);
+    this.callGraphBuilder = new CallGraphBuilder(this.symbolIndexer);
+  }
+
+  async analyze(query: FunctionQuery): Promise<FunctionReport> {
+    const workingDir = this.symbolIndexer.getWorkingDir();
+    const file = resolveProjectPath(workingDir, query.file);
+    const content = await fs.readFile(path.join(workingDir, file), 'utf-8');
+    const parser = getParserForFile(file, content);
+    if (!parser) {
+      throw new ToolError(
+        'UNSUPPORTED_LANGUAGE',
+        `Unsupported language: ${path.basename(file)}`,
+        file
+      );
+    }
+
+    const symbol = this.findFunction(this.symbolIndexer.parseWith(parser, content, file), query);
+    const context = await this.contextResolver.resolve(file, symbol.line);
+    const entry = this.complexityAnalyzer
+      .analyzeSource(content, file, parser.language)
+      .find(e => e.line === symbol.line && e.function === qualifiedName(symbol));
+
+    return {
+      symbol,
+      startLine: context.startLine,
+      endLine: symbol.endLine,
+      source: context.content,
+      complexity: entry?.complexity ?? 1,
+      parameters: symbol.params ?? parseParameters(symbol),
+      calls:
+        symbol.language === 'go'
+          ? await this.goCalls(symbol)
+          : await this.calls(symbol, new SourceText(content, parser.syntax)),
+    };
+  }
+
+  /**
+   * The function or method the query names, by name and/or a line inside it
+   */
+  private findFunction(symbols: SymbolEntry[], query: FunctionQuery): SymbolEntry {
+    const { symbol: name, line } = query;
+    if (!name && line === undefined) {
+      throw new ToolError('INVALID_ARGUMENT', 'Provide a symbol name or a line');
+    }
+
+    const matches = symbols
+      .filter(
+        s =>
+          FUNCTION_KINDS.has(s.kind) &&
+          (!name || s.name === name || qualifiedName(s) === name) &&
+          (line === undefined || (s.line <= line && line <= s.endLine))
+      )
+      // Innermost first when a line falls inside nested functions
+      .sort((a, b) => a.endLine - a.line - (b.endLine - b.line));
+
+    const target = name ?? `line ${line ?? ''}`;
+    const first = matches[0];
+    if (!first) {
+      throw new ToolError(
+        'INVALID_ARGUMENT',
+        `No function ${target} in ${query.file}`,
+        query.file
+      );
+    }
+    if (line === undefined && matches.length > 1) {
+      const lines = matches.map(s => s.line).join(', ');
+      throw new ToolError(
+        'INVALID_ARGUMENT',
+        `${target} is defined ${matches.length} times in ${query.file} (lines ${lines}); pass line`,
+        query.file
+      );
+    }
+    return first;
+  }
+
+  /**
+   * Go calls from the package call graph, with receiver methods resolved
+   */
+  private async goCalls(symbol: SymbolEntry): Promise<FunctionCall[]> {
+    const graph = await this.callGraphBuilder.build(symbol.file, { includeExternal: true });
+    const caller = qualifiedName(symbol);
+    return graph.edges
+      .filter(e => e.caller === caller)
+      .map(e => ({ callee: e.callee, external: e.external }));
+  }
+
+  /**
+   * Distinct call expressions in the body, matched to project functions by
+   * name, or by type for this.name() and self.name()
+   */
+  private async calls(symbol: SymbolEntry, source: SourceText): Promise<FunctionCall[]> {
+    const functions = (await this.symbolIndexer.scan()).filter(
+      s => FUNCTION_KINDS.has(s.kind) && s.language === symbol.language
+    );
+    // Calls start after the signature's parameter list
+    const open = source.masked.indexOf('(', source.lineStart(symbol.line));
+    const close = open === -1 ? -1 : source.findMatching(open);
+    const body = source.masked.slice(close + 1, source.lineStart(symbol.endLine + 1));
+    const calls = new Map<string, FunctionCall>();
+
+    CALL_PATTERN.lastIndex = 0;
+    let match;
+    while ((match = CALL_PATTERN.exec(body)) !== null) {
+      const receiver = match[1];
+      const name = match[2] ?? '';
+      if (!receiver && NOT_CALLS.has(name)) continue;
+      // Declarations and definitions look like calls: class Foo(, def foo(
+      const preceding = body.slice(Math.max(0, match.index - 16), match.index);
+      if (/\b(?:class|def|fn|function|func)\s+$/.test(preceding)) continue;
+
+      const self = receiver && ['this', 'self', 'cls', 'Self'].includes(receiver);
+      const candidates = functions.filter(
+        f =>
+          f.name === name &&
+          (self ? f.parent === symbol.parent : !receiver || f.parent !== undefined)
+      );
+      const resolved = candidates.length === 1 && candidates[0] ? candidates[0] : undefined;
+      const callee = resolved ? qualifiedName(resolved) : receiver ? `${receiver}.${name}` : name;
+      if (!calls.has(callee)) {
+        calls.set(callee, { callee, external: candidates.length === 0 });
+      }
+    }
+
+    return [...calls.values()];
+  }
+
+  formatReport(report: FunctionReport): string {
+    const { symbol } = report;
+    let output = `${symbol.kind} ${qualifiedName(symbol)} (${symbol.file}:`;
+    output += `${report.startLine}-${report.endLine}), complexity ${report.complexity}\n`;
+    output += `  ${symbol.signature}\n`;
+
+    if (report.parameters.length > 0) {
+      output += `\nParameters (${report.parameters.length}):\n`;
+      for (const p of report.parameters) {
+        const type = `${p.variadic ? '...' : ''}${p.type}`;
+        output += `  ${[p.name, type].filter(Boolean).join(' ')}\n`;
+      }
+    }
+
+    if (report.calls.length > 0) {
+      output += `\nCalls (${report.calls.length}):\n`;
+      for (const call of report.calls) {
+        output += `  ${call.callee}${call.external ? ' (external)' : ''}\n`;
+      }
+    }
+
+    output += '\n```' + symbol.language + '\n' + report.source + '\n```';
+    return output;
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+/**
+ * Parameters from a non-Go signature: "name: type" (TypeScript, Python, Rust,
+ * Kotlin, Swift) or "Type name" (Java, C, C++). Defaults are dropped, untyped
+ * names keep an empty type, Python's self and cls are skipped, and a Swift
+ * argument label gives way to the parameter name.
+ */
+function parseParameters(symbol: SymbolEntry): Parameter[] {
+  const open = symbol.signature.indexOf('(');
+  const close = symbol.signature.lastIndexOf(')');
+  if (open === -1 || close < open) return [];
+
+  const colonTyped = ['typescript', 'python', 'rust', 'kotlin', 'swift'].includes(
+    symbol.language
+  );
+  const parameters: Parameter[] = [];
+  for (const raw of splitParams(symbol.signature.slice(open + 1, close))) {
+    let text = raw.replace(/\s*=(?![>=])[\s\S]*$/, '').trim();
+    // Kotlin's vararg items: String and Swift's items: String...
+    const variadic = /^\.\.\.|^\*(?!\*)|\.\.\.\s*\w+$|^vararg\s|\.\.\.$/.test(text);
+    text = text
+      .replace(/^(?:\.\.\.|\*{1,2}|vararg\s+)/, '')
+      .replace(/\.\.\.(?=\s*\w+$)|\.\.\.$/, '');
+
+    if (colonTyped) {
+      const colon = text.search(/:(?!:)/);
+      let name = (colon === -1 ? text : text.slice(0, colon)).replace(/^mut\s+|\?$/g, '').trim();
+      // Swift's argument label comes first: _ view: UIView
+      if (symbol.language === 'swift') name = name.split(/\s+/).pop() ?? name;
+      if (symbol.language === 'python' && (name === 'self' || name === 'cls')) continue;
+      if (symbol.language === 'rust' && /^&?(?:mut\s+)?self$/.test(name)) continue;
+      if (name === '/' || name === '') continue;
+      parameters.push({
+        name,
+        type: colon === -1 ? '' : text.slice(colon + 1).trim(),
+        ...(variadic ? { variadic } : {}),
+      });
+    } else {
+      const named = /^(.*[\w>\]*&.])\s+([A-Za-z_]\w*)((?:\s*\[\s*\])*)$/.exec(text);
+      parameters.push({
+        ...(named?.[2] ? { name: named[2] } : {}),
+        type: named?.[1] ? `${named[1]}${named[3] ?? ''}`.trim() : text,
+        ...(variadic ? { variadic } : {}),
+      });
+    }
+  }
+  return parameters;
+}
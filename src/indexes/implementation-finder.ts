@@ -0,0 +1,471 @@
+/**
+ * Implementation Finder
+ * Finds Go types whose method sets structurally satisfy an interface
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { Parameter, SymbolEntry } from '../types/index.js';
+import { parseGoParameters, parseGoResults, parseGoStructFields } from '../parsers/go-parser.js';
+import { SourceText, GO_SYNTAX } from '../parsers/source.js';
+import { SymbolIndexer } from './symbol-indexer.js';
+import { receiverKind } from './type-inspector.js';
+
+export interface SatisfyingMethod {
+  name: string;
+  file: string;
+  line: number;
+  signature: string;
+  promotedFrom?: string | undefined; // Embedded type the method comes from
+}
+
+export interface Implementation {
+  type: string;
+  file: string;
+  line: number;
+  pointer: boolean; // Only *T satisfies the interface (pointer receiver methods)
+  methods: SatisfyingMethod[];
+}
+
+export interface ImplementationReport {
+  interface: string;
+  file?: string | undefined; // Unset for standard library interfaces
+  line?: number | undefined;
+  methods: string[]; // Full method set, embedded interfaces expanded
+  typeSet: boolean; // Constraint interface (~int | string): no type implements it by methods
+  implementations: Implementation[];
+}
+
+interface MethodSpec {
+  name: string;
+  display: string; // As declared: Write(p []byte) (n int, err error)
+  shape: string; // Parameter and result types only: ([]byte)(int,error)
+}
+
+interface InterfaceSpec {
+  methods: MethodSpec[];
+  typeSet: boolean;
+}
+
+interface MethodSetEntry {
+  symbol: SymbolEntry;
+  shape: string;
+  pointerOnly: boolean;
+  promotedFrom?: string | undefined;
+}
+
+// Method sets of common standard library interfaces; entries without
+// parentheses embed another interface
+const STDLIB_INTERFACES: Record<string, string[]> = {
+  error: ['Error() string'],
+  'fmt.Stringer': ['String() string'],
+  'fmt.GoStringer': ['GoString() string'],
+  'io.Reader': ['Read(p []byte) (n int, err error)'],
+  'io.Writer': ['Write(p []byte) (n int, err error)'],
+  'io.Closer': ['Close() error'],
+  'io.Seeker': ['Seek(offset int64, whence int) (int64, error)'],
+  'io.ReaderAt': ['ReadAt(p []byte, off int64) (n int, err error)'],
+  'io.WriterAt': ['WriteAt(p []byte, off int64) (n int, err error)'],
+  'io.ReaderFrom': ['ReadFrom(r Reader) (n int64, err error)'],
+  'io.WriterTo': ['WriteTo(w Writer) (n int64, err error)'],
+  'io.ByteReader': ['ReadByte() (byte, error)'],
+  'io.ByteWriter': ['WriteByte(c byte) error'],
+  'io.StringWriter': ['WriteString(s string) (n int, err error)'],
+  'io.ReadWriter': ['io.Reader', 'io.Writer'],
+  'io.ReadCloser': ['io.Reader', 'io.Closer'],
+  'io.WriteCloser': ['io.Writer', 'io.Closer'],
+  'io.ReadWriteCloser': ['io.Reader', 'io.Writer', 'io.Closer'],
+  'io.ReadSeeker': ['io.Reader', 'io.Seeker'],
+  'sort.Interface': ['Len() int', 'Less(i, j int) bool', 'Swap(i, j int)'],
+  'heap.Interface': ['sort.Interface', 'Push(x any)', 'Pop() any'],
+  'hash.Hash': ['io.Writer', 'Sum(b []byte) []byte', 'Reset()', 'Size() int', 'BlockSize() int'],
+  'http.Handler': ['ServeHTTP(w ResponseWriter, r *Request)'],
+  'http.ResponseWriter': [
+    'Header() Header',
+    'Write([]byte) (int, error)',
+    'WriteHeader(statusCode int)',
+  ],
+  'json.Marshaler': ['MarshalJSON() ([]byte, error)'],
+  'json.Unmarshaler': ['UnmarshalJSON([]byte) error'],
+  'encoding.TextMarshaler': ['MarshalText() (text []byte, err error)'],
+  'encoding.TextUnmarshaler': ['UnmarshalText(text []byte) error'],
+  'flag.Value': ['String() string', 'Set(string) error'],
+  'sql.Scanner': ['Scan(src any) error'],
+  'driver.Valuer': ['Value() (Value, error)'],
+  'context.Context': [
+    'Deadline() (deadline time.Time, ok bool)',
+    'Done() <-chan struct{}',
+    'Err() error',
+    'Value(key any) any',
+  ],
+};
+
+export class ImplementationFinder {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  /**
+   * Types satisfying each interface with this name: a project interface, or a
+   * standard library one such as io.Writer
+   */
+  async find(interfaceName: string): Promise<ImplementationReport[]> {
+    const symbols = (await this.symbolIndexer.scan()).filter(s => s.language === 'go');
+    const name = interfaceName.replace(/^.*\//, ''); // net/http.Handler -> http.Handler
+    const [qualifier, typeName] = name.includes('.') ? name.split('.', 2) : [undefined, name];
+
+    const targets: Array<{ name: string; symbol?: SymbolEntry; spec: InterfaceSpec }> = [];
+    const stdlib = STDLIB_INTERFACES[name];
+    if (stdlib) {
+      targets.push({ name, spec: this.stdlibSpec(stdlib) });
+    } else {
+      const declared = symbols.filter(
+        s =>
+          s.kind === 'interface' &&
+          s.name === typeName &&
+          (!qualifier || path.posix.basename(path.posix.dirname(s.file)) === qualifier)
+      );
+      for (const symbol of declared) {
+        const spec = await this.interfaceSpec(symbol, symbols, new Set());
+        targets.push({ name: symbol.name, symbol, spec });
+      }
+    }
+
+    const reports: ImplementationReport[] = [];
+    for (const { name: targetName, symbol, spec } of targets) {
+      const report: ImplementationReport = {
+        interface: targetName,
+        methods: spec.methods.map(m => m.display),
+        typeSet: spec.typeSet,
+        implementations: [],
+      };
+      if (symbol) {
+        report.file = symbol.file;
+        report.line = symbol.line;
+      }
+      if (!spec.typeSet && spec.methods.length > 0) {
+        report.implementations = await this.implementations(spec, symbol, symbols);
+      }
+      reports.push(report);
+    }
+
+    return reports;
+  }
+
+  private async implementations(
+    spec: InterfaceSpec,
+    target: SymbolEntry | undefined,
+    symbols: SymbolEntry[]
+  ): Promise<Implementation[]> {
+    // Unexported methods can only be implemented inside the interface's package
+    const samePackageOnly = spec.methods.some(m => !/^[A-Z]/.test(m.name));
+    const targetPackage = target ? path.posix.dirname(target.file) : undefined;
+
+    const candidates = symbols.filter(
+      s =>
+        (s.kind === 'struct' || s.kind === 'type') &&
+        (!samePackageOnly || path.posix.dirname(s.file) === targetPackage)
+    );
+
+    const found: Implementation[] = [];
+    for (const type of candidates) {
+      const methodSet = await this.methodSet(type, symbols, new Set());
+      const matched = spec.methods.map(m => {
+        const entry = methodSet.get(m.name);
+        return entry?.shape === m.shape ? entry : undefined;
+      });
+      if (matched.some(m => m === undefined)) continue;
+
+      const entries = matched.filter((m): m is MethodSetEntry => m !== undefined);
+      found.push({
+        type: type.name,
+        file: type.file,
+        line: type.line,
+        pointer: entries.some(m => m.pointerOnly),
+        methods: entries.map(({ symbol, promotedFrom }) => {
+          const method: SatisfyingMethod = {
+            name: symbol.name,
+            file: symbol.file,
+            line: symbol.line,
+            signature: symbol.signature,
+          };
+          if (promotedFrom) method.promotedFrom = promotedFrom;
+          return method;
+        }),
+      });
+    }
+
+    return found.sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+  }
+
+  /**
+   * Methods declared on a type plus those promoted from types it embeds in
+   * the same package. pointerOnly marks methods only *T has.
+   */
+  private async methodSet(
+    type: SymbolEntry,
+    symbols: SymbolEntry[],
+    seen: Set<string>
+  ): Promise<Map<string, MethodSetEntry>> {
+    const methods = new Map<string, MethodSetEntry>();
+    const pkg = path.posix.dirname(type.file);
+    const key = `${pkg}/${type.name}`;
+    if (seen.has(key)) return methods;
+    seen.add(key);
+
+    for (const symbol of symbols) {
+      if (symbol.kind !== 'method' || symbol.parent !== type.name) continue;
+      if (path.posix.dirname(symbol.file) !== pkg) continue;
+      const spec = parseMethod(symbol.signature.replace(/^func\s*\([^)]*\)\s*/, ''));
+      if (!spec) continue;
+      methods.set(symbol.name, {
+        symbol,
+        shape: spec.shape,
+        pointerOnly: receiverKind(symbol.signature) === 'pointer',
+      });
+    }
+
+    if (type.kind !== 'struct') return methods;
+
+    for (const field of await this.embeddedFields(type)) {
+      const embedded = symbols.find(
+        s =>
+          s.name === field.name &&
+          s.kind !== 'method' &&
+          s.kind !== 'function' &&
+          path.posix.dirname(s.file) === pkg
+      );
+      if (!embedded) continue;
+
+      // Embedding *E promotes all of E's methods to T; embedding E only its value methods
+      const viaPointer = field.type.startsWith('*');
+      for (const [name, entry] of await this.methodSet(embedded, symbols, seen)) {
+        if (methods.has(name)) continue; // Shallower methods win
+        methods.set(name, {
+          ...entry,
+          pointerOnly: entry.pointerOnly && !viaPointer,
+          promotedFrom: entry.promotedFrom ?? embedded.name,
+        });
+      }
+    }
+
+    return methods;
+  }
+
+  private async embeddedFields(type: SymbolEntry): Promise<Array<{ name: string; type: string }>> {
+    const source = await this.readSource(type.file);
+    if (!source) return [];
+
+    const declaration = source.masked.slice(
+      source.lineStart(type.line),
+      source.lineStart(type.endLine + 1)
+    );
+    const brace = /\bstruct\s*\{/.exec(declaration);
+    if (!brace) return [];
+
+    const open = source.lineStart(type.line) + brace.index + brace[0].length - 1;
+    return parseGoStructFields(source, open).filter(f => f.embedded);
+  }
+
+  /**
+   * Method specs of a project interface, with embedded interfaces expanded
+   */
+  private async interfaceSpec(
+    symbol: SymbolEntry,
+    symbols: SymbolEntry[],
+    seen: Set<string>
+  ): Promise<InterfaceSpec> {
+    const spec: InterfaceSpec = { methods: [], typeSet: false };
+    const key = `${symbol.file}:${symbol.line}`;
+    if (seen.has(key)) return spec;
+    seen.add(key);
+
+    const source = await this.readSource(symbol.file);
+    if (!source) return spec;
+
+    const start = source.lineStart(symbol.line);
+    const declaration = source.masked.slice(start, source.lineStart(symbol.endLine + 1));
+    const brace = /\binterface\s*\{/.exec(declaration);
+    if (!brace) return spec;
+
+    const open = start + brace.index + brace[0].length - 1;
+    for (const element of interfaceElements(source, open)) {
+      const method = parseMethod(element);
+      if (method) {
+        spec.methods.push(method);
+        continue;
+      }
+
+      const embedded = /^([\w.]+)$/.exec(element)?.[1];
+      if (!embedded) {
+        spec.typeSet = true; // ~int | ~string and other constraint elements
+        continue;
+      }
+
+      const stdlib = STDLIB_INTERFACES[embedded];
+      const inner = stdlib
+        ? this.stdlibSpec(stdlib)
+        : await this.embeddedInterfaceSpec(embedded, symbol, symbols, seen);
+      spec.methods.push(...inner.methods);
+      spec.typeSet ||= inner.typeSet;
+    }
+
+    return spec;
+  }
+
+  private async embeddedInterfaceSpec(
+    name: string,
+    from: SymbolEntry,
+    symbols: SymbolEntry[],
+    seen: Set<string>
+  ): Promise<InterfaceSpec> {
+    const pkg = path.posix.dirname(from.file);
+    const candidates = symbols.filter(s => s.kind === 'interface' && s.name === name);
+    const embedded = candidates.find(s => path.posix.dirname(s.file) === pkg) ?? candidates[0];
+    return embedded ? this.interfaceSpec(embedded, symbols, seen) : { methods: [], typeSet: false };
+  }
+
+  private stdlibSpec(entries: string[]): InterfaceSpec {
+    const spec: InterfaceSpec = { methods: [], typeSet: false };
+    for (const entry of entries) {
+      const method = parseMethod(entry);
+      if (method) {
+        spec.methods.push(method);
+      } else {
+        spec.methods.push(...this.stdlibSpec(STDLIB_INTERFACES[entry] ?? []).methods);
+      }
+    }
+    return spec;
+  }
+
+  private async readSource(file: string): Promise<SourceText | undefined> {
+    try {
+      const content = await fs.readFile(
+        path.join(this.symbolIndexer.getWorkingDir(), file),
+        'utf-8'
+      );
+      return new SourceText(content, GO_SYNTAX);
+    } catch {
+      // Skip files that can't be read
+      return undefined;
+    }
+  }
+
+  formatReports(interfaceName: string, reports: ImplementationReport[]): string {
+    if (reports.length === 0) {
+      return `No Go interface named ${interfaceName} found (project or common standard library).`;
+    }
+
+    let output = '';
+    for (const report of reports) {
+      const where = report.file ? ` (${report.file}:${report.line})` : ' (standard library)';
+      output += `interface ${report.interface}${where}\n`;
+      for (const method of report.methods) {
+        output += `  ${method}\n`;
+      }
+
+      if (report.typeSet) {
+        output += '\nConstraint interface: only usable as a type parameter bound.\n\n';
+        continue;
+      }
+      if (report.methods.length === 0) {
+        output += '\nEmpty interface: every type satisfies it.\n\n';
+        continue;
+      }
+      if (report.implementations.length === 0) {
+        output += '\nNo implementing types found.\n\n';
+        continue;
+      }
+
+      output += `\nImplemented by ${report.implementations.length} type(s):\n`;
+      for (const impl of report.implementations) {
+        const name = impl.pointer ? `*${impl.type}` : impl.type;
+        output += `  ${name} (${impl.file}:${impl.line})\n`;
+        for (const method of impl.methods) {
+          const promoted = method.promotedFrom ? ` via ${method.promotedFrom}` : '';
+          output += `      ${method.signature} :${method.line}${promoted}\n`;
+        }
+      }
+      output += '\n';
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+/**
+ * Interface body elements, one per line or semicolon outside brackets
+ */
+function interfaceElements(source: SourceText, openOffset: number): string[] {
+  const close = source.findMatching(openOffset);
+  if (close === -1) return [];
+
+  const elements: string[] = [];
+  let depth = 0;
+  let segmentStart = openOffset + 1;
+  for (let i = openOffset + 1; i <= close; i++) {
+    const ch = source.masked[i] ?? '';
+    if ('({['.includes(ch)) depth++;
+    else if (')}]'.includes(ch) && i < close) depth--;
+
+    if (i === close || (depth === 0 && (ch === '\n' || ch === ';'))) {
+      const element = source.masked.slice(segmentStart, i).replace(/\s+/g, ' ').trim();
+      if (element) elements.push(element);
+      segmentStart = i + 1;
+    }
+  }
+  return elements;
+}
+
+/**
+ * "Write(p []byte) (n int, err error)" -> shape "([]byte)(int,error)"
+ */
+function parseMethod(text: string): MethodSpec | undefined {
+  const match = /^([A-Za-z_]\w*)\s*\(/.exec(text);
+  if (!match?.[1]) return undefined;
+
+  const paramsOpen = match[0].length - 1;
+  const paramsClose = matchingParen(text, paramsOpen);
+  if (paramsClose === -1) return undefined;
+
+  const params = parseGoParameters(text.slice(paramsOpen + 1, paramsClose)).map(shapeOf);
+  const results = parseGoResults(text.slice(paramsClose + 1)).map(shapeOf);
+
+  return {
+    name: match[1],
+    display: text.replace(/\s+/g, ' ').trim(),
+    shape: `(${params.join(',')})(${results.join(',')})`,
+  };
+}
+
+function shapeOf(parameter: Parameter): string {
+  return normalizeType(parameter.variadic ? `...${parameter.type}` : parameter.type);
+}
+
+/**
+ * Compare types without package qualifiers or spacing: "*http.Request" and
+ * "*Request" match, as do "interface{}" and "any"
+ */
+function normalizeType(type: string): string {
+  return type
+    .replace(/\binterface\s*\{\s*\}/g, 'any')
+    .replace(/\b[A-Za-z_]\w*\.(?=[A-Za-z_])/g, '')
+    .replace(/\s*([()[\]{},*])\s*/g, '$1')
+    .replace(/\s+/g, ' ')
+    .trim();
+}
+
+function matchingParen(text: string, open: number): number {
+  let depth = 0;
+  for (let i = open; i < text.length; i++) {
+    if (text[i] === '(') depth++;
+    else if (text[i] === ')' && --depth === 0) return i;
+  }
+  return -1;
+}
@@ -0,0 +1,659 @@
+/**
+ * Import Analyzer
+ * Groups each Go file's imports into standard library, third-party, and intra-module,
+ * builds the package dependency graph of a module, and finds imports a file
+ * doesn't use or is missing
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type {
+  FileImports,
+  ImportHygieneReport,
+  ImportIssue,
+  ImportReport,
+  PackageEdge,
+  PackageGraph,
+  PackageNode,
+} from '../types/index.js';
+import { walkFiles } from '../core/file-walker.js';
+import { resolveProjectPath } from '../core/file-reader.js';
+import { goImportSectionEnd, parseGoImports } from '../parsers/go-parser.js';
+import type { GoImport } from '../parsers/go-parser.js';
+import { GO_SYNTAX, SourceText } from '../parsers/source.js';
+
+// Node standing for every package outside the module when they are collapsed
+export const EXTERNAL_NODE = 'external';
+
+export interface PackageGraphOptions {
+  collapseExternal?: boolean | undefined;
+}
+
+interface GoModule {
+  path: string;
+  dir: string; // Directory holding go.mod, relative to the project root
+}
+
+// Standard library packages by name, for naming the import a file is missing.
+// Names two packages share (rand, template, scanner) are left out.
+export const STDLIB_PACKAGES: Record<string, string> = {
+  atomic: 'sync/atomic',
+  base64: 'encoding/base64',
+  binary: 'encoding/binary',
+  bufio: 'bufio',
+  bytes: 'bytes',
+  context: 'context',
+  csv: 'encoding/csv',
+  embed: 'embed',
+  errors: 'errors',
+  exec: 'os/exec',
+  filepath: 'path/filepath',
+  flag: 'flag',
+  fmt: 'fmt',
+  fs: 'io/fs',
+  hex: 'encoding/hex',
+  http: 'net/http',
+  httptest: 'net/http/httptest',
+  io: 'io',
+  ioutil: 'io/ioutil',
+  json: 'encoding/json',
+  log: 'log',
+  maps: 'maps',
+  math: 'math',
+  net: 'net',
+  os: 'os',
+  reflect: 'reflect',
+  regexp: 'regexp',
+  runtime: 'runtime',
+  sha256: 'crypto/sha256',
+  signal: 'os/signal',
+  slices: 'slices',
+  slog: 'log/slog',
+  sort: 'sort',
+  strconv: 'strconv',
+  strings: 'strings',
+  sync: 'sync',
+  testing: 'testing',
+  time: 'time',
+  unicode: 'unicode',
+  url: 'net/url',
+  utf8: 'unicode/utf8',
+  xml: 'encoding/xml',
+};
+
+// pkg.Member in code, not itself behind a dot (a.pkg.Member)
+const SELECTOR = /(?<!\.\s*|\w)([A-Za-z_]\w*)\s*\.\s*([A-Za-z_]\w*)/g;
+
+// An identifier that isn't a selector's qualifier: a declaration or a plain use
+const PLAIN_IDENTIFIER = /(?<!\.\s*|\w)([A-Za-z_]\w*)\b(?!\s*\.)/g;
+
+interface ParsedGoFile {
+  file: string;
+  source: SourceText;
+  code: string; // Masked source after the import section
+  imports: Array<GoImport & { names: string[] }>; // Possible package names of each
+}
+
+export class ImportAnalyzer {
+  private workingDir: string;
+  // Nearest go.mod per directory (null = none up to the project root)
+  private modules = new Map<string, GoModule | null>();
+  // Package clause per directory (null = no Go files), and the identifiers
+  // a directory's package uses other than as selector qualifiers
+  private packageClauses = new Map<string, string | null>();
+  private plainIdentifiers = new Map<string, Set<string>>();
+
+  constructor(workingDir?: string) {
+    this.workingDir = workingDir ?? process.cwd();
+  }
+
+  async analyze(target = '.'): Promise<ImportReport> {
+    this.modules.clear();
+    const relativeTarget = resolveProjectPath(this.workingDir, target);
+    const stat = await fs.stat(path.join(this.workingDir, relativeTarget));
+    const files = stat.isFile()
+      ? [relativeTarget]
+      : await walkFiles(this.workingDir, ['**/*.go'], { cwd: relativeTarget });
+
+    const results: FileImports[] = [];
+    for (const file of files) {
+      const result = await this.analyzeFile(file);
+      if (result) results.push(result);
+    }
+
+    return {
+      files: results,
+      external: countPackages(results.map(r => r.thirdParty)),
+      stdlib: countPackages(results.map(r => r.stdlib)),
+    };
+  }
+
+  private async analyzeFile(file: string): Promise<FileImports | null> {
+    let content: string;
+    try {
+      content = await fs.readFile(path.join(this.workingDir, file), 'utf-8');
+    } catch {
+      // Skip files that can't be read
+      return null;
+    }
+
+    const module = (await this.findModule(path.posix.dirname(file)))?.path;
+    const result: FileImports = { file, stdlib: [], thirdParty: [], internal: [] };
+    if (module) result.module = module;
+
+    for (const { path: importPath } of parseGoImports(new SourceText(content, GO_SYNTAX))) {
+      if (module && (importPath === module || importPath.startsWith(`${module}/`))) {
+        result.internal.push(importPath);
+      } else if (isStdlib(importPath)) {
+        result.stdlib.push(importPath);
+      } else {
+        result.thirdParty.push(importPath);
+      }
+    }
+
+    return result;
+  }
+
+  /**
+   * Imports each Go file under target declares but never uses, and packages
+   * it uses without importing them. A package counts as used when its name
+   * qualifies a selector (fmt.Println) in code, so a local variable shadowing
+   * a package hides an unused import. A missing import is only reported
+   * when the name is a known standard library package, or a package imported
+   * elsewhere under target, and nothing in the file's package declares it.
+   */
+  async unusedImports(target = '.'): Promise<ImportHygieneReport> {
+    this.modules.clear();
+    this.packageClauses.clear();
+    this.plainIdentifiers.clear();
+    const relativeTarget = resolveProjectPath(this.workingDir, target);
+    const stat = await fs.stat(path.join(this.workingDir, relativeTarget));
+    const files = stat.isFile()
+      ? [relativeTarget]
+      : await walkFiles(this.workingDir, ['**/*.go'], { cwd: relativeTarget });
+
+    const parsed: ParsedGoFile[] = [];
+    for (const file of files) {
+      const result = await this.parseFile(file);
+      if (result) parsed.push(result);
+    }
+
+    // Import paths by the name files use for them; null when two packages share a name
+    const known = new Map<string, string | null>(Object.entries(STDLIB_PACKAGES));
+    for (const { code, imports } of parsed) {
+      for (const { path: importPath, names } of imports) {
+        const name = names.find(n => hasSelector(code, n));
+        if (name === undefined) continue;
+        const current = known.get(name);
+        known.set(name, current === undefined || current === importPath ? importPath : null);
+      }
+    }
+
+    const report: ImportHygieneReport = {
+      files: parsed.length,
+      unused: 0,
+      missing: 0,
+      results: [],
+    };
+    for (const file of parsed) {
+      const issues = await this.importIssues(file, known);
+      if (issues.length === 0) continue;
+      report.unused += issues.filter(i => i.status === 'unused').length;
+      report.missing += issues.filter(i => i.status === 'missing').length;
+      report.results.push({ file: file.file, issues });
+    }
+
+    return report;
+  }
+
+  private async parseFile(file: string): Promise<ParsedGoFile | null> {
+    let content: string;
+    try {
+      content = await fs.readFile(path.join(this.workingDir, file), 'utf-8');
+    } catch {
+      // Skip files that can't be read
+      return null;
+    }
+
+    const source = new SourceText(content, GO_SYNTAX);
+    const imports = [];
+    for (const spec of parseGoImports(source)) {
+      const names = spec.alias ? [spec.alias] : await this.packageNames(file, spec.path);
+      imports.push({ ...spec, names });
+    }
+
+    return { file, source, code: source.masked.slice(goImportSectionEnd(source)), imports };
+  }
+
+  private async importIssues(
+    { file, source, code, imports }: ParsedGoFile,
+    known: Map<string, string | null>
+  ): Promise<ImportIssue[]> {
+    const issues: ImportIssue[] = [];
+    const imported = new Set<string>();
+
+    for (const { path: importPath, alias, names, line } of imports) {
+      if (alias === '_' || alias === '.') {
+        const status = alias === '_' ? 'blank' : 'dot';
+        issues.push({ line, path: importPath, name: alias, status });
+        continue;
+      }
+      names.forEach(name => imported.add(name));
+      // import "C" is cgo's pseudo-package, used by the preamble comment
+      if (importPath === 'C' || names.some(name => hasSelector(code, name))) continue;
+      issues.push({ line, path: importPath, name: names[0] ?? importPath, status: 'unused' });
+    }
+
+    const codeStart = source.masked.length - code.length;
+    const declared = await this.packageIdentifiers(path.posix.dirname(file));
+    const reported = new Set<string>();
+    SELECTOR.lastIndex = 0;
+    let selector;
+    while ((selector = SELECTOR.exec(code)) !== null) {
+      const [, name = '', member = ''] = selector;
+      const importPath = known.get(name);
+      if (!importPath || imported.has(name) || declared.has(name) || reported.has(name)) continue;
+      reported.add(name);
+      issues.push({
+        line: source.lineOf(codeStart + selector.index),
+        path: importPath,
+        name,
+        status: 'missing',
+        usage: `${name}.${member}`,
+      });
+    }
+
+    return issues.sort((a, b) => a.line - b.line);
+  }
+
+  /**
+   * Names an import may go by without an alias: the package clause of a
+   * package in the module or its vendor directory, or else guesses from the
+   * last path element (yaml.v3 -> yaml, go-isatty -> isatty)
+   */
+  private async packageNames(file: string, importPath: string): Promise<string[]> {
+    const module = await this.findModule(path.posix.dirname(file));
+    if (module) {
+      const dir =
+        importPath === module.path || importPath.startsWith(`${module.path}/`)
+          ? path.posix.join(module.dir, importPath.slice(module.path.length))
+          : path.posix.join(module.dir, 'vendor', importPath);
+      const name = await this.packageClause(dir);
+      if (name) return [name];
+    }
+    return guessPackageNames(importPath);
+  }
+
+  /**
+   * Package name declared by the non-test Go files of a directory
+   */
+  private async packageClause(dir: string): Promise<string | null> {
+    const cached = this.packageClauses.get(dir);
+    if (cached !== undefined) return cached;
+
+    let name: string | null = null;
+    const entries = await fs.readdir(path.join(this.workingDir, dir)).catch(() => []);
+    for (const entry of entries.filter(e => e.endsWith('.go') && !e.endsWith('_test.go'))) {
+      const content = await fs
+        .readFile(path.join(this.workingDir, dir, entry), 'utf-8')
+        .catch(() => '');
+      const clause = /^package\s+(\w+)/m.exec(new SourceText(content, GO_SYNTAX).masked)?.[1];
+      if (clause) {
+        name = clause;
+        break;
+      }
+    }
+
+    this.packageClauses.set(dir, name);
+    return name;
+  }
+
+  /**
+   * Identifiers the Go files of a directory use other than as a selector's
+   * qualifier; any of them may be a variable that shadows a package name
+   */
+  private async packageIdentifiers(dir: string): Promise<Set<string>> {
+    const cached = this.plainIdentifiers.get(dir);
+    if (cached) return cached;
+
+    const identifiers = new Set<string>();
+    const entries = await fs.readdir(path.join(this.workingDir, dir)).catch(() => []);
+    for (const entry of entries.filter(e => e.endsWith('.go'))) {
+      const content = await fs
+        .readFile(path.join(this.workingDir, dir, entry), 'utf-8')
+        .catch(() => '');
+      const source = new SourceText(content, GO_SYNTAX);
+      const code = source.masked.slice(goImportSectionEnd(source));
+      for (const [, identifier = ''] of code.matchAll(PLAIN_IDENTIFIER)) {
+        identifiers.add(identifier);
+      }
+    }
+
+    this.plainIdentifiers.set(dir, identifiers);
+    return identifiers;
+  }
+
+  /**
+   * Package dependency graph of the Go files under target: package A -> B
+   * when a file of A imports B. Test files are left out, since external test
+   * packages may import their package back. Cycles are found among the
+   * module's own packages.
+   */
+  async packageGraph(target = '.', options: PackageGraphOptions = {}): Promise<PackageGraph> {
+    const report = await this.analyze(target);
+    const nodes = new Map<string, PackageNode>();
+    const edges = new Map<string, PackageEdge>();
+    const addNode = (id: string, kind: PackageNode['kind']): void => {
+      if (!nodes.has(id)) nodes.set(id, { id, kind });
+    };
+
+    for (const file of report.files.filter(f => !f.file.endsWith('_test.go'))) {
+      const dir = path.posix.dirname(file.file);
+      const from = await this.packagePath(dir);
+      const node = nodes.get(from) ?? { id: from, kind: 'internal' };
+      node.dir = dir;
+      node.files = (node.files ?? 0) + 1;
+      nodes.set(from, node);
+
+      const imports = [
+        ...file.internal.map(p => ({ path: p, kind: 'internal' as const })),
+        ...file.stdlib.map(p => ({ path: p, kind: 'stdlib' as const })),
+        ...file.thirdParty.map(p => ({ path: p, kind: 'external' as const })),
+      ];
+      const targets = new Set<string>();
+      for (const { path: importPath, kind } of imports) {
+        const collapse = options.collapseExternal && kind !== 'internal';
+        const to = collapse ? EXTERNAL_NODE : importPath;
+        addNode(to, collapse ? 'external' : kind);
+        targets.add(to);
+      }
+      for (const to of targets) {
+        const key = `${from}\0${to}`;
+        const edge = edges.get(key) ?? { from, to, files: 0 };
+        edge.files++;
+        edges.set(key, edge);
+      }
+    }
+
+    const sortedEdges = [...edges.values()].sort(
+      (a, b) => a.from.localeCompare(b.from) || a.to.localeCompare(b.to)
+    );
+    return {
+      nodes: [...nodes.values()].sort(
+        (a, b) => kindOrder(a.kind) - kindOrder(b.kind) || a.id.localeCompare(b.id)
+      ),
+      edges: sortedEdges,
+      cycles: findCycles(sortedEdges.filter(e => nodes.get(e.to)?.kind === 'internal')),
+    };
+  }
+
+  /**
+   * Import path of the package in a directory: the module path plus the
+   * directory below go.mod, or the directory itself outside a module
+   */
+  private async packagePath(dir: string): Promise<string> {
+    const module = await this.findModule(dir);
+    if (!module) return dir;
+    const relative = path.posix.relative(module.dir, dir);
+    return relative ? `${module.path}/${relative}` : module.path;
+  }
+
+  /**
+   * The nearest go.mod at or above a directory
+   */
+  private async findModule(dir: string): Promise<GoModule | null> {
+    const cached = this.modules.get(dir);
+    if (cached !== undefined) return cached;
+
+    let module: GoModule | null = null;
+    try {
+      const goMod = await fs.readFile(path.join(this.workingDir, dir, 'go.mod'), 'utf-8');
+      const modulePath = /^module\s+(\S+)/m.exec(goMod)?.[1]?.replace(/^"|"$/g, '');
+      if (modulePath) module = { path: modulePath, dir };
+    } catch {
+      if (dir !== '.' && dir !== '') {
+        module = await this.findModule(path.posix.dirname(dir));
+      }
+    }
+
+    this.modules.set(dir, module);
+    return module;
+  }
+
+  formatReport(report: ImportReport): string {
+    if (report.files.length === 0) {
+      return 'No Go files found.';
+    }
+
+    let output = '';
+    for (const file of report.files) {
+      output += `${file.file}${file.module ? ` (module ${file.module})` : ''}:\n`;
+      for (const [label, packages] of [
+        ['stdlib', file.stdlib],
+        ['third-party', file.thirdParty],
+        ['internal', file.internal],
+      ] as const) {
+        if (packages.length > 0) {
+          output += `  ${label.padEnd(12)} ${packages.join(', ')}\n`;
+        }
+      }
+      output += '\n';
+    }
+
+    if (report.files.length > 1) {
+      output += `External dependencies (${report.external.length}):\n`;
+      for (const { package: pkg, files } of report.external) {
+        output += `  ${pkg} (${files} file(s))\n`;
+      }
+      if (report.external.length === 0) {
+        output += '  None\n';
+      }
+    }
+
+    return output.trimEnd();
+  }
+
+  formatUnusedImports(report: ImportHygieneReport): string {
+    if (report.files === 0) {
+      return 'No Go files found.';
+    }
+    if (report.results.length === 0) {
+      return `No import issues in ${report.files} Go file(s).`;
+    }
+
+    let output = `Import issues in ${report.files} Go file(s): `;
+    output += `${report.unused} unused, ${report.missing} missing\n\n`;
+    for (const { file, issues } of report.results) {
+      output += `${file}:\n`;
+      for (const issue of issues) {
+        const quoted = JSON.stringify(issue.path);
+        let detail: string;
+        if (issue.status === 'unused') {
+          const alias = issue.name === path.posix.basename(issue.path) ? '' : `${issue.name} `;
+          detail = `${alias}${quoted} unused`;
+        } else if (issue.status === 'missing') {
+          detail = `${issue.usage ?? issue.name} used without importing ${quoted}`;
+        } else if (issue.status === 'blank') {
+          detail = `_ ${quoted} blank import (side effects only)`;
+        } else {
+          detail = `. ${quoted} dot import (names used unqualified)`;
+        }
+        output += `  ${String(issue.line).padStart(4)}: ${detail}\n`;
+      }
+      output += '\n';
+    }
+
+    return output.trimEnd();
+  }
+
+  formatGraph(graph: PackageGraph): string {
+    const internal = graph.nodes.filter(n => n.kind === 'internal');
+    if (internal.length === 0) {
+      return 'No Go packages found.';
+    }
+
+    let output = `Package graph: ${internal.length} package(s), `;
+    output += `${graph.nodes.length - internal.length} external, ${graph.edges.length} edge(s)\n\n`;
+    for (const node of internal.filter(n => n.dir !== undefined)) {
+      output += `${node.id} (${node.dir}, ${node.files ?? 0} file(s))\n`;
+      for (const edge of graph.edges.filter(e => e.from === node.id)) {
+        const kind = graph.nodes.find(n => n.id === edge.to)?.kind;
+        const label = kind === 'internal' ? '' : ` (${kind})`;
+        output += `  -> ${edge.to}${label}  ${edge.files} file(s)\n`;
+      }
+    }
+
+    if (graph.cycles.length > 0) {
+      output += `\nImport cycles (${graph.cycles.length}):\n`;
+      for (const cycle of graph.cycles) {
+        output += `  ${cycle.join(' -> ')}\n`;
+      }
+    } else {
+      output += '\nNo import cycles.\n';
+    }
+
+    return output.trimEnd();
+  }
+
+  formatDot(graph: PackageGraph): string {
+    const inCycle = new Set(graph.cycles.flat());
+    let output = 'digraph packages {\n';
+    output += '  rankdir=LR;\n';
+    output += '  node [shape=box];\n';
+
+    for (const node of graph.nodes) {
+      const style =
+        node.kind !== 'internal' ? ' [style=dashed]' : inCycle.has(node.id) ? ' [color=red]' : '';
+      output += `  ${JSON.stringify(node.id)}${style};\n`;
+    }
+    for (const edge of graph.edges) {
+      output += `  ${JSON.stringify(edge.from)} -> ${JSON.stringify(edge.to)};\n`;
+    }
+
+    return output + '}';
+  }
+
+  setWorkingDir(dir: string): void {
+    this.workingDir = dir;
+    this.modules.clear();
+  }
+}
+
+function kindOrder(kind: PackageNode['kind']): number {
+  return ['internal', 'external', 'stdlib'].indexOf(kind);
+}
+
+/**
+ * One cycle per strongly connected component of more than one package
+ * (Tarjan), starting from its first package by name
+ */
+function findCycles(edges: PackageEdge[]): string[][] {
+  const successors = new Map<string, string[]>();
+  for (const edge of edges) {
+    successors.set(edge.from, [...(successors.get(edge.from) ?? []), edge.to]);
+  }
+
+  const index = new Map<string, number>();
+  const lowLink = new Map<string, number>();
+  const stack: string[] = [];
+  const onStack = new Set<string>();
+  const components: string[][] = [];
+  const connect = (node: string): void => {
+    index.set(node, index.size);
+    lowLink.set(node, index.get(node) ?? 0);
+    stack.push(node);
+    onStack.add(node);
+    for (const next of successors.get(node) ?? []) {
+      if (!index.has(next)) {
+        connect(next);
+        lowLink.set(node, Math.min(lowLink.get(node) ?? 0, lowLink.get(next) ?? 0));
+      } else if (onStack.has(next)) {
+        lowLink.set(node, Math.min(lowLink.get(node) ?? 0, index.get(next) ?? 0));
+      }
+    }
+    if (lowLink.get(node) !== index.get(node)) return;
+    const component: string[] = [];
+    let member;
+    do {
+      member = stack.pop() ?? node;
+      onStack.delete(member);
+      component.push(member);
+    } while (member !== node);
+    if (component.length > 1) components.push(component);
+  };
+  for (const node of [...successors.keys()].sort()) {
+    if (!index.has(node)) connect(node);
+  }
+
+  return components
+    .map(component => cycleThrough(new Set(component), successors))
+    .sort((a, b) => (a[0] ?? '').localeCompare(b[0] ?? ''));
+}
+
+/**
+ * Shortest path from the first package of a component back to itself
+ */
+function cycleThrough(component: Set<string>, successors: Map<string, string[]>): string[] {
+  const start = [...component].sort()[0] ?? '';
+  const previous = new Map<string, string>();
+  const queue = [start];
+  while (queue.length > 0) {
+    const node = queue.shift() ?? start;
+    for (const next of successors.get(node) ?? []) {
+      if (!component.has(next)) continue;
+      if (next === start) {
+        const path = [start];
+        for (let at: string | undefined = node; at !== undefined && at !== start; ) {
+          path.unshift(at);
+          at = previous.get(at);
+        }
+        return [start, ...path];
+      }
+      if (!previous.has(next)) {
+        previous.set(next, node);
+        queue.push(next);
+      }
+    }
+  }
+  return [start];
+}
+
+/**
+ * Standard library import paths have no dot in their first element
+ * ("net/http"), unlike hosted modules ("github.com/...")
+ */
+export function isStdlib(importPath: string): boolean {
+  const first = importPath.split('/')[0] ?? '';
+  return !first.includes('.') && first !== '';
+}
+
+/**
+ * Package names an import path likely declares; Go packages usually drop a
+ * "go-" prefix or "-go" suffix and a gopkg.in ".vN" version
+ */
+export function guessPackageNames(importPath: string): string[] {
+  const segments = importPath.split('/').filter(s => !/^v\d+$/.test(s));
+  const last = (segments[segments.length - 1] ?? importPath).replace(/\.v\d+$/, '');
+  const trimmed = last.replace(/^go-|[-.]go$/g, '');
+  const parts = trimmed.split(/[-.]/);
+  const candidates = [last, trimmed, parts.join(''), parts[parts.length - 1] ?? ''];
+  const names = candidates.filter(name => /^[A-Za-z_]\w*$/.test(name));
+  return [...new Set(names.length > 0 ? names : [last])];
+}
+
+function hasSelector(code: string, name: string): boolean {
+  return new RegExp(`(?<!\\.\\s*|\\w)${name}\\s*\\.\\s*[A-Za-z_]`).test(code);
+}
+
+function countPackages(lists: string[][]): Array<{ package: string; files: number }> {
+  const counts = new Map<string, number>();
+  for (const list of lists) {
+    for (const pkg of new Set(list)) {
+      counts.set(pkg, (counts.get(pkg) ?? 0) + 1);
+    }
+  }
+
+  return [...counts.entries()]
+    .map(([pkg, files]) => ({ package: pkg, files }))
+    .sort((a, b) => b.files - a.files || a.package.localeCompare(b.package));
+}
@@ -0,0 +1,109 @@
+/**
+ * Index Watcher
+ * Watches the project directory and refreshes the symbol index after changes settle
+ */
+
+import { watch } from 'fs';
+import type { FSWatcher } from 'fs';
+import { DEFAULT_IGNORE } from '../core/file-walker.js';
+import { logger } from '../core/logger.js';
+import { SymbolIndexer } from './symbol-indexer.js';
+import type { FileChange, RefreshResult } from './symbol-indexer.js';
+
+export const DEFAULT_DEBOUNCE_MS = 300;
+
+export type IndexChangeListener = (changes: FileChange[], result: RefreshResult) => Promise<void>;
+
+// Directories whose churn (git objects, installs, our own index writes) never
+// reaches the index, so events under them don't trigger a refresh
+const QUIET_DIRS = new Set([
+  '.git',
+  ...DEFAULT_IGNORE.map(pattern => pattern.replace(/^\*\*\/|\/\*\*$/g, '')),
+]);
+
+export class IndexWatcher {
+  private symbolIndexer: SymbolIndexer;
+  private onChange: IndexChangeListener;
+  private debounceMs: number;
+  private watcher?: FSWatcher | undefined;
+  private timer?: ReturnType<typeof setTimeout> | undefined;
+  private refreshing = false;
+  private pending = false; // Events arrived during a refresh
+
+  constructor(
+    symbolIndexer: SymbolIndexer,
+    onChange: IndexChangeListener,
+    debounceMs = DEFAULT_DEBOUNCE_MS
+  ) {
+    this.symbolIndexer = symbolIndexer;
+    this.onChange = onChange;
+    this.debounceMs = debounceMs;
+  }
+
+  /**
+   * Index the project as a baseline, then watch it. Changes found by later
+   * refreshes are passed to the listener; the baseline itself is not.
+   */
+  async start(): Promise<void> {
+    if (this.watcher) return;
+
+    await this.symbolIndexer.refresh();
+    // Recursive watching on Linux needs Node 20, the minimum in package.json
+    this.watcher = watch(
+      this.symbolIndexer.getWorkingDir(),
+      { recursive: true, persistent: false },
+      (_event, filename) => {
+        if (filename && isQuiet(filename.toString())) return;
+        this.schedule();
+      }
+    );
+    this.watcher.on('error', (error: Error) => {
+      logger.error('File watcher stopped', { error });
+      this.stop();
+    });
+  }
+
+  stop(): void {
+    clearTimeout(this.timer);
+    this.watcher?.close();
+    this.watcher = undefined;
+  }
+
+  /**
+   * Restart the debounce timer, so a burst of saves causes one refresh
+   */
+  private schedule(): void {
+    clearTimeout(this.timer);
+    this.timer = setTimeout(() => void this.flush(), this.debounceMs);
+  }
+
+  private async flush(): Promise<void> {
+    if (this.refreshing) {
+      this.pending = true;
+      return;
+    }
+
+    this.refreshing = true;
+    try {
+      // A full refresh applies .gitignore and the default ignores, and turns a
+      // rename into a removal plus an addition
+      const result = await this.symbolIndexer.refresh();
+      if (result.changes.length > 0) {
+        await this.onChange(result.changes, result);
+      }
+    } catch (error) {
+      logger.error('Index refresh after file change failed', { error });
+    } finally {
+      this.refreshing = false;
+    }
+
+    if (this.pending) {
+      this.pending = false;
+      this.schedule();
+    }
+  }
+}
+
+function isQuiet(filename: string): boolean {
+  return filename.split(/[\\/]/).some(segment => QUIET_DIRS.has(segment));
+}
@@ -0,0 +1,158 @@
+/**
+ * Line Counter
+ * Counts code, comment, and blank lines per file and per language
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { SourceText } from '../parsers/source.js';
+import { getParserForFile } from '../parsers/index.js';
+import type { WalkOptions } from '../core/file-walker.js';
+import { SymbolIndexer } from './symbol-indexer.js';
+
+export interface LineCounts {
+  lines: number;
+  code: number; // Any code, even with a trailing comment
+  comments: number; // Only comment text
+  blank: number;
+}
+
+export interface FileLineCounts extends LineCounts {
+  file: string;
+  language: string;
+}
+
+export interface LanguageLineCounts extends LineCounts {
+  language: string;
+  files: number;
+}
+
+export interface LineReport {
+  total: LineCounts & { files: number };
+  languages: LanguageLineCounts[]; // Most code first
+  files: FileLineCounts[];
+}
+
+export class LineCounter {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  async count(target = '.', options: Omit<WalkOptions, 'cwd'> = {}): Promise<LineReport> {
+    const files = await this.symbolIndexer.listFiles(target, options);
+    const report: LineReport = {
+      total: { files: 0, ...emptyCounts() },
+      languages: [],
+      files: [],
+    };
+    const languages = new Map<string, LanguageLineCounts>();
+
+    for (const file of files) {
+      let content: string;
+      try {
+        content = await fs.readFile(path.join(this.symbolIndexer.getWorkingDir(), file), 'utf-8');
+      } catch {
+        // Skip files that can't be read
+        continue;
+      }
+
+      const parser = getParserForFile(file, content);
+      if (!parser) continue;
+
+      const counts = countLines(new SourceText(content, parser.syntax));
+      report.files.push({ file, language: parser.language, ...counts });
+
+      let language = languages.get(parser.language);
+      if (!language) {
+        language = { language: parser.language, files: 0, ...emptyCounts() };
+        languages.set(parser.language, language);
+      }
+      language.files++;
+      report.total.files++;
+      addCounts(language, counts);
+      addCounts(report.total, counts);
+    }
+
+    report.languages = [...languages.values()].sort(
+      (a, b) => b.code - a.code || a.language.localeCompare(b.language)
+    );
+    return report;
+  }
+
+  formatReport(report: LineReport): string {
+    if (report.total.files === 0) {
+      return 'No source files found.';
+    }
+
+    const cells = (values: Array<string | number>): string =>
+      values.map(v => String(v).padStart(8)).join('  ');
+    const header = cells(['Lines', 'Code', 'Comments', 'Blank']);
+    const counts = (c: LineCounts): string => cells([c.lines, c.code, c.comments, c.blank]);
+
+    const width = Math.max(8, ...report.languages.map(l => l.language.length));
+    let output = `Line counts for ${report.total.files} file(s):\n\n`;
+    output += `  ${'Language'.padEnd(width)}  ${'Files'.padStart(5)}  ${header}\n`;
+    for (const language of report.languages) {
+      output += `  ${language.language.padEnd(width)}  ${String(language.files).padStart(5)}`;
+      output += `  ${counts(language)}\n`;
+    }
+    output += `  ${'Total'.padEnd(width)}  ${String(report.total.files).padStart(5)}`;
+    output += `  ${counts(report.total)}\n`;
+
+    const fileWidth = Math.max(...report.files.map(f => f.file.length));
+    output += `\n  ${'File'.padEnd(fileWidth)}  ${header}\n`;
+    for (const file of report.files) {
+      output += `  ${file.file.padEnd(fileWidth)}  ${counts(file)}\n`;
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+/**
+ * Classify each line. Comments come from the lexer, so a "//" or "/*" inside
+ * a string literal stays code.
+ */
+function countLines(source: SourceText): LineCounts {
+  // Content with only comments blanked: whatever remains on a line is code
+  const chars = source.content.split('');
+  for (const comment of source.comments) {
+    for (let i = comment.start; i < comment.end; i++) {
+      if (chars[i] !== '\n') chars[i] = ' ';
+    }
+  }
+
+  const code = chars.join('').split('\n');
+  const original = source.content.split('\n');
+  // A trailing newline ends the last line rather than starting an empty one
+  if (source.content.endsWith('\n')) {
+    code.pop();
+    original.pop();
+  }
+
+  const counts = emptyCounts();
+  original.forEach((text, i) => {
+    counts.lines++;
+    if ((code[i] ?? '').trim() !== '') counts.code++;
+    else if (text.trim() !== '') counts.comments++;
+    else counts.blank++;
+  });
+  return counts;
+}
+
+function emptyCounts(): LineCounts {
+  return { lines: 0, code: 0, comments: 0, blank: 0 };
+}
+
+function addCounts(into: LineCounts, counts: LineCounts): void {
+  into.lines += counts.lines;
+  into.code += counts.code;
+  into.comments += counts.comments;
+  into.blank += counts.blank;
+}
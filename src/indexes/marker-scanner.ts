@@ -0,0 +1,273 @@
+/**
+ * Marker Scanner
+ * Collects TODO/FIXME-style markers from comments in every supported language,
+ * grouped by keyword or by owner
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { CommentRange } from '../parsers/source.js';
+import { SourceText } from '../parsers/source.js';
+import { getParserForFile } from '../parsers/index.js';
+import type { WalkOptions } from '../core/file-walker.js';
+import { getBlame } from '../core/git.js';
+import { fileStateFingerprint } from '../core/pagination.js';
+import { SymbolIndexer } from './symbol-indexer.js';
+
+export const DEFAULT_MARKERS = ['TODO', 'FIXME', 'HACK', 'XXX'];
+
+export interface Marker {
+  keyword: string;
+  file: string;
+  line: number;
+  author?: string | undefined; // From TODO(alice)
+  owner?: string | undefined; // The author tag without @, or the line's git blame author
+  ownerSource?: 'tag' | 'blame' | undefined;
+  text: string; // The comment from the keyword on, with continuation lines
+}
+
+export interface OwnerGroup {
+  owner?: string | undefined; // Unset for the unassigned bucket
+  count: number;
+  keywords: Record<string, number>; // Count per keyword: { TODO: 3, FIXME: 1 }
+  markers: Marker[];
+}
+
+export interface MarkerGroup {
+  keyword: string;
+  count: number;
+  markers: Marker[];
+}
+
+export interface MarkerReport {
+  total: number;
+  groups: MarkerGroup[]; // In keyword order; keywords without markers are omitted
+  owners?: OwnerGroup[] | undefined; // Most markers first, unassigned last; when grouped by owner
+}
+
+export interface MarkerScanOptions extends Omit<WalkOptions, 'cwd'> {
+  blame?: boolean | undefined; // Attribute markers without an owner tag to the line's blame author
+}
+
+interface CommentLine {
+  line: number;
+  text: string; // Without comment delimiters
+  comment: CommentRange;
+  ownLine: boolean; // Nothing but the comment on this line
+}
+
+export class MarkerScanner {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  /**
+   * Fingerprint of the files a scan of target would read, so a page cursor
+   * goes stale when one of them is edited, added, or removed
+   */
+  async fileState(target = '.', options: Omit<WalkOptions, 'cwd'> = {}): Promise<string> {
+    const files = await this.symbolIndexer.listFiles(target, options);
+    return fileStateFingerprint(this.symbolIndexer.getWorkingDir(), files);
+  }
+
+  async scan(
+    target = '.',
+    keywords: string[] = DEFAULT_MARKERS,
+    options: MarkerScanOptions = {}
+  ): Promise<MarkerReport> {
+    const pattern = markerPattern(keywords);
+    const files = await this.symbolIndexer.listFiles(target, options);
+    const markers: Marker[] = [];
+
+    for (const file of files) {
+      let content: string;
+      try {
+        content = await fs.readFile(path.join(this.symbolIndexer.getWorkingDir(), file), 'utf-8');
+      } catch {
+        // Skip files that can't be read
+        continue;
+      }
+
+      const parser = getParserForFile(file, content);
+      if (!parser) continue;
+
+      // Only comments: a log message containing "FIXME" is a string, not a marker
+      const lines = commentLines(new SourceText(content, parser.syntax));
+      lines.forEach((entry, i) => {
+        const match = pattern.exec(entry.text);
+        if (!match) return;
+
+        const text = [entry.text.slice(match.index).trim()];
+        for (let j = i + 1; j < lines.length; j++) {
+          const next = lines[j];
+          if (!next || !continues(lines[j - 1], next) || pattern.test(next.text)) break;
+          text.push(next.text.trim());
+        }
+
+        const marker: Marker = {
+          keyword: match[1] ?? '',
+          file,
+          line: entry.line,
+          text: text.join(' '),
+        };
+        const author = match[2]?.trim();
+        if (author) marker.author = author;
+        const owner = author && ownerOf(author);
+        if (owner) {
+          marker.owner = owner;
+          marker.ownerSource = 'tag';
+        }
+        markers.push(marker);
+      });
+    }
+
+    if (options.blame) await this.attributeToBlame(markers);
+
+    const groups = keywords
+      .map(keyword => {
+        const group = markers.filter(m => m.keyword === keyword);
+        return { keyword, count: group.length, markers: group };
+      })
+      .filter(g => g.count > 0);
+
+    return { total: markers.length, groups };
+  }
+
+  /**
+   * Markers bucketed by owner: most markers first, ties by name, with the
+   * unassigned bucket last
+   */
+  groupByOwner(markers: Marker[]): OwnerGroup[] {
+    const byOwner = new Map<string | undefined, Marker[]>();
+    for (const marker of markers) {
+      byOwner.set(marker.owner, [...(byOwner.get(marker.owner) ?? []), marker]);
+    }
+
+    return [...byOwner]
+      .map(([owner, owned]) => {
+        const keywords: Record<string, number> = {};
+        for (const marker of owned) keywords[marker.keyword] = (keywords[marker.keyword] ?? 0) + 1;
+        return { ...(owner ? { owner } : {}), count: owned.length, keywords, markers: owned };
+      })
+      .sort(
+        (a, b) =>
+          Number(!a.owner) - Number(!b.owner) ||
+          b.count - a.count ||
+          (a.owner ?? '').localeCompare(b.owner ?? '')
+      );
+  }
+
+  /**
+   * Owner of each untagged marker from git blame; uncommitted lines and files
+   * git can't blame stay unassigned
+   */
+  private async attributeToBlame(markers: Marker[]): Promise<void> {
+    const untagged = markers.filter(m => !m.owner);
+    for (const file of new Set(untagged.map(m => m.file))) {
+      const blame = await getBlame(this.symbolIndexer.getWorkingDir(), file);
+      if (!blame) continue;
+      for (const marker of untagged.filter(m => m.file === file)) {
+        const author = blame.get(marker.line)?.author;
+        if (author) {
+          marker.owner = author;
+          marker.ownerSource = 'blame';
+        }
+      }
+    }
+  }
+
+  formatReport(report: MarkerReport): string {
+    if (report.total === 0) {
+      return 'No markers found.';
+    }
+    if (report.owners) {
+      return this.formatOwners(report.total, report.owners);
+    }
+
+    let output = `${report.total} marker(s):\n`;
+    for (const group of report.groups) {
+      output += `\n${group.keyword} (${group.count}):\n`;
+      for (const marker of group.markers) {
+        const author = marker.author ? ` [${marker.author}]` : '';
+        output += `  ${marker.file}:${marker.line}${author}  ${marker.text}\n`;
+      }
+    }
+
+    return output.trimEnd();
+  }
+
+  private formatOwners(total: number, owners: OwnerGroup[]): string {
+    let output = `${total} marker(s) by owner:\n`;
+    for (const group of owners) {
+      const keywords = Object.entries(group.keywords)
+        .map(([keyword, count]) => `${keyword} ${count}`)
+        .join(', ');
+      output += `\n${group.owner ?? '(unassigned)'} (${group.count}: ${keywords}):\n`;
+      for (const marker of group.markers) {
+        const via = marker.ownerSource === 'blame' ? ' (blame)' : '';
+        output += `  ${marker.file}:${marker.line}${via}  ${marker.text}\n`;
+      }
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+/**
+ * Keyword as a whole word, optionally followed by an author tag: TODO(alice)
+ */
+function markerPattern(keywords: string[]): RegExp {
+  const alternatives = keywords.map(k => k.replace(/[.*+?^${}()|[\]\\]/g, '\\$&')).join('|');
+  return new RegExp(`(?<![\\w$])(${alternatives})(?![\\w$])(?:\\(([^)\\n]*)\\))?`);
+}
+
+/**
+ * Owner named by an author tag: the first name in TODO(@alice, bob), without
+ * the @. Issue references like TODO(#123) or TODO(b/123) name no owner.
+ */
+function ownerOf(tag: string): string | undefined {
+  const first = tag.split(',')[0]?.trim().replace(/^@/, '') ?? '';
+  if (first === '' || /^#?\d+$/.test(first) || first.includes('/')) return undefined;
+  return first;
+}
+
+/**
+ * Every line of every comment, delimiters stripped
+ */
+function commentLines(source: SourceText): CommentLine[] {
+  const lines: CommentLine[] = [];
+
+  for (const comment of source.comments) {
+    const before = source.content.slice(source.lineStart(comment.line), comment.start);
+    comment.text.split('\n').forEach((raw, i) => {
+      const text = comment.block
+        ? raw.replace(/^\s*(?:\/\*+!?|\*+(?!\/))?/, '').replace(/\*+\/\s*$/, '')
+        : raw.replace(/^(?:\/\/[/!]?|#+|--)/, '');
+      lines.push({
+        line: comment.line + i,
+        text,
+        comment,
+        ownLine: i > 0 || before.trim() === '',
+      });
+    });
+  }
+
+  return lines;
+}
+
+/**
+ * Whether a comment line carries on the marker text above it: the next line
+ * of the same block comment, or a line comment alone on the following line
+ */
+function continues(previous: CommentLine | undefined, next: CommentLine): boolean {
+  if (!previous || next.line !== previous.line + 1 || next.text.trim() === '') {
+    return false;
+  }
+  return next.comment === previous.comment || (!next.comment.block && next.ownLine);
+}
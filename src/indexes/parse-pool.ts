@@ -0,0 +1,249 @@
+/**
+ * Parse Pool
+ * Spreads file parsing over worker threads so a cold index build uses every
+ * core; a parser error, crashed worker, or parse past the deadline fails only
+ * the file it was parsing
+ */
+
+import * as os from 'os';
+import { Worker } from 'worker_threads';
+import type { SymbolEntry } from '../types/index.js';
+import { TimeoutError, currentDeadline, withDeadline } from '../core/deadline.js';
+import type { Deadline } from '../core/deadline.js';
+import { logger } from '../core/logger.js';
+import { getParser, parseSymbols } from '../parsers/index.js';
+import type { SymbolParser } from '../parsers/index.js';
+import type { ParseRequest, ParseResponse } from './parse-worker.js';
+
+// One worker per core the process may use
+export const DEFAULT_PARSE_WORKERS =
+  typeof os.availableParallelism === 'function' ? os.availableParallelism() : os.cpus().length;
+
+// How long past the deadline a worker may take to notice it before it is
+// terminated: parsers check the deadline between matches, not inside one
+const TIMEOUT_GRACE_MS = 100;
+// Longest delay setTimeout takes; longer ones fire at once
+const MAX_TIMER_MS = 2 ** 31 - 1;
+
+interface ParseTask extends ParseRequest {
+  deadline: Deadline | undefined; // The invocation's, which the worker thread doesn't see
+  resolve: (symbols: SymbolEntry[]) => void;
+  reject: (error: Error) => void;
+}
+
+interface PoolWorker {
+  worker: Worker;
+  ready: boolean; // Loaded; a worker that dies before this couldn't start at all
+  task?: ParseTask | undefined; // The file being parsed; idle when unset
+  timer?: NodeJS.Timeout | undefined; // Terminates the worker if the task outlives its deadline
+}
+
+export class ParsePool {
+  readonly size: number; // Worker threads; 0 or 1 parses on the calling thread
+  private workers: PoolWorker[] = [];
+  private queue: ParseTask[] = [];
+  private nextId = 0;
+  private inline: boolean; // No workers: too few requested, or they can't be started
+
+  constructor(size = DEFAULT_PARSE_WORKERS) {
+    this.size = Math.max(0, Math.floor(size));
+    this.inline = this.size <= 1;
+  }
+
+  /**
+   * Symbols of one file, with byte ranges. Rejects if the parser throws or
+   * its worker dies, and with TimeoutError once the invocation's deadline
+   * passes; other files in flight are unaffected.
+   */
+  parse(parser: SymbolParser, content: string, file: string): Promise<SymbolEntry[]> {
+    if (this.inline) {
+      try {
+        return Promise.resolve(parseSymbols(parser, content, file));
+      } catch (error) {
+        return Promise.reject(error instanceof Error ? error : new Error(String(error)));
+      }
+    }
+
+    const deadline = currentDeadline();
+    return new Promise((resolve, reject) => {
+      const id = this.nextId++;
+      this.queue.push({ id, language: parser.language, content, file, deadline, resolve, reject });
+      this.dispatch();
+    });
+  }
+
+  /**
+   * Hand queued files to idle workers, starting workers up to the pool size
+   */
+  private dispatch(): void {
+    while (this.queue.length > 0) {
+      let idle = this.workers.find(w => !w.task);
+      if (!idle && !this.inline && this.workers.length < this.size) idle = this.spawn();
+      if (!idle) return;
+
+      const task = this.queue.shift();
+      if (!task) return;
+      // The deadline may have passed while the file waited in the queue
+      const remainingMs = task.deadline?.remainingMs() ?? Infinity;
+      if (remainingMs === 0) {
+        task.reject(timedOut(task));
+        continue;
+      }
+
+      idle.task = task;
+      const request: ParseRequest = {
+        id: task.id,
+        language: task.language,
+        content: task.content,
+        file: task.file,
+        timeoutMs: Number.isFinite(remainingMs) ? remainingMs : undefined,
+      };
+      if (Number.isFinite(remainingMs)) {
+        const worker = idle;
+        worker.timer = setTimeout(
+          () => this.expire(worker),
+          Math.min(remainingMs + TIMEOUT_GRACE_MS, MAX_TIMER_MS)
+        );
+        worker.timer.unref();
+      }
+      idle.worker.postMessage(request);
+    }
+  }
+
+  private spawn(): PoolWorker | undefined {
+    let worker: Worker;
+    try {
+      worker = new Worker(new URL('./parse-worker.js', import.meta.url));
+    } catch (error) {
+      this.fallBackInline(error);
+      return undefined;
+    }
+    // Idle workers don't keep the process alive
+    worker.unref();
+
+    const entry: PoolWorker = { worker, ready: false };
+    worker.on('message', (response: ParseResponse) => {
+      if ('ready' in response) {
+        entry.ready = true;
+        return;
+      }
+      const task = entry.task;
+      if (!task || task.id !== response.id) return;
+      this.release(entry);
+      if ('timedOut' in response) task.reject(timedOut(task));
+      else if ('error' in response) task.reject(new Error(response.error));
+      else task.resolve(response.symbols);
+      this.dispatch();
+    });
+    worker.on('error', error => this.retire(entry, error));
+    worker.on('exit', code => {
+      this.retire(entry, new Error(`parse worker exited with code ${code}`));
+    });
+
+    this.workers.push(entry);
+    return entry;
+  }
+
+  /**
+   * Stop a worker whose parse ran past the deadline without checking it, e.g.
+   * stuck inside one regex match. The file is rejected with TimeoutError and
+   * a new worker takes over the queue.
+   */
+  private expire(entry: PoolWorker): void {
+    const index = this.workers.indexOf(entry);
+    const task = entry.task;
+    if (index === -1 || !task) return;
+    this.workers.splice(index, 1);
+    this.release(entry);
+    logger.warn('Parse worker ran past the deadline, restarting it', { file: task.file });
+    void entry.worker.terminate();
+    task.reject(timedOut(task));
+    this.dispatch();
+  }
+
+  /**
+   * Mark a worker idle, cancelling its task's timer
+   */
+  private release(entry: PoolWorker): void {
+    clearTimeout(entry.timer);
+    entry.timer = undefined;
+    entry.task = undefined;
+  }
+
+  /**
+   * Drop a worker that crashed or exited, failing the file it held; queued
+   * files go to the remaining or a replacement worker
+   */
+  private retire(entry: PoolWorker, error: Error): void {
+    const index = this.workers.indexOf(entry);
+    if (index === -1) return;
+    this.workers.splice(index, 1);
+
+    const task = entry.task;
+    this.release(entry);
+    if (!entry.ready) {
+      // Not the file's fault: the worker never loaded
+      if (task) this.queue.unshift(task);
+      this.fallBackInline(error);
+      return;
+    }
+    task?.reject(new Error(`Parse worker crashed: ${error.message}`));
+    this.dispatch();
+  }
+
+  /**
+   * Parse on this thread from now on, e.g. when the worker script is missing.
+   * Queued files are parsed here unless a running worker can take them.
+   */
+  private fallBackInline(error: unknown): void {
+    if (!this.inline) {
+      logger.warn('Parse workers unavailable, parsing on the main thread', { error });
+    }
+    this.inline = true;
+    if (this.workers.some(w => w.ready)) {
+      this.dispatch();
+      return;
+    }
+    for (const task of this.queue.splice(0)) {
+      const parser = getParser(task.language);
+      if (!parser) {
+        task.reject(new Error(`Unsupported language: ${task.language}`));
+        continue;
+      }
+      // Off the caller's async context here, so its deadline is put back
+      const parse = (): Promise<SymbolEntry[]> => this.parse(parser, task.content, task.file);
+      (task.deadline ? withDeadline(task.deadline, parse) : parse()).then(
+        task.resolve,
+        task.reject
+      );
+    }
+  }
+
+  /**
+   * Stop every worker; files still queued or parsing are rejected
+   */
+  async close(): Promise<void> {
+    const workers = this.workers.splice(0);
+    for (const task of this.queue.splice(0)) {
+      task.reject(new Error('Parse pool closed'));
+    }
+    for (const entry of workers) {
+      entry.task?.reject(new Error('Parse pool closed'));
+      this.release(entry);
+    }
+    await Promise.all(workers.map(({ worker }) => worker.terminate()));
+  }
+}
+
+/**
+ * The error for a file its deadline cut off. Checking the deadline marks it
+ * interrupted, as a check on the calling thread would have.
+ */
+function timedOut(task: ParseTask): TimeoutError {
+  try {
+    task.deadline?.check();
+  } catch (error) {
+    if (error instanceof TimeoutError) return error;
+  }
+  return new TimeoutError(task.deadline?.timeoutMs ?? 0);
+}
@@ -0,0 +1,50 @@
+/**
+ * Parse Worker
+ * Worker thread body for ParsePool: parses the files it is sent and posts
+ * their symbols back
+ */
+
+import { parentPort } from 'worker_threads';
+import type { SymbolEntry } from '../types/index.js';
+import { Deadline, TimeoutError, withDeadline } from '../core/deadline.js';
+import { getParser, parseSymbols } from '../parsers/index.js';
+
+export interface ParseRequest {
+  id: number;
+  language: string;
+  content: string;
+  file: string;
+  // What is left of the invocation's deadline; parsers check it here, as
+  // they would on the main thread
+  timeoutMs?: number | undefined;
+}
+
+export type ParseResponse =
+  | { ready: true } // Loaded and listening; sent once, before any result
+  | { id: number; symbols: SymbolEntry[] }
+  | { id: number; error: string } // The parser threw; the worker stays up
+  | { id: number; timedOut: true }; // The parser stopped at the deadline
+
+parentPort?.on('message', (request: ParseRequest) => {
+  let response: ParseResponse;
+  try {
+    const parser = getParser(request.language);
+    if (!parser) {
+      throw new Error(`Unsupported language: ${request.language}`);
+    }
+    const parse = (): SymbolEntry[] => parseSymbols(parser, request.content, request.file);
+    const symbols =
+      request.timeoutMs === undefined
+        ? parse()
+        : withDeadline(new Deadline(request.timeoutMs), parse);
+    response = { id: request.id, symbols };
+  } catch (error) {
+    response =
+      error instanceof TimeoutError
+        ? { id: request.id, timedOut: true }
+        : { id: request.id, error: error instanceof Error ? error.message : String(error) };
+  }
+  parentPort?.postMessage(response);
+});
+
+parentPort?.postMessage({ ready: true } satisfies ParseResponse);
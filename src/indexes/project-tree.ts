@@ -0,0 +1,157 @@
+/**
+ * Project Tree
+ * Directory hierarchy with per-directory file counts by language and exported symbols
+ */
+
+import * as path from 'path';
+import { resolveProjectPath } from '../core/file-reader.js';
+import { walkFiles } from '../core/file-walker.js';
+import type { WalkOptions } from '../core/file-walker.js';
+import { detectLanguage } from '../core/language-detector.js';
+import { SymbolIndexer } from './symbol-indexer.js';
+
+export interface TreeDirectory {
+  path: string; // Relative to the project root
+  depth: number; // 0 for the tree's root
+  files: number; // In this directory and below
+  languages: Array<{ language: string; files: number }>;
+  exportedSymbols: number;
+  collapsed: boolean; // Has subdirectories below maxDepth, folded into these counts
+}
+
+export interface ProjectTree {
+  root: string;
+  directories: TreeDirectory[]; // Depth-first, parents before children
+  tooDeep: string[]; // Directories below walkDepth, never walked or counted
+}
+
+export interface TreeOptions extends Omit<WalkOptions, 'cwd' | 'maxDepth' | 'onDepthLimit'> {
+  maxDepth?: number | undefined; // Deepest level listed; deeper files still count
+  walkDepth?: number | undefined; // Deepest level walked at all
+}
+
+// Files no detection rule recognizes
+const OTHER = 'other';
+
+interface DirectoryCounts {
+  depth: number;
+  files: number;
+  languages: Map<string, number>;
+  exportedSymbols: number;
+  collapsed: boolean;
+}
+
+export class ProjectTreeBuilder {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  async build(target = '.', options: TreeOptions = {}): Promise<ProjectTree> {
+    const workingDir = this.symbolIndexer.getWorkingDir();
+    const root = resolveProjectPath(workingDir, target) || '.';
+    const walk = {
+      ignore: options.ignore,
+      includeIgnored: options.includeIgnored,
+      include: options.include,
+      exclude: options.exclude,
+      maxDepth: options.walkDepth,
+    };
+    const maxDepth = options.maxDepth ?? Infinity;
+
+    const tooDeep: string[] = [];
+    const files = await walkFiles(workingDir, ['**/*'], {
+      ...walk,
+      cwd: root,
+      onDepthLimit: dir => tooDeep.push(dir),
+    });
+    const symbols = await this.symbolIndexer.scan(root, walk);
+    const directories = new Map<string, DirectoryCounts>();
+
+    // Each file counts toward every ancestor down to maxDepth
+    const visit = (file: string, count: (dir: DirectoryCounts) => void): void => {
+      const parts = path.posix.relative(root, path.posix.dirname(file));
+      const segments = parts ? parts.split('/') : [];
+      for (let depth = 0; depth <= Math.min(segments.length, maxDepth); depth++) {
+        const dir = path.posix.join(root, ...segments.slice(0, depth));
+        let counts = directories.get(dir);
+        if (!counts) {
+          counts = { depth, files: 0, languages: new Map(), exportedSymbols: 0, collapsed: false };
+          directories.set(dir, counts);
+        }
+        if (depth === maxDepth && segments.length > maxDepth) counts.collapsed = true;
+        count(counts);
+      }
+    };
+
+    for (const file of files) {
+      const language = (await detectLanguage(file, undefined, workingDir))?.language ?? OTHER;
+      visit(file, dir => {
+        dir.files++;
+        dir.languages.set(language, (dir.languages.get(language) ?? 0) + 1);
+      });
+    }
+    for (const symbol of symbols.filter(s => s.exported)) {
+      visit(symbol.file, dir => dir.exportedSymbols++);
+    }
+
+    return {
+      root,
+      directories: [...directories.entries()]
+        .sort(([a], [b]) =>
+          comparePaths(path.posix.relative(root, a), path.posix.relative(root, b))
+        )
+        .map(([dir, counts]) => ({
+          path: dir,
+          depth: counts.depth,
+          files: counts.files,
+          languages: [...counts.languages.entries()]
+            .map(([language, count]) => ({ language, files: count }))
+            .sort((a, b) => b.files - a.files || a.language.localeCompare(b.language)),
+          exportedSymbols: counts.exportedSymbols,
+          collapsed: counts.collapsed,
+        })),
+      tooDeep,
+    };
+  }
+
+  formatTree(tree: ProjectTree): string {
+    if (tree.directories.length === 0) {
+      return `No files found under ${tree.root}.`;
+    }
+
+    let output = '';
+    for (const dir of tree.directories) {
+      const name = dir.depth === 0 ? dir.path : `${path.posix.basename(dir.path)}/`;
+      const languages = dir.languages.map(l => `${l.language} ${l.files}`).join(', ');
+      output += `${'  '.repeat(dir.depth)}${name}  ${dir.files} file(s) (${languages})`;
+      output += `, ${dir.exportedSymbols} exported${dir.collapsed ? ' [collapsed]' : ''}\n`;
+    }
+
+    if (tree.tooDeep.length > 0) {
+      output += `\nNot walked, below walkDepth (${tree.tooDeep.length}):\n`;
+      output += tree.tooDeep.map(dir => `  ${dir}/\n`).join('');
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+/**
+ * Segment-wise order, so a directory's children follow it directly; the
+ * root ("") sorts first
+ */
+function comparePaths(a: string, b: string): number {
+  const left = a.split('/');
+  const right = b.split('/');
+  for (let i = 0; i < Math.min(left.length, right.length); i++) {
+    const order = (left[i] ?? '').localeCompare(right[i] ?? '');
+    if (order !== 0) return order;
+  }
+  return left.length - right.length;
+}
@@ -0,0 +1,245 @@
+/**
+ * Reference Finder
+ * Locates definition and usage sites of a symbol, ignoring comments and strings
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { ReferenceEntry, SymbolEntry } from '../types/index.js';
+import { ByteOffsets } from '../core/byte-offsets.js';
+import { ToolError } from '../core/errors.js';
+import { detectLanguage } from '../core/language-detector.js';
+import { getParser, getParserForFile } from '../parsers/index.js';
+import { SourceText } from '../parsers/source.js';
+import { SymbolIndexer, qualifiedName } from './symbol-indexer.js';
+import { clampContext } from './text-search.js';
+
+// Snippet lines either side of a reference unless the query says otherwise
+const DEFAULT_CONTEXT_LINES = 1;
+
+export interface ReferenceQuery {
+  symbol?: string | undefined;
+  file?: string | undefined;
+  line?: number | undefined;
+  contextBefore?: number | undefined; // Snippet lines before each reference (default: 1)
+  contextAfter?: number | undefined;
+}
+
+interface SnippetContext {
+  before: number;
+  after: number;
+}
+
+export interface ReferenceResult {
+  symbol: string;
+  definitions: SymbolEntry[];
+  references: ReferenceEntry[];
+}
+
+export class ReferenceFinder {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  /**
+   * Find a symbol's definitions and references. With onReferences, each
+   * file's matches are handed over as found instead of being collected, and
+   * the returned references list is empty.
+   */
+  async find(
+    query: ReferenceQuery,
+    onReferences?: (references: ReferenceEntry[]) => Promise<void>
+  ): Promise<ReferenceResult> {
+    const symbols = await this.symbolIndexer.scan();
+    const definitions = this.resolveDefinitions(symbols, query);
+    const name = definitions[0]?.name ?? query.symbol?.split('.').pop();
+    if (!name) {
+      throw new ToolError(
+        'SYMBOL_NOT_FOUND',
+        `No symbol defined at ${query.file ?? ''}:${query.line ?? ''}`
+      );
+    }
+
+    const context: SnippetContext = {
+      before: clampContext(query.contextBefore ?? DEFAULT_CONTEXT_LINES),
+      after: clampContext(query.contextAfter ?? DEFAULT_CONTEXT_LINES),
+    };
+    const references: ReferenceEntry[] = [];
+    for (const file of await this.filesInScope(definitions)) {
+      const found = await this.findInFile(file, name, definitions, context);
+      if (onReferences) {
+        if (found.length > 0) await onReferences(found);
+      } else {
+        references.push(...found);
+      }
+    }
+
+    // Definitions first, then by location
+    references.sort(
+      (a, b) =>
+        (a.kind === 'definition' ? 0 : 1) - (b.kind === 'definition' ? 0 : 1) ||
+        a.file.localeCompare(b.file) ||
+        a.line - b.line ||
+        a.column - b.column
+    );
+
+    return {
+      symbol: definitions[0] ? qualifiedName(definitions[0]) : name,
+      definitions,
+      references,
+    };
+  }
+
+  /**
+   * Pick the definitions the query refers to: the symbol at file:line when
+   * given, otherwise every symbol with a matching (optionally qualified) name
+   */
+  private resolveDefinitions(symbols: SymbolEntry[], query: ReferenceQuery): SymbolEntry[] {
+    const matchesName = (s: SymbolEntry): boolean =>
+      !query.symbol || s.name === query.symbol || qualifiedName(s) === query.symbol;
+
+    if (query.file && query.line !== undefined) {
+      const file = query.file.replace(/\\/g, '/');
+      const line = query.line;
+      const enclosing = symbols
+        .filter(s => s.file === file && s.line <= line && line <= s.endLine && matchesName(s))
+        .sort((a, b) => a.endLine - a.line - (b.endLine - b.line));
+      return enclosing.slice(0, 1);
+    }
+
+    if (!query.symbol) {
+      throw new ToolError('INVALID_ARGUMENT', 'Provide a symbol name, or a file and line');
+    }
+
+    return symbols.filter(matchesName);
+  }
+
+  /**
+   * Unexported symbols can only be referenced from their own package (Go)
+   * or file (TypeScript/JavaScript), so narrow the search when possible
+   */
+  private async filesInScope(definitions: SymbolEntry[]): Promise<string[]> {
+    const files = await this.symbolIndexer.listFiles();
+    if (definitions.length === 0) {
+      return files;
+    }
+
+    // Detected as the indexer does, so scripts named for no language count by shebang
+    const workingDir = this.symbolIndexer.getWorkingDir();
+    const languages = new Set(definitions.map(d => d.language));
+    const inLanguage: string[] = [];
+    for (const file of files) {
+      const detected = await detectLanguage(file, undefined, workingDir);
+      const language = detected && getParser(detected.language)?.language;
+      if (language && languages.has(language)) inLanguage.push(file);
+    }
+
+    if (definitions.some(d => d.exported)) {
+      return inLanguage;
+    }
+
+    return inLanguage.filter(f =>
+      definitions.some(d =>
+        d.language === 'go' ? path.dirname(f) === path.dirname(d.file) : f === d.file
+      )
+    );
+  }
+
+  private async findInFile(
+    file: string,
+    name: string,
+    definitions: SymbolEntry[],
+    context: SnippetContext
+  ): Promise<ReferenceEntry[]> {
+    let content: string;
+    try {
+      content = await fs.readFile(path.join(this.symbolIndexer.getWorkingDir(), file), 'utf-8');
+    } catch {
+      // Skip files that can't be read
+      return [];
+    }
+
+    const parser = getParserForFile(file, content);
+    if (!parser || !content.includes(name)) {
+      return [];
+    }
+
+    const source = new SourceText(content, parser.syntax);
+    const pattern = new RegExp(`(?<![\\w$])${escapeRegExp(name)}(?![\\w$])`, 'g');
+    const definitionLines = new Set(definitions.filter(d => d.file === file).map(d => d.line));
+    const offsets = new ByteOffsets(content);
+    const nameBytes = Buffer.byteLength(name);
+    const entries: ReferenceEntry[] = [];
+    let match;
+
+    while ((match = pattern.exec(source.masked)) !== null) {
+      const line = source.lineOf(match.index);
+      const column = match.index - source.lineStart(line) + 1;
+
+      // Only the first occurrence on a definition line is the definition itself
+      const isDefinition = definitionLines.delete(line);
+      // Context stays inside this file, clipped at its first and last line
+      const snippetLine = Math.max(1, line - context.before);
+      const lastLine = Math.min(source.lineCount, line + context.after);
+
+      entries.push({
+        symbol: name,
+        kind: isDefinition ? 'definition' : 'reference',
+        file,
+        line,
+        column,
+        startByte: offsets.at(match.index),
+        endByte: offsets.at(match.index) + nameBytes,
+        snippet: snippetLines(source, snippetLine, lastLine),
+        snippetLine,
+      });
+    }
+
+    return entries;
+  }
+
+  formatResult(result: ReferenceResult): string {
+    const { references } = result;
+    if (references.length === 0) {
+      return `No references to ${result.symbol} found.`;
+    }
+
+    const definitionCount = references.filter(r => r.kind === 'definition').length;
+    const location = (r: ReferenceEntry): string => (r.root ? `[${r.root}] ${r.file}` : r.file);
+    const files = new Set(references.map(location));
+
+    let output = `Found ${references.length} occurrence(s) of ${result.symbol} `;
+    output += `(${definitionCount} definition(s), ${references.length - definitionCount} usage(s)) `;
+    output += `in ${files.size} file(s):\n\n`;
+
+    for (const ref of references) {
+      output += `${location(ref)}:${ref.line}:${ref.column} (${ref.kind})\n`;
+      ref.snippet.split('\n').forEach((text, i) => {
+        const lineNumber = ref.snippetLine + i;
+        const marker = lineNumber === ref.line ? '>' : ' ';
+        output += `  ${marker} ${String(lineNumber).padStart(4)} | ${text}\n`;
+      });
+      output += '\n';
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+function snippetLines(source: SourceText, first: number, last: number): string {
+  const lines: string[] = [];
+  for (let l = first; l <= last; l++) {
+    lines.push(source.lineText(l));
+  }
+  return lines.join('\n');
+}
+
+function escapeRegExp(text: string): string {
+  return text.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+}
@@ -0,0 +1,320 @@
+/**
+ * Reference Validator
+ * Name resolution for Go packages without a type checker: every identifier a
+ * file uses must be declared in it, at package level, imported, or built in.
+ * What is left over is usually a half-finished rename or a missing import.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { ReferenceValidation, SymbolEntry, UnresolvedReference } from '../types/index.js';
+import type { ScopeOptions } from '../core/file-walker.js';
+import {
+  goImportSectionEnd,
+  parseGoImports,
+  parseGoParameters,
+  parseGoStructFields,
+} from '../parsers/go-parser.js';
+import { getParser } from '../parsers/index.js';
+import { GO_SYNTAX, SourceText } from '../parsers/source.js';
+import { STDLIB_PACKAGES, guessPackageNames } from './import-analyzer.js';
+import { SymbolIndexer } from './symbol-indexer.js';
+
+// Predeclared identifiers of the universe block
+const GO_BUILTINS = new Set([
+  ...['any', 'bool', 'byte', 'comparable', 'complex64', 'complex128', 'error', 'float32'],
+  ...['float64', 'int', 'int8', 'int16', 'int32', 'int64', 'rune', 'string', 'uint'],
+  ...['uint8', 'uint16', 'uint32', 'uint64', 'uintptr', 'true', 'false', 'iota', 'nil'],
+  ...['append', 'cap', 'clear', 'close', 'complex', 'copy', 'delete', 'imag', 'len'],
+  ...['make', 'max', 'min', 'new', 'panic', 'print', 'println', 'real', 'recover'],
+]);
+
+const GO_KEYWORDS = new Set([
+  ...['break', 'case', 'chan', 'const', 'continue', 'default', 'defer', 'else'],
+  ...['fallthrough', 'for', 'func', 'go', 'goto', 'if', 'import', 'interface', 'map'],
+  ...['package', 'range', 'return', 'select', 'struct', 'switch', 'type', 'var'],
+]);
+
+// Not the field or method of a selector, which needs types to resolve
+const IDENTIFIER = /(?<!\.\s*|\w)[A-Za-z_]\w*/g;
+
+const PACKAGE_CLAUSE = /^\s*package\s+([A-Za-z_]\w*)/m;
+
+// a, b := ...
+const SHORT_VAR = /(?<!\.\s*|\w)([A-Za-z_]\w*(?:\s*,\s*[A-Za-z_]\w*)*)\s*:=/g;
+
+const DECLARATION = /\b(?:var|const|type)\s+(\()?/g;
+
+const NAME_LIST = /^\s*([A-Za-z_]\w*(?:\s*,\s*[A-Za-z_]\w*)*)/;
+
+const FUNC_KEYWORD = /\bfunc\b/g;
+
+// Interface method specs: Read(p []byte) (n int, err error)
+const METHOD_SPEC = /^[ \t]*([A-Za-z_]\w*)\s*\(/gm;
+
+export class ReferenceValidator {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  /**
+   * Unresolved identifiers in the Go files under target, checked against the
+   * files of their own package: a directory's files with the same package
+   * clause, so an external _test package is a package of its own
+   */
+  async validate(target = '.', scope: ScopeOptions = {}): Promise<ReferenceValidation> {
+    const files = (await this.symbolIndexer.listFiles(target, scope)).filter(f =>
+      f.endsWith('.go')
+    );
+    const report: ReferenceValidation = { packages: 0, files: 0, references: 0, unresolved: [] };
+
+    for (const dir of new Set(files.map(f => path.posix.dirname(f)))) {
+      const sources = new Map<string, SourceText>();
+      const clauses = new Map<string, string>();
+      const dirFiles = (await this.symbolIndexer.listFiles(dir, { maxDepth: 0 })).filter(f =>
+        f.endsWith('.go')
+      );
+      for (const file of dirFiles) {
+        const source = await this.read(file);
+        if (!source) continue;
+        sources.set(file, source);
+        clauses.set(file, PACKAGE_CLAUSE.exec(source.masked)?.[1] ?? '');
+      }
+
+      // Package-level names by package clause; methods only resolve through
+      // selectors. Every file declares into its package whatever its build
+      // constraint, since helper_windows.go may hold what a plain file uses
+      // and no one platform is being checked.
+      const packageScopes = new Map<string, Set<string>>();
+      for (const [file, source] of sources) {
+        const clause = clauses.get(file) ?? '';
+        const names = packageScopes.get(clause) ?? new Set<string>();
+        for (const symbol of getParser('go')?.parse(source.content, file) ?? []) {
+          if (isPackageLevel(symbol)) names.add(symbol.name);
+        }
+        packageScopes.set(clause, names);
+      }
+
+      const checked = new Set<string>();
+      for (const file of files.filter(f => path.posix.dirname(f) === dir)) {
+        const source = sources.get(file);
+        const clause = clauses.get(file);
+        if (!source || clause === undefined) continue;
+        checked.add(clause);
+        report.files++;
+        const result = checkFile(file, source, packageScopes.get(clause) ?? new Set());
+        report.references += result.references;
+        report.unresolved.push(...result.unresolved);
+      }
+      report.packages += checked.size;
+    }
+
+    report.unresolved.sort(
+      (a, b) => a.file.localeCompare(b.file) || a.line - b.line || a.column - b.column
+    );
+    return report;
+  }
+
+  private async read(file: string): Promise<SourceText | undefined> {
+    try {
+      const content = await fs.readFile(
+        path.join(this.symbolIndexer.getWorkingDir(), file),
+        'utf-8'
+      );
+      return new SourceText(content, GO_SYNTAX);
+    } catch {
+      // Skip files that can't be read
+      return undefined;
+    }
+  }
+
+  formatReport(report: ReferenceValidation): string {
+    const checked = `${report.references} reference(s) in ${report.files} file(s)`;
+    if (report.unresolved.length === 0) {
+      return `All ${checked} resolve.`;
+    }
+
+    let output = `${report.unresolved.length} unresolved of ${checked}:\n`;
+    let currentFile = '';
+    for (const ref of report.unresolved) {
+      if (ref.file !== currentFile) {
+        output += `\n${ref.file}:\n`;
+        currentFile = ref.file;
+      }
+      const hint = ref.qualifier
+        ? ref.importPath
+          ? ` (missing import "${ref.importPath}"?)`
+          : ' (not imported)'
+        : '';
+      const position = `${ref.line}:${ref.column}`.padStart(8);
+      output += `  ${position}  ${ref.name}${hint}\n`;
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+function isPackageLevel(symbol: SymbolEntry): boolean {
+  return symbol.language === 'go' && symbol.kind !== 'method' && !symbol.parent;
+}
+
+function checkFile(
+  file: string,
+  source: SourceText,
+  packageScope: Set<string>
+): { references: number; unresolved: UnresolvedReference[] } {
+  const imports = parseGoImports(source);
+  const imported = new Set(
+    imports.flatMap(i => (i.alias ? [i.alias] : [i.name, ...guessPackageNames(i.path)]))
+  );
+  // Names a dot import brings in are unknown, so only qualifiers are checked
+  const dotImport = imports.some(i => i.alias === '.');
+  const declared = fileDeclarations(source);
+
+  const clause = PACKAGE_CLAUSE.exec(source.masked);
+  const start = Math.max(goImportSectionEnd(source), clause ? clause.index + clause[0].length : 0);
+  const code = source.masked.slice(start);
+
+  let references = 0;
+  const unresolved: UnresolvedReference[] = [];
+  const reported = new Set<string>();
+  IDENTIFIER.lastIndex = 0;
+  let match;
+  while ((match = IDENTIFIER.exec(code)) !== null) {
+    const name = match[0];
+    if (name === '_' || GO_KEYWORDS.has(name)) continue;
+    const after = code.slice(match.index + name.length, match.index + name.length + 40);
+    // Composite literal keys and labels; slice bounds and case values look alike
+    if (/^\s*:(?!=)/.test(after)) continue;
+    const before = code.slice(Math.max(0, match.index - 12), match.index);
+    if (/\b(?:break|continue|goto)\s+$/.test(before)) continue;
+
+    references++;
+    if (GO_BUILTINS.has(name) || declared.has(name) || packageScope.has(name)) continue;
+    if (imported.has(name)) continue;
+    const qualifier = /^\s*\./.test(after);
+    if (dotImport && !qualifier) continue;
+
+    const offset = start + match.index;
+    const line = source.lineOf(offset);
+    const key = `${line}\0${name}`;
+    if (reported.has(key)) continue;
+    reported.add(key);
+
+    const ref: UnresolvedReference = {
+      name,
+      file,
+      line,
+      column: offset - source.lineStart(line) + 1,
+      qualifier,
+    };
+    const stdlib = qualifier ? STDLIB_PACKAGES[name] : undefined;
+    if (stdlib) ref.importPath = stdlib;
+    unresolved.push(ref);
+  }
+
+  return { references, unresolved };
+}
+
+/**
+ * Every name the file declares at any scope: parameters, receivers, results,
+ * and type parameters; := and var, const, and type declarations; struct
+ * fields and interface methods. Scopes aren't told apart, so a name declared
+ * in one function also counts as declared in another.
+ */
+function fileDeclarations(source: SourceText): Set<string> {
+  const code = source.masked;
+  const declared = new Set<string>();
+  const addList = (list: string): void => {
+    for (const name of list.split(',')) declared.add(name.trim());
+  };
+  const addParameters = (open: number): number => {
+    const close = source.findMatching(open);
+    if (close === -1) return code.length;
+    for (const parameter of parseGoParameters(code.slice(open + 1, close))) {
+      if (parameter.name) declared.add(parameter.name);
+    }
+    return close + 1;
+  };
+  const skipSpace = (i: number): number => {
+    while (i < code.length && /\s/.test(code[i] ?? '')) i++;
+    return i;
+  };
+
+  for (const [, list = ''] of code.matchAll(SHORT_VAR)) addList(list);
+
+  DECLARATION.lastIndex = 0;
+  let decl;
+  while ((decl = DECLARATION.exec(code)) !== null) {
+    const start = decl.index + decl[0].length;
+    if (!decl[1]) {
+      const names = NAME_LIST.exec(code.slice(start));
+      if (names?.[1]) addList(names[1]);
+      // type List[T any] ...
+      const bracket = /^\s*[A-Za-z_]\w*\s*\[/.exec(code.slice(start));
+      if (decl[0].startsWith('type') && bracket) {
+        const open = start + bracket[0].length - 1;
+        const close = source.findMatching(open);
+        if (close !== -1) {
+          for (const parameter of parseGoParameters(code.slice(open + 1, close))) {
+            if (parameter.name) declared.add(parameter.name);
+          }
+        }
+      }
+      continue;
+    }
+    // A group: one spec per line at its top level
+    const close = source.findMatching(start - 1);
+    const group = code.slice(start, close === -1 ? undefined : close);
+    for (const line of group.split('\n')) {
+      const names = NAME_LIST.exec(line);
+      if (names?.[1]) addList(names[1]);
+    }
+  }
+
+  // func (recv T) Name[P any](params) (results), func literals, and func types
+  FUNC_KEYWORD.lastIndex = 0;
+  let fn;
+  while ((fn = FUNC_KEYWORD.exec(code)) !== null) {
+    let i = skipSpace(fn.index + fn[0].length);
+    if (code[i] === '(') i = skipSpace(addParameters(i));
+    const name = /^[A-Za-z_]\w*/.exec(code.slice(i))?.[0];
+    if (name) {
+      declared.add(name);
+      i = skipSpace(i + name.length);
+    }
+    if (code[i] === '[') i = skipSpace(addParameters(i));
+    if (code[i] === '(') i = skipSpace(addParameters(i));
+    if (code[i] === '(') addParameters(i);
+  }
+
+  for (const match of code.matchAll(/\bstruct\s*\{/g)) {
+    const open = match.index + match[0].length - 1;
+    for (const field of parseGoStructFields(source, open)) {
+      if (!field.embedded) declared.add(field.name);
+    }
+  }
+
+  for (const match of code.matchAll(/\binterface\s*\{/g)) {
+    const open = match.index + match[0].length - 1;
+    const close = source.findMatching(open);
+    if (close === -1) continue;
+    const body = code.slice(open + 1, close);
+    METHOD_SPEC.lastIndex = 0;
+    let spec;
+    while ((spec = METHOD_SPEC.exec(body)) !== null) {
+      declared.add(spec[1] ?? '');
+      const paren = open + 1 + spec.index + spec[0].length - 1;
+      const after = skipSpace(addParameters(paren));
+      if (code[after] === '(') addParameters(after);
+    }
+  }
+
+  return declared;
+}
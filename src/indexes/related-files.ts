@@ -0,0 +1,199 @@
+/**
+ * Related Files
+ * Tests, mocks, and same-package siblings of a source file, found by naming
+ * and layout conventions
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { ToolError } from '../core/errors.js';
+import { resolveProjectPath } from '../core/file-reader.js';
+import { walkFiles } from '../core/file-walker.js';
+import type { WalkOptions } from '../core/file-walker.js';
+import { detectLanguageFromName } from '../core/language-detector.js';
+
+export type FileRole = 'source' | 'test' | 'mock';
+
+export interface RelatedFiles {
+  file: string;
+  role: FileRole;
+  sources: string[]; // Code a test or mock file covers
+  tests: string[];
+  mocks: string[];
+  siblings: string[]; // Other source files in the same package (Go) or directory
+}
+
+interface ClassifiedFile {
+  file: string;
+  dir: string;
+  language: string;
+  role: FileRole;
+  stem: string; // Base name without test/mock affixes, lowercased: x_test.go -> x
+}
+
+// Directories that hold the tests or mocks of their parent directory
+const TEST_DIRS = new Set(['__tests__', 'tests', 'test']);
+const MOCK_DIRS = new Set(['__mocks__', 'mocks', 'mock', 'fakes']);
+
+// Affixes on the base name (without extension). Java-style CamelCase suffixes
+// need a lowercase letter or digit before them, so Contest.java stays a source
+const TEST_NAMES = [
+  /^test_(.+)$/, // Python
+  /^(.+)_test$/, // Go, Python
+  /^(.+)[.-](?:test|spec)$/, // JavaScript, TypeScript
+  /^(.+[a-z\d])(?:Tests?|IT)$/, // Java
+];
+const MOCK_NAMES = [
+  /^mock_(.+)$/, // mockgen
+  /^(.+)_mock$/,
+  /^(.+)[.-]mock$/,
+  /^Mock([A-Z].*)$/,
+  /^(.+[a-z\d])Mock$/,
+];
+
+export class RelatedFileFinder {
+  private workingDir: string;
+
+  constructor(workingDir?: string) {
+    this.workingDir = workingDir ?? process.cwd();
+  }
+
+  async find(file: string, options: Omit<WalkOptions, 'cwd'> = {}): Promise<RelatedFiles> {
+    const relativePath = resolveProjectPath(this.workingDir, file);
+    const stat = await fs.stat(path.join(this.workingDir, relativePath)).catch(() => undefined);
+    if (!stat?.isFile()) {
+      throw new ToolError('FILE_NOT_FOUND', `Not a file: ${relativePath}`, relativePath);
+    }
+    const target = classify(relativePath);
+    if (!target) {
+      throw new ToolError(
+        'UNSUPPORTED_LANGUAGE',
+        `Unsupported language: ${path.basename(relativePath)}`,
+        relativePath
+      );
+    }
+
+    const candidates = (await walkFiles(this.workingDir, ['**/*'], options))
+      .filter(f => f !== relativePath)
+      .map(classify)
+      .filter((f): f is ClassifiedFile => f?.language === target.language);
+
+    const result: RelatedFiles = {
+      file: relativePath,
+      role: target.role,
+      sources: [],
+      tests: [],
+      mocks: [],
+      siblings: [],
+    };
+    const packageName = await this.goPackage(target);
+
+    for (const candidate of candidates) {
+      if (candidate.stem === target.stem && isNearby(target, candidate)) {
+        const list =
+          candidate.role === 'test'
+            ? result.tests
+            : candidate.role === 'mock'
+              ? result.mocks
+              : result.sources;
+        list.push(candidate.file);
+      } else if (
+        candidate.role === 'source' &&
+        candidate.dir === target.dir &&
+        (await this.goPackage(candidate)) === packageName
+      ) {
+        result.siblings.push(candidate.file);
+      }
+    }
+
+    return result;
+  }
+
+  /**
+   * Package clause of a Go file, ignoring the _test suffix of external test
+   * packages; undefined for other languages, whose directory is the package
+   */
+  private async goPackage(file: ClassifiedFile): Promise<string | undefined> {
+    if (file.language !== 'go') return undefined;
+    try {
+      const content = await fs.readFile(path.join(this.workingDir, file.file), 'utf-8');
+      return /^package\s+(\w+)/m.exec(content)?.[1]?.replace(/_test$/, '');
+    } catch {
+      return undefined;
+    }
+  }
+
+  formatRelated(related: RelatedFiles): string {
+    let output = `${related.file} (${related.role})\n`;
+    const sections: Array<[string, string[]]> = [
+      ['Sources', related.sources],
+      ['Tests', related.tests],
+      ['Mocks', related.mocks],
+      ['Same package', related.siblings],
+    ];
+
+    for (const [title, files] of sections) {
+      if (files.length === 0) continue;
+      output += `\n${title} (${files.length}):\n`;
+      for (const f of files) output += `  ${f}\n`;
+    }
+    if (sections.every(([, files]) => files.length === 0)) {
+      output += '\nNo related files found.';
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.workingDir = dir;
+  }
+}
+
+function classify(file: string): ClassifiedFile | undefined {
+  const detected = detectLanguageFromName(file)?.language;
+  if (!detected) return undefined;
+
+  // JavaScript tests and mocks often accompany TypeScript sources and vice versa
+  const language = detected === 'javascript' ? 'typescript' : detected;
+  const dir = path.posix.dirname(file);
+  const base = path.posix.basename(file);
+  // Both extensions of x.test.ts: the stem is x.test
+  const name = base.slice(0, base.length - path.posix.extname(base).length);
+  const parent = path.posix.basename(dir);
+
+  for (const [role, patterns, dirs] of [
+    ['test', TEST_NAMES, TEST_DIRS],
+    ['mock', MOCK_NAMES, MOCK_DIRS],
+  ] as const) {
+    for (const pattern of patterns) {
+      const stem = pattern.exec(name)?.[1];
+      if (stem) return { file, dir, language, role, stem: stem.toLowerCase() };
+    }
+    if (dirs.has(parent)) return { file, dir, language, role, stem: name.toLowerCase() };
+  }
+
+  return { file, dir, language, role: 'source', stem: name.toLowerCase() };
+}
+
+/**
+ * Whether two files with the same stem sit where conventions put a file and
+ * its tests or mocks: the same directory, a tests/ or mocks/ directory beside
+ * it or higher up (Python and Rust tests/, mockery's mocks/), or the src/test
+ * mirror of src/main (Java)
+ */
+function isNearby(a: ClassifiedFile, b: ClassifiedFile): boolean {
+  if (a.dir === b.dir) return true;
+
+  for (const [holder, other] of [
+    [a, b],
+    [b, a],
+  ] as const) {
+    const dirName = path.posix.basename(holder.dir);
+    if (holder.role === 'source' || !(TEST_DIRS.has(dirName) || MOCK_DIRS.has(dirName))) continue;
+    const root = path.posix.dirname(holder.dir);
+    if (root === '.' || other.dir === root || other.dir.startsWith(`${root}/`)) return true;
+  }
+
+  const mirror = (dir: string): string => `/${dir}/`.replace('/test/', '/main/');
+  return mirror(a.dir) === mirror(b.dir);
+}
@@ -0,0 +1,256 @@
+/**
+ * Remote Symbols
+ * Symbols of a git repository that isn't checked out: shallow-cloned into a
+ * temporary directory, kept briefly for follow-up calls, then removed
+ */
+
+import * as fs from 'fs/promises';
+import * as os from 'os';
+import * as path from 'path';
+import type { SymbolEntry } from '../types/index.js';
+import { ToolError } from '../core/errors.js';
+import type { ScopeOptions } from '../core/file-walker.js';
+import { runProcess } from '../core/process.js';
+import type { ParsePool } from './parse-pool.js';
+import { SymbolIndexer, formatBytes } from './symbol-indexer.js';
+import type { ExcludedFile } from './symbol-indexer.js';
+
+export const DEFAULT_REMOTE_MAX_BYTES = 100 * 1024 * 1024;
+
+// How long an unused clone is kept for follow-up calls
+export const DEFAULT_REMOTE_CACHE_TTL_MS = 10 * 60 * 1000;
+
+const CLONE_TIMEOUT_MS = 120_000;
+
+// How often a running fetch is measured against the size limit
+const SIZE_CHECK_INTERVAL_MS = 500;
+
+// Never ask for credentials, and never fetch through file:// or ext:: helpers
+const GIT_ENV = { GIT_TERMINAL_PROMPT: '0', GIT_ALLOW_PROTOCOL: 'https:http:ssh:git' };
+
+// https://host/repo.git, ssh://git@host/repo, git://host/repo, or git@host:owner/repo
+const REMOTE_URL = /^(?:(?:https?|ssh|git):\/\/[^\s/]+\/\S+|[\w.-]+@[\w.-]+:[^\s-]\S*)$/;
+
+const GIT_REF = /^[\w][\w./@~^{}-]*$/;
+
+export interface RemoteSymbolsOptions extends ScopeOptions {
+  ref?: string | undefined; // Branch, tag, or commit; the default branch when unset
+  path?: string | undefined; // Directory or file inside the repository
+  maxRepoBytes?: number | undefined; // Clones larger than this on disk are refused
+}
+
+export interface RemoteSymbols {
+  url: string;
+  ref?: string | undefined;
+  commit: string;
+  cached: boolean; // The clone was already there from another call
+  sizeBytes: number; // Clone on disk, .git included
+  symbols: SymbolEntry[]; // Files are relative to the repository root
+  excluded: ExcludedFile[];
+}
+
+interface RemoteClone {
+  dir: string;
+  commit: string;
+  sizeBytes: number;
+  indexer: SymbolIndexer;
+  active: number; // Scans running on the clone; it isn't removed under them
+  timer?: ReturnType<typeof setTimeout> | undefined;
+}
+
+/**
+ * Shallow clones by URL and ref, shared by every root and client. Each clone
+ * has its own symbol index, so a repeated call re-parses nothing.
+ */
+export class RemoteRepoCache {
+  private clones = new Map<string, Promise<RemoteClone>>();
+  private ttlMs: number;
+  private parsePool: ParsePool | undefined;
+
+  constructor(ttlMs = DEFAULT_REMOTE_CACHE_TTL_MS, parsePool?: ParsePool) {
+    this.ttlMs = ttlMs;
+    this.parsePool = parsePool;
+  }
+
+  /**
+   * Symbols of a repository at a ref, cloning it unless a recent call did
+   */
+  async extract(url: string, options: RemoteSymbolsOptions = {}): Promise<RemoteSymbols> {
+    if (!REMOTE_URL.test(url)) {
+      throw new ToolError(
+        'INVALID_ARGUMENT',
+        `Not a remote git URL: ${url} (expected https://, ssh://, git://, or user@host:path)`
+      );
+    }
+    if (options.ref !== undefined && !GIT_REF.test(options.ref)) {
+      throw new ToolError('INVALID_ARGUMENT', `Invalid git ref: ${options.ref}`);
+    }
+    const maxRepoBytes = options.maxRepoBytes ?? DEFAULT_REMOTE_MAX_BYTES;
+
+    const key = `${url}#${options.ref ?? ''}`;
+    let pending = this.clones.get(key);
+    const cached = pending !== undefined;
+    if (!pending) {
+      pending = this.clone(url, options.ref, maxRepoBytes);
+      this.clones.set(key, pending);
+      // A failed clone isn't cached; the next call tries again
+      pending.catch(() => this.clones.delete(key));
+    }
+
+    const clone = await pending;
+    if (clone.sizeBytes > maxRepoBytes) {
+      throw tooLarge(maxRepoBytes, clone.sizeBytes);
+    }
+
+    clone.active++;
+    clearTimeout(clone.timer);
+    try {
+      const symbols = await clone.indexer.scan(options.path ?? '.', {
+        include: options.include,
+        exclude: options.exclude,
+      });
+      return {
+        url,
+        ref: options.ref,
+        commit: clone.commit,
+        cached,
+        sizeBytes: clone.sizeBytes,
+        symbols,
+        excluded: clone.indexer.getExcluded(),
+      };
+    } finally {
+      clone.active--;
+      clone.timer = setTimeout(() => void this.evict(key), this.ttlMs);
+      clone.timer.unref();
+    }
+  }
+
+  /**
+   * Remove every clone, e.g. at shutdown
+   */
+  async clear(): Promise<void> {
+    await Promise.all([...this.clones.keys()].map(key => this.evict(key, true)));
+  }
+
+  private async evict(key: string, force = false): Promise<void> {
+    const pending = this.clones.get(key);
+    const clone = await pending?.catch(() => undefined);
+    if (!clone || (clone.active > 0 && !force)) return;
+    this.clones.delete(key);
+    clearTimeout(clone.timer);
+    await fs.rm(clone.dir, { recursive: true, force: true });
+  }
+
+  /**
+   * Fetch just the one commit into a new temporary directory and check it
+   * out. The fetch is killed as soon as the directory outgrows maxBytes.
+   */
+  private async clone(
+    url: string,
+    ref: string | undefined,
+    maxBytes: number
+  ): Promise<RemoteClone> {
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'mcp-eng-remote-'));
+    try {
+      await git(dir, ['init', '--quiet']);
+      // Symlinks are checked out as plain files, so none can point outside the clone
+      await git(dir, ['config', 'core.symlinks', 'false']);
+
+      const controller = new AbortController();
+      let exceeded: number | undefined;
+      const watchdog = setInterval(() => {
+        void directorySize(dir).then(size => {
+          if (size <= maxBytes) return;
+          exceeded = size;
+          controller.abort();
+        });
+      }, SIZE_CHECK_INTERVAL_MS);
+      try {
+        const fetch = ['fetch', '--depth', '1', '--no-tags', '--quiet', '--', url, ref ?? 'HEAD'];
+        await git(dir, fetch, controller.signal);
+      } catch (error) {
+        if (exceeded !== undefined) throw tooLarge(maxBytes, exceeded);
+        throw error;
+      } finally {
+        clearInterval(watchdog);
+      }
+
+      await git(dir, ['checkout', '--quiet', '--detach', 'FETCH_HEAD']);
+      const commit = (await git(dir, ['rev-parse', 'HEAD'])).trim();
+      const sizeBytes = await directorySize(dir);
+      if (sizeBytes > maxBytes) throw tooLarge(maxBytes, sizeBytes);
+
+      return {
+        dir,
+        commit,
+        sizeBytes,
+        indexer: new SymbolIndexer(dir, undefined, this.parsePool),
+        active: 0,
+      };
+    } catch (error) {
+      await fs.rm(dir, { recursive: true, force: true });
+      throw error;
+    }
+  }
+
+  formatResult(result: RemoteSymbols, signaturesOnly = false): string {
+    const at = result.ref ? ` @ ${result.ref}` : '';
+    const cached = result.cached ? ', cached' : '';
+    let output = `Symbols of ${result.url}${at} (commit ${result.commit.slice(0, 12)}, `;
+    output += `${formatBytes(result.sizeBytes)} clone${cached})\n\n`;
+    output += new SymbolIndexer().formatSymbols(result.symbols, signaturesOnly);
+    return output;
+  }
+}
+
+async function git(dir: string, args: string[], signal?: AbortSignal): Promise<string> {
+  const result = await runProcess('git', args, {
+    cwd: dir,
+    env: GIT_ENV,
+    timeoutMs: CLONE_TIMEOUT_MS,
+    signal,
+  });
+  if (!result) {
+    throw new ToolError('FAILED_PRECONDITION', 'git is not installed');
+  }
+  if (result.code !== 0) {
+    const detail = result.stderr.trim();
+    const reason = detail === '' ? `exit code ${result.code}` : detail;
+    throw new ToolError('FAILED_PRECONDITION', `git ${args[0]} failed: ${reason}`);
+  }
+  return result.stdout;
+}
+
+function tooLarge(maxBytes: number, size: number): ToolError {
+  return new ToolError(
+    'FAILED_PRECONDITION',
+    `Repository is over the size limit (${formatBytes(size)} > ${formatBytes(maxBytes)}); ` +
+      'raise maxRepoBytes to fetch it'
+  );
+}
+
+/**
+ * Bytes of every file below dir, without following symlinks. Files removed
+ * while it runs are skipped.
+ */
+async function directorySize(dir: string): Promise<number> {
+  let total = 0;
+  let entries;
+  try {
+    entries = await fs.readdir(dir, { withFileTypes: true });
+  } catch {
+    return 0;
+  }
+  for (const entry of entries) {
+    const entryPath = path.join(dir, entry.name);
+    if (entry.isDirectory()) {
+      total += await directorySize(entryPath);
+    } else if (entry.isFile()) {
+      total += await fs
+        .lstat(entryPath)
+        .then(stat => stat.size)
+        .catch(() => 0);
+    }
+  }
+  return total;
+}
@@ -0,0 +1,434 @@
+/**
+ * Rename Planner
+ * Builds (and optionally applies) the text edits needed to rename a symbol
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { ReferenceEntry, SymbolEntry, TextEdit } from '../types/index.js';
+import { ToolError } from '../core/errors.js';
+import { isSafeToModify, writeFilesAtomically } from '../core/safety.js';
+import type { WriteResult } from '../core/safety.js';
+import { getParserForFile } from '../parsers/index.js';
+import { SourceText } from '../parsers/source.js';
+import { DefinitionResolver } from './definition-resolver.js';
+import type { DefinitionResult } from './definition-resolver.js';
+import { ReferenceFinder } from './reference-finder.js';
+import { SymbolIndexer, qualifiedName } from './symbol-indexer.js';
+import { TypeInspector } from './type-inspector.js';
+
+export interface RenameRequest {
+  symbol?: string | undefined;
+  newName: string;
+  file?: string | undefined;
+  line?: number | undefined;
+  apply?: boolean | undefined;
+  backup?: boolean | undefined; // Keep each original as <file>.bak when applying
+}
+
+export interface RenamePlan {
+  symbol: string;
+  newName: string;
+  edits: TextEdit[];
+  files: string[];
+  applied: boolean;
+  modified: string[]; // Files written; empty for a dry run
+  backups?: string[] | undefined;
+  warnings: string[];
+}
+
+const IDENTIFIER = /^[A-Za-z_$][\w$]*$/;
+
+// Go declarations that can be embedded in a struct
+const TYPE_KINDS = new Set(['struct', 'interface', 'type']);
+
+export class RenamePlanner {
+  private symbolIndexer: SymbolIndexer;
+  private referenceFinder: ReferenceFinder;
+  private definitionResolver: DefinitionResolver;
+  private typeInspector: TypeInspector;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+    this.referenceFinder = new ReferenceFinder(this.symbolIndexer);
+    this.definitionResolver = new DefinitionResolver(this.symbolIndexer);
+    this.typeInspector = new TypeInspector(this.symbolIndexer);
+  }
+
+  /**
+   * Plan a rename. Occurrences in comments and strings are never touched, nor
+   * are those that resolve to another symbol of the same name; ones that
+   * might mean either are left alone with a warning. Nothing is written
+   * unless apply is set.
+   */
+  async plan(request: RenameRequest): Promise<RenamePlan> {
+    const { newName } = request;
+    if (!IDENTIFIER.test(newName)) {
+      throw new ToolError('INVALID_ARGUMENT', `Invalid identifier: ${newName}`);
+    }
+
+    const result = await this.referenceFinder.find({
+      symbol: request.symbol,
+      file: request.file,
+      line: request.line,
+    });
+    const oldName = result.definitions[0]?.name;
+    if (!oldName) {
+      const wanted = request.symbol ?? `${request.file}:${request.line}`;
+      throw new ToolError('SYMBOL_NOT_FOUND', `Symbol not found: ${wanted}`);
+    }
+    if (oldName === newName) {
+      throw new ToolError('INVALID_ARGUMENT', `${result.symbol} is already named ${newName}`);
+    }
+
+    // Resolved the way go to definition would, so c.Add only counts when c is
+    // the renamed method's type
+    const warnings = this.visibilityWarnings(result.definitions, newName);
+    const resolved = await this.definitionResolver.resolveAll(result.references);
+    const references = result.references.filter((ref, i) => {
+      const candidates = resolved[i]?.definitions ?? [];
+      const targets = candidates.filter(c => result.definitions.some(d => sameSymbol(c, d)));
+      if (targets.length === 0) return false; // Another symbol with the same name
+      if (targets.length < candidates.length) {
+        const where = `${ref.file}:${ref.line}:${ref.column}`;
+        const usage = describeUsage(resolved[i]);
+        warnings.push(`${where}: ${usage} may not refer to ${result.symbol}; left unchanged`);
+        return false;
+      }
+      return true;
+    });
+
+    const collisions = await this.findCollisions(result.definitions, references, newName);
+    if (collisions.length > 0) {
+      throw new ToolError(
+        'FAILED_PRECONDITION',
+        `${newName} would collide with existing symbol(s): ${collisions.join(', ')}`
+      );
+    }
+
+    const edits: TextEdit[] = references.map(ref => ({
+      file: ref.file,
+      line: ref.line,
+      column: ref.column,
+      startByte: ref.startByte,
+      endByte: ref.endByte,
+      oldText: oldName,
+      newText: newName,
+    }));
+
+    const plan: RenamePlan = {
+      symbol: result.symbol,
+      newName,
+      edits,
+      files: [...new Set(edits.map(e => e.file))],
+      applied: false,
+      modified: [],
+      warnings,
+    };
+
+    if (request.apply) {
+      const written = await this.applyEdits(edits, request.backup);
+      plan.applied = true;
+      plan.modified = written.modified;
+      if (request.backup) plan.backups = written.backups;
+    }
+
+    return plan;
+  }
+
+  /**
+   * What newName would clash with: symbols already so named in the same scope
+   * as a definition (the same Go package or the same file elsewhere, with the
+   * same parent type), Go members it would hide or be hidden by through
+   * embedding, and locals that would capture a renamed top-level name
+   */
+  private async findCollisions(
+    definitions: SymbolEntry[],
+    references: ReferenceEntry[],
+    newName: string
+  ): Promise<string[]> {
+    const symbols = this.symbolIndexer.getSymbols();
+    const collisions = symbols
+      .filter(
+        s =>
+          s.name === newName &&
+          definitions.some(
+            d =>
+              s.language === d.language &&
+              (s.parent ?? '') === (d.parent ?? '') &&
+              (d.language === 'go'
+                ? path.posix.dirname(s.file) === path.posix.dirname(d.file)
+                : s.file === d.file)
+          )
+      )
+      .map(s => `${qualifiedName(s)} (${s.file}:${s.line})`);
+
+    for (const definition of definitions) {
+      if (definition.language === 'go') {
+        collisions.push(...(await this.embeddingCollisions(definition, newName, symbols)));
+      }
+    }
+    if (definitions.some(d => !d.parent)) {
+      collisions.push(...(await this.shadowingLocals(references, newName, symbols)));
+    }
+    return [...new Set(collisions)];
+  }
+
+  /**
+   * Go members named newName that the renamed name would meet through
+   * embedding: a field of the method's own type or a member promoted into it,
+   * which the method would clash with or hide, and any member of a struct in
+   * the package that embeds the type, where the renamed method would be
+   * promoted, or the renamed type becomes the embedded field's name
+   */
+  private async embeddingCollisions(
+    definition: SymbolEntry,
+    newName: string,
+    symbols: SymbolEntry[]
+  ): Promise<string[]> {
+    if (definition.parent === undefined && !TYPE_KINDS.has(definition.kind)) return [];
+    const typeName = definition.parent ?? definition.name;
+    const pkg = path.posix.dirname(definition.file);
+    const structs = symbols.filter(
+      s => s.language === 'go' && s.kind === 'struct' && path.posix.dirname(s.file) === pkg
+    );
+    // Promoted fields only know their line; the file is that of the type declaring them
+    const fieldSite = (via: string[], line: number): string =>
+      `${structs.find(s => s.name === via[via.length - 1])?.file ?? pkg}:${line}`;
+
+    const collisions: string[] = [];
+    const owner = definition.parent ? structs.find(s => s.name === definition.parent) : undefined;
+    if (owner) {
+      const details = await this.typeInspector.describe(owner, symbols);
+      for (const field of details.fields) {
+        if (field.name !== newName) continue;
+        collisions.push(`field ${owner.name}.${newName} (${owner.file}:${field.line})`);
+      }
+      for (const { field, via } of details.promotedFields) {
+        if (field.name !== newName) continue;
+        const where = fieldSite(via, field.line);
+        collisions.push(`field ${via.join('.')}.${newName} promoted into ${owner.name} (${where})`);
+      }
+      for (const { symbol, via } of details.promotedMethods) {
+        if (symbol.name !== newName) continue;
+        const where = `${symbol.file}:${symbol.line}`;
+        const from = via.slice(0, -1).join('.');
+        collisions.push(
+          `${from ? `${from}.` : ''}${qualifiedName(symbol)} promoted into ${owner.name} (${where})`
+        );
+      }
+    }
+
+    for (const struct of structs) {
+      if (struct.name === typeName) continue;
+      const details = await this.typeInspector.describe(struct, symbols);
+      if (!details.fields.some(f => f.embedded && f.name === typeName)) continue;
+      // Members promoted through the type itself were reported with its own
+      const promoted = <T extends { via: string[] }>(members: T[]): T[] =>
+        members.filter(m => m.via[0] !== typeName);
+      const sites = [
+        ...details.fields.map(f => ({ name: f.name, at: `${struct.file}:${f.line}` })),
+        ...promoted(details.promotedFields).map(({ field, via }) => ({
+          name: field.name,
+          at: fieldSite(via, field.line),
+        })),
+        ...[...details.methods, ...promoted(details.promotedMethods)].map(({ symbol }) => ({
+          name: symbol.name,
+          at: `${symbol.file}:${symbol.line}`,
+        })),
+      ];
+      for (const { at } of sites.filter(site => site.name === newName)) {
+        collisions.push(`${struct.name}.${newName} (${at}; ${struct.name} embeds ${typeName})`);
+      }
+    }
+    return collisions;
+  }
+
+  /**
+   * Names newName already means inside functions that use the renamed
+   * top-level symbol without a qualifier: a parameter, local, or import
+   * there would capture those uses once renamed
+   */
+  private async shadowingLocals(
+    references: ReferenceEntry[],
+    newName: string,
+    symbols: SymbolEntry[]
+  ): Promise<string[]> {
+    const workingDir = this.symbolIndexer.getWorkingDir();
+    const checked = new Set<SymbolEntry>();
+    const collisions: string[] = [];
+    const sources = new Map<string, SourceText | undefined>();
+
+    for (const ref of references) {
+      if (ref.kind === 'definition') continue;
+      const enclosing = symbols
+        .filter(
+          s =>
+            s.file === ref.file &&
+            (s.kind === 'function' || s.kind === 'method') &&
+            s.line <= ref.line &&
+            ref.line <= s.endLine
+        )
+        .sort((a, b) => a.endLine - a.line - (b.endLine - b.line))[0];
+      if (!enclosing || checked.has(enclosing)) continue;
+
+      if (!sources.has(ref.file)) {
+        sources.set(ref.file, await readSource(workingDir, ref.file));
+      }
+      const source = sources.get(ref.file);
+      if (!source || qualifiedAt(source, ref)) continue;
+      checked.add(enclosing);
+
+      // Go composite literal keys and labels are followed by a lone colon
+      const key = enclosing.language === 'go' ? '(?!\\s*:(?!=))' : '';
+      const pattern = new RegExp(`(?<![\\w$.])${escapeRegExp(newName)}(?![\\w$])${key}`);
+      // From after the function's own name, which a method may share with a top-level name
+      const declared = source.masked.indexOf(enclosing.name, source.lineStart(enclosing.line));
+      const bodyStart =
+        declared === -1 ? source.lineStart(enclosing.line) : declared + enclosing.name.length;
+      const body = source.masked.slice(bodyStart, source.lineStart(enclosing.endLine + 1));
+      const match = pattern.exec(body);
+      if (match) {
+        const line = source.lineOf(bodyStart + match.index);
+        collisions.push(`${newName} in ${qualifiedName(enclosing)} (${ref.file}:${line})`);
+      }
+    }
+    return collisions;
+  }
+
+  private visibilityWarnings(definitions: SymbolEntry[], newName: string): string[] {
+    const goDefinitions = definitions.filter(d => d.language === 'go');
+    if (goDefinitions.length === 0) {
+      return [];
+    }
+
+    // Go visibility follows the first letter
+    const wasExported = goDefinitions.some(d => d.exported);
+    const willExport = /^[A-Z]/.test(newName);
+    if (wasExported && !willExport) {
+      return [`${newName} is unexported; uses outside the package will no longer compile`];
+    }
+    if (!wasExported && willExport) {
+      return [`${newName} is exported and becomes part of the package API`];
+    }
+    return [];
+  }
+
+  /**
+   * Write edits to disk, refusing the whole rename if any file is protected
+   * from modification, as for formatting, or no longer contains the expected
+   * text at an edit location. Files are replaced atomically, and a failed
+   * write rolls back the files already written.
+   */
+  private async applyEdits(edits: TextEdit[], backup?: boolean): Promise<WriteResult> {
+    const workingDir = this.symbolIndexer.getWorkingDir();
+    const byFile = new Map<string, TextEdit[]>();
+    for (const edit of edits) {
+      byFile.set(edit.file, [...(byFile.get(edit.file) ?? []), edit]);
+    }
+
+    for (const file of byFile.keys()) {
+      const safety = await isSafeToModify(file, workingDir);
+      if (!safety.safe) {
+        throw new ToolError(
+          'FAILED_PRECONDITION',
+          `Refusing to write ${file}: ${safety.reason ?? 'unsafe path'}`,
+          file
+        );
+      }
+    }
+
+    const updated = new Map<string, string>();
+    for (const [file, fileEdits] of byFile) {
+      const content = await fs.readFile(path.join(workingDir, file), 'utf-8');
+      const lines = content.split('\n');
+
+      // Right to left so earlier columns stay valid
+      const ordered = [...fileEdits].sort((a, b) => b.line - a.line || b.column - a.column);
+      for (const edit of ordered) {
+        const text = lines[edit.line - 1];
+        const start = edit.column - 1;
+        if (text?.slice(start, start + edit.oldText.length) !== edit.oldText) {
+          throw new ToolError(
+            'FAILED_PRECONDITION',
+            `${file}:${edit.line}:${edit.column} changed since planning`,
+            file
+          );
+        }
+        lines[edit.line - 1] =
+          text.slice(0, start) + edit.newText + text.slice(start + edit.oldText.length);
+      }
+
+      updated.set(file, lines.join('\n'));
+    }
+
+    return writeFilesAtomically(
+      workingDir,
+      [...updated].map(([file, content]) => ({ file, content })),
+      { backup }
+    );
+  }
+
+  formatPlan(plan: RenamePlan): string {
+    if (plan.edits.length === 0) {
+      return `No occurrences of ${plan.symbol} found.`;
+    }
+
+    let output = plan.applied
+      ? `Renamed ${plan.symbol} to ${plan.newName}: `
+      : `Rename plan for ${plan.symbol} -> ${plan.newName} (dry run, pass apply to write): `;
+    output += `${plan.edits.length} edit(s) in ${plan.files.length} file(s)\n`;
+
+    for (const warning of plan.warnings) {
+      output += `Warning: ${warning}\n`;
+    }
+    output += '\n';
+
+    for (const edit of plan.edits) {
+      output += `${edit.file}:${edit.line}:${edit.column}  ${edit.oldText} -> ${edit.newText}\n`;
+    }
+    if (plan.backups && plan.backups.length > 0) {
+      output += `\nOriginals kept as ${plan.backups.join(', ')}\n`;
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+function sameSymbol(a: SymbolEntry, b: SymbolEntry): boolean {
+  return a.file === b.file && a.line === b.line && a.name === b.name && a.kind === b.kind;
+}
+
+function describeUsage(result: DefinitionResult | undefined): string {
+  if (!result) return 'this occurrence';
+  return result.receiver ? `${result.receiver}.${result.name}` : result.name;
+}
+
+/**
+ * Whether an occurrence is reached through a receiver or package: c.Add, pkg.Add
+ */
+function qualifiedAt(source: SourceText, ref: ReferenceEntry): boolean {
+  const lineStart = source.lineStart(ref.line);
+  const before = source.masked.slice(lineStart, lineStart + ref.column - 1);
+  return /(?:\.|::|->)\s*$/.test(before);
+}
+
+async function readSource(workingDir: string, file: string): Promise<SourceText | undefined> {
+  let content: string;
+  try {
+    content = await fs.readFile(path.join(workingDir, file), 'utf-8');
+  } catch {
+    // Skip files that can't be read
+    return undefined;
+  }
+  const parser = getParserForFile(file, content);
+  return parser ? new SourceText(content, parser.syntax) : undefined;
+}
+
+function escapeRegExp(text: string): string {
+  return text.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+}
@@ -0,0 +1,303 @@
+/**
+ * Route Finder
+ * Maps the HTTP routes a Go service registers to their handlers: net/http
+ * HandleFunc and Handle, gorilla/mux, and the Gin, Echo, Chi, and Fiber verb
+ * methods, with group and subrouter prefixes applied
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { RouteIndexEntry, SymbolEntry } from '../types/index.js';
+import { resolveProjectPath } from '../core/file-reader.js';
+import { scopeMatcher } from '../core/file-walker.js';
+import type { ScopeOptions } from '../core/file-walker.js';
+import {
+  type GoArgument,
+  goCallArguments,
+  goStringValue,
+  parseGoImports,
+} from '../parsers/go-parser.js';
+import { GO_SYNTAX, SourceText } from '../parsers/source.js';
+import { SymbolIndexer } from './symbol-indexer.js';
+
+type HttpMethod = RouteIndexEntry['method'];
+
+export interface GoRoute extends RouteIndexEntry {
+  handlerFile?: string | undefined; // Where the handler is defined, when it's a project function
+  handlerLine?: number | undefined;
+}
+
+const HTTP_METHODS = new Set(['GET', 'POST', 'PUT', 'DELETE', 'PATCH', 'OPTIONS', 'HEAD']);
+
+// recv.Name( for every call that registers a route or opens a prefix
+const REGISTRATION_PATTERN =
+  /\b([A-Za-z_]\w*)\s*\.\s*(HandleFunc|Handle|Method|MethodFunc|GET|POST|PUT|DELETE|PATCH|OPTIONS|HEAD|Any|Get|Post|Put|Delete|Patch|Options|Head|All|Group|Route|PathPrefix)\s*\(/g;
+
+// A handler that rejects every other method: if r.Method != http.MethodGet {
+const METHOD_GUARD = /\.Method\s*!=\s*(?:http\.Method([A-Z][a-z]+)|"([A-Z]+)")/;
+
+export class RouteFinder {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  /**
+   * Routes registered in the Go files under target, with each handler
+   * resolved to its definition when it's a function of the project
+   */
+  async find(target = '.', scope: ScopeOptions = {}): Promise<GoRoute[]> {
+    const workingDir = this.symbolIndexer.getWorkingDir();
+    const root = resolveProjectPath(workingDir, target);
+    // A missing target fails here, as a scan of it would
+    await fs.stat(path.join(workingDir, root));
+    const inScope = scopeMatcher(scope);
+    // One scan of the whole project, since handlers can be defined outside target
+    const symbols = (await this.symbolIndexer.scan()).filter(s => s.language === 'go');
+    const functions = symbols.filter(s => s.kind === 'function' || s.kind === 'method');
+    const files = symbols
+      .map(s => s.file)
+      .filter(f => (!root || f === root || f.startsWith(`${root}/`)) && inScope(f));
+    const sources = new Map<string, SourceText>();
+    const read = async (file: string): Promise<SourceText> => {
+      let source = sources.get(file);
+      if (!source) {
+        const content = await fs.readFile(path.join(workingDir, file), 'utf-8');
+        source = new SourceText(content, GO_SYNTAX);
+        sources.set(file, source);
+      }
+      return source;
+    };
+
+    const routes: GoRoute[] = [];
+    for (const file of [...new Set(files)].sort()) {
+      const source = await read(file);
+      const packages = new Map(parseGoImports(source).map(i => [i.name, i.path]));
+
+      for (const route of parseGoRoutes(source, file)) {
+        const handler = resolveHandler(route.handler, file, packages, functions);
+        if (!handler) {
+          routes.push(route);
+          continue;
+        }
+        const entry: GoRoute = { ...route, handlerFile: handler.file, handlerLine: handler.line };
+        if (route.method === 'ALL') {
+          const body = await read(handler.file);
+          entry.method = guardedMethod(
+            body.content.slice(body.lineStart(handler.line), body.lineStart(handler.endLine + 1))
+          );
+        }
+        routes.push(entry);
+      }
+    }
+    return routes;
+  }
+
+  formatRoutes(routes: GoRoute[]): string {
+    if (routes.length === 0) {
+      return 'No HTTP routes found.';
+    }
+
+    let output = `Found ${routes.length} route(s):\n\n`;
+    const width = Math.max(...routes.map(r => r.method.length + 1 + r.path.length));
+    for (const route of routes) {
+      const endpoint = `${route.method} ${route.path}`.padEnd(width);
+      const middleware = route.middleware ? ` (via ${route.middleware.join(', ')})` : '';
+      output += `  ${endpoint} -> ${route.handler}${middleware}  ${route.file}:${route.line}\n`;
+    }
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+/**
+ * Route registrations in one Go file, in source order. Handlers are as
+ * written; method is ALL when the registration doesn't restrict it.
+ */
+export function parseGoRoutes(source: SourceText, file: string): GoRoute[] {
+  const routes: GoRoute[] = [];
+  // Prefixes of router variables (v1 := r.Group("/v1")) and of Chi Route closures
+  const prefixes = new Map<string, string>();
+  const closures: { start: number; end: number; name: string; prefix: string }[] = [];
+  const prefixOf = (receiver: string, offset: number): string => {
+    const closure = closures
+      .filter(c => c.name === receiver && c.start < offset && offset < c.end)
+      .sort((a, b) => b.start - a.start)[0];
+    return closure ? closure.prefix : (prefixes.get(receiver) ?? '');
+  };
+
+  REGISTRATION_PATTERN.lastIndex = 0;
+  let match;
+  while ((match = REGISTRATION_PATTERN.exec(source.masked)) !== null) {
+    const receiver = match[1] ?? '';
+    const call = match[2] ?? '';
+    const open = match.index + match[0].length - 1;
+    const close = source.findMatching(open);
+    if (close === -1) continue;
+
+    const args = goCallArguments(source, open, close);
+    const strings = args.map(goStringValue);
+    const line = source.lineOf(match.index);
+    const prefix = prefixOf(receiver, match.index);
+
+    if (call === 'Group' || call === 'PathPrefix') {
+      const variable = assignedVariable(source, match.index);
+      if (variable && strings[0] !== undefined) {
+        prefixes.set(variable, joinPath(prefix, strings[0]));
+      }
+      continue;
+    }
+    if (call === 'Route') {
+      // Chi: r.Route("/api", func(r chi.Router) { ... })
+      const body = args[1];
+      const parameter = body ? /^func\s*\(\s*([A-Za-z_]\w*)/.exec(body.text)?.[1] : undefined;
+      if (body && parameter && strings[0] !== undefined) {
+        closures.push({
+          start: body.start,
+          end: body.end,
+          name: parameter,
+          prefix: joinPath(prefix, strings[0]),
+        });
+      }
+      continue;
+    }
+
+    let methods: string[];
+    let pattern: string | undefined;
+    let handlers: GoArgument[];
+    if (call === 'HandleFunc' || call === 'Handle') {
+      const methodFirst = strings[0] !== undefined && HTTP_METHODS.has(strings[0]);
+      if (methodFirst && args.length >= 3) {
+        // Gin: r.Handle("GET", "/path", handler)
+        methods = [strings[0] ?? ''];
+        pattern = strings[1];
+        handlers = args.slice(2);
+      } else {
+        // net/http 1.22 patterns carry the method: "GET /health"
+        const methodPattern = /^([A-Z]+)\s+(\S.*)$/.exec(strings[0] ?? '');
+        methods =
+          methodPattern?.[1] && HTTP_METHODS.has(methodPattern[1])
+            ? [methodPattern[1]]
+            : chainedMethods(source, close);
+        pattern = methodPattern?.[2] ?? strings[0];
+        handlers = args.slice(1);
+      }
+    } else if (call === 'Method' || call === 'MethodFunc') {
+      // Chi: r.Method("GET", "/path", handler)
+      methods = [strings[0]?.toUpperCase() ?? ''];
+      pattern = strings[1];
+      handlers = args.slice(2);
+    } else {
+      methods = [call === 'Any' || call === 'All' ? 'ALL' : call.toUpperCase()];
+      pattern = strings[0];
+      handlers = args.slice(1);
+      // Verb methods are common names; a route's path starts with /
+      if (!pattern?.startsWith('/')) continue;
+    }
+
+    const last = handlers[handlers.length - 1];
+    if (pattern === undefined || !last) continue;
+    const guard = last.text.startsWith('func') ? guardedMethod(last.text) : 'ALL';
+    for (const method of methods.length > 0 ? methods : [guard]) {
+      const route: GoRoute = {
+        method: toHttpMethod(method),
+        path: joinPath(prefix, pattern),
+        handler: handlerName(last.text),
+        file,
+        line,
+      };
+      if (handlers.length > 1) route.middleware = handlers.slice(0, -1).map(h => h.text);
+      routes.push(route);
+    }
+  }
+
+  return routes;
+}
+
+/**
+ * Gorilla mux: r.HandleFunc("/users", h).Methods("GET", "POST")
+ */
+function chainedMethods(source: SourceText, close: number): string[] {
+  const chained = /^\s*\.\s*Methods\s*\(/.exec(source.masked.slice(close + 1));
+  if (!chained) return [];
+  const open = close + chained[0].length;
+  const end = source.findMatching(open);
+  if (end === -1) return [];
+  return goCallArguments(source, open, end)
+    .map(goStringValue)
+    .filter((m): m is string => m !== undefined)
+    .map(m => m.toUpperCase());
+}
+
+/**
+ * The variable a call's result is assigned to: v1 in v1 := r.Group("/v1")
+ */
+function assignedVariable(source: SourceText, offset: number): string | undefined {
+  const before = source.masked.slice(source.lineStart(source.lineOf(offset)), offset);
+  return /([A-Za-z_]\w*)\s*:?=\s*$/.exec(before)?.[1];
+}
+
+function joinPath(prefix: string, pattern: string): string {
+  if (!prefix) return pattern;
+  if (pattern === '' || pattern === '/') return prefix;
+  return `${prefix.replace(/\/+$/, '')}/${pattern.replace(/^\/+/, '')}`;
+}
+
+/**
+ * Handler as written, unwrapping http.HandlerFunc(f); "anonymous" for a
+ * function literal
+ */
+function handlerName(text: string): string {
+  if (text.startsWith('func')) return 'anonymous';
+  return /^http\.HandlerFunc\((.*)\)$/s.exec(text)?.[1]?.trim() ?? text;
+}
+
+/**
+ * The one method a handler body accepts when it rejects the rest, else ALL
+ */
+function guardedMethod(body: string): HttpMethod {
+  const guard = METHOD_GUARD.exec(body);
+  return toHttpMethod(guard?.[1] ?? guard?.[2] ?? 'ALL');
+}
+
+function toHttpMethod(method: string): HttpMethod {
+  const upper = method.toUpperCase();
+  return HTTP_METHODS.has(upper) ? (upper as HttpMethod) : 'ALL';
+}
+
+/**
+ * The project function a handler names: healthHandler in its package,
+ * pkg.Handler in an imported package, or h.List as a method with that name
+ */
+function resolveHandler(
+  handler: string,
+  file: string,
+  packages: Map<string, string>,
+  functions: SymbolEntry[]
+): SymbolEntry | undefined {
+  const reference = /^&?([A-Za-z_]\w*)(?:\.([A-Za-z_]\w*))?$/.exec(handler);
+  const first = reference?.[1];
+  if (!reference || !first) return undefined;
+  const second = reference[2];
+  const dir = path.posix.dirname(file);
+
+  let candidates: SymbolEntry[];
+  if (!second) {
+    candidates = functions.filter(
+      f => !f.parent && f.name === first && path.posix.dirname(f.file) === dir
+    );
+  } else if (packages.has(first)) {
+    const importPath = packages.get(first) ?? '';
+    candidates = functions.filter(f => {
+      const fileDir = path.posix.dirname(f.file);
+      return !f.parent && f.name === second && fileDir !== '.' && importPath.endsWith(fileDir);
+    });
+  } else {
+    candidates = functions.filter(f => f.parent && f.name === second);
+  }
+  return candidates.length === 1 ? candidates[0] : undefined;
+}
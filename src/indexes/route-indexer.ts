@@ -8,6 +8,8 @@ import * as path from 'path';
 import { glob } from 'glob';
 import { stringify } from 'yaml';
 import type { RouteIndexEntry } from '../types/index.js';
+import { GO_SYNTAX, SourceText } from '../parsers/source.js';
+import { parseGoRoutes } from './route-finder.js';
 
 type HttpMethod = RouteIndexEntry['method'];
 
@@ -63,22 +65,6 @@ const ROUTE_PATTERNS: Record<string, RoutePattern[]> = {
       pathGroup: 2,
     },
   ],
-  // Go (Gin, Echo, Chi)
-  go: [
-    // r.GET('/path', handler)
-    {
-      regex: /\.\s*(GET|POST|PUT|DELETE|PATCH|OPTIONS|HEAD)\s*\(\s*"([^"]+)"/gi,
-      methodGroup: 1,
-      pathGroup: 2,
-    },
-    // http.HandleFunc('/path', handler)
-    {
-      regex: /HandleFunc\s*\(\s*"([^"]+)"/gi,
-      methodGroup: -1,
-      pathGroup: 1,
-      defaultMethod: 'ALL',
-    },
-  ],
   // ASP.NET
   aspnet: [
     // [HttpGet('/path')] or [HttpPost('/path')]
@@ -196,6 +182,17 @@ export class RouteIndexer {
   }
 
   private async scanFile(filePath: string, framework: string): Promise<void> {
+    if (framework === 'go') {
+      // Go registrations span lines and name their handler as an argument
+      try {
+        const content = await fs.readFile(path.join(this.workingDir, filePath), 'utf-8');
+        this.routes.push(...parseGoRoutes(new SourceText(content, GO_SYNTAX), filePath));
+      } catch {
+        // Skip files that can't be read
+      }
+      return;
+    }
+
     const patterns = ROUTE_PATTERNS[framework];
     if (!patterns) return;
 
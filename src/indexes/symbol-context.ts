@@ -0,0 +1,116 @@
+/**
+ * Symbol Context
+ * Returns the smallest declaration enclosing a line, including its doc comment
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { SymbolEntry } from '../types/index.js';
+import { ToolError } from '../core/errors.js';
+import { resolveProjectPath } from '../core/file-reader.js';
+import { getParserForFile } from '../parsers/index.js';
+import { SourceText } from '../parsers/source.js';
+import { SymbolIndexer, formatBlame, qualifiedName } from './symbol-indexer.js';
+
+export interface SymbolContext {
+  symbol: SymbolEntry;
+  startLine: number; // Includes the doc comment and decorators
+  endLine: number;
+  content: string;
+}
+
+// Declarations that have a body worth returning, smallest-range wins among these
+const CONTAINER_KINDS = new Set([
+  'function',
+  'method',
+  'class',
+  'struct',
+  'interface',
+  'type',
+  'enum',
+]);
+
+export class SymbolContextResolver {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  async resolve(file: string, line: number): Promise<SymbolContext> {
+    const workingDir = this.symbolIndexer.getWorkingDir();
+    const relativePath = resolveProjectPath(workingDir, file);
+    const content = await fs.readFile(path.join(workingDir, relativePath), 'utf-8');
+    const parser = getParserForFile(relativePath, content);
+    if (!parser) {
+      throw new ToolError(
+        'UNSUPPORTED_LANGUAGE',
+        `Unsupported language: ${path.basename(relativePath)}`,
+        relativePath
+      );
+    }
+    const source = new SourceText(content, parser.syntax);
+    const symbols = this.symbolIndexer.parseWith(parser, content, relativePath);
+
+    const candidates = symbols
+      .map(symbol => ({ symbol, startLine: this.declarationStart(source, symbol) }))
+      .filter(c => c.startLine <= line && line <= c.symbol.endLine);
+
+    // Prefer bodies (functions, types) over values; then the tightest range
+    candidates.sort(
+      (a, b) =>
+        Number(CONTAINER_KINDS.has(b.symbol.kind)) - Number(CONTAINER_KINDS.has(a.symbol.kind)) ||
+        a.symbol.endLine - a.startLine - (b.symbol.endLine - b.startLine)
+    );
+
+    const best = candidates[0];
+    if (!best) {
+      throw new ToolError(
+        'SYMBOL_NOT_FOUND',
+        `No declaration encloses ${relativePath}:${line}`,
+        relativePath
+      );
+    }
+
+    const lines: string[] = [];
+    for (let l = best.startLine; l <= best.symbol.endLine; l++) {
+      lines.push(source.lineText(l));
+    }
+
+    return {
+      symbol: best.symbol,
+      startLine: best.startLine,
+      endLine: best.symbol.endLine,
+      content: lines.join('\n'),
+    };
+  }
+
+  /**
+   * First line of a declaration, walking up over decorators and the doc comment
+   */
+  private declarationStart(source: SourceText, symbol: SymbolEntry): number {
+    let start = symbol.line;
+
+    // Decorators (Rust attributes) sit directly above, possibly spanning several lines each;
+    // Java annotations may also share the declaration line: @Override public void run()
+    let seen = (source.lineText(start, true).match(/(?:^|\s)@(?!interface\b)[\w$]/g) ?? []).length;
+    while (seen < (symbol.decorators?.length ?? 0) && start > 1) {
+      start--;
+      if (/^\s*(?:@|#\[)/.test(source.lineText(start, true))) seen++;
+    }
+
+    return source.docCommentBefore(start)?.startLine ?? start;
+  }
+
+  formatContext(context: SymbolContext): string {
+    const { symbol } = context;
+    let output = `${symbol.kind} ${qualifiedName(symbol)} (${symbol.file}:`;
+    output += `${context.startLine}-${context.endLine})${formatBlame(symbol)}\n\n`;
+    output += '```' + symbol.language + '\n' + context.content + '\n```';
+    return output;
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
@@ -0,0 +1,255 @@
+/**
+ * Symbol Diff
+ * Symbols added, removed, or re-signed between two git revisions, read from
+ * git objects without checking either revision out
+ */
+
+import * as path from 'path';
+import type { SymbolEntry, SymbolKind } from '../types/index.js';
+import { isBinary, resolveProjectPath } from '../core/file-reader.js';
+import { DEFAULT_IGNORE, matchesAnyGlob, scopeMatcher } from '../core/file-walker.js';
+import type { ScopeOptions } from '../core/file-walker.js';
+import { listTree, readBlob, resolveCommit } from '../core/git.js';
+import { getParserForFile, getSupportedExtensions } from '../parsers/index.js';
+import { SymbolIndexer, qualifiedName } from './symbol-indexer.js';
+
+export interface DiffedSymbol {
+  name: string;
+  kind: SymbolKind;
+  file: string;
+  line: number;
+  signature: string;
+  parent?: string | undefined;
+  exported: boolean;
+}
+
+export interface ModifiedSymbol {
+  name: string;
+  kind: SymbolKind;
+  file: string; // Location in the newer revision
+  line: number;
+  parent?: string | undefined;
+  exported: boolean; // In the older revision, i.e. the change is breaking
+  before: string;
+  after: string;
+}
+
+export interface SymbolDiff {
+  from: string;
+  to: string;
+  filesChanged: number;
+  added: DiffedSymbol[];
+  removed: DiffedSymbol[];
+  modified: ModifiedSymbol[];
+  breaking: number; // Exported symbols removed or re-signed
+}
+
+// A file whose blob differs between two revisions; content is unset in a
+// revision without the file, or where it is binary
+export interface ChangedSource {
+  file: string;
+  before?: string | undefined;
+  after?: string | undefined;
+}
+
+export interface SymbolDiffOptions extends ScopeOptions {
+  path?: string | undefined;
+  exportedOnly?: boolean | undefined;
+  ignore?: string[] | undefined;
+  includeIgnored?: boolean | undefined;
+}
+
+export class SymbolDiffer {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  async diff(from: string, to = 'HEAD', options: SymbolDiffOptions = {}): Promise<SymbolDiff> {
+    const changed = await this.changedSources(from, to, options);
+    const oldSymbols = changed.flatMap(c => this.extract(c.file, c.before));
+    const newSymbols = changed.flatMap(c => this.extract(c.file, c.after));
+
+    const result: SymbolDiff = {
+      from,
+      to,
+      filesChanged: changed.length,
+      added: [],
+      removed: [],
+      modified: [],
+      breaking: 0,
+    };
+    const oldByKey = groupByKey(oldSymbols);
+    const newByKey = groupByKey(newSymbols);
+
+    for (const key of [...new Set([...oldByKey.keys(), ...newByKey.keys()])]) {
+      // Same-named overloads pair up in order once identical signatures are set aside
+      const unchanged = new Set(
+        (oldByKey.get(key) ?? [])
+          .map(s => s.signature)
+          .filter(sig => (newByKey.get(key) ?? []).some(s => s.signature === sig))
+      );
+      const olds = (oldByKey.get(key) ?? []).filter(s => !unchanged.has(s.signature));
+      const news = (newByKey.get(key) ?? []).filter(s => !unchanged.has(s.signature));
+
+      for (let i = 0; i < Math.max(olds.length, news.length); i++) {
+        const old = olds[i];
+        const current = news[i];
+        if (old && current) {
+          result.modified.push({
+            ...location(current),
+            exported: old.exported,
+            before: old.signature,
+            after: current.signature,
+          });
+        } else if (old) {
+          result.removed.push(toDiffed(old));
+        } else if (current) {
+          result.added.push(toDiffed(current));
+        }
+      }
+    }
+
+    if (options.exportedOnly) {
+      result.added = result.added.filter(s => s.exported);
+      result.removed = result.removed.filter(s => s.exported);
+      result.modified = result.modified.filter(s => s.exported);
+    }
+    for (const list of [result.added, result.removed, result.modified]) {
+      list.sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+    }
+    result.breaking =
+      result.removed.filter(s => s.exported).length +
+      result.modified.filter(s => s.exported).length;
+
+    return result;
+  }
+
+  /**
+   * Parseable files in a commit under the target path, by blob id
+   */
+  private async sourceFiles(
+    commit: string,
+    options: SymbolDiffOptions
+  ): Promise<Map<string, string>> {
+    const workingDir = this.symbolIndexer.getWorkingDir();
+    const target = resolveProjectPath(workingDir, options.path ?? '.');
+    const extensions = new Set(getSupportedExtensions());
+    const ignore = [...(options.includeIgnored ? [] : DEFAULT_IGNORE), ...(options.ignore ?? [])];
+    const inScope = scopeMatcher(options);
+
+    const files = new Map<string, string>();
+    for (const { file, blob } of await listTree(workingDir, commit)) {
+      if (target && file !== target && !file.startsWith(`${target}/`)) continue;
+      if (!extensions.has(path.extname(file)) || matchesAnyGlob(file, ignore)) continue;
+      if (!inScope(file)) continue;
+      files.set(file, blob);
+    }
+    return files;
+  }
+
+  /**
+   * Parseable files under the target path whose blob differs between two
+   * revisions, with their content at each. Only these files can have changed
+   * symbols.
+   */
+  async changedSources(
+    from: string,
+    to: string,
+    options: SymbolDiffOptions = {}
+  ): Promise<ChangedSource[]> {
+    const workingDir = this.symbolIndexer.getWorkingDir();
+    const [fromCommit, toCommit] = await Promise.all([
+      resolveCommit(workingDir, from),
+      resolveCommit(workingDir, to),
+    ]);
+    const [before, after] = await Promise.all([
+      this.sourceFiles(fromCommit, options),
+      this.sourceFiles(toCommit, options),
+    ]);
+
+    const read = async (blob: string | undefined): Promise<string | undefined> => {
+      if (!blob) return undefined; // Not in this revision
+      const buffer = await readBlob(workingDir, blob);
+      return isBinary(buffer) ? undefined : buffer.toString('utf-8');
+    };
+    const changed: ChangedSource[] = [];
+    for (const file of new Set([...before.keys(), ...after.keys()])) {
+      if (before.get(file) === after.get(file)) continue;
+      const [oldContent, newContent] = await Promise.all([
+        read(before.get(file)),
+        read(after.get(file)),
+      ]);
+      changed.push({ file, before: oldContent, after: newContent });
+    }
+    return changed;
+  }
+
+  private extract(file: string, content: string | undefined): SymbolEntry[] {
+    if (content === undefined) return [];
+    const parser = getParserForFile(file, content);
+    return parser ? this.symbolIndexer.parseWith(parser, content, file) : [];
+  }
+
+  formatDiff(diff: SymbolDiff): string {
+    let output = `Symbols ${diff.from} -> ${diff.to}: ${diff.added.length} added, `;
+    output += `${diff.removed.length} removed, ${diff.modified.length} modified`;
+    output += ` (${diff.breaking} breaking) in ${diff.filesChanged} changed file(s)\n`;
+
+    const name = (s: DiffedSymbol | ModifiedSymbol): string =>
+      `${s.kind} ${s.parent ? `${s.parent}.${s.name}` : s.name} (${s.file}:${s.line})`;
+    const breaking = (s: DiffedSymbol | ModifiedSymbol): string =>
+      s.exported ? ' [breaking]' : '';
+
+    if (diff.added.length > 0) {
+      output += '\nAdded:\n';
+      for (const s of diff.added) output += `  + ${name(s)}\n`;
+    }
+    if (diff.removed.length > 0) {
+      output += '\nRemoved:\n';
+      for (const s of diff.removed) output += `  - ${name(s)}${breaking(s)}\n`;
+    }
+    if (diff.modified.length > 0) {
+      output += '\nModified:\n';
+      for (const s of diff.modified) {
+        output += `  ~ ${name(s)}${breaking(s)}\n`;
+        output += `      - ${s.before}\n      + ${s.after}\n`;
+      }
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+/**
+ * Symbols are matched by qualified name within their file, except in Go where
+ * a package spans its directory: moving a function between files of one
+ * package doesn't change it
+ */
+function groupByKey(symbols: SymbolEntry[]): Map<string, SymbolEntry[]> {
+  const groups = new Map<string, SymbolEntry[]>();
+  for (const symbol of symbols) {
+    const scope = symbol.language === 'go' ? path.posix.dirname(symbol.file) : symbol.file;
+    const key = `${symbol.language}\0${scope}\0${qualifiedName(symbol)}`;
+    const group = groups.get(key) ?? [];
+    group.push(symbol);
+    groups.set(key, group);
+  }
+  return groups;
+}
+
+type SymbolLocation = Pick<DiffedSymbol, 'name' | 'kind' | 'file' | 'line' | 'parent'>;
+
+function location(symbol: SymbolEntry): SymbolLocation {
+  const { name, kind, file, line, parent } = symbol;
+  return parent ? { name, kind, file, line, parent } : { name, kind, file, line };
+}
+
+function toDiffed(symbol: SymbolEntry): DiffedSymbol {
+  return { ...location(symbol), signature: symbol.signature, exported: symbol.exported };
+}
@@ -0,0 +1,957 @@
+/**
+ * Symbol Indexer
+ * Extracts symbols from source files using the language-specific parsers
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import * as crypto from 'crypto';
+import { stringify } from 'yaml';
+import { SymbolKindSchema } from '../types/index.js';
+import type { SymbolEntry, SymbolSignature } from '../types/index.js';
+import { buildExclusionReason, parseBuildConstraint } from '../core/build-constraints.js';
+import type { BuildContext } from '../core/build-constraints.js';
+import { TimeoutError, checkDeadline } from '../core/deadline.js';
+import { ToolError } from '../core/errors.js';
+import { isBinary, resolveProjectPath } from '../core/file-reader.js';
+import { isGeneratedFile } from '../core/generated-files.js';
+import { walkFiles } from '../core/file-walker.js';
+import { detectLanguage, detectLanguageFromName } from '../core/language-detector.js';
+import { getBlame } from '../core/git.js';
+import { LruCache } from '../core/lru-cache.js';
+import type { CacheStats } from '../core/lru-cache.js';
+import type { WalkOptions } from '../core/file-walker.js';
+import {
+  getParser,
+  getParserForFile,
+  getSupportedExtensions,
+  parseSymbols,
+} from '../parsers/index.js';
+import type { SymbolParser } from '../parsers/index.js';
+import type { ParsePool } from './parse-pool.js';
+
+export const DEFAULT_MAX_FILE_SIZE = 2 * 1024 * 1024;
+
+export const DEFAULT_CACHE_SIZE = 5000;
+
+// File changes remembered for symbol deltas; a client further behind gets the full index
+const DELTA_HISTORY = 2000;
+
+interface CachedFile {
+  mtimeMs: number;
+  size: number;
+  hash: string;
+  symbols: SymbolEntry[];
+  excluded?: string; // Why the file wasn't parsed (too large, binary, parser error)
+  buildConstraint?: string | undefined; // Go //go:build expression
+  generated?: boolean | undefined; // Output of a code generator, by name or header
+}
+
+export interface ExcludedFile {
+  file: string;
+  reason: string;
+}
+
+export interface FileChange {
+  file: string;
+  change: 'added' | 'changed' | 'removed';
+}
+
+export interface RefreshResult {
+  added: number;
+  changed: number;
+  removed: number;
+  skipped: number;
+  excluded: ExcludedFile[];
+  files: number;
+  symbols: number;
+  changes: FileChange[]; // Files whose symbols were added, re-parsed, or dropped
+}
+
+export interface IndexStats {
+  files: number; // Files parsed into the index, excluded ones not counted
+  symbols: number;
+  excluded: number;
+  indexedAt?: string | undefined; // ISO time of the last complete project scan or refresh
+}
+
+export interface SymbolDelta {
+  since?: string | undefined; // The version the delta starts from, as passed in
+  version: string; // Current index version, to pass as since next time
+  full: boolean; // since was missing, unknown, or too old: added holds the whole index
+  added: SymbolEntry[];
+  changed: SymbolEntry[]; // New state of symbols whose declaration changed or moved
+  removed: SymbolEntry[]; // As they were at since
+}
+
+type FileStatus = 'added' | 'changed' | 'skipped' | 'excluded';
+
+export interface ScanOptions extends Omit<WalkOptions, 'cwd'> {
+  only?: string[] | undefined; // Restrict to these files, e.g. a diff's changed set
+  // Per file, as scanned; the scan then hands them over instead of returning them
+  onSymbols?: ((symbols: SymbolEntry[]) => Promise<void>) | undefined;
+  maxFileSizeBytes?: number | undefined; // Larger files are not parsed (default: 2 MB)
+  build?: BuildContext | undefined; // Leave out Go files this target doesn't build
+}
+
+export class SymbolIndexer {
+  private workingDir: string;
+  // Files the last scan or refresh indexed; their symbols stay in the cache
+  private files: string[] = [];
+  private excluded: ExcludedFile[] = [];
+  private interrupted = false; // The last scan ran out of time before every file was loaded
+  private indexedAt: Date | undefined;
+  // Per-file parse results, reused while the file's stat/hash is unchanged
+  private cache = new Map<string, CachedFile>();
+  // Parse results by language and content hash, shared by identical files and
+  // kept after a file changes so reverting it doesn't re-parse
+  private parseCache: LruCache<string, SymbolEntry[]>;
+  // Worker threads that scans parse on; without one, files parse one at a time
+  private parsePool: ParsePool | undefined;
+  // Index version: bumped by every file whose symbols change. Versions carry
+  // an id of this index, so one from another process or project is unknown.
+  private indexId = crypto.randomBytes(4).toString('hex');
+  private version = 0;
+  // Symbols of each changed file before the change, oldest first
+  private history: Array<{ version: number; file: string; before: SymbolEntry[] }> = [];
+
+  constructor(workingDir?: string, cacheSize = DEFAULT_CACHE_SIZE, parsePool?: ParsePool) {
+    this.workingDir = workingDir ?? process.cwd();
+    this.parseCache = new LruCache(cacheSize);
+    this.parsePool = parsePool;
+  }
+
+  /**
+   * Extract symbols from a file or directory (relative to the project root),
+   * optionally limited to a set of files such as those changed in a diff
+   */
+  async scan(target = '.', options: ScanOptions = {}): Promise<SymbolEntry[]> {
+    const { only, maxDepth } = options;
+    const tooDeep: ExcludedFile[] = [];
+    const allFiles = await this.listFiles(target, {
+      ...options,
+      onDepthLimit: dir => tooDeep.push(depthExclusion(dir, maxDepth)),
+    });
+    const files = only ? allFiles.filter(f => only.includes(f)) : allFiles;
+    const symbols: SymbolEntry[] = [];
+    this.files = [];
+    this.excluded = tooDeep;
+    this.interrupted = false;
+
+    for await (const [file, status] of this.loadInOrder(files, options.maxFileSizeBytes)) {
+      if (status === 'excluded') {
+        this.excluded.push(this.exclusion(file));
+        continue;
+      }
+      const constrained = this.buildExclusion(file, options.build);
+      if (constrained) {
+        this.excluded.push(constrained);
+        continue;
+      }
+      const fileSymbols = this.cache.get(file)?.symbols ?? [];
+      this.files.push(file);
+      if (!options.onSymbols) {
+        symbols.push(...fileSymbols);
+      } else if (fileSymbols.length > 0) {
+        await options.onSymbols(fileSymbols);
+      }
+    }
+
+    // A depth-limited or filtered walk misses files that still exist, so the cache keeps them
+    const wholeProject = path.resolve(this.workingDir, target) === path.resolve(this.workingDir);
+    if (wholeProject && walksEverything(options)) {
+      this.pruneCache(allFiles);
+      if (!only && !this.interrupted) this.indexedAt = new Date();
+    }
+
+    return symbols;
+  }
+
+  /**
+   * Re-index the whole project, re-parsing only files whose content changed
+   * since the previous scan
+   */
+  async refresh(options: Omit<ScanOptions, 'only'> = {}): Promise<RefreshResult> {
+    const tooDeep: ExcludedFile[] = [];
+    const files = await this.listFiles('.', {
+      ...options,
+      onDepthLimit: dir => tooDeep.push(depthExclusion(dir, options.maxDepth)),
+    });
+    // Files below maxDepth or outside include/exclude weren't listed, not removed
+    const removed = walksEverything(options) ? this.pruneCache(files) : [];
+    const result: RefreshResult = {
+      added: 0,
+      changed: 0,
+      removed: removed.length,
+      skipped: 0,
+      excluded: tooDeep,
+      files: files.length,
+      symbols: 0,
+      changes: removed.map(file => ({ file, change: 'removed' })),
+    };
+
+    this.files = [];
+    this.interrupted = false;
+    for await (const [file, status] of this.loadInOrder(files, options.maxFileSizeBytes)) {
+      if (status === 'excluded') {
+        result.excluded.push(this.exclusion(file));
+        continue;
+      }
+      result[status]++;
+      if (status !== 'skipped') result.changes.push({ file, change: status });
+      const constrained = this.buildExclusion(file, options.build);
+      if (constrained) {
+        result.excluded.push(constrained);
+        continue;
+      }
+      this.files.push(file);
+      result.symbols += this.cache.get(file)?.symbols.length ?? 0;
+    }
+    this.excluded = result.excluded;
+    if (!this.interrupted && walksEverything(options)) this.indexedAt = new Date();
+
+    return result;
+  }
+
+  /**
+   * Load files in order, with several reading and parsing at once when there
+   * is a parse pool. Stops at the first file the deadline cut off, so results
+   * are always a prefix of the file list. Every load settles by the deadline,
+   * as the pool times out its workers, so this never waits past it.
+   */
+  private async *loadInOrder(
+    files: string[],
+    maxFileSizeBytes: number | undefined
+  ): AsyncGenerator<[string, FileStatus]> {
+    const workers = this.parsePool?.size ?? 0;
+    // Twice the workers keeps each busy while the next file is read
+    const window = workers > 1 ? workers * 2 : 1;
+    const pending: Array<Promise<FileStatus | undefined>> = [];
+    let started = 0;
+
+    try {
+      for (const file of files) {
+        while (pending.length < window) {
+          const next = files[started];
+          if (next === undefined) break;
+          started++;
+          pending.push(this.loadBeforeDeadline(next, maxFileSizeBytes));
+        }
+        const status = await pending.shift();
+        if (status === undefined) return;
+        yield [file, status];
+      }
+    } finally {
+      // Loads started ahead of a stop still settle; nothing waits on them
+      for (const load of pending) load.catch(() => undefined);
+    }
+  }
+
+  /**
+   * Load a file unless the invocation's deadline has passed, before or during
+   * its parse; undefined then, and the scan keeps only what it reached
+   */
+  private async loadBeforeDeadline(
+    file: string,
+    maxFileSizeBytes: number | undefined
+  ): Promise<FileStatus | undefined> {
+    try {
+      checkDeadline();
+      return await this.loadFile(file, maxFileSizeBytes);
+    } catch (error) {
+      if (!(error instanceof TimeoutError)) throw error;
+      this.interrupted = true;
+      return undefined;
+    }
+  }
+
+  /**
+   * Bring the cache entry for a file up to date. A stat match skips the read;
+   * otherwise a hash match skips the parse. Oversized and binary files are
+   * recorded with an exclusion reason instead of being parsed, as are files
+   * their parser fails on.
+   */
+  private async loadFile(
+    file: string,
+    maxFileSizeBytes = DEFAULT_MAX_FILE_SIZE
+  ): Promise<FileStatus> {
+    const cached = this.cache.get(file);
+    const fullPath = path.join(this.workingDir, file);
+
+    try {
+      const stat = await fs.stat(fullPath);
+      if (stat.size > maxFileSizeBytes) {
+        // No hash: once under a raised limit, the file is read again
+        this.store(file, {
+          mtimeMs: stat.mtimeMs,
+          size: stat.size,
+          hash: '',
+          symbols: [],
+          excluded: `too large (${formatBytes(stat.size)} > ${formatBytes(maxFileSizeBytes)})`,
+        });
+        return 'excluded';
+      }
+
+      if (cached?.hash && cached.mtimeMs === stat.mtimeMs && cached.size === stat.size) {
+        return cached.excluded ? 'excluded' : 'skipped';
+      }
+
+      // A file its name doesn't place (README, LICENSE, a script) is read in
+      // full only when its first bytes show a shebang or source we can parse
+      if (!detectLanguageFromName(file)) {
+        const peeked = await detectLanguage(file, undefined, this.workingDir);
+        if (!peeked || !getParser(peeked.language)) {
+          this.forget(file);
+          return 'skipped';
+        }
+      }
+
+      const buffer = await fs.readFile(fullPath);
+      const hash = crypto.createHash('sha1').update(buffer).digest('hex');
+      if (cached?.hash === hash) {
+        this.cache.set(file, { ...cached, mtimeMs: stat.mtimeMs, size: stat.size });
+        return cached.excluded ? 'excluded' : 'skipped';
+      }
+
+      if (isBinary(buffer)) {
+        this.store(file, {
+          mtimeMs: stat.mtimeMs,
+          size: stat.size,
+          hash,
+          symbols: [],
+          excluded: 'binary (contains null bytes)',
+        });
+        return 'excluded';
+      }
+
+      const content = buffer.toString('utf-8');
+      const parser = getParserForFile(file, content);
+      let symbols: SymbolEntry[] = [];
+      if (parser) {
+        try {
+          symbols = await this.parseOnPool(parser, content, file, hash);
+        } catch (error) {
+          if (error instanceof TimeoutError) throw error;
+          // Hashed, so the file isn't retried until it changes
+          this.store(file, {
+            mtimeMs: stat.mtimeMs,
+            size: stat.size,
+            hash,
+            symbols: [],
+            excluded: `parse failed: ${error instanceof Error ? error.message : String(error)}`,
+          });
+          return 'excluded';
+        }
+      }
+      this.store(file, {
+        mtimeMs: stat.mtimeMs,
+        size: stat.size,
+        hash,
+        symbols,
+        buildConstraint: parser?.language === 'go' ? parseBuildConstraint(content) : undefined,
+        generated: isGeneratedFile(file, content),
+      });
+      return cached ? 'changed' : 'added';
+    } catch (error) {
+      if (error instanceof TimeoutError) throw error;
+      // Skip files that can't be read; drop anything they contributed before
+      this.forget(file);
+      return 'skipped';
+    }
+  }
+
+  /**
+   * Replace a file's cache entry, recording the change when its symbols differ
+   */
+  private store(file: string, entry: CachedFile): void {
+    const before = this.cache.get(file)?.symbols ?? [];
+    this.cache.set(file, entry);
+    const changed =
+      before.length !== entry.symbols.length ||
+      (before.length > 0 && JSON.stringify(before) !== JSON.stringify(entry.symbols));
+    if (changed) this.recordChange(file, before);
+  }
+
+  private forget(file: string): void {
+    const before = this.cache.get(file)?.symbols ?? [];
+    this.cache.delete(file);
+    if (before.length > 0) this.recordChange(file, before);
+  }
+
+  private recordChange(file: string, before: SymbolEntry[]): void {
+    this.version++;
+    this.history.push({ version: this.version, file, before });
+    if (this.history.length > DELTA_HISTORY) {
+      this.history.splice(0, this.history.length - DELTA_HISTORY);
+    }
+  }
+
+  private exclusion(file: string): ExcludedFile {
+    return { file, reason: this.cache.get(file)?.excluded ?? 'excluded' };
+  }
+
+  /**
+   * A Go file the build target leaves out, from the constraint cached at parse time
+   */
+  private buildExclusion(file: string, build: BuildContext | undefined): ExcludedFile | undefined {
+    const cached = this.cache.get(file);
+    if (!build || !cached || !file.endsWith('.go')) return undefined;
+    const reason = buildExclusionReason(file, cached.buildConstraint, build);
+    return reason ? { file, reason } : undefined;
+  }
+
+  /**
+   * Drop cache entries for files that no longer exist, returning their paths
+   */
+  private pruneCache(files: string[]): string[] {
+    const current = new Set(files);
+    const removed: string[] = [];
+    for (const file of this.cache.keys()) {
+      if (!current.has(file)) {
+        this.forget(file);
+        removed.push(file);
+      }
+    }
+    return removed;
+  }
+
+  /**
+   * Extract symbols from source text, using the language if given or the file extension
+   */
+  extractSource(content: string, file: string, language?: string): SymbolEntry[] {
+    const parser = language ? getParser(language) : getParserForFile(file, content);
+    if (!parser) {
+      throw new ToolError(
+        'UNSUPPORTED_LANGUAGE',
+        `Unsupported language: ${language ?? path.extname(file)}`,
+        file
+      );
+    }
+    return this.parseWith(parser, content, file);
+  }
+
+  /**
+   * Parse through the content-hash cache. Cached symbols of an identical file
+   * elsewhere are returned with this file's path.
+   */
+  parseWith(parser: SymbolParser, content: string, file: string, hash?: string): SymbolEntry[] {
+    const contentHash = hash ?? crypto.createHash('sha1').update(content).digest('hex');
+    const key = `${parser.language}:${contentHash}`;
+    const cached = this.cachedParse(key, file);
+    if (cached) return cached;
+
+    let symbols: SymbolEntry[];
+    try {
+      symbols = parseSymbols(parser, content, file);
+    } catch (error) {
+      if (error instanceof TimeoutError) throw error;
+      const message = error instanceof Error ? error.message : String(error);
+      throw new ToolError('PARSE_ERROR', message, file);
+    }
+    this.parseCache.set(key, symbols);
+    return symbols;
+  }
+
+  /**
+   * parseWith, but on a worker thread when there is a parse pool
+   */
+  private async parseOnPool(
+    parser: SymbolParser,
+    content: string,
+    file: string,
+    hash: string
+  ): Promise<SymbolEntry[]> {
+    if (!this.parsePool) return this.parseWith(parser, content, file, hash);
+
+    const key = `${parser.language}:${hash}`;
+    const cached = this.cachedParse(key, file);
+    if (cached) return cached;
+
+    const symbols = await this.parsePool.parse(parser, content, file);
+    this.parseCache.set(key, symbols);
+    return symbols;
+  }
+
+  /**
+   * Cached symbols of an identical file, given this file's path
+   */
+  private cachedParse(key: string, file: string): SymbolEntry[] | undefined {
+    return this.parseCache.get(key)?.map(s => (s.file === file ? s : { ...s, file }));
+  }
+
+  getCacheStats(): CacheStats {
+    return this.parseCache.stats();
+  }
+
+  /**
+   * Size of the index as of the files loaded so far, whichever scans loaded them
+   */
+  getIndexStats(): IndexStats {
+    let files = 0;
+    let symbols = 0;
+    let excluded = 0;
+    for (const cached of this.cache.values()) {
+      if (cached.excluded) {
+        excluded++;
+      } else {
+        files++;
+        symbols += cached.symbols.length;
+      }
+    }
+    return { files, symbols, excluded, indexedAt: this.indexedAt?.toISOString() };
+  }
+
+  /**
+   * Hash of every indexed file's content hash; changes whenever a scan or
+   * refresh picks up an edited, added, or removed file
+   */
+  fingerprint(): string {
+    const entries = [...this.cache.entries()]
+      .map(([file, cached]) => `${file}:${cached.hash}`)
+      .sort();
+    return crypto.createHash('sha1').update(entries.join('\n')).digest('hex').slice(0, 16);
+  }
+
+  /**
+   * Opaque version of the index as loaded so far, e.g. "3f9a1c2e.42"
+   */
+  getVersion(): string {
+    return `${this.indexId}.${this.version}`;
+  }
+
+  /**
+   * Symbols added, changed, and removed since an earlier version of the
+   * index. Without since, or when the changes since it are no longer all
+   * remembered, the whole index is returned as added.
+   */
+  delta(since?: string): SymbolDelta {
+    const version = this.getVersion();
+    const [id, count] = since?.split('.') ?? [];
+    const base = id === this.indexId && /^\d+$/.test(count ?? '') ? Number(count) : undefined;
+    const oldest = this.history[0]?.version ?? this.version + 1;
+    if (base === undefined || base > this.version || oldest > base + 1) {
+      const added = [...this.cache.values()].flatMap(cached => cached.symbols);
+      return { since, version, full: true, added, changed: [], removed: [] };
+    }
+
+    // Each file's symbols as of base: from before its first change since then
+    const before = new Map<string, SymbolEntry[]>();
+    for (const entry of this.history) {
+      if (entry.version > base && !before.has(entry.file)) before.set(entry.file, entry.before);
+    }
+
+    const delta: SymbolDelta = { since, version, full: false, added: [], changed: [], removed: [] };
+    for (const [file, old] of before) {
+      const previous = keyedSymbols(old);
+      const current = keyedSymbols(this.cache.get(file)?.symbols ?? []);
+      for (const [key, symbol] of current) {
+        const was = previous.get(key);
+        if (!was) delta.added.push(symbol);
+        else if (JSON.stringify(was) !== JSON.stringify(symbol)) delta.changed.push(symbol);
+      }
+      for (const [key, symbol] of previous) {
+        if (!current.has(key)) delta.removed.push(symbol);
+      }
+    }
+    return delta;
+  }
+
+  /**
+   * List parseable source files under a file or directory, skipping paths
+   * matched by .gitignore and the default ignore list unless includeIgnored
+   */
+  async listFiles(target = '.', options: Omit<WalkOptions, 'cwd'> = {}): Promise<string[]> {
+    const relativeTarget = resolveProjectPath(this.workingDir, target);
+    const fullTarget = path.join(this.workingDir, relativeTarget);
+
+    const stat = await fs.stat(fullTarget);
+    if (stat.isFile()) {
+      return [relativeTarget];
+    }
+
+    // Extensionless files (scripts, Makefiles) are routed by name or shebang on
+    // load, which reads only the first few KB of those that are neither
+    return walkFiles(
+      this.workingDir,
+      [...getSupportedExtensions().map(ext => `**/*${ext}`), '**/!(*.*)'],
+      { ...options, cwd: relativeTarget }
+    );
+  }
+
+  /**
+   * Write the last scan to .engineering/index/symbols.yaml. A scan cut short
+   * by its deadline is partial, so the previous index is kept instead.
+   */
+  async saveIndex(): Promise<string> {
+    const indexPath = path.join(this.workingDir, '.engineering', 'index', 'symbols.yaml');
+    if (this.interrupted) {
+      return indexPath;
+    }
+    await fs.mkdir(path.dirname(indexPath), { recursive: true });
+
+    const index = { symbols: this.getSymbols(), excluded: this.excluded };
+    const content = stringify(index, { indent: 2 });
+    await fs.writeFile(indexPath, content, 'utf-8');
+
+    return indexPath;
+  }
+
+  getSymbols(): SymbolEntry[] {
+    return this.files.flatMap(file => this.cache.get(file)?.symbols ?? []);
+  }
+
+  /**
+   * Copies of the symbols with git blame for their definition lines. Symbols
+   * in files git can't blame, or on uncommitted lines, are returned as-is.
+   */
+  async withBlame(symbols: SymbolEntry[]): Promise<SymbolEntry[]> {
+    const blameByFile = new Map<string, Awaited<ReturnType<typeof getBlame>>>();
+    const result: SymbolEntry[] = [];
+
+    for (const symbol of symbols) {
+      if (!blameByFile.has(symbol.file)) {
+        blameByFile.set(symbol.file, await getBlame(this.workingDir, symbol.file));
+      }
+      const blame = blameByFile.get(symbol.file)?.get(symbol.line);
+      result.push(blame ? { ...symbol, blame } : symbol);
+    }
+
+    return result;
+  }
+
+  /**
+   * Whether a loaded file is generated code (see isGeneratedFile). Generated
+   * files stay in the index; tools that show hand-written code filter them.
+   */
+  isGenerated(file: string): boolean {
+    return this.cache.get(file)?.generated === true;
+  }
+
+  /**
+   * Files the last scan or refresh found but did not parse, with reasons
+   */
+  getExcluded(): ExcludedFile[] {
+    return this.excluded;
+  }
+
+  /**
+   * Symbols grouped by file; signaturesOnly lists each declaration line instead
+   * of kind and name. Flat keeps the given order, one file:line per symbol, for
+   * sorted results that grouping would scramble.
+   */
+  formatSymbols(symbols: SymbolEntry[], signaturesOnly = false, flat = false): string {
+    if (symbols.length === 0) {
+      return 'No symbols found.';
+    }
+
+    if (flat) {
+      let output = `Found ${symbols.length} symbol(s):\n\n`;
+      for (const s of symbols) {
+        const location = `${s.file}:${s.line}`;
+        const marker = s.exported ? '' : ' (unexported)';
+        output += signaturesOnly
+          ? `  ${location}  ${s.signature}`
+          : `  ${s.kind.padEnd(9)} ${qualifiedName(s)}  ${location}${marker}`;
+        output += `${formatComplexity(s)}${formatBlame(s)}\n`;
+      }
+      return output.trimEnd();
+    }
+
+    const byFile = new Map<string, SymbolEntry[]>();
+    for (const symbol of symbols) {
+      const existing = byFile.get(symbol.file) ?? [];
+      existing.push(symbol);
+      byFile.set(symbol.file, existing);
+    }
+
+    let output = `Found ${symbols.length} symbol(s) in ${byFile.size} file(s):\n\n`;
+
+    for (const [file, fileSymbols] of byFile) {
+      output += `${file}:\n`;
+      for (const s of fileSymbols) {
+        if (signaturesOnly) {
+          output += `  ${String(s.line).padStart(4)}  ${s.signature}`;
+          output += `${formatComplexity(s)}${formatBlame(s)}\n`;
+          continue;
+        }
+        const marker = s.exported ? '' : ' (unexported)';
+        output += `  ${s.kind.padEnd(9)} ${qualifiedName(s)} :${s.line}${marker}`;
+        output += `${formatComplexity(s)}${formatBlame(s)}\n`;
+      }
+      output += '\n';
+    }
+
+    return output.trimEnd();
+  }
+
+  getWorkingDir(): string {
+    return this.workingDir;
+  }
+
+  formatRefresh(result: RefreshResult): string {
+    let output = `Index refreshed: ${result.files} file(s), ${result.symbols} symbol(s)\n\n`;
+    output += `  Added:   ${result.added}\n`;
+    output += `  Changed: ${result.changed}\n`;
+    output += `  Removed: ${result.removed}\n`;
+    output += `  Skipped: ${result.skipped} (unchanged)`;
+    if (result.excluded.length > 0) {
+      output += `\n  Excluded: ${result.excluded.length}\n\n`;
+      output += this.formatExcluded(result.excluded);
+    }
+    return output;
+  }
+
+  formatCacheStats(stats: CacheStats): string {
+    const lookups = stats.hits + stats.misses;
+    const hitRate = lookups > 0 ? ` (${((stats.hits / lookups) * 100).toFixed(1)}% hit rate)` : '';
+
+    let output = `Parse cache: ${stats.size} of ${stats.maxEntries} entries\n\n`;
+    output += `  Hits:      ${stats.hits}${hitRate}\n`;
+    output += `  Misses:    ${stats.misses}\n`;
+    output += `  Evictions: ${stats.evictions}\n`;
+    return output;
+  }
+
+  formatExcluded(excluded: ExcludedFile[]): string {
+    let output = `Not parsed (${excluded.length}):\n`;
+    for (const { file, reason } of excluded) {
+      output += `  ${file}: ${reason}\n`;
+    }
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.workingDir = dir;
+    this.files = [];
+    this.cache.clear();
+    // Versions of the old project's index mean nothing for the new one
+    this.indexId = crypto.randomBytes(4).toString('hex');
+    this.version = 0;
+    this.history = [];
+  }
+
+  formatDelta(delta: SymbolDelta): string {
+    if (delta.full) {
+      return `Index version ${delta.version}: full index, ${delta.added.length} symbol(s)`;
+    }
+
+    let output = `Index version ${delta.version} (since ${delta.since ?? ''}): `;
+    output += `${delta.added.length} added, ${delta.changed.length} changed, `;
+    output += `${delta.removed.length} removed`;
+
+    const sections: Array<[string, SymbolEntry[]]> = [
+      ['+', delta.added],
+      ['~', delta.changed],
+      ['-', delta.removed],
+    ];
+    for (const [mark, symbols] of sections) {
+      for (const s of symbols) {
+        output += `\n  ${mark} ${s.kind.padEnd(9)} ${qualifiedName(s)}  ${s.file}:${s.line}`;
+      }
+    }
+    return output;
+  }
+}
+
+// Whether a walk with these options lists every file it would without them
+function walksEverything(options: Omit<WalkOptions, 'cwd'>): boolean {
+  return (
+    options.maxDepth === undefined &&
+    !options.include?.length &&
+    !options.exclude?.length &&
+    !options.ignore?.length
+  );
+}
+
+// A directory the walker stopped above, reported like an excluded file
+function depthExclusion(dir: string, maxDepth: number | undefined): ExcludedFile {
+  return { file: `${dir}/`, reason: `deeper than maxDepth ${maxDepth ?? 0}, not walked` };
+}
+
+/**
+ * Symbols by kind and qualified name; overloads and redeclarations are told
+ * apart by their order in the file
+ */
+function keyedSymbols(symbols: SymbolEntry[]): Map<string, SymbolEntry> {
+  const keyed = new Map<string, SymbolEntry>();
+  const seen = new Map<string, number>();
+  for (const symbol of symbols) {
+    const base = `${symbol.kind}:${qualifiedName(symbol)}`;
+    const n = seen.get(base) ?? 0;
+    seen.set(base, n + 1);
+    keyed.set(n === 0 ? base : `${base}#${n}`, symbol);
+  }
+  return keyed;
+}
+
+/**
+ * " [complexity 7]" when the symbol carries its complexity, else ""
+ */
+export function formatComplexity(symbol: SymbolEntry): string {
+  return symbol.complexity === undefined ? '' : ` [complexity ${symbol.complexity}]`;
+}
+
+/**
+ * " - alice, 2024-05-01 (1a2b3c4)" when the symbol carries blame, else ""
+ */
+export function formatBlame(symbol: SymbolEntry): string {
+  if (!symbol.blame) return '';
+  const { author, date, commit } = symbol.blame;
+  return ` - ${author}, ${date.slice(0, 10)} (${commit.slice(0, 7)})`;
+}
+
+/**
+ * Declaration-only view of a symbol: no end line, visibility, or decorators,
+ * and the doc comment only if includeDocs
+ */
+export function toSignature(symbol: SymbolEntry, includeDocs = true): SymbolSignature {
+  const { name, kind, file, line, signature, parent, doc, complexity, blame } = symbol;
+  const entry: SymbolSignature = { name, kind, file, line, signature };
+  if (parent) entry.parent = parent;
+  if (doc && includeDocs) entry.doc = doc;
+  if (complexity !== undefined) entry.complexity = complexity;
+  if (blame) entry.blame = blame;
+  return entry;
+}
+
+/**
+ * Whether a symbol carries a decorator or annotation, matched by name with
+ * or without @ and ignoring arguments: Override, @GetMapping, app.route
+ */
+export function hasDecorator(symbol: SymbolEntry, decorator: string): boolean {
+  const wanted = decorator.replace(/^@/, '');
+  return (symbol.decorators ?? []).some(d => {
+    const name = d.replace(/^@/, '').replace(/\(.*$/s, '').trim();
+    return name === wanted || name.split('.').pop() === wanted;
+  });
+}
+
+// How much of each doc comment results carry
+export const DOC_MODES = ['summary', 'full', 'none'] as const;
+
+export type DocMode = (typeof DOC_MODES)[number];
+
+type Documented = { doc?: string | undefined };
+
+/**
+ * Mapper that trims doc comments for a docMode: summary keeps the leading
+ * sentence, none drops the doc. Undefined for full, which leaves symbols as
+ * they are; throws on a mode that isn't one of DOC_MODES.
+ */
+export function docTrimmer(
+  mode: string | undefined
+): (<T extends Documented>(symbol: T) => T) | undefined {
+  if (mode === undefined || mode === 'full') return undefined;
+  if (mode !== 'summary' && mode !== 'none') {
+    throw new ToolError(
+      'INVALID_ARGUMENT',
+      `Unknown docMode: ${mode}. Valid modes: ${DOC_MODES.join(', ')}`
+    );
+  }
+  return symbol => {
+    if (symbol.doc === undefined) return symbol;
+    const trimmed = { ...symbol };
+    if (mode === 'none') delete trimmed.doc;
+    else trimmed.doc = docSummary(symbol.doc);
+    return trimmed;
+  };
+}
+
+// Keys symbol results can be sorted on
+export const SORT_KEYS = ['name', 'line', 'kind', 'complexity'] as const;
+
+export const SORT_ORDERS = ['asc', 'desc'] as const;
+
+/**
+ * Comparator for a sortBy key and order (default asc), undefined without a
+ * key. line compares the file first; symbols without a complexity go last
+ * either way. Array sort is stable, so equal keys keep the order they had.
+ * Throws on an unknown key or order, or complexity that wasn't computed.
+ */
+export function symbolSorter(
+  sortBy: string | undefined,
+  order: string | undefined,
+  withComplexity = false
+): ((a: SymbolEntry, b: SymbolEntry) => number) | undefined {
+  const orders: readonly string[] = SORT_ORDERS;
+  if (order !== undefined && !orders.includes(order)) {
+    throw new ToolError(
+      'INVALID_ARGUMENT',
+      `Unknown order: ${order}. Valid orders: ${SORT_ORDERS.join(', ')}`
+    );
+  }
+  if (sortBy === undefined) return undefined;
+  const direction = order === 'desc' ? -1 : 1;
+
+  switch (sortBy) {
+    case 'name':
+      return (a, b) => direction * a.name.localeCompare(b.name);
+    case 'line':
+      return (a, b) => direction * (a.file.localeCompare(b.file) || a.line - b.line);
+    case 'kind':
+      return (a, b) => direction * a.kind.localeCompare(b.kind);
+    case 'complexity':
+      if (!withComplexity) {
+        throw new ToolError('INVALID_ARGUMENT', 'sortBy complexity requires includeComplexity');
+      }
+      return (a, b) => {
+        if (a.complexity === undefined || b.complexity === undefined) {
+          return Number(a.complexity === undefined) - Number(b.complexity === undefined);
+        }
+        return direction * (a.complexity - b.complexity);
+      };
+    default:
+      throw new ToolError(
+        'INVALID_ARGUMENT',
+        `Unknown sortBy: ${sortBy}. Valid keys: ${SORT_KEYS.join(', ')}`
+      );
+  }
+}
+
+/**
+ * First sentence of a doc comment's first paragraph, with its line breaks
+ * joined, as go/doc takes a synopsis: "Add sums two numbers." of a longer doc.
+ * A period after an initial or in e.g./i.e. doesn't end the sentence.
+ */
+export function docSummary(doc: string): string {
+  const paragraph = (doc.trim().split(/\n\s*\n/)[0] ?? '').replace(/\s*\n\s*/g, ' ');
+  const end = /(?<!\b(?:[A-Z]|e\.g|i\.e))[.!?](?=\s|$)/.exec(paragraph);
+  return end ? paragraph.slice(0, end.index + 1) : paragraph;
+}
+
+// Kinds that declare a type; filtering by "type" matches all of them
+const TYPE_KINDS = new Set<string>(['class', 'struct', 'interface', 'type', 'enum']);
+
+/**
+ * Predicate for a kinds filter: a symbol passes if it matches any of the
+ * kinds, and "type" matches every type declaration. Undefined when no kinds
+ * are given; throws on a kind that isn't in the enum.
+ */
+export function kindFilter(
+  kinds: string[] | undefined
+): ((symbol: SymbolEntry) => boolean) | undefined {
+  if (!kinds || kinds.length === 0) return undefined;
+  const valid: readonly string[] = SymbolKindSchema.options;
+  const unknown = kinds.filter(k => !valid.includes(k));
+  if (unknown.length > 0) {
+    throw new ToolError(
+      'INVALID_ARGUMENT',
+      `Unknown kind(s): ${unknown.join(', ')}. Valid kinds: ${valid.join(', ')}`
+    );
+  }
+  const wanted = new Set(kinds);
+  return symbol => wanted.has(symbol.kind) || (wanted.has('type') && TYPE_KINDS.has(symbol.kind));
+}
+
+/**
+ * Display name including the enclosing type, e.g. Calculator.Add
+ */
+export function qualifiedName(symbol: SymbolEntry): string {
+  return symbol.parent ? `${symbol.parent}.${symbol.name}` : symbol.name;
+}
+
+export function formatBytes(bytes: number): string {
+  if (bytes < 1024) return `${bytes} B`;
+  if (bytes < 1024 * 1024) return `${(bytes / 1024).toFixed(1)} KB`;
+  return `${(bytes / (1024 * 1024)).toFixed(1)} MB`;
+}
@@ -0,0 +1,135 @@
+/**
+ * Symbol Search
+ * Fuzzy matching of symbol names, ranked exact > prefix > substring > subsequence
+ */
+
+import type { SymbolEntry } from '../types/index.js';
+import { formatBlame, formatComplexity, qualifiedName } from './symbol-indexer.js';
+
+export type MatchType = 'exact' | 'prefix' | 'word' | 'substring' | 'subsequence';
+
+export interface SymbolMatch {
+  symbol: SymbolEntry;
+  match: MatchType;
+  score: number;
+}
+
+const MATCH_SCORES: Record<MatchType, number> = {
+  exact: 1000,
+  prefix: 800,
+  word: 600, // Prefix of an inner camelCase/snake_case word: "calc" in NewCalculator
+  substring: 400,
+  subsequence: 200,
+};
+
+export const DEFAULT_SEARCH_LIMIT = 20;
+
+export function searchSymbols(
+  symbols: SymbolEntry[],
+  query: string,
+  limit = DEFAULT_SEARCH_LIMIT
+): SymbolMatch[] {
+  const needle = query.trim();
+  if (needle === '') {
+    return [];
+  }
+
+  const matches: SymbolMatch[] = [];
+  for (const symbol of symbols) {
+    // Qualified queries ("Calculator.a") match against Parent.Name
+    const candidate = needle.includes('.') ? qualifiedName(symbol) : symbol.name;
+    const result = fuzzyMatch(needle, candidate);
+    if (result) {
+      matches.push({ symbol, ...result });
+    }
+  }
+
+  return rankMatches(matches, limit);
+}
+
+/**
+ * The best matches first, at most limit of them. Ranking each batch of a
+ * search and then the kept matches together ranks the same as all at once.
+ */
+export function rankMatches(matches: SymbolMatch[], limit = DEFAULT_SEARCH_LIMIT): SymbolMatch[] {
+  return matches
+    .sort(
+      (a, b) =>
+        b.score - a.score ||
+        a.symbol.name.length - b.symbol.name.length ||
+        a.symbol.name.localeCompare(b.symbol.name) ||
+        a.symbol.file.localeCompare(b.symbol.file)
+    )
+    .slice(0, Math.max(0, limit));
+}
+
+/**
+ * Score how well a query matches a name, or null when it doesn't match at all
+ */
+export function fuzzyMatch(
+  query: string,
+  name: string
+): { match: MatchType; score: number } | null {
+  const q = query.toLowerCase();
+  const n = name.toLowerCase();
+  // Small bonus when the case matches exactly
+  const caseBonus = (matched: boolean): number => (matched ? 50 : 0);
+
+  if (n === q) {
+    return { match: 'exact', score: MATCH_SCORES.exact + caseBonus(name === query) };
+  }
+  if (n.startsWith(q)) {
+    return { match: 'prefix', score: MATCH_SCORES.prefix + caseBonus(name.startsWith(query)) };
+  }
+
+  const index = n.indexOf(q);
+  if (index !== -1) {
+    const atWord = wordStarts(name).includes(index);
+    return {
+      match: atWord ? 'word' : 'substring',
+      score: (atWord ? MATCH_SCORES.word : MATCH_SCORES.substring) - index,
+    };
+  }
+
+  // Subsequence: every query character in order; tighter matches score higher
+  let position = -1;
+  let gaps = 0;
+  for (const ch of q) {
+    const next = n.indexOf(ch, position + 1);
+    if (next === -1) return null;
+    if (position !== -1) gaps += next - position - 1;
+    position = next;
+  }
+
+  return { match: 'subsequence', score: MATCH_SCORES.subsequence - Math.min(gaps, 150) };
+}
+
+/**
+ * Offsets where a new word begins: after _ . or -, or at a lower-to-upper case change
+ */
+function wordStarts(name: string): number[] {
+  const starts: number[] = [];
+  for (let i = 1; i < name.length; i++) {
+    const prev = name[i - 1] ?? '';
+    const ch = name[i] ?? '';
+    if (/[_.\-]/.test(prev) || (/[a-z0-9]/.test(prev) && /[A-Z]/.test(ch))) {
+      starts.push(i);
+    }
+  }
+  return starts;
+}
+
+export function formatMatches(query: string, matches: SymbolMatch[]): string {
+  if (matches.length === 0) {
+    return `No symbols matching "${query}".`;
+  }
+
+  let output = `Found ${matches.length} symbol(s) matching "${query}":\n\n`;
+  for (const { symbol, match } of matches) {
+    const file = symbol.root ? `[${symbol.root}] ${symbol.file}` : symbol.file;
+    output += `  ${symbol.kind.padEnd(9)} ${qualifiedName(symbol)}  ${file}:${symbol.line}`;
+    output += ` (${match})${formatComplexity(symbol)}${formatBlame(symbol)}\n`;
+  }
+
+  return output.trimEnd();
+}
@@ -0,0 +1,379 @@
+/**
+ * Test Catalog
+ * Lists the tests Go _test.go files define without running them: Test,
+ * Benchmark, Fuzz, and Example functions, and the subtests they start with
+ * t.Run, expanded from their case tables when the names are literals
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { SymbolEntry } from '../types/index.js';
+import type { ScopeOptions } from '../core/file-walker.js';
+import { goCallArguments, goStringValue } from '../parsers/go-parser.js';
+import { GO_SYNTAX, SourceText } from '../parsers/source.js';
+import { SymbolIndexer } from './symbol-indexer.js';
+
+export type GoTestKind = 'test' | 'benchmark' | 'fuzz' | 'example';
+
+export interface GoSubtest {
+  name: string; // As written; the expression itself when dynamic
+  fullName: string; // What go test -run matches: TestParse/empty_input
+  line: number; // The name literal: the t.Run call, or the case in its table
+  dynamic?: boolean | undefined; // The name isn't known without running the test
+  subtests?: GoSubtest[] | undefined;
+}
+
+export interface GoTestFunction {
+  name: string;
+  kind: GoTestKind;
+  file: string;
+  line: number;
+  endLine: number;
+  parent?: string | undefined; // Suite type for testify-style suite methods
+  subtests: GoSubtest[];
+}
+
+export interface TestCatalogReport {
+  files: number; // Test files with at least one test
+  tests: number;
+  subtests: number; // Every known subtest, nested ones included; dynamic ones excluded
+  functions: GoTestFunction[];
+}
+
+// Test, Benchmark, Fuzz, or Example followed by anything but a lower-case letter
+const TEST_NAME = /^(Test|Benchmark|Fuzz|Example)(?![a-z])/;
+
+const TEST_KINDS: Record<string, GoTestKind> = {
+  Test: 'test',
+  Benchmark: 'benchmark',
+  Fuzz: 'fuzz',
+  Example: 'example',
+};
+
+// Parameters that can start a subtest: t *testing.T, b *testing.B
+const RUNNER_PARAM = /([A-Za-z_]\w*)\s+\*\s*testing\.[TB]\b/g;
+
+const RUN_CALL = /(?<![\w.])([A-Za-z_]\w*)\s*\.\s*Run\s*\(/g;
+
+// for _, tt := range tests, for name, tc := range cases
+const RANGE_LOOP =
+  /\bfor\s+([A-Za-z_]\w*)\s*(?:,\s*([A-Za-z_]\w*)\s*)?:=\s*range\s+([A-Za-z_][\w.]*)/g;
+
+interface RunCall {
+  open: number;
+  close: number;
+  subtests: GoSubtest[]; // Names this call produces, before nesting
+}
+
+export class TestCatalog {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  async list(target = '.', scope: ScopeOptions = {}): Promise<TestCatalogReport> {
+    const tests = (await this.symbolIndexer.scan(target, scope)).filter(isTestFunction);
+    const functions: GoTestFunction[] = [];
+
+    for (const file of new Set(tests.map(s => s.file))) {
+      let source: SourceText;
+      try {
+        const content = await fs.readFile(
+          path.join(this.symbolIndexer.getWorkingDir(), file),
+          'utf-8'
+        );
+        source = new SourceText(content, GO_SYNTAX);
+      } catch {
+        // Skip files that can't be read
+        continue;
+      }
+
+      for (const symbol of tests.filter(s => s.file === file)) {
+        const test: GoTestFunction = {
+          name: symbol.name,
+          kind: TEST_KINDS[TEST_NAME.exec(symbol.name)?.[1] ?? ''] ?? 'test',
+          file,
+          line: symbol.line,
+          endLine: symbol.endLine,
+          subtests: subtests(source, symbol),
+        };
+        if (symbol.parent) test.parent = symbol.parent;
+        functions.push(test);
+      }
+    }
+
+    functions.sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+    return {
+      files: new Set(functions.map(f => f.file)).size,
+      tests: functions.length,
+      subtests: countSubtests(functions.flatMap(f => f.subtests)),
+      functions,
+    };
+  }
+
+  formatReport(report: TestCatalogReport): string {
+    if (report.tests === 0) {
+      return 'No Go tests found.';
+    }
+
+    let output = `${report.tests} test(s) and ${report.subtests} subtest(s)`;
+    output += ` in ${report.files} file(s):\n`;
+    let currentFile = '';
+    for (const test of report.functions) {
+      if (test.file !== currentFile) {
+        output += `\n${test.file}:\n`;
+        currentFile = test.file;
+      }
+      const name = test.parent ? `${test.parent}.${test.name}` : test.name;
+      const kind = test.kind === 'test' ? '' : ` (${test.kind})`;
+      output += `  ${String(test.line).padStart(4)}  ${name}${kind}\n`;
+      output += formatSubtests(test.subtests, 2);
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+function isTestFunction(symbol: SymbolEntry): boolean {
+  if (symbol.language !== 'go' || !symbol.file.endsWith('_test.go')) return false;
+  if (symbol.name === 'TestMain' && !symbol.parent) return false;
+  // Suite methods hang off a type; plain tests are functions
+  return (
+    (symbol.kind === 'function' || (symbol.kind === 'method' && symbol.name.startsWith('Test'))) &&
+    TEST_NAME.test(symbol.name)
+  );
+}
+
+/**
+ * Subtests a test function starts, nested by the t.Run callbacks they sit in
+ */
+function subtests(source: SourceText, symbol: SymbolEntry): GoSubtest[] {
+  const start = source.lineStart(symbol.line);
+  const end = source.lineStart(symbol.endLine + 1);
+  const body = source.masked.slice(start, end);
+
+  // t, b, and any callback parameters; a suite method's receiver runs s.Run
+  const runners = new Set([...body.matchAll(RUNNER_PARAM)].map(m => m[1] ?? ''));
+  const receiver = symbol.parent && /^func\s*\(\s*([A-Za-z_]\w*)/.exec(body)?.[1];
+  if (receiver) runners.add(receiver);
+
+  const calls: RunCall[] = [];
+  RUN_CALL.lastIndex = 0;
+  let match;
+  while ((match = RUN_CALL.exec(body)) !== null) {
+    if (!runners.has(match[1] ?? '')) continue;
+    const open = start + match.index + match[0].length - 1;
+    const close = source.findMatching(open);
+    const [nameArg] = close === -1 ? [] : goCallArguments(source, open, close);
+    if (!nameArg) continue;
+    calls.push({ open, close, subtests: namesOf(source, nameArg, start, end) });
+  }
+
+  return nest(calls, symbol.name);
+}
+
+/**
+ * Names a t.Run name argument stands for: a literal, or the cases of the
+ * table a range loop takes it from (tt.name, or the key of a map table)
+ */
+function namesOf(
+  source: SourceText,
+  arg: { text: string; start: number },
+  bodyStart: number,
+  bodyEnd: number
+): GoSubtest[] {
+  const line = source.lineOf(arg.start);
+  const literal = goStringValue(arg);
+  if (literal !== undefined) return [{ name: literal, fullName: literal, line }];
+
+  const dynamic = [{ name: arg.text, fullName: arg.text, line, dynamic: true }];
+  const field = /^([A-Za-z_]\w*)(?:\.([A-Za-z_]\w*))?$/.exec(arg.text);
+  if (!field?.[1]) return dynamic;
+  const [, variable, fieldName] = field;
+
+  // for _, tt := range tests { ... } or for name, tc := range cases { ... }
+  const before = source.masked.slice(bodyStart, arg.start);
+  const loop = [...before.matchAll(RANGE_LOOP)]
+    .reverse()
+    .find(m => (fieldName ? (m[2] ?? m[1]) === variable : m[1] === variable && m[2]));
+  const tableName = loop?.[3];
+  if (!tableName) return dynamic;
+
+  const table =
+    findTable(source, tableName, bodyStart, bodyEnd) ??
+    findTable(source, tableName, 0, source.masked.length);
+  if (!table) return dynamic;
+
+  const cases = fieldName
+    ? table.elements.map(e => caseField(source, e.value, fieldName, table.fields))
+    : table.elements.map(e => e.key);
+  const names = cases.flatMap(c => {
+    const value = c && goStringValue(c);
+    return c && value !== undefined
+      ? [{ name: value, fullName: value, line: source.lineOf(c.start) }]
+      : [];
+  });
+  return names.length === cases.length && names.length > 0 ? names : dynamic;
+}
+
+interface TableElement {
+  key?: { text: string; start: number } | undefined; // Map tables
+  value: { start: number; end: number }; // The element's braces
+}
+
+/**
+ * The composite literal assigned to a table variable: tests := []struct{...}{...},
+ * var cases = map[string]testCase{...}. Fields are the inline struct's, in order.
+ */
+function findTable(
+  source: SourceText,
+  name: string,
+  from: number,
+  to: number
+): { elements: TableElement[]; fields: string[] } | undefined {
+  const text = source.masked;
+  const escaped = name.replace(/\./g, '\\.');
+  const pattern = new RegExp(`(?<![\\w.])${escaped}\\s*(?::=|=)\\s*`, 'g');
+  pattern.lastIndex = from;
+  const assignment = pattern.exec(text);
+  if (!assignment || assignment.index >= to) return undefined;
+
+  // Skip the type: []T, map[K]T, []struct{...}, map[string]struct{...}
+  let i = assignment.index + assignment[0].length;
+  const prefix = /^(?:\[\]|map\s*\[[^\]]*\])\s*\*?\s*/.exec(text.slice(i));
+  if (!prefix) return undefined;
+  i += prefix[0].length;
+  const isMap = prefix[0].startsWith('map');
+
+  let fields: string[] = [];
+  const inline = /^struct\s*\{/.exec(text.slice(i));
+  if (inline) {
+    const structOpen = i + inline[0].length - 1;
+    const structClose = source.findMatching(structOpen);
+    if (structClose === -1) return undefined;
+    fields = structFieldNames(text.slice(structOpen + 1, structClose));
+    i = structClose + 1;
+  } else {
+    const type = /^[A-Za-z_][\w.]*\s*/.exec(text.slice(i));
+    if (!type) return undefined;
+    i += type[0].length;
+  }
+  if (text[i] !== '{') return undefined;
+  const close = source.findMatching(i);
+  if (close === -1) return undefined;
+
+  const elements: TableElement[] = [];
+  for (const element of goCallArguments(source, i, close)) {
+    const key = isMap ? /^((?:"(?:[^"\\]|\\.)*"|`[^`]*`))\s*:/.exec(element.text) : null;
+    const open = text.indexOf('{', element.start);
+    const end = open === -1 || open >= element.end ? -1 : source.findMatching(open);
+    if (end === -1) continue;
+    elements.push({
+      key: key?.[1] ? { text: key[1], start: codeStart(source, element.start) } : undefined,
+      value: { start: open, end },
+    });
+  }
+  return { elements, fields };
+}
+
+/**
+ * The field of a case literal: keyed (name: "x"), or by position in the
+ * table's inline struct
+ */
+function caseField(
+  source: SourceText,
+  value: { start: number; end: number },
+  field: string,
+  fields: string[]
+): { text: string; start: number } | undefined {
+  const values = goCallArguments(source, value.start, value.end);
+  const keyed = values.find(v => new RegExp(`^${field}\\s*:`).test(v.text));
+  if (keyed) {
+    const text = keyed.text.replace(/^\w+\s*:\s*/, '');
+    return { text, start: codeStart(source, source.masked.indexOf(':', keyed.start) + 1) };
+  }
+  if (values.some(v => /^\w+\s*:/.test(v.text))) return undefined;
+  const positional = values[fields.indexOf(field)];
+  return positional && { text: positional.text, start: codeStart(source, positional.start) };
+}
+
+/**
+ * The first offset from start that isn't whitespace
+ */
+function codeStart(source: SourceText, start: number): number {
+  let i = start;
+  while (/\s/.test(source.masked[i] ?? '')) i++;
+  return i;
+}
+
+/**
+ * Field names of a struct body in declaration order: "name, want string" is two
+ */
+function structFieldNames(body: string): string[] {
+  return body
+    .split(/[\n;]/)
+    .map(line => line.trim())
+    .filter(line => line !== '')
+    .flatMap(line => {
+      const names = /^([A-Za-z_]\w*(?:\s*,\s*[A-Za-z_]\w*)*)\s+\S/.exec(line)?.[1];
+      return names ? names.split(',').map(n => n.trim()) : [line.replace(/^\*/, '')];
+    });
+}
+
+/**
+ * Subtests nested by the t.Run calls enclosing them, with go test's full
+ * names: spaces become underscores, levels join with /
+ */
+function nest(calls: RunCall[], testName: string): GoSubtest[] {
+  const childrenOf = (parent: RunCall | undefined): RunCall[] =>
+    calls.filter(call => {
+      const enclosing = calls
+        .filter(c => c !== call && c.open < call.open && call.close < c.close)
+        .sort((a, b) => b.open - a.open)[0];
+      return enclosing === parent;
+    });
+
+  const expand = (parent: RunCall | undefined, prefix: string): GoSubtest[] =>
+    childrenOf(parent).flatMap(call =>
+      call.subtests.map(subtest => {
+        const fullName = `${prefix}/${subtest.dynamic ? subtest.name : goTestName(subtest.name)}`;
+        const children = expand(call, fullName);
+        return {
+          ...subtest,
+          fullName,
+          ...(children.length > 0 ? { subtests: children } : {}),
+        };
+      })
+    );
+
+  return expand(undefined, testName);
+}
+
+/**
+ * A subtest name as go test reports and matches it
+ */
+function goTestName(name: string): string {
+  return name.replace(/\s/g, '_');
+}
+
+function countSubtests(subtests: GoSubtest[]): number {
+  return subtests.reduce(
+    (count, s) => count + (s.dynamic ? 0 : 1) + countSubtests(s.subtests ?? []),
+    0
+  );
+}
+
+function formatSubtests(subtests: GoSubtest[], depth: number): string {
+  let output = '';
+  for (const subtest of subtests) {
+    const name = subtest.dynamic ? `(dynamic: ${subtest.name})` : subtest.fullName;
+    output += `  ${String(subtest.line).padStart(4)}  ${'  '.repeat(depth - 1)}${name}\n`;
+    output += formatSubtests(subtest.subtests ?? [], depth + 1);
+  }
+  return output;
+}
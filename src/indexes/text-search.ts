@@ -0,0 +1,231 @@
+/**
+ * Text Search
+ * Regex search over raw file contents, grep-style
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { ToolError } from '../core/errors.js';
+import { isBinary, resolveProjectPath } from '../core/file-reader.js';
+import { walkFiles } from '../core/file-walker.js';
+import { fileStateFingerprint } from '../core/pagination.js';
+import type { WalkOptions } from '../core/file-walker.js';
+import { DEFAULT_MAX_FILE_SIZE } from './symbol-indexer.js';
+
+export const DEFAULT_MAX_RESULTS = 200;
+
+// Long lines (minified code) are cut in results
+const MAX_LINE_LENGTH = 500;
+
+// Upper bound on contextBefore and contextAfter
+export const MAX_CONTEXT_LINES = 20;
+
+export interface TextSearchOptions extends Omit<WalkOptions, 'cwd'> {
+  pattern: string;
+  path?: string | undefined; // Directory or file to search (default: project root)
+  glob?: string | undefined; // Limit to matching files, e.g. *.go or src/**/*.ts
+  ignoreCase?: boolean | undefined;
+  wholeWord?: boolean | undefined;
+  maxResults?: number | undefined;
+  maxFileSizeBytes?: number | undefined; // Larger files are skipped (default: 2 MB)
+  contextBefore?: number | undefined; // Lines of context before each match (default: 0)
+  contextAfter?: number | undefined;
+}
+
+export interface TextMatch {
+  file: string;
+  line: number;
+  column: number; // 1-based start of the match
+  startByte: number; // UTF-8 byte range of the match in the file
+  endByte: number;
+  text: string; // The whole matching line
+  snippet?: string | undefined; // With context: the matching line and the lines around it
+  snippetLine?: number | undefined; // Line number of the snippet's first line
+  root?: string | undefined; // Set when several project roots are registered
+}
+
+export interface TextSearchResult {
+  pattern: string;
+  matches: TextMatch[];
+  filesSearched: number;
+  truncated: boolean; // More matches exist beyond maxResults
+}
+
+export class TextSearcher {
+  private workingDir: string;
+
+  constructor(workingDir?: string) {
+    this.workingDir = workingDir ?? process.cwd();
+  }
+
+  async search(options: TextSearchOptions): Promise<TextSearchResult> {
+    const regex = this.compile(options);
+    const maxResults = Math.max(1, options.maxResults ?? DEFAULT_MAX_RESULTS);
+    const maxFileSize = options.maxFileSizeBytes ?? DEFAULT_MAX_FILE_SIZE;
+    const before = clampContext(options.contextBefore);
+    const after = clampContext(options.contextAfter);
+    const files = await this.listFiles(options);
+
+    const result: TextSearchResult = {
+      pattern: options.pattern,
+      matches: [],
+      filesSearched: 0,
+      truncated: false,
+    };
+
+    for (const file of files) {
+      const content = await this.readText(file, maxFileSize);
+      if (content === undefined) continue;
+      result.filesSearched++;
+
+      const lines = content.split('\n');
+      // A trailing newline ends the last line rather than starting another
+      const lastLine = lines.length - (content.endsWith('\n') ? 2 : 1);
+      let lineByte = 0; // Byte offset of the current line
+      for (let i = 0; i < lines.length; i++) {
+        const raw = lines[i] ?? '';
+        const text = raw.replace(/\r$/, '');
+        regex.lastIndex = 0;
+
+        let match;
+        while ((match = regex.exec(text)) !== null) {
+          if (result.matches.length === maxResults) {
+            result.truncated = true;
+            return result;
+          }
+          const startByte = lineByte + Buffer.byteLength(text.slice(0, match.index));
+          const entry: TextMatch = {
+            file,
+            line: i + 1,
+            column: match.index + 1,
+            startByte,
+            endByte: startByte + Buffer.byteLength(match[0]),
+            text: truncateLine(text),
+          };
+          if (before > 0 || after > 0) {
+            // Context stays inside this file, clipped at its first and last line
+            const first = Math.max(0, i - before);
+            const last = Math.min(lastLine, i + after);
+            entry.snippet = lines
+              .slice(first, last + 1)
+              .map(l => truncateLine(l.replace(/\r$/, '')))
+              .join('\n');
+            entry.snippetLine = first + 1;
+          }
+          result.matches.push(entry);
+          // Step past empty matches so patterns like ^ don't loop forever
+          if (match[0] === '') regex.lastIndex++;
+        }
+        lineByte += Buffer.byteLength(raw) + 1;
+      }
+    }
+
+    return result;
+  }
+
+  /**
+   * Fingerprint of the files a search with these options would read, so a
+   * page cursor goes stale when one of them is edited, added, or removed
+   */
+  async fileState(options: Omit<TextSearchOptions, 'pattern'>): Promise<string> {
+    return fileStateFingerprint(this.workingDir, await this.listFiles(options));
+  }
+
+  private compile(options: TextSearchOptions): RegExp {
+    const source = options.wholeWord ? `\\b(?:${options.pattern})\\b` : options.pattern;
+    try {
+      return new RegExp(source, options.ignoreCase ? 'gi' : 'g');
+    } catch (error) {
+      const reason = error instanceof Error ? error.message : String(error);
+      throw new ToolError('INVALID_ARGUMENT', `Invalid pattern: ${reason}`);
+    }
+  }
+
+  private async listFiles(options: Omit<TextSearchOptions, 'pattern'>): Promise<string[]> {
+    const relativeTarget = resolveProjectPath(this.workingDir, options.path ?? '.');
+    const stat = await fs.stat(path.join(this.workingDir, relativeTarget));
+    if (stat.isFile()) {
+      return [relativeTarget];
+    }
+
+    // A glob without a directory part matches at any depth, like grep --include
+    const glob = options.glob ?? '**/*';
+    return walkFiles(this.workingDir, [glob.includes('/') ? glob : `**/${glob}`], {
+      ignore: options.ignore,
+      includeIgnored: options.includeIgnored,
+      include: options.include,
+      exclude: options.exclude,
+      cwd: relativeTarget,
+    });
+  }
+
+  /**
+   * File contents, or undefined for unreadable, oversized, and binary files
+   */
+  private async readText(file: string, maxFileSize: number): Promise<string | undefined> {
+    const fullPath = path.join(this.workingDir, file);
+    try {
+      const stat = await fs.stat(fullPath);
+      if (stat.size > maxFileSize) return undefined;
+
+      const buffer = await fs.readFile(fullPath);
+      return isBinary(buffer) ? undefined : buffer.toString('utf-8');
+    } catch {
+      // Skip files that can't be read
+      return undefined;
+    }
+  }
+
+  formatResult(result: TextSearchResult): string {
+    if (result.matches.length === 0) {
+      return `No matches for /${result.pattern}/ in ${result.filesSearched} file(s).`;
+    }
+
+    const location = (m: TextMatch): string => (m.root ? `[${m.root}] ${m.file}` : m.file);
+    const fileCount = new Set(result.matches.map(location)).size;
+    let output = `${result.matches.length}${result.truncated ? '+' : ''} match(es) for `;
+    output += `/${result.pattern}/ in ${fileCount} file(s):\n\n`;
+    let currentFile = '';
+
+    for (const match of result.matches) {
+      if (location(match) !== currentFile) {
+        if (currentFile) output += '\n';
+        currentFile = location(match);
+        output += `${currentFile}:\n`;
+      }
+      if (match.snippet === undefined) {
+        output += `  ${match.line}:${match.column}  ${match.text.trim()}\n`;
+        continue;
+      }
+      output += `  ${match.line}:${match.column}\n`;
+      match.snippet.split('\n').forEach((text, i) => {
+        const lineNumber = (match.snippetLine ?? match.line) + i;
+        const marker = lineNumber === match.line ? '>' : ' ';
+        output += `  ${marker} ${String(lineNumber).padStart(4)} | ${text}\n`;
+      });
+    }
+
+    if (result.truncated) {
+      output += '\n(truncated; raise maxResults or narrow the pattern or glob)\n';
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.workingDir = dir;
+  }
+}
+
+/**
+ * Context line count from a request: whole, non-negative, and at most
+ * MAX_CONTEXT_LINES
+ */
+export function clampContext(lines: number | undefined): number {
+  if (lines === undefined || !Number.isFinite(lines)) return 0;
+  return Math.min(MAX_CONTEXT_LINES, Math.max(0, Math.floor(lines)));
+}
+
+function truncateLine(text: string): string {
+  return text.length > MAX_LINE_LENGTH ? `${text.slice(0, MAX_LINE_LENGTH)}...` : text;
+}
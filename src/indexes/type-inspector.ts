@@ -0,0 +1,302 @@
+/**
+ * Type Inspector
+ * Groups a Go type's fields, methods (by receiver kind), and constructors,
+ * plus the fields and methods promoted from the types it embeds
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { SymbolEntry } from '../types/index.js';
+import { parseGoStructFields } from '../parsers/go-parser.js';
+import type { GoStructField } from '../parsers/go-parser.js';
+import { GO_SYNTAX, SourceText } from '../parsers/source.js';
+import { SymbolIndexer } from './symbol-indexer.js';
+
+export type ReceiverKind = 'pointer' | 'value';
+
+export interface TypeMethod {
+  symbol: SymbolEntry;
+  receiver: ReceiverKind;
+}
+
+export interface PromotedField {
+  field: GoStructField;
+  via: string[]; // Embedded types it is reached through, outermost first
+}
+
+export interface PromotedMethod extends TypeMethod {
+  via: string[];
+  pointerOnly: boolean; // Only in *T's method set: a pointer method never reached through *E
+}
+
+export interface TypeDetails {
+  type: SymbolEntry;
+  fields: GoStructField[];
+  methods: TypeMethod[]; // Declared on the type itself
+  promotedFields: PromotedField[];
+  promotedMethods: PromotedMethod[];
+  constructors: SymbolEntry[]; // New<Type>... functions returning the type
+}
+
+interface Embedding {
+  field: GoStructField;
+  via: string[];
+  pointer: boolean; // Some link on the path embeds a pointer (*E)
+}
+
+const TYPE_KINDS = new Set(['struct', 'interface', 'type']);
+
+export class TypeInspector {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  /**
+   * Details for every Go type with this name (one per package), optionally
+   * limited to the type declared in a given file
+   */
+  async inspect(typeName: string, file?: string): Promise<TypeDetails[]> {
+    const symbols = await this.symbolIndexer.scan();
+    const normalizedFile = file?.replace(/\\/g, '/');
+    const types = symbols.filter(
+      s =>
+        s.language === 'go' &&
+        TYPE_KINDS.has(s.kind) &&
+        s.name === typeName &&
+        (!normalizedFile || s.file === normalizedFile)
+    );
+
+    const details: TypeDetails[] = [];
+    for (const type of types) {
+      details.push(await this.describe(type, symbols));
+    }
+
+    return details;
+  }
+
+  /**
+   * Details of one Go type, looked up in symbols from a scan the caller has
+   * already made
+   */
+  async describe(type: SymbolEntry, symbols: SymbolEntry[]): Promise<TypeDetails> {
+    const pkg = path.posix.dirname(type.file);
+    const inPackage = symbols.filter(
+      s => s.language === 'go' && path.posix.dirname(s.file) === pkg
+    );
+
+    const fields = type.kind === 'struct' ? await this.readFields(type) : [];
+    const methods = methodsOf(type.name, inPackage);
+    return {
+      type,
+      fields,
+      methods,
+      ...(await this.promote(type, fields, methods, inPackage)),
+      constructors: inPackage.filter(
+        s => s.kind === 'function' && /^[Nn]ew/.test(s.name) && returnsType(s, type.name)
+      ),
+    };
+  }
+
+  /**
+   * Members promoted through embedded types of the same package, breadth first
+   * as Go resolves selectors: a name at a shallower depth (or on the type
+   * itself) hides deeper ones, and a name found twice at one depth is
+   * ambiguous and not promoted at all.
+   */
+  private async promote(
+    type: SymbolEntry,
+    fields: GoStructField[],
+    methods: TypeMethod[],
+    inPackage: SymbolEntry[]
+  ): Promise<Pick<TypeDetails, 'promotedFields' | 'promotedMethods'>> {
+    const promotedFields: PromotedField[] = [];
+    const promotedMethods: PromotedMethod[] = [];
+    const taken = new Set([...fields.map(f => f.name), ...methods.map(m => m.symbol.name)]);
+    const seen = new Set([type.name]);
+
+    let level: Embedding[] = fields
+      .filter(f => f.embedded)
+      .map(field => ({ field, via: [], pointer: field.type.startsWith('*') }));
+
+    while (level.length > 0) {
+      const candidates = new Map<string, Array<PromotedField | PromotedMethod>>();
+      const add = (name: string, member: PromotedField | PromotedMethod): void => {
+        candidates.set(name, [...(candidates.get(name) ?? []), member]);
+      };
+      const next: Embedding[] = [];
+      const visited: string[] = [];
+
+      for (const { field, via, pointer } of level) {
+        const embedded = embeddedType(field, inPackage);
+        if (!embedded || seen.has(embedded.name)) continue;
+        visited.push(embedded.name);
+        const path = [...via, embedded.name];
+
+        const inner = embedded.kind === 'struct' ? await this.readFields(embedded) : [];
+        for (const innerField of inner) {
+          add(innerField.name, { field: innerField, via: path });
+          if (innerField.embedded) {
+            next.push({
+              field: innerField,
+              via: path,
+              pointer: pointer || innerField.type.startsWith('*'),
+            });
+          }
+        }
+        for (const method of methodsOf(embedded.name, inPackage)) {
+          const pointerOnly = method.receiver === 'pointer' && !pointer;
+          add(method.symbol.name, { ...method, via: path, pointerOnly });
+        }
+      }
+
+      for (const [name, members] of candidates) {
+        if (taken.has(name)) continue;
+        taken.add(name);
+        const [member] = members;
+        if (!member || members.length > 1) continue;
+        if ('field' in member) promotedFields.push(member);
+        else promotedMethods.push(member);
+      }
+
+      for (const name of visited) seen.add(name);
+      level = next;
+    }
+
+    return { promotedFields, promotedMethods };
+  }
+
+  private async readFields(type: SymbolEntry): Promise<GoStructField[]> {
+    const fullPath = path.join(this.symbolIndexer.getWorkingDir(), type.file);
+    let content: string;
+    try {
+      content = await fs.readFile(fullPath, 'utf-8');
+    } catch {
+      // Skip files that can't be read
+      return [];
+    }
+
+    const source = new SourceText(content, GO_SYNTAX);
+    const declaration = source.masked.slice(
+      source.lineStart(type.line),
+      source.lineStart(type.endLine + 1)
+    );
+    const brace = /\bstruct\s*\{/.exec(declaration);
+    if (!brace) return [];
+
+    const open = source.lineStart(type.line) + brace.index + brace[0].length - 1;
+    return parseGoStructFields(source, open);
+  }
+
+  formatDetails(typeName: string, details: TypeDetails[]): string {
+    if (details.length === 0) {
+      return `No Go type named ${typeName} found.`;
+    }
+
+    let output = '';
+    for (const detail of details) {
+      const { type, fields, methods, promotedFields, promotedMethods, constructors } = detail;
+      output += `${type.signature} (${type.file}:${type.line})\n`;
+      if (type.doc) output += `  ${type.doc.split('\n').join('\n  ')}\n`;
+
+      if (fields.length > 0) {
+        output += `\nFields (${fields.length}):\n`;
+        for (const field of fields) {
+          const name = field.embedded ? '(embedded)' : field.name;
+          output += `  ${name} ${field.type}${field.tag ? ` ${field.tag}` : ''}\n`;
+        }
+      }
+
+      if (promotedFields.length > 0) {
+        output += `\nPromoted fields (${promotedFields.length}):\n`;
+        for (const { field, via } of promotedFields) {
+          const name = field.embedded ? '(embedded)' : field.name;
+          output += `  ${name} ${field.type}  (via ${via.join('.')})\n`;
+        }
+      }
+
+      if (constructors.length > 0) {
+        output += '\nConstructors:\n';
+        for (const ctor of constructors) {
+          output += formatMember(ctor);
+        }
+      }
+
+      for (const receiver of ['pointer', 'value'] as const) {
+        const group = methods.filter(m => m.receiver === receiver);
+        if (group.length === 0) continue;
+        output += `\n${receiver === 'pointer' ? 'Pointer' : 'Value'} receiver methods:\n`;
+        for (const { symbol } of group) {
+          output += formatMember(symbol);
+        }
+      }
+
+      if (promotedMethods.length > 0) {
+        output += '\nPromoted methods:\n';
+        for (const { symbol, via, pointerOnly } of promotedMethods) {
+          const pointer = pointerOnly ? `, *${type.name} only` : '';
+          output += `  ${symbol.signature}  (via ${via.join('.')}${pointer})\n`;
+        }
+      }
+
+      output += '\n';
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+/**
+ * "func (c *Calculator) Add(...)" uses a pointer receiver
+ */
+export function receiverKind(signature: string): ReceiverKind {
+  return /^func\s*\(\s*(?:\w+\s+)?\*/.test(signature) ? 'pointer' : 'value';
+}
+
+function methodsOf(typeName: string, inPackage: SymbolEntry[]): TypeMethod[] {
+  return inPackage
+    .filter(s => s.kind === 'method' && s.parent === typeName)
+    .map(symbol => ({ symbol, receiver: receiverKind(symbol.signature) }));
+}
+
+/**
+ * The same-package type an embedded field names; "pkg.T" is declared elsewhere
+ */
+function embeddedType(field: GoStructField, inPackage: SymbolEntry[]): SymbolEntry | undefined {
+  if (field.type.replace(/\[.*$/, '').includes('.')) return undefined;
+  return inPackage.find(s => TYPE_KINDS.has(s.kind) && s.name === field.name);
+}
+
+function returnsType(fn: SymbolEntry, typeName: string): boolean {
+  // ") *T", ") T", or ") (*T, error)": the type must be the first result
+  const end = paramsEnd(fn.signature);
+  if (end === -1) return false;
+  const results = fn.signature.slice(end);
+  return new RegExp(`^\\)\\s*\\(?\\s*\\*?${typeName}\\b`).test(results);
+}
+
+/**
+ * Offset of the parenthesis closing a function's parameter list
+ */
+function paramsEnd(signature: string): number {
+  const name = /^func\s*(?:\([^)]*\)\s*)?\w+\s*(?:\[[^\]]*\])?\s*\(/.exec(signature);
+  if (!name) return -1;
+
+  let depth = 1;
+  for (let i = name[0].length; i < signature.length; i++) {
+    if (signature[i] === '(') depth++;
+    else if (signature[i] === ')' && --depth === 0) return i;
+  }
+  return -1;
+}
+
+function formatMember(symbol: SymbolEntry): string {
+  let output = `  ${symbol.signature}  (line ${symbol.line})\n`;
+  if (symbol.doc) output += `      ${symbol.doc.split('\n')[0]}\n`;
+  return output;
+}
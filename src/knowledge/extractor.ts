@@ -7,6 +7,7 @@ import * as fs from 'fs/promises';
 import * as path from 'path';
 import { parse, stringify } from 'yaml';
 import type { KnowledgeEntry, KnowledgeBase } from '../types/index.js';
+import { logger } from '../core/logger.js';
 
 interface FeatureManifest {
   name: string;
@@ -94,7 +95,7 @@ export class KnowledgeExtractor {
       entries.push(...patternEntries);
     } catch (error) {
       // Feature might not have all expected files
-      console.error('Knowledge extraction error:', error);
+      logger.warn('Knowledge extraction failed', { error });
     }
 
     return entries;
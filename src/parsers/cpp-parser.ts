@@ -0,0 +1,621 @@
+/**
+ * C/C++ Parser
+ * Extracts namespaces, classes, structs, unions, enums, and functions from C
+ * and C++ source, attaching out-of-class definitions (Foo::bar) to their class
+ */
+
+import type { SymbolEntry, SymbolKind } from '../types/index.js';
+import { checkDeadline } from '../core/deadline.js';
+import { SourceText, C_STYLE_SYNTAX } from './source.js';
+import type { SymbolParser } from './index.js';
+
+const IDENT = '[A-Za-z_]\\w*';
+// Template arguments nested up to three deep: map<string, vector<pair<int, int>>>
+const GENERICS = '<(?:[^<>;{}]|<(?:[^<>;{}]|<[^<>;{}]*>)*>)*>';
+// Attributes and export macros between a type keyword and its name:
+// class [[nodiscard]] API_EXPORT Widget
+const DECORATION =
+  '(?:\\[\\[[^\\]]*\\]\\]\\s*|__attribute__\\s*\\(\\([^)]*\\)\\)\\s*|__declspec\\s*\\([^)]*\\)\\s*|alignas\\s*\\([^)]*\\)\\s*|[A-Z][A-Z0-9_]*\\s+)*';
+
+const NAMESPACE_PATTERN = new RegExp(
+  `(?<![\\w:])(inline\\s+)?namespace\\b\\s*((?:${IDENT}\\s*::\\s*)*${IDENT})?\\s*(?:\\[\\[[^\\]]*\\]\\]\\s*)?\\{`,
+  'g'
+);
+// String contents are blanked in masked text: extern "C" { -> extern " " {
+const LINKAGE_PATTERN = /(?<![\w:])extern\s*"\s*"\s*\{/g;
+const TYPE_PATTERN = new RegExp(
+  `(?<![\\w:])(class|struct|union|enum(?:\\s+(?:class|struct))?)\\s+${DECORATION}((?:${IDENT}\\s*::\\s*)*${IDENT})`,
+  'g'
+);
+const ANONYMOUS_TYPE_PATTERN = /(?<![\w:])(struct|union|enum)\s*\{/g;
+const FUNCTION_PATTERN = new RegExp(
+  `(?<![\\w.:~>$])((?:::\\s*)?(?:${IDENT}\\s*(?:${GENERICS})?\\s*::\\s*)*(?:~\\s*${IDENT}|operator\\s*(?:\\(\\s*\\)|\\[\\s*\\]|[^\\s\\w(]+|${IDENT})|${IDENT}))\\s*\\(`,
+  'g'
+);
+
+// Words before "name(" that make it a statement or expression, not a declaration
+const NOT_DECLARATIONS = new Set([
+  'return',
+  'else',
+  'throw',
+  'new',
+  'delete',
+  'case',
+  'goto',
+  'typedef',
+  'using',
+  'friend',
+  'sizeof',
+  'co_return',
+  'co_yield',
+  'co_await',
+]);
+
+// Call-like keywords: if (...), sizeof(...), static_assert(...)
+const CALL_KEYWORDS = new Set([
+  'if',
+  'for',
+  'while',
+  'switch',
+  'catch',
+  'return',
+  'sizeof',
+  'alignof',
+  'alignas',
+  'decltype',
+  'typeid',
+  'static_assert',
+  'noexcept',
+  'throw',
+  'defined',
+  '__attribute__',
+  '__declspec',
+]);
+
+// Specifiers that don't name a return type: a constructor may carry them
+const SPECIFIERS = new Set([
+  'static',
+  'inline',
+  'virtual',
+  'explicit',
+  'constexpr',
+  'consteval',
+  'extern',
+]);
+
+const TYPE_KINDS: Record<string, SymbolKind> = {
+  class: 'class',
+  struct: 'struct',
+  union: 'struct',
+  enum: 'enum',
+};
+
+const HEADER_EXTENSIONS = /\.(?:h|hh|hpp|hxx)$/;
+
+interface Scope {
+  kind: 'namespace' | 'class' | 'linkage'; // linkage: extern "C" { ... }
+  keyword: string; // class, struct, union; namespace or extern otherwise
+  name: string; // Dot-qualified (geo.Shape); anonymous namespaces and extern "C" reuse the parent's
+  open: number;
+  close: number;
+  exported: boolean; // False inside anonymous namespaces and non-public nested types
+}
+
+interface FunctionBody {
+  bodyOpen?: number | undefined;
+  signatureEnd: number; // Before the body or a constructor's initializer list
+  end: number; // Closing brace, or the ; ending a declaration
+}
+
+export class CppParser implements SymbolParser {
+  readonly language = 'cpp';
+  readonly version = 1;
+  readonly aliases = ['c'];
+  readonly syntax = C_STYLE_SYNTAX;
+  readonly extensions = ['.c', '.h', '.cpp', '.cc', '.cxx', '.hpp', '.hh'];
+
+  parse(content: string, file: string): SymbolEntry[] {
+    const source = new SourceText(blankDirectives(content), this.syntax);
+    const symbols: SymbolEntry[] = [];
+
+    const scopes = this.findNamespaces(source, file, symbols);
+    this.findTypes(source, file, scopes, symbols);
+    this.findFunctions(source, file, scopes, symbols);
+
+    return symbols.sort((a, b) => a.line - b.line);
+  }
+
+  /**
+   * Namespace and extern "C" blocks, outer ones first; named namespaces are
+   * added to symbols
+   */
+  private findNamespaces(source: SourceText, file: string, symbols: SymbolEntry[]): Scope[] {
+    const blocks: Array<{ match: RegExpExecArray; linkage: boolean }> = [
+      ...[...matches(source, NAMESPACE_PATTERN)].map(match => ({ match, linkage: false })),
+      ...[...matches(source, LINKAGE_PATTERN)].map(match => ({ match, linkage: true })),
+    ].sort((a, b) => a.match.index - b.match.index);
+    const scopes: Scope[] = [];
+
+    for (const { match, linkage } of blocks) {
+      const owner = this.enclosingScope(source, scopes, match.index);
+      if (owner === undefined || owner?.kind === 'class') continue;
+
+      const open = match.index + match[0].length - 1;
+      const close = source.findMatching(open);
+      if (close === -1) continue;
+
+      const parts = linkage ? [] : splitQualified(match[2] ?? '');
+      const name = parts.length > 0 ? qualify(owner, parts.join('.')) : (owner?.name ?? '');
+      const exported = (owner?.exported ?? true) && (linkage || parts.length > 0);
+      scopes.push({
+        kind: linkage ? 'linkage' : 'namespace',
+        keyword: linkage ? 'extern' : 'namespace',
+        name,
+        open,
+        close,
+        exported,
+      });
+
+      const simpleName = parts.pop();
+      if (simpleName === undefined) continue;
+      const start = itemStart(match);
+      symbols.push(
+        this.createSymbol(source, start, {
+          name: simpleName,
+          kind: 'namespace',
+          file,
+          endLine: source.lineOf(close),
+          signature: collapse(source.content.slice(start, open)),
+          exported,
+          parent: parts.length > 0 ? qualify(owner, parts.join('.')) : owner?.name || undefined,
+        })
+      );
+    }
+
+    return scopes;
+  }
+
+  /**
+   * Class, struct, union, and enum definitions at namespace level or nested
+   * in another class; classes, structs, and unions become scopes for their
+   * members. Forward declarations and elaborated types (struct stat st) are
+   * skipped.
+   */
+  private findTypes(
+    source: SourceText,
+    file: string,
+    scopes: Scope[],
+    symbols: SymbolEntry[]
+  ): void {
+    const found: Array<{ start: number; keyword: string; name: string; open: number }> = [];
+
+    for (const match of matches(source, TYPE_PATTERN)) {
+      const open = this.typeBody(source, match.index + match[0].length);
+      if (open === -1) continue;
+      const keyword = (match[1] ?? '').split(/\s+/)[0] ?? '';
+      found.push({ start: itemStart(match), keyword, name: match[2] ?? '', open });
+    }
+
+    // typedef struct { ... } point_t; takes the typedef name
+    for (const match of matches(source, ANONYMOUS_TYPE_PATTERN)) {
+      if (!/\btypedef\s*$/.test(source.masked.slice(0, match.index))) continue;
+      const open = match.index + match[0].length - 1;
+      const close = source.findMatching(open);
+      const alias =
+        close === -1 ? null : new RegExp(`^\\s*(${IDENT})`).exec(source.masked.slice(close + 1));
+      if (!alias?.[1]) continue;
+      const typedefStart = source.masked.slice(0, match.index).search(/\btypedef\s*$/);
+      found.push({ start: typedefStart, keyword: match[1] ?? '', name: alias[1], open });
+    }
+
+    // Outer types come first in the source, so their scopes exist by the time
+    // a nested type looks for its parent
+    found.sort((a, b) => a.start - b.start);
+    for (const { start, keyword, name: qualifiedName, open } of found) {
+      const owner = this.enclosingScope(source, scopes, start);
+      if (owner === undefined) continue;
+      const close = source.findMatching(open);
+      if (close === -1) continue;
+
+      const parts = splitQualified(qualifiedName);
+      const name = parts.pop() ?? '';
+      const parent = parts.length > 0 ? qualify(owner, parts.join('.')) : owner?.name || undefined;
+      const exported =
+        (owner?.exported ?? true) &&
+        (owner?.kind !== 'class' || this.access(source, owner, start) === 'public');
+
+      if (keyword !== 'enum') {
+        scopes.push({
+          kind: 'class',
+          keyword,
+          name: parent ? `${parent}.${name}` : name,
+          open,
+          close,
+          exported,
+        });
+      }
+      symbols.push(
+        this.createSymbol(source, start, {
+          name,
+          kind: TYPE_KINDS[keyword] ?? 'struct',
+          file,
+          endLine: source.lineOf(close),
+          signature: collapse(source.content.slice(start, open)),
+          exported,
+          parent,
+        })
+      );
+    }
+  }
+
+  /**
+   * Function definitions, plus declarations inside class bodies and in
+   * headers. A qualified definition (Foo::bar) outside its class becomes a
+   * method of Foo unless Foo is a namespace declared in the file.
+   */
+  private findFunctions(
+    source: SourceText,
+    file: string,
+    scopes: Scope[],
+    symbols: SymbolEntry[]
+  ): void {
+    const header = HEADER_EXTENSIONS.test(file);
+    const namespaces = new Set(scopes.filter(s => s.kind === 'namespace').map(s => s.name));
+    const classes = new Set(scopes.filter(s => s.kind === 'class').map(s => s.name));
+    // Whether members declared in a class body are public, for definitions outside it
+    const memberVisibility = new Map<string, boolean>();
+
+    for (const match of matches(source, FUNCTION_PATTERN)) {
+      const owner = this.enclosingScope(source, scopes, match.index);
+      if (owner === undefined) continue;
+
+      const qualified = match[1] ?? '';
+      const absolute = qualified.startsWith('::');
+      const parts = splitQualified(qualified);
+      const name = (parts.pop() ?? '').replace(/^(operator|~)\s+/, '$1');
+      if (CALL_KEYWORDS.has(name)) continue;
+
+      const paramsOpen = match.index + match[0].length - 1;
+      const paramsClose = source.findMatching(paramsOpen);
+      if (paramsClose === -1) continue;
+      const body = this.functionBody(source, paramsClose + 1);
+      if (!body) continue;
+
+      const start = this.declarationStart(source, match.index);
+      const returnType = declarationPrefix(source.masked.slice(start, match.index));
+      if (returnType === undefined) continue;
+
+      // Only constructors, destructors, and conversion operators lack a return type
+      const className = owner?.kind === 'class' ? owner.name.split('.').pop() : parts.at(-1);
+      const special =
+        name.startsWith('operator') || name === className || name === `~${className ?? ''}`;
+      if (!special && returnType.filter(word => !SPECIFIERS.has(word)).length === 0) continue;
+
+      const inClass = owner?.kind === 'class';
+      if (body.bodyOpen === undefined && !inClass && !header) continue;
+
+      let kind: SymbolKind = 'function';
+      let parent = owner?.name || undefined;
+      let exported = (owner?.exported ?? true) && !returnType.includes('static');
+      if (owner && inClass) {
+        kind = 'method';
+        exported = owner.exported && this.access(source, owner, start) === 'public';
+        memberVisibility.set(`${owner.name}.${name}`, exported);
+      } else if (parts.length > 0) {
+        parent = qualify(absolute ? null : owner, parts.join('.'));
+        kind = namespaces.has(parent) && !classes.has(parent) ? 'function' : 'method';
+        exported = (owner?.exported ?? true) && (memberVisibility.get(`${parent}.${name}`) ?? true);
+      }
+
+      symbols.push(
+        this.createSymbol(source, start, {
+          name,
+          kind,
+          file,
+          endLine: source.lineOf(body.end),
+          signature: collapse(source.content.slice(start, body.signatureEnd)),
+          exported,
+          parent,
+        })
+      );
+    }
+  }
+
+  /**
+   * The namespace, class, or extern "C" block directly containing an offset:
+   * null at file level, undefined inside a function body or initializer
+   */
+  private enclosingScope(
+    source: SourceText,
+    scopes: Scope[],
+    offset: number
+  ): Scope | null | undefined {
+    const depth = source.depthAt(offset);
+    if (depth === 0) return null;
+
+    let innermost: Scope | undefined;
+    for (const scope of scopes) {
+      if (scope.open < offset && offset < scope.close) {
+        if (!innermost || scope.open > innermost.open) innermost = scope;
+      }
+    }
+
+    if (innermost && source.depthAt(innermost.open) + 1 === depth) {
+      return innermost;
+    }
+    return undefined;
+  }
+
+  /**
+   * Access in effect at an offset in a class body: the last public:,
+   * protected:, or private: label before it, else the keyword's default
+   */
+  private access(source: SourceText, scope: Scope, offset: number): string {
+    const depth = source.depthAt(scope.open) + 1;
+    let access = scope.keyword === 'class' ? 'private' : 'public';
+    for (const label of source.masked
+      .slice(scope.open + 1, offset)
+      .matchAll(/\b(public|protected|private)\s*:(?!:)/g)) {
+      if (source.depthAt(scope.open + 1 + label.index) === depth) {
+        access = label[1] ?? access;
+      }
+    }
+    return access;
+  }
+
+  /**
+   * Opening brace of a class body after its name, allowing final and a base
+   * clause; -1 for forward declarations and elaborated type specifiers
+   */
+  private typeBody(source: SourceText, from: number): number {
+    const masked = source.masked;
+    let bases = false;
+
+    for (let i = from; i < masked.length; i++) {
+      const ch = masked[i] ?? '';
+      if (ch === '{') return i;
+      if (ch === ';') return -1;
+      if (bases) {
+        if (ch === '<') {
+          const close = source.findMatching(i);
+          if (close === -1) return -1;
+          i = close;
+        }
+      } else if (ch === ':' && masked[i + 1] !== ':' && masked[i - 1] !== ':') {
+        bases = true;
+      } else if (!/[\s\w:]/.test(ch)) {
+        return -1;
+      }
+    }
+    return -1;
+  }
+
+  /**
+   * What follows a parameter list: qualifiers (const, noexcept, override,
+   * -> trailing return), then a body, a constructor initializer list and
+   * body, or the ; of a declaration (including = 0, = default, = delete).
+   * Undefined if it isn't a function declaration after all.
+   */
+  private functionBody(source: SourceText, from: number): FunctionBody | undefined {
+    const masked = source.masked;
+
+    for (let i = from; i < masked.length; i++) {
+      const ch = masked[i] ?? '';
+      if (ch === '{') {
+        const close = source.findMatching(i);
+        return { bodyOpen: i, signatureEnd: i, end: close === -1 ? i : close };
+      }
+      if (ch === ';') {
+        return { signatureEnd: i, end: i };
+      }
+      if (ch === '=') {
+        const end = masked.indexOf(';', i);
+        return end === -1 ? undefined : { signatureEnd: end, end };
+      }
+      if (ch === '(' || ch === '[') {
+        const close = source.findMatching(i);
+        if (close === -1) return undefined;
+        i = close;
+      } else if (ch === ':' && masked[i + 1] !== ':') {
+        const bodyOpen = this.initializerListEnd(source, i + 1);
+        if (bodyOpen === -1) return undefined;
+        const close = source.findMatching(bodyOpen);
+        return { bodyOpen, signatureEnd: i, end: close === -1 ? bodyOpen : close };
+      } else if (
+        !/[\s\w&*<>:.,-]/.test(ch) ||
+        (ch === ',' && !this.inTemplateArgs(masked, from, i))
+      ) {
+        return undefined;
+      }
+    }
+    return undefined;
+  }
+
+  /**
+   * Body brace after a constructor initializer list: member(x), base{y}, ...
+   */
+  private initializerListEnd(source: SourceText, from: number): number {
+    const masked = source.masked;
+    let i = from;
+
+    for (;;) {
+      while (i < masked.length && /[\s\w:<>.,]/.test(masked[i] ?? '')) i++;
+      const ch = masked[i];
+      if (ch !== '(' && ch !== '{') return -1;
+      const close = source.findMatching(i);
+      if (close === -1) return -1;
+
+      i = close + 1;
+      while (/\s/.test(masked[i] ?? '')) i++;
+      if (masked[i] === ',') {
+        i++;
+      } else {
+        return masked[i] === '{' ? i : -1;
+      }
+    }
+  }
+
+  /**
+   * Whether a comma in a trailing return type sits inside template arguments
+   */
+  private inTemplateArgs(masked: string, from: number, offset: number): boolean {
+    let depth = 0;
+    for (let i = from; i < offset; i++) {
+      if (masked[i] === '<') depth++;
+      else if (masked[i] === '>' && masked[i - 1] !== '-') depth--;
+    }
+    return depth > 0;
+  }
+
+  /**
+   * First character of the declaration ending in a name: just after the
+   * previous ;, brace, or access label
+   */
+  private declarationStart(source: SourceText, nameStart: number): number {
+    const masked = source.masked;
+    let i = nameStart - 1;
+    while (i >= 0 && !';{}'.includes(masked[i] ?? '')) i--;
+
+    let start = i + 1;
+    const label = /^\s*(?:public|protected|private)\s*:(?!:)/.exec(masked.slice(start, nameStart));
+    if (label) start += label[0].length;
+    while (start < nameStart && /\s/.test(masked[start] ?? '')) start++;
+    return start;
+  }
+
+  private createSymbol(
+    source: SourceText,
+    start: number,
+    fields: Omit<SymbolEntry, 'language' | 'line' | 'doc'>
+  ): SymbolEntry {
+    const line = source.lineOf(start);
+    const symbol: SymbolEntry = { ...fields, language: this.language, line };
+    if (fields.parent === undefined) delete symbol.parent;
+
+    const doc = source.docCommentBefore(line);
+    if (doc) symbol.doc = doc.text;
+    return symbol;
+  }
+}
+
+/**
+ * Blank preprocessor lines (and their \ continuations) so macros don't read
+ * as code, along with #elif and #else branches, which would otherwise open a
+ * second copy of a brace their #if branch already opened. Offsets and lines
+ * are kept.
+ */
+function blankDirectives(content: string): string {
+  const lines = content.split('\n');
+  const skipping: boolean[] = []; // Per open #if: whether a later branch is being dropped
+  let inComment = false;
+  let continued = false;
+
+  for (let i = 0; i < lines.length; i++) {
+    const line = lines[i] ?? '';
+    if (continued || (!inComment && line.trimStart().startsWith('#'))) {
+      if (!continued) {
+        const directive = /^\s*#\s*(\w+)/.exec(line)?.[1] ?? '';
+        if (directive.startsWith('if')) {
+          skipping.push(false);
+        } else if ((directive === 'elif' || directive === 'else') && skipping.length > 0) {
+          skipping[skipping.length - 1] = true;
+        } else if (directive === 'endif') {
+          skipping.pop();
+        }
+      }
+      continued = line.trimEnd().endsWith('\\');
+      lines[i] = blank(line);
+      continue;
+    }
+
+    // Track block comments so a # inside one isn't taken for a directive
+    const code = line.replace(/"(?:[^"\\]|\\.)*"/g, '""');
+    let position = 0;
+    for (;;) {
+      if (inComment) {
+        const close = code.indexOf('*/', position);
+        if (close === -1) break;
+        inComment = false;
+        position = close + 2;
+      } else {
+        const open = code.indexOf('/*', position);
+        const lineComment = code.indexOf('//', position);
+        if (open === -1 || (lineComment !== -1 && lineComment < open)) break;
+        inComment = true;
+        position = open + 2;
+      }
+    }
+    if (skipping.includes(true)) lines[i] = blank(line);
+  }
+
+  return lines.join('\n');
+}
+
+function blank(line: string): string {
+  return line.replace(/[^\r]/g, ' ');
+}
+
+/**
+ * Words of the return type and specifiers in front of a function name, with
+ * template headers, attributes, and template arguments removed; undefined if
+ * the text makes this a statement or expression rather than a declaration
+ */
+function declarationPrefix(text: string): string[] | undefined {
+  let cleaned = text
+    .replace(new RegExp(`\\btemplate\\s*${GENERICS}`, 'g'), ' ')
+    .replace(/\[\[[^\]]*\]\]/g, ' ')
+    .replace(/\b__attribute__\s*\(\([^)]*\)\)/g, ' ')
+    .replace(/\b(?:__declspec|alignas|decltype)\s*\([^)]*\)/g, ' T ')
+    .replace(/\bextern\s*"\s*"/g, ' extern ');
+  let previous;
+  do {
+    previous = cleaned;
+    cleaned = cleaned.replace(new RegExp(GENERICS, 'g'), ' ');
+  } while (cleaned !== previous);
+
+  if (!/^[\w\s*&:~]*$/.test(cleaned)) return undefined;
+  const words = cleaned.match(/\w+/g) ?? [];
+  return words.some(word => NOT_DECLARATIONS.has(word)) ? undefined : words;
+}
+
+function* matches(source: SourceText, pattern: RegExp): Generator<RegExpExecArray> {
+  pattern.lastIndex = 0;
+  let match;
+  while ((match = pattern.exec(source.masked)) !== null) {
+    checkDeadline();
+    yield match;
+  }
+}
+
+/**
+ * Components of a qualified name without template arguments or a leading
+ * ::, e.g. "::geo::Box<T>::area" -> ["geo", "Box", "area"]
+ */
+function splitQualified(name: string): string[] {
+  let stripped = name;
+  let previous;
+  do {
+    previous = stripped;
+    stripped = stripped.replace(new RegExp(GENERICS, 'g'), '');
+  } while (stripped !== previous);
+
+  return stripped
+    .split('::')
+    .map(part => part.trim())
+    .filter(Boolean);
+}
+
+function qualify(owner: Scope | null | undefined, name: string): string {
+  return owner?.name ? `${owner.name}.${name}` : name;
+}
+
+/**
+ * Offset of the first non-blank character of a match
+ */
+function itemStart(match: RegExpExecArray): number {
+  return match.index + Math.max(0, match[0].search(/\S/));
+}
+
+function collapse(text: string): string {
+  return text.replace(/\s+/g, ' ').trim();
+}
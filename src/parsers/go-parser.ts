@@ -0,0 +1,903 @@
+/**
+ * Go Parser
+ * Extracts functions, methods, type declarations, constants, and variables from Go source
+ */
+
+import type { Parameter, SymbolEntry, TypeParameter } from '../types/index.js';
+import { checkDeadline } from '../core/deadline.js';
+import { SourceText, GO_SYNTAX } from './source.js';
+import type { SymbolParser } from './index.js';
+
+const FUNC_PATTERN = /^func\s*(?:\(([^)]*)\)\s*)?([A-Za-z_]\w*)\s*(\[[^\]]*\])?\s*\(/gm;
+const TYPE_PATTERN = /^type\s+([A-Za-z_]\w*)/gm;
+const TYPE_GROUP_PATTERN = /^type\s*\(/gm;
+const VALUE_PATTERN = /^(const|var)\s+([A-Za-z_]\w*)/gm;
+const VALUE_GROUP_PATTERN = /^(const|var)\s*\(/gm;
+
+export class GoParser implements SymbolParser {
+  readonly language = 'go';
+  readonly version = 2;
+  readonly syntax = GO_SYNTAX;
+  readonly extensions = ['.go'];
+
+  parse(content: string, file: string): SymbolEntry[] {
+    const source = new SourceText(content, this.syntax);
+    const symbols: SymbolEntry[] = [];
+
+    this.parseFunctions(source, file, symbols);
+    this.parseTypes(source, file, symbols);
+    this.parseValues(source, file, symbols);
+
+    return symbols.sort((a, b) => a.line - b.line);
+  }
+
+  private parseFunctions(source: SourceText, file: string, symbols: SymbolEntry[]): void {
+    FUNC_PATTERN.lastIndex = 0;
+    let match;
+
+    while ((match = FUNC_PATTERN.exec(source.masked)) !== null) {
+      checkDeadline();
+      const receiver = match[1];
+      const name = match[2] ?? '';
+      const paramsOpen = match.index + match[0].length - 1;
+      const paramsClose = source.findMatching(paramsOpen);
+      if (paramsClose === -1) continue;
+
+      const bodyOpen = this.findBodyOpen(source, paramsClose + 1);
+      const signatureEnd = bodyOpen === -1 ? source.statementEnd(paramsClose) : bodyOpen;
+      const bodyClose = bodyOpen === -1 ? -1 : source.findMatching(bodyOpen);
+      const line = source.lineOf(match.index);
+
+      const symbol = this.createSymbol(source, {
+        name,
+        kind: receiver !== undefined ? 'method' : 'function',
+        file,
+        line,
+        endLine: bodyClose === -1 ? source.lineOf(signatureEnd) : source.lineOf(bodyClose),
+        signature: collapse(source.content.slice(match.index, signatureEnd)),
+        parent: receiver !== undefined ? receiverType(receiver) : undefined,
+      });
+      symbol.params = parseGoParameters(source.masked.slice(paramsOpen + 1, paramsClose));
+      symbol.returns = parseGoResults(source.masked.slice(paramsClose + 1, signatureEnd));
+      if (match[3]) {
+        symbol.typeParams = parseGoParameters(match[3].slice(1, -1)).map(p => ({
+          name: p.name ?? '',
+          constraint: p.type,
+        }));
+      }
+      symbols.push(symbol);
+    }
+  }
+
+  private parseTypes(source: SourceText, file: string, symbols: SymbolEntry[]): void {
+    TYPE_PATTERN.lastIndex = 0;
+    let match;
+
+    while ((match = TYPE_PATTERN.exec(source.masked)) !== null) {
+      checkDeadline();
+      this.addType(source, file, match.index, symbols);
+    }
+
+    // Grouped declarations: type ( A struct{...}; B int )
+    TYPE_GROUP_PATTERN.lastIndex = 0;
+    while ((match = TYPE_GROUP_PATTERN.exec(source.masked)) !== null) {
+      checkDeadline();
+      const open = match.index + match[0].length - 1;
+      const close = source.findMatching(open);
+      if (close === -1) continue;
+
+      const firstLine = source.lineOf(open) + 1;
+      const lastLine = source.lineOf(close);
+      for (let line = firstLine; line <= lastLine; line++) {
+        const text = source.lineText(line, true);
+        const start = source.lineStart(line);
+        if (/^\s+[A-Za-z_]\w*\s/.test(text) && source.depthAt(start) === 0) {
+          const indent = text.search(/\S/);
+          const offset = start + indent;
+          // Skip lines nested inside a struct/interface body of the group
+          if (this.parenDepth(source, open, offset) === 1) {
+            this.addType(source, file, offset, symbols);
+          }
+        }
+      }
+    }
+  }
+
+  private parseValues(source: SourceText, file: string, symbols: SymbolEntry[]): void {
+    const specs: { keyword: 'const' | 'var'; spec: GoValueSpec; at: GoSpecPosition }[] = [];
+    VALUE_PATTERN.lastIndex = 0;
+    let match;
+
+    while ((match = VALUE_PATTERN.exec(source.masked)) !== null) {
+      checkDeadline();
+      const keyword = match[1] === 'const' ? 'const' : 'var';
+      const offset = match.index + match[0].length - (match[2] ?? '').length;
+      const end = source.statementEnd(match.index);
+      const spec = parseGoValueSpec(source, offset, end);
+      const signature = collapse(source.content.slice(match.index, end));
+      if (spec) specs.push({ keyword, spec, at: { offset, end, signature, iota: 0 } });
+    }
+
+    // Grouped declarations: const ( A = iota; B )
+    VALUE_GROUP_PATTERN.lastIndex = 0;
+    while ((match = VALUE_GROUP_PATTERN.exec(source.masked)) !== null) {
+      checkDeadline();
+      const keyword = match[1] === 'const' ? 'const' : 'var';
+      const open = match.index + match[0].length - 1;
+      const close = source.findMatching(open);
+      if (close === -1) continue;
+
+      // A const spec without a type or values repeats those of the spec above
+      let previous: GoValueSpec | undefined;
+      let iota = 0;
+      let offset = open + 1;
+      for (;;) {
+        while (offset < close && /[\s;]/.test(source.masked[offset] ?? '')) offset++;
+        if (offset >= close) break;
+        const end = Math.min(source.statementEnd(offset), close);
+        let spec = parseGoValueSpec(source, offset, end);
+        if (spec && keyword === 'const' && spec.values.length === 0 && !spec.type && previous) {
+          spec = { ...spec, type: previous.type, values: previous.values };
+        } else if (spec) {
+          previous = spec;
+        }
+        const signature = `${keyword} ${collapse(source.content.slice(offset, end))}`;
+        if (spec) specs.push({ keyword, spec, at: { offset, end, signature, iota } });
+        iota++;
+        offset = end + 1;
+      }
+    }
+
+    // In source order, so constants can build on the ones declared before them
+    const scope = goValueScope(symbols);
+    specs.sort((a, b) => a.at.offset - b.at.offset);
+    for (const { keyword, spec, at } of specs) {
+      this.addValues(source, file, keyword, spec, at, scope, symbols);
+    }
+  }
+
+  /**
+   * One symbol per name of a const or var spec, with its type, initializer,
+   * and, for integer constants, the computed value
+   */
+  private addValues(
+    source: SourceText,
+    file: string,
+    keyword: 'const' | 'var',
+    spec: GoValueSpec,
+    at: GoSpecPosition,
+    scope: GoValueScope,
+    symbols: SymbolEntry[]
+  ): void {
+    const line = source.lineOf(at.offset);
+    const endLine = source.lineOf(lastCodeOffset(source, at.offset, at.end));
+    // "a, b = f()": one initializer for several names can't be split
+    const paired = spec.values.length === spec.names.length;
+
+    spec.names.forEach((name, i) => {
+      const value = paired ? spec.values[i] : undefined;
+      const constant =
+        keyword === 'const' && value !== undefined
+          ? evaluateGoConstant(value, at.iota, scope.constants)
+          : undefined;
+      const inferred = value === undefined ? undefined : inferGoType(value, scope);
+      // A constant built from a typed one has its type (Tuesday + 1 is a
+      // Weekday); other integer constants default to int
+      const typed = value?.match(/[A-Za-z_]\w*/g)?.find(n => scope.constantTypes.has(n));
+      const constantType = typed ? scope.constantTypes.get(typed) : 'int';
+      const valueType =
+        spec.type ?? inferred ?? (constant !== undefined ? constantType : undefined);
+      if (constant !== undefined) {
+        scope.constants.set(name, constant);
+        if (valueType && valueType !== 'int') scope.constantTypes.set(name, valueType);
+      }
+      // The blank identifier declares nothing
+      if (name === '_') return;
+
+      const symbol = this.createSymbol(source, {
+        name,
+        kind: keyword,
+        file,
+        line,
+        endLine,
+        signature: at.signature,
+        parent: undefined,
+      });
+      if (valueType) symbol.valueType = valueType;
+      if (value !== undefined) symbol.value = value;
+      if (constant !== undefined) symbol.constValue = constant.toString();
+      symbols.push(symbol);
+    });
+  }
+
+  private addType(source: SourceText, file: string, offset: number, symbols: SymbolEntry[]): void {
+    const rest = source.masked.slice(offset);
+    const match = /^(?:type\s+)?([A-Za-z_]\w*)(\[[^\]]*\])?\s*(=\s*)?(struct|interface)?\s*(\{)?/.exec(
+      rest
+    );
+    if (!match?.[1]) return;
+
+    const name = match[1];
+    const keyword = match[4];
+    const line = source.lineOf(offset);
+    let endLine = line;
+    let signatureEnd = source.statementEnd(offset);
+
+    if (keyword && match[5]) {
+      const open = offset + match[0].length - 1;
+      const close = source.findMatching(open);
+      signatureEnd = open;
+      if (close !== -1) endLine = source.lineOf(close);
+    } else {
+      endLine = source.lineOf(signatureEnd);
+    }
+
+    const signature = collapse(source.content.slice(offset, signatureEnd));
+
+    symbols.push(
+      this.createSymbol(source, {
+        name,
+        kind: keyword === 'struct' ? 'struct' : keyword === 'interface' ? 'interface' : 'type',
+        file,
+        line,
+        endLine,
+        signature: signature.startsWith('type ') ? signature : `type ${signature}`,
+        parent: undefined,
+      })
+    );
+  }
+
+  /**
+   * Find the opening brace of a function body, skipping return types
+   * such as struct{}, interface{}, or func() (...)
+   */
+  private findBodyOpen(source: SourceText, from: number): number {
+    const masked = source.masked;
+
+    for (let i = from; i < masked.length; i++) {
+      const ch = masked[i];
+      if (ch === '(' || ch === '[') {
+        const close = source.findMatching(i);
+        if (close === -1) return -1;
+        i = close;
+      } else if (ch === '{') {
+        if (/\b(?:struct|interface)\s*$/.test(masked.slice(from, i))) {
+          const close = source.findMatching(i);
+          if (close === -1) return -1;
+          i = close;
+          continue;
+        }
+        return i;
+      } else if (ch === '\n') {
+        return -1;
+      }
+    }
+
+    return -1;
+  }
+
+  private parenDepth(source: SourceText, from: number, to: number): number {
+    let depth = 0;
+    for (let i = from; i < to; i++) {
+      const ch = source.masked[i];
+      if (ch === '(' || ch === '{') depth++;
+      else if (ch === ')' || ch === '}') depth--;
+    }
+    return depth;
+  }
+
+  private createSymbol(
+    source: SourceText,
+    fields: Omit<SymbolEntry, 'language' | 'exported' | 'doc'>
+  ): SymbolEntry {
+    const doc = source.docCommentBefore(fields.line);
+    const symbol: SymbolEntry = {
+      ...fields,
+      language: this.language,
+      exported: /^[A-Z]/.test(fields.name),
+    };
+    if (fields.parent === undefined) delete symbol.parent;
+    if (doc) symbol.doc = doc.text;
+    const deprecation = doc ? deprecationNotice(doc.text) : undefined;
+    if (deprecation !== undefined) {
+      symbol.deprecated = true;
+      if (deprecation) symbol.deprecation = deprecation;
+    }
+    return symbol;
+  }
+}
+
+/**
+ * Message of the "Deprecated:" paragraph in a Go doc comment, unwrapped to one
+ * line ("" if it says nothing more), or undefined if there is none
+ */
+export function deprecationNotice(doc: string): string | undefined {
+  const paragraph = doc
+    .split(/\n\s*\n/)
+    .map(p => p.trim())
+    .find(p => p.startsWith('Deprecated:'));
+  return paragraph === undefined ? undefined : collapse(paragraph.slice('Deprecated:'.length));
+}
+
+/**
+ * Receiver type name from a receiver clause: "c *Calculator" -> "Calculator"
+ */
+function receiverType(receiver: string): string {
+  const parts = receiver.trim().split(/\s+/);
+  const typePart = parts[parts.length - 1] ?? '';
+  return typePart.replace(/^\*/, '').replace(/\[.*$/, '');
+}
+
+function collapse(text: string): string {
+  return text.replace(/\s+/g, ' ').trim();
+}
+
+// Words that begin a type, so "chan int" is an unnamed parameter, not "chan" of type int
+const GO_TYPE_KEYWORDS = new Set(['chan', 'func', 'map', 'struct', 'interface']);
+
+/**
+ * Parameters of a Go parameter list, without the parentheses. Names sharing a
+ * type ("a, b int") each get the type; a list without names ("int, string")
+ * yields unnamed parameters.
+ */
+export function parseGoParameters(list: string): Parameter[] {
+  const entries = splitTopLevel(collapse(list));
+  const named = entries.some(entry => {
+    const first = /^([A-Za-z_]\w*)\s+\S/.exec(entry)?.[1];
+    return first !== undefined && !GO_TYPE_KEYWORDS.has(first);
+  });
+
+  const parameters: Parameter[] = [];
+  let type = '';
+  // Walk backwards so names in "a, b int" take the type that follows them
+  for (let i = entries.length - 1; i >= 0; i--) {
+    const entry = entries[i] ?? '';
+    const typed = named ? /^([A-Za-z_]\w*)\s+(\S.*)$/.exec(entry) : null;
+    if (named && !typed) {
+      parameters.unshift(withType({ name: entry }, type));
+      continue;
+    }
+
+    type = typed?.[2] ?? entry;
+    const parameter: Parameter = typed?.[1] ? { name: typed[1], type: '' } : { type: '' };
+    parameters.unshift(withType(parameter, type));
+  }
+
+  return parameters;
+}
+
+/**
+ * Results after a Go parameter list: "error", "*User", or "(n int, err error)"
+ */
+export function parseGoResults(text: string): Parameter[] {
+  const results = text.trim();
+  if (results === '') return [];
+  if (results.startsWith('(') && results.endsWith(')')) {
+    return parseGoParameters(results.slice(1, -1));
+  }
+  return [{ type: collapse(results) }];
+}
+
+function withType(parameter: Omit<Parameter, 'type'>, type: string): Parameter {
+  if (type.startsWith('...')) {
+    return { ...parameter, type: type.slice(3), variadic: true };
+  }
+  return { ...parameter, type };
+}
+
+/**
+ * Split on commas outside brackets: "m map[K]V, f func(a, b int)" -> 2 entries
+ */
+function splitTopLevel(list: string): string[] {
+  const entries: string[] = [];
+  let depth = 0;
+  let start = 0;
+  for (let i = 0; i < list.length; i++) {
+    const ch = list[i] ?? '';
+    if ('([{'.includes(ch)) depth++;
+    else if (')]}'.includes(ch)) depth--;
+    else if (ch === ',' && depth === 0) {
+      entries.push(list.slice(start, i));
+      start = i + 1;
+    }
+  }
+  entries.push(list.slice(start));
+  return entries.map(e => e.trim()).filter(e => e !== '');
+}
+
+interface GoValueSpec {
+  names: string[];
+  type?: string | undefined; // As declared: the "int" in var a, b int
+  values: string[]; // Initializers, one per name unless a call returns several
+}
+
+interface GoSpecPosition {
+  offset: number; // First name of the spec
+  end: number;
+  signature: string;
+  iota: number; // Index of the spec in its const group
+}
+
+// What the file declares that a value's type or constant value can refer to
+interface GoValueScope {
+  types: Set<string>;
+  results: Map<string, string>; // Function name -> its only result type
+  constants: Map<string, bigint>; // Integer constants evaluated so far
+  constantTypes: Map<string, string>; // Those of them with a type other than int
+}
+
+const GO_BUILTIN_TYPES = new Set([
+  'bool',
+  'string',
+  'int',
+  'int8',
+  'int16',
+  'int32',
+  'int64',
+  'uint',
+  'uint8',
+  'uint16',
+  'uint32',
+  'uint64',
+  'uintptr',
+  'byte',
+  'rune',
+  'float32',
+  'float64',
+  'complex64',
+  'complex128',
+  'error',
+  'any',
+]);
+
+function goValueScope(symbols: SymbolEntry[]): GoValueScope {
+  const scope: GoValueScope = {
+    types: new Set(),
+    results: new Map(),
+    constants: new Map(),
+    constantTypes: new Map(),
+  };
+  for (const symbol of symbols) {
+    if (['struct', 'interface', 'type'].includes(symbol.kind)) scope.types.add(symbol.name);
+    const only = symbol.returns?.length === 1 ? symbol.returns[0] : undefined;
+    if (symbol.kind === 'function' && only) scope.results.set(symbol.name, only.type);
+  }
+  return scope;
+}
+
+/**
+ * Names, type, and initializers of the const or var spec between two
+ * offsets: "A, B int = 1, 2". Undefined when it doesn't start with a name.
+ */
+function parseGoValueSpec(source: SourceText, start: number, end: number): GoValueSpec | undefined {
+  const masked = source.masked.slice(start, end);
+  const head = /^[A-Za-z_]\w*(?:\s*,\s*[A-Za-z_]\w*)*/.exec(masked);
+  if (!head) return undefined;
+
+  // The = of the spec: outside brackets and not part of ==, !=, <=, >=
+  let assign = -1;
+  let depth = 0;
+  for (let i = head[0].length; i < masked.length && assign === -1; i++) {
+    const ch = masked[i] ?? '';
+    if ('([{'.includes(ch)) depth++;
+    else if (')]}'.includes(ch)) depth--;
+    else if (ch === '=' && depth === 0 && !/[=!<>]/.test(masked[i - 1] ?? '')) {
+      if (masked[i + 1] !== '=') assign = i;
+    }
+  }
+
+  const values: string[] = [];
+  if (assign !== -1) {
+    depth = 0;
+    let from = assign + 1;
+    for (let i = from; i <= masked.length; i++) {
+      const ch = masked[i] ?? ',';
+      if ('([{'.includes(ch)) depth++;
+      else if (')]}'.includes(ch)) depth--;
+      else if (ch === ',' && depth === 0) {
+        const value = expressionText(source, start + from, start + i);
+        if (value) values.push(value);
+        from = i + 1;
+      }
+    }
+  }
+
+  const type = expressionText(source, start + head[0].length, assign === -1 ? end : start + assign);
+  return {
+    names: head[0].split(',').map(name => name.trim()),
+    type: type || undefined,
+    values,
+  };
+}
+
+/**
+ * Source between two offsets without comments, whitespace collapsed outside
+ * string literals
+ */
+function expressionText(source: SourceText, start: number, end: number): string {
+  const strings = source.strings.filter(s => s.start < end && s.end > start);
+  let text = '';
+  for (let i = start; i < end; i++) {
+    const literal = strings.find(s => s.start <= i && i < s.end);
+    if (literal) {
+      text += source.content.slice(i, Math.min(literal.end, end));
+      i = literal.end - 1;
+    } else if (/\s/.test(source.masked[i] ?? '')) {
+      if (!text.endsWith(' ')) text += ' ';
+    } else {
+      text += source.content[i];
+    }
+  }
+  return text.trim();
+}
+
+/**
+ * Offset of the last character of a spec that isn't whitespace or a comment
+ */
+function lastCodeOffset(source: SourceText, start: number, end: number): number {
+  let last = end - 1;
+  while (last > start && /\s/.test(source.masked[last] ?? '')) last--;
+  return last;
+}
+
+/**
+ * Type of a value that is evident from the expression: a literal, composite
+ * literal, conversion, make or new, errors.New or fmt.Errorf, or a call to a
+ * function of the file with one result
+ */
+function inferGoType(value: string, scope: GoValueScope): string | undefined {
+  if (/^["`]/.test(value) && /["`]$/.test(value) && !/["`]\s*\+/.test(value)) return 'string';
+  if (/^'.*'$/.test(value)) return 'rune';
+  if (value === 'true' || value === 'false') return 'bool';
+  if (/^-?(?:\d[\d_]*)?\.?\d[\d_]*(?:[eE][-+]?\d+)?i$/.test(value)) return 'complex128';
+  if (/^-?(?:\d[\d_]*\.[\d_]*|\.\d[\d_]*|\d[\d_]*[eE][-+]?\d+)(?:[eE][-+]?\d+)?$/.test(value)) {
+    return 'float64';
+  }
+  if (/^(?:errors\.New|fmt\.Errorf)\(/.test(value)) return 'error';
+
+  // A call or conversion covering the whole expression: T(x), make(T, n), f()
+  const call = /^([A-Za-z_]\w*)\(/.exec(value);
+  if (call?.[1] && closesAtEnd(value, call[0].length - 1)) {
+    const name = call[1];
+    const first = splitTopLevel(value.slice(name.length + 1, -1))[0] ?? '';
+    if (name === 'make') return first || undefined;
+    if (name === 'new') return first ? `*${first}` : undefined;
+    if (GO_BUILTIN_TYPES.has(name) || scope.types.has(name)) return name;
+    return scope.results.get(name);
+  }
+
+  // Composite and function literals: T{...}, &T{...}, []string{...}, func(...) {...}
+  const brace = value.search(/\{/);
+  if (brace > 0 && value.endsWith('}') && closesAtEnd(value, brace)) {
+    const type = value.slice(0, brace).trim();
+    if (/^(?:struct|interface)\b/.test(type)) return undefined;
+    return type.startsWith('&') ? `*${type.slice(1).trim()}` : type;
+  }
+  return undefined;
+}
+
+/**
+ * Whether the bracket at index open is closed by the last character of text.
+ * Brackets in string literals are counted, which only errs toward undefined.
+ */
+function closesAtEnd(text: string, open: number): boolean {
+  let depth = 0;
+  for (let i = open; i < text.length; i++) {
+    const ch = text[i] ?? '';
+    if ('([{'.includes(ch)) depth++;
+    else if (')]}'.includes(ch) && --depth === 0) return i === text.length - 1;
+  }
+  return false;
+}
+
+// Go operator precedence for integer constant expressions, higher binds tighter
+const GO_PRECEDENCE: Record<string, number> = {
+  '*': 2,
+  '/': 2,
+  '%': 2,
+  '<<': 2,
+  '>>': 2,
+  '&': 2,
+  '&^': 2,
+  '+': 1,
+  '-': 1,
+  '|': 1,
+  '^': 1,
+};
+
+const GO_CONSTANT_TOKEN = /0[xXbBoO][\da-fA-F_]+|\d[\d_]*|[A-Za-z_]\w*|<<|>>|&\^|\S/g;
+
+/**
+ * Value of an integer constant expression, e.g. 1 << (10 * (iota + 1)) or
+ * Weekday(iota) + 1, or undefined when it isn't one. The names it may use
+ * are iota and constants evaluated earlier; conversions keep the value.
+ */
+export function evaluateGoConstant(
+  expression: string,
+  iota: number,
+  constants: Map<string, bigint> = new Map()
+): bigint | undefined {
+  const tokens = expression.match(GO_CONSTANT_TOKEN) ?? [];
+  let pos = 0;
+
+  const operand = (): bigint | undefined => {
+    const token = tokens[pos++];
+    if (token === undefined) return undefined;
+    if (token === '(' || (/^[A-Za-z_]/.test(token) && tokens[pos] === '(')) {
+      // Parenthesized expression or a conversion: int64(x)
+      if (token !== '(') pos++;
+      const value = binary(1);
+      return tokens[pos++] === ')' ? value : undefined;
+    }
+    if (token === '-' || token === '+' || token === '^') {
+      const value = operand();
+      if (value === undefined) return undefined;
+      return token === '-' ? -value : token === '^' ? ~value : value;
+    }
+    if (/^\d/.test(token)) {
+      const digits = token.replace(/_/g, '').toLowerCase();
+      // Legacy octal: 0755
+      return BigInt(/^0\d+$/.test(digits) ? `0o${digits.slice(1)}` : digits);
+    }
+    if (token === 'iota') return BigInt(iota);
+    return constants.get(token);
+  };
+
+  const binary = (minPrecedence: number): bigint | undefined => {
+    let left = operand();
+    while (left !== undefined) {
+      const operator = tokens[pos] ?? '';
+      const precedence = GO_PRECEDENCE[operator];
+      if (precedence === undefined || precedence < minPrecedence) break;
+      pos++;
+      const right = binary(precedence + 1);
+      left = right === undefined ? undefined : applyGoOperator(operator, left, right);
+    }
+    return left;
+  };
+
+  try {
+    const value = binary(1);
+    return pos === tokens.length ? value : undefined;
+  } catch {
+    // Malformed digits, e.g. a float
+    return undefined;
+  }
+}
+
+function applyGoOperator(operator: string, left: bigint, right: bigint): bigint | undefined {
+  switch (operator) {
+    case '*':
+      return left * right;
+    case '/':
+      return right === 0n ? undefined : left / right;
+    case '%':
+      return right === 0n ? undefined : left % right;
+    case '<<':
+      return right < 0n || right > 1024n ? undefined : left << right;
+    case '>>':
+      return right < 0n ? undefined : left >> right;
+    case '&':
+      return left & right;
+    case '&^':
+      return left & ~right;
+    case '+':
+      return left + right;
+    case '-':
+      return left - right;
+    case '|':
+      return left | right;
+    case '^':
+      return left ^ right;
+    default:
+      return undefined;
+  }
+}
+
+export interface GoImport {
+  path: string;
+  name: string; // Local package name: the alias, or the last path element
+  alias?: string;
+  line: number;
+}
+
+const IMPORT_PATTERN = /^import\s*(\()?/gm;
+const IMPORT_SPEC_PATTERN = /(?:([\w.]+)[ \t]+)?"[^"\n]*"/g;
+
+/**
+ * Import specs of a Go file; commented-out imports are ignored
+ */
+export function parseGoImports(source: SourceText): GoImport[] {
+  const imports: GoImport[] = [];
+
+  IMPORT_PATTERN.lastIndex = 0;
+  let block;
+  while ((block = IMPORT_PATTERN.exec(source.masked)) !== null) {
+    const start = block.index + block[0].length;
+    const end = block[1] ? source.findMatching(start - 1) : source.statementEnd(start);
+    const specs = source.masked.slice(start, end === -1 ? undefined : end);
+
+    IMPORT_SPEC_PATTERN.lastIndex = 0;
+    let spec;
+    while ((spec = IMPORT_SPEC_PATTERN.exec(specs)) !== null) {
+      const open = start + spec.index + spec[0].indexOf('"');
+      const close = start + spec.index + spec[0].length - 1;
+      const importPath = source.content.slice(open + 1, close);
+      const alias = spec[1];
+      const segments = importPath.split('/').filter(s => !/^v\d+$/.test(s));
+
+      const entry: GoImport = {
+        path: importPath,
+        name: alias ?? segments[segments.length - 1] ?? importPath,
+        line: source.lineOf(start + spec.index),
+      };
+      if (alias) entry.alias = alias;
+      imports.push(entry);
+    }
+  }
+
+  return imports;
+}
+
+/**
+ * Offset just past the last import declaration, 0 without imports; the
+ * file's own code follows it
+ */
+export function goImportSectionEnd(source: SourceText): number {
+  let sectionEnd = 0;
+
+  IMPORT_PATTERN.lastIndex = 0;
+  let block;
+  while ((block = IMPORT_PATTERN.exec(source.masked)) !== null) {
+    const start = block.index + block[0].length;
+    const end = block[1] ? source.findMatching(start - 1) : source.statementEnd(start);
+    sectionEnd = end === -1 ? source.masked.length : end + 1;
+  }
+
+  return sectionEnd;
+}
+
+export interface GoStructField {
+  name: string;
+  type: string;
+  tag?: string | undefined; // Raw literal, e.g. `json:"name,omitempty" db:"name"`
+  tags?: Record<string, string> | undefined; // Parsed tag: {json: "name,omitempty", db: "name"}
+  embedded: boolean;
+  exported: boolean;
+  line: number;
+  doc?: string | undefined;
+}
+
+/**
+ * Fields of the struct body opened by the brace at openOffset. Fields sharing
+ * a type ("X, Y int") are returned separately; embedded types are named after
+ * their type ("*sync.Mutex" -> "Mutex").
+ */
+export function parseGoStructFields(source: SourceText, openOffset: number): GoStructField[] {
+  const close = source.findMatching(openOffset);
+  if (close === -1) return [];
+
+  const fields: GoStructField[] = [];
+  let depth = 0;
+  let segmentStart = openOffset + 1;
+
+  for (let i = openOffset + 1; i <= close; i++) {
+    const ch = source.masked[i] ?? '';
+    if ('({['.includes(ch)) depth++;
+    else if (')}]'.includes(ch) && i < close) depth--;
+
+    // Fields end at a newline or semicolon outside nested types
+    if (i === close || (depth === 0 && (ch === '\n' || ch === ';'))) {
+      fields.push(...parseFieldSegment(source, segmentStart, i));
+      segmentStart = i + 1;
+    }
+  }
+
+  return fields;
+}
+
+function parseFieldSegment(source: SourceText, start: number, end: number): GoStructField[] {
+  const masked = source.masked.slice(start, end);
+  const leading = masked.length - masked.trimStart().length;
+  const trimmed = masked.trim();
+  if (trimmed === '') return [];
+
+  const offset = start + leading;
+  const code = source.content.slice(offset, offset + trimmed.length);
+  const tagMatch = /(`[^`]*`|"[^"]*")$/.exec(trimmed);
+  const tag = tagMatch ? code.slice(tagMatch.index) : undefined;
+  const declaration = collapse(tagMatch ? code.slice(0, tagMatch.index) : code);
+  const line = source.lineOf(offset);
+  const doc = source.docCommentBefore(line)?.text;
+
+  const tags = tag ? parseStructTag(tag) : {};
+  const field = (name: string, type: string, embedded: boolean): GoStructField => {
+    const entry: GoStructField = { name, type, embedded, exported: /^[A-Z]/.test(name), line };
+    if (tag) entry.tag = tag;
+    if (Object.keys(tags).length > 0) entry.tags = tags;
+    if (doc) entry.doc = doc;
+    return entry;
+  };
+
+  const named = /^([A-Za-z_]\w*(?:\s*,\s*[A-Za-z_]\w*)*)\s+(\S.*)$/.exec(declaration);
+  if (named?.[1] && named[2] && !/^\*?[\w.]+$/.test(declaration)) {
+    const type = named[2];
+    return named[1].split(',').map(name => field(name.trim(), type, false));
+  }
+
+  const embeddedName = declaration.replace(/^\*/, '').replace(/\[.*$/, '').split('.').pop();
+  if (!embeddedName || !/^[A-Za-z_]\w*$/.test(embeddedName)) return [];
+  return [field(embeddedName, declaration, true)];
+}
+
+/**
+ * Key/value pairs of a struct tag literal following the reflect.StructTag
+ * convention (key:"value" separated by spaces). Parsing stops at the first
+ * malformed pair, as reflect's Lookup does.
+ */
+export function parseStructTag(literal: string): Record<string, string> {
+  let body = literal.slice(1, -1);
+  if (literal.startsWith('"')) {
+    try {
+      body = JSON.parse(literal) as string; // Interpreted string literal: "json:\"name\""
+    } catch {
+      return {};
+    }
+  }
+
+  const tags: Record<string, string> = {};
+  const pair = /\s*([^\s:"]+):("(?:[^"\\]|\\.)*")/y;
+  let match;
+  while ((match = pair.exec(body)) !== null) {
+    const [, key, quoted] = match;
+    if (!key || !quoted) break;
+    try {
+      tags[key] = JSON.parse(quoted) as string;
+    } catch {
+      break;
+    }
+  }
+  return tags;
+}
+
+// A call argument as written, comments removed, with its offsets
+export interface GoArgument {
+  text: string;
+  start: number;
+  end: number;
+}
+
+/**
+ * Arguments of the call whose parentheses are at open and close
+ */
+export function goCallArguments(source: SourceText, open: number, close: number): GoArgument[] {
+  const args: GoArgument[] = [];
+  let depth = 0;
+  let from = open + 1;
+  for (let i = from; i <= close; i++) {
+    const ch = source.masked[i] ?? '';
+    if (i < close && '([{'.includes(ch)) depth++;
+    else if (i < close && ')]}'.includes(ch)) depth--;
+    else if (i === close || (ch === ',' && depth === 0)) {
+      const text = expressionText(source, from, i);
+      if (text) args.push({ text, start: from, end: i });
+      from = i + 1;
+    }
+  }
+  return args;
+}
+
+/**
+ * Value of a string literal argument; undefined for any other expression
+ */
+export function goStringValue(arg: Pick<GoArgument, 'text'>): string | undefined {
+  const raw = /^`([^`]*)`$/.exec(arg.text);
+  if (raw) return raw[1];
+  if (!/^"(?:[^"\\]|\\.)*"$/.test(arg.text)) return undefined;
+  try {
+    return JSON.parse(arg.text) as string;
+  } catch {
+    // A Go escape JSON doesn't have, e.g. \x41
+    return arg.text.slice(1, -1);
+  }
+}
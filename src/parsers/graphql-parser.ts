@@ -0,0 +1,350 @@
+/**
+ * GraphQL Parser
+ * Extracts SDL type definitions with their fields, arguments, and return
+ * types, and the Query/Mutation/Subscription root operations
+ */
+
+import type { GraphQLField, Parameter, SymbolEntry, SymbolKind } from '../types/index.js';
+import { checkDeadline } from '../core/deadline.js';
+import { SourceText, GRAPHQL_SYNTAX } from './source.js';
+import type { SymbolParser } from './index.js';
+
+const DEFINITION_PATTERN =
+  /^[ \t]*(extend\s+)?(type|input|interface|enum|union|scalar|schema|directive)\b\s*@?([_A-Za-z]\w*)?/gm;
+
+const NAME = /[_A-Za-z]\w*/y;
+
+// Named, list, and non-null types: String, [ID!]!
+const TYPE_REFERENCE = /\s*((?:\[\s*)*[_A-Za-z]\w*(?:\s*!)?(?:\s*\]\s*!?)*)/y;
+
+const DEFINITION_KINDS: Record<string, SymbolKind> = {
+  type: 'type',
+  input: 'type',
+  union: 'type',
+  scalar: 'type',
+  interface: 'interface',
+  enum: 'enum',
+};
+
+const ROOT_OPERATIONS: Record<string, string> = {
+  query: 'Query',
+  mutation: 'Mutation',
+  subscription: 'Subscription',
+};
+
+interface Definition {
+  keyword: string;
+  name: string;
+  start: number; // The keyword, or extend
+  end: number; // Next definition, or the end of the file
+}
+
+interface ParsedField extends GraphQLField {
+  start: number;
+  end: number; // Exclusive, after the type and directives
+  deprecation?: string | undefined;
+}
+
+export class GraphQLParser implements SymbolParser {
+  readonly language = 'graphql';
+  readonly version = 1;
+  readonly syntax = GRAPHQL_SYNTAX;
+  readonly extensions = ['.graphql', '.gql'];
+
+  parse(content: string, file: string): SymbolEntry[] {
+    const source = new SourceText(content, this.syntax);
+    const definitions = findDefinitions(source);
+    const roots = rootTypes(source, definitions);
+    const symbols: SymbolEntry[] = [];
+
+    for (const definition of definitions) {
+      checkDeadline();
+      const kind = DEFINITION_KINDS[definition.keyword];
+      if (!kind || !definition.name) continue;
+
+      const body = definitionBody(source, definition);
+      const headerEnd = body?.open ?? lastCodeOffset(source, definition.start, definition.end);
+      const end = body ? body.close + 1 : headerEnd;
+      const fields = body ? parseFields(source, body.open, body.close) : [];
+      const symbol = createSymbol(source, file, definition.start, {
+        name: definition.name,
+        kind,
+        endLine: source.lineOf(Math.max(definition.start, end - 1)),
+        signature: source.codeText(definition.start, headerEnd),
+      });
+      if (fields.length > 0) symbol.fields = fields.map(publicField);
+      symbols.push(symbol);
+
+      if (definition.keyword !== 'type' || !roots.has(definition.name)) continue;
+      for (const field of fields) {
+        const operation = createSymbol(source, file, field.start, {
+          name: field.name,
+          kind: 'method',
+          endLine: source.lineOf(Math.max(field.start, field.end - 1)),
+          signature: operationSignature(field),
+        });
+        operation.parent = definition.name;
+        operation.params = field.args ?? [];
+        if (field.type) operation.returns = [{ type: field.type }];
+        if (field.deprecated) {
+          operation.deprecated = true;
+          if (field.deprecation) operation.deprecation = field.deprecation;
+        }
+        symbols.push(operation);
+      }
+    }
+
+    return symbols.sort((a, b) => a.line - b.line);
+  }
+}
+
+/**
+ * name(arg: Type, ...): Type, without argument descriptions and defaults
+ */
+function operationSignature(field: ParsedField): string {
+  const args = field.args?.map(arg => `${arg.name ?? ''}: ${arg.type}`).join(', ');
+  return `${field.name}${args === undefined ? '' : `(${args})`}: ${field.type ?? ''}`;
+}
+
+function createSymbol(
+  source: SourceText,
+  file: string,
+  start: number,
+  fields: Pick<SymbolEntry, 'name' | 'kind' | 'endLine' | 'signature'>
+): SymbolEntry {
+  const symbol: SymbolEntry = {
+    ...fields,
+    language: 'graphql',
+    file,
+    line: source.lineOf(start),
+    exported: true,
+  };
+  const doc = description(source, start);
+  if (doc) symbol.doc = doc;
+  return symbol;
+}
+
+/**
+ * Top-level definitions in source order; directive arguments and fields
+ * named like keywords sit inside brackets and are skipped
+ */
+function findDefinitions(source: SourceText): Definition[] {
+  const found: Array<Omit<Definition, 'end'>> = [];
+  DEFINITION_PATTERN.lastIndex = 0;
+  let match;
+  while ((match = DEFINITION_PATTERN.exec(source.masked)) !== null) {
+    const start = match.index + match[0].length - match[0].trimStart().length;
+    if (source.depthAt(start) !== 0 || inParens(source, start)) continue;
+    found.push({ keyword: match[2] ?? '', name: match[3] ?? '', start });
+  }
+  return found.map((definition, i) => ({
+    ...definition,
+    end: found[i + 1]?.start ?? source.masked.length,
+  }));
+}
+
+function inParens(source: SourceText, offset: number): boolean {
+  let depth = 0;
+  for (let i = source.lineStart(source.lineOf(offset)) - 1; i >= 0; i--) {
+    const ch = source.masked[i];
+    if (ch === ')') depth++;
+    else if (ch === '(' && depth-- === 0) return true;
+    else if (ch === '}' || ch === '{') return false;
+  }
+  return false;
+}
+
+/**
+ * Operation root type names: Query, Mutation, and Subscription unless a
+ * schema definition names others
+ */
+function rootTypes(source: SourceText, definitions: Definition[]): Set<string> {
+  const roots = { ...ROOT_OPERATIONS };
+  for (const definition of definitions.filter(d => d.keyword === 'schema')) {
+    const body = definitionBody(source, definition);
+    if (!body) continue;
+    const text = source.masked.slice(body.open + 1, body.close);
+    const operations = text.matchAll(/\b(query|mutation|subscription)\s*:\s*(\w+)/g);
+    for (const [, operation, type] of operations) {
+      if (operation && type) roots[operation] = type;
+    }
+  }
+  return new Set(Object.values(roots));
+}
+
+function definitionBody(
+  source: SourceText,
+  definition: Definition
+): { open: number; close: number } | undefined {
+  const open = source.masked.indexOf('{', definition.start);
+  if (open === -1 || open >= definition.end) return undefined;
+  const close = source.findMatching(open);
+  return close === -1 ? undefined : { open, close };
+}
+
+/**
+ * Fields, or enum values, of the body between braces. Fields keep their
+ * argument list and type; enum values have neither.
+ */
+function parseFields(source: SourceText, open: number, close: number): ParsedField[] {
+  const text = source.masked;
+  const fields: ParsedField[] = [];
+  let i = open + 1;
+
+  while (i < close) {
+    NAME.lastIndex = i;
+    const name = NAME.exec(text);
+    if (!name) {
+      i++;
+      continue;
+    }
+    const field: ParsedField = { name: name[0], line: source.lineOf(i), start: i, end: i };
+    i = NAME.lastIndex;
+
+    i = skipSpace(text, i);
+    if (text[i] === '(') {
+      const argsClose = source.findMatching(i);
+      if (argsClose === -1 || argsClose > close) break;
+      field.args = parseArguments(source, i, argsClose);
+      i = skipSpace(text, argsClose + 1);
+    }
+    if (text[i] === ':') {
+      TYPE_REFERENCE.lastIndex = i + 1;
+      const type = TYPE_REFERENCE.exec(text);
+      if (type?.[1]) {
+        field.type = type[1].replace(/\s+/g, '');
+        i = TYPE_REFERENCE.lastIndex;
+      }
+    }
+    i = skipDefault(source, i);
+    i = readDirectives(source, i, field);
+
+    field.end = lastCodeOffset(source, field.start, i);
+    const doc = description(source, field.start);
+    if (doc) field.doc = doc;
+    fields.push(field);
+  }
+
+  return fields;
+}
+
+/**
+ * Arguments between parentheses as parameters; default values and
+ * directives are dropped
+ */
+function parseArguments(source: SourceText, open: number, close: number): Parameter[] {
+  return parseFields(source, open, close)
+    .filter(arg => arg.type)
+    .map(arg => ({ name: arg.name, type: arg.type ?? '' }));
+}
+
+/**
+ * @directive and @directive(args) after a field; @deprecated marks it
+ */
+function readDirectives(source: SourceText, offset: number, field: ParsedField): number {
+  const text = source.masked;
+  let i = skipSpace(text, offset);
+  while (text[i] === '@') {
+    NAME.lastIndex = i + 1;
+    const name = NAME.exec(text)?.[0] ?? '';
+    i = name ? NAME.lastIndex : i + 1;
+    let args = '';
+    const next = skipSpace(text, i);
+    if (text[next] === '(') {
+      const close = source.findMatching(next);
+      if (close === -1) return source.masked.length;
+      args = source.content.slice(next, close + 1);
+      i = close + 1;
+    }
+    if (name === 'deprecated') {
+      field.deprecated = true;
+      const reason = /reason\s*:\s*"((?:\\.|[^"\\])*)"/.exec(args)?.[1];
+      if (reason) field.deprecation = reason;
+    }
+    i = skipSpace(text, i);
+  }
+  return i;
+}
+
+/**
+ * Past "= value" after an argument or input field type
+ */
+function skipDefault(source: SourceText, offset: number): number {
+  const text = source.masked;
+  let i = skipSpace(text, offset);
+  if (text[i] !== '=') return offset;
+  i = skipSpace(text, i + 1);
+  if (text[i] === '{' || text[i] === '[') {
+    const close = source.findMatching(i);
+    return close === -1 ? text.length : close + 1;
+  }
+  const literal = source.strings.find(s => s.start === i);
+  if (literal) return literal.end;
+  while (i < text.length && !/[\s,)}@]/.test(text[i] ?? '')) i++;
+  return i;
+}
+
+function skipSpace(text: string, offset: number): number {
+  let i = offset;
+  while (i < text.length && /[\s,]/.test(text[i] ?? '')) i++;
+  return i;
+}
+
+/**
+ * Just past the last character of code before end, so a span doesn't take
+ * in the whitespace or comments that follow it
+ */
+function lastCodeOffset(source: SourceText, start: number, end: number): number {
+  let i = end;
+  while (i > start && /[\s,]/.test(source.masked[i - 1] ?? '')) i--;
+  return i;
+}
+
+/**
+ * The description string directly before an offset, or # comments when
+ * there is none
+ */
+function description(source: SourceText, offset: number): string | undefined {
+  let literal;
+  for (let i = source.strings.length - 1; i >= 0; i--) {
+    const s = source.strings[i];
+    if (s && s.end <= offset) {
+      literal = s;
+      break;
+    }
+  }
+  // Only a string on its own: not a default value (= "x") or a directive argument
+  const before = literal && source.masked[lastCodeOffset(source, 0, literal.start) - 1];
+  if (literal && source.masked.slice(literal.end, offset).trim() === '' && before !== '=') {
+    const raw = source.content.slice(literal.start, literal.end);
+    return raw.startsWith('"""') ? dedent(raw.slice(3, -3)) : raw.slice(1, -1);
+  }
+  return source.docCommentBefore(source.lineOf(offset))?.text;
+}
+
+/**
+ * Block string value: common indentation and blank first and last lines removed
+ */
+function dedent(text: string): string {
+  const lines = text.split('\n');
+  const indents = lines
+    .slice(1)
+    .filter(line => line.trim() !== '')
+    .map(line => /^[ \t]*/.exec(line)?.[0].length ?? 0);
+  const indent = indents.length > 0 ? Math.min(...indents) : 0;
+  return lines
+    .map((line, i) => (i === 0 ? line : line.slice(indent)))
+    .join('\n')
+    .trim();
+}
+
+function publicField(field: ParsedField): GraphQLField {
+  return {
+    name: field.name,
+    line: field.line,
+    ...(field.type ? { type: field.type } : {}),
+    ...(field.args ? { args: field.args } : {}),
+    ...(field.deprecated ? { deprecated: true } : {}),
+    ...(field.doc ? { doc: field.doc } : {}),
+  };
+}
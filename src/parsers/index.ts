@@ -0,0 +1,81 @@
+/**
+ * Parser Registry
+ * Maps file extensions to language-specific symbol parsers
+ */
+
+import type { SymbolEntry } from '../types/index.js';
+import { ByteOffsets } from '../core/byte-offsets.js';
+import { detectLanguageFromContent, detectLanguageFromName } from '../core/language-detector.js';
+import type { LexicalSyntax } from './source.js';
+import { CppParser } from './cpp-parser.js';
+import { GoParser } from './go-parser.js';
+import { GraphQLParser } from './graphql-parser.js';
+import { JavaParser } from './java-parser.js';
+import { KotlinParser } from './kotlin-parser.js';
+import { PythonParser } from './python-parser.js';
+import { RustParser } from './rust-parser.js';
+import { SqlParser } from './sql-parser.js';
+import { SwiftParser } from './swift-parser.js';
+import { TypeScriptParser } from './typescript-parser.js';
+
+export interface SymbolParser {
+  readonly language: string;
+  readonly version: number; // Bumped whenever the symbols it emits for the same source change
+  readonly aliases?: string[]; // Other detected languages this parser handles
+  readonly extensions: string[];
+  readonly syntax: LexicalSyntax;
+  parse(content: string, file: string): SymbolEntry[];
+}
+
+const PARSERS: SymbolParser[] = [
+  new GoParser(),
+  new TypeScriptParser(),
+  new PythonParser(),
+  new RustParser(),
+  new JavaParser(),
+  new KotlinParser(),
+  new SwiftParser(),
+  new CppParser(),
+  new GraphQLParser(),
+  new SqlParser(),
+];
+
+export function getParser(language: string): SymbolParser | undefined {
+  return PARSERS.find(p => p.language === language || p.aliases?.includes(language));
+}
+
+/**
+ * Parser for a file, chosen by detected language: extension or well-known
+ * name first, then shebang and content heuristics when content is given
+ */
+export function getParserForFile(file: string, content?: string): SymbolParser | undefined {
+  const detected =
+    detectLanguageFromName(file) ??
+    (content !== undefined ? detectLanguageFromContent(content) : null);
+  return detected ? getParser(detected.language) : undefined;
+}
+
+export function getSupportedExtensions(): string[] {
+  return PARSERS.flatMap(p => p.extensions);
+}
+
+export function getSupportedLanguages(): string[] {
+  return PARSERS.map(p => p.language);
+}
+
+export function getParsers(): readonly SymbolParser[] {
+  return PARSERS;
+}
+
+/**
+ * Parse a file and attach UTF-8 byte ranges, which run from a declaration's
+ * first character to the end of its last line
+ */
+export function parseSymbols(parser: SymbolParser, content: string, file: string): SymbolEntry[] {
+  const offsets = new ByteOffsets(content);
+  return parser.parse(content, file).map(s => ({
+    ...s,
+    startByte: offsets.lineStart(s.line),
+    endByte: offsets.lineEnd(s.endLine),
+  }));
+}
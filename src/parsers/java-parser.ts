@@ -0,0 +1,337 @@
+/**
+ * Java Parser
+ * Extracts the package, classes, interfaces, enums, records, and methods with
+ * their modifiers, annotations, and Javadoc
+ */
+
+import type { SymbolEntry, SymbolKind } from '../types/index.js';
+import { checkDeadline } from '../core/deadline.js';
+import { SourceText, JAVA_SYNTAX, cleanComment } from './source.js';
+import type { SymbolParser } from './index.js';
+
+const IDENT = '[A-Za-z_$][\\w$]*';
+const MODIFIERS =
+  '((?:(?:public|protected|private|static|final|abstract|sealed|non-sealed|strictfp|default|synchronized|native)\\s+)*)';
+// Type arguments nested up to three deep: Map<String, List<Set<Long>>>
+const GENERICS = '<(?:[^<>;{}()]|<(?:[^<>;{}()]|<[^<>;{}()]*>)*>)*>';
+const TYPE = `[\\w$.]+(?:\\s*${GENERICS})?(?:\\s*\\[\\s*\\])*`;
+
+const PACKAGE_PATTERN = /^[ \t]*package\s+([\w$.]+)\s*;/m;
+const TYPE_PATTERN = new RegExp(
+  `(?<![\\w$@.])${MODIFIERS}(class|interface|enum|record|@\\s*interface)\\s+(${IDENT})`,
+  'g'
+);
+const METHOD_PATTERN = new RegExp(
+  `(?<![\\w$@.])${MODIFIERS}(?:${GENERICS}\\s*)?(?:(${TYPE})\\s+)?(${IDENT})\\s*\\(`,
+  'g'
+);
+
+// Words that can precede "name(" in a type body without declaring a method
+const NOT_TYPES = new Set(['new', 'return', 'throw', 'else', 'case', 'yield', 'assert']);
+
+const TYPE_KINDS: Record<string, SymbolKind> = {
+  class: 'class',
+  record: 'class',
+  enum: 'enum',
+  interface: 'interface',
+  '@interface': 'interface', // Annotation types
+};
+
+interface TypeBlock {
+  name: string; // Qualified by enclosing types: Outer.Inner
+  keyword: string;
+  open: number;
+  close: number;
+  exported: boolean;
+}
+
+export class JavaParser implements SymbolParser {
+  readonly language = 'java';
+  readonly version = 1;
+  readonly syntax = JAVA_SYNTAX;
+  readonly extensions = ['.java'];
+
+  parse(content: string, file: string): SymbolEntry[] {
+    const source = new SourceText(content, this.syntax);
+    const symbols: SymbolEntry[] = [];
+
+    const pkg = PACKAGE_PATTERN.exec(source.masked);
+    if (pkg) {
+      const start = itemStart(pkg);
+      symbols.push(
+        this.createSymbol(source, start, {
+          name: pkg[1] ?? '',
+          kind: 'package',
+          file,
+          endLine: source.lineOf(start),
+          signature: collapse(source.content.slice(start, pkg.index + pkg[0].length - 1)),
+          exported: true,
+        })
+      );
+    }
+
+    const blocks = this.findTypes(source, file, symbols);
+
+    for (const match of matches(source, METHOD_PATTERN)) {
+      const start = itemStart(match);
+      const owner = this.enclosingType(source, blocks, start);
+      if (!owner) continue;
+
+      const name = match[3] ?? '';
+      const returnType = match[2];
+      const simpleName = owner.name.split('.').pop();
+      // Without a return type only a constructor declares a method; enum
+      // constants like RED("r") look the same otherwise
+      if (returnType ? NOT_TYPES.has(returnType) : name !== simpleName) continue;
+
+      const paramsOpen = match.index + match[0].length - 1;
+      const paramsClose = source.findMatching(paramsOpen);
+      if (paramsClose === -1) continue;
+      const [bodyOpen, end] = this.memberBody(source, paramsClose + 1);
+      const modifiers = splitModifiers(match[1]);
+
+      symbols.push(
+        this.createSymbol(source, start, {
+          name,
+          kind: 'method',
+          file,
+          endLine: source.lineOf(end),
+          signature: collapse(source.content.slice(start, bodyOpen ?? end)),
+          exported: owner.exported && isVisible(modifiers, owner),
+          parent: owner.name,
+          modifiers,
+        })
+      );
+    }
+
+    return symbols.sort((a, b) => a.line - b.line);
+  }
+
+  /**
+   * Type declarations at file level or directly inside another type, added
+   * to symbols; local and anonymous classes inside method bodies are skipped
+   */
+  private findTypes(source: SourceText, file: string, symbols: SymbolEntry[]): TypeBlock[] {
+    const blocks: TypeBlock[] = [];
+
+    // Outer types come first in the source, so their blocks exist by the time
+    // a nested type looks for its parent
+    for (const match of matches(source, TYPE_PATTERN)) {
+      const start = itemStart(match);
+      const owner = source.depthAt(start) === 0 ? null : this.enclosingType(source, blocks, start);
+      if (owner === undefined) continue;
+
+      const open = this.typeBody(source, match.index + match[0].length);
+      const close = open === -1 ? -1 : source.findMatching(open);
+      if (close === -1) continue;
+
+      const keyword = (match[2] ?? '').replace(/\s+/g, '');
+      const simpleName = match[3] ?? '';
+      const modifiers = splitModifiers(match[1]);
+      const exported = (owner?.exported ?? true) && isVisible(modifiers, owner);
+
+      blocks.push({
+        name: owner ? `${owner.name}.${simpleName}` : simpleName,
+        keyword,
+        open,
+        close,
+        exported,
+      });
+      symbols.push(
+        this.createSymbol(source, start, {
+          name: simpleName,
+          kind: TYPE_KINDS[keyword] ?? 'class',
+          file,
+          endLine: source.lineOf(close),
+          signature: collapse(source.content.slice(start, open)),
+          exported,
+          parent: owner?.name,
+          modifiers,
+        })
+      );
+    }
+
+    return blocks;
+  }
+
+  /**
+   * The type whose body directly contains an offset, or undefined when the
+   * offset is at file level or nested inside a method or initializer
+   */
+  private enclosingType(
+    source: SourceText,
+    blocks: TypeBlock[],
+    offset: number
+  ): TypeBlock | undefined {
+    let innermost: TypeBlock | undefined;
+    for (const block of blocks) {
+      if (block.open < offset && offset < block.close) {
+        if (!innermost || block.open > innermost.open) innermost = block;
+      }
+    }
+
+    if (innermost && source.depthAt(innermost.open) + 1 === source.depthAt(offset)) {
+      return innermost;
+    }
+    return undefined;
+  }
+
+  /**
+   * Opening brace of a type body, skipping record components and type
+   * parameters; -1 if a `;` comes first
+   */
+  private typeBody(source: SourceText, from: number): number {
+    for (let i = from; i < source.masked.length; i++) {
+      const ch = source.masked[i];
+      if (ch === '(' || ch === '<') {
+        const close = source.findMatching(i);
+        if (close === -1) return -1;
+        i = close;
+      } else if (ch === '{') {
+        return i;
+      } else if (ch === ';') {
+        return -1;
+      }
+    }
+    return -1;
+  }
+
+  /**
+   * Body of a method after its parameter list: [bodyOpen, end], or no body
+   * for abstract and interface methods ending in `;`
+   */
+  private memberBody(source: SourceText, from: number): [number | undefined, number] {
+    for (let i = from; i < source.masked.length; i++) {
+      const ch = source.masked[i];
+      if (ch === '(') {
+        // Annotation type defaults: String[] value() default {"a"};
+        const close = source.findMatching(i);
+        if (close === -1) break;
+        i = close;
+      } else if (ch === '{') {
+        const close = source.findMatching(i);
+        if (/\bdefault\s*$/.test(source.masked.slice(from, i))) {
+          i = close === -1 ? i : close;
+          continue;
+        }
+        return [i, close === -1 ? i : close];
+      } else if (ch === ';') {
+        return [undefined, i];
+      }
+    }
+    return [undefined, source.masked.length - 1];
+  }
+
+  private createSymbol(
+    source: SourceText,
+    start: number,
+    fields: Omit<SymbolEntry, 'language' | 'line' | 'doc' | 'decorators'>
+  ): SymbolEntry {
+    const line = source.lineOf(start);
+    const symbol: SymbolEntry = { ...fields, language: this.language, line };
+    if (fields.parent === undefined) delete symbol.parent;
+    if (fields.modifiers?.length === 0) delete symbol.modifiers;
+
+    const { annotations, start: declarationStart } = this.annotationsBefore(source, start);
+    if (annotations.length > 0) symbol.decorators = annotations;
+
+    const doc = this.javadocBefore(source, declarationStart);
+    if (doc) symbol.doc = doc;
+    return symbol;
+  }
+
+  /**
+   * Annotations in front of a declaration, e.g. @Override or
+   * @GetMapping("/users/{id}"), and the offset of the first one
+   */
+  private annotationsBefore(
+    source: SourceText,
+    offset: number
+  ): { annotations: string[]; start: number } {
+    const masked = source.masked;
+    const annotations: string[] = [];
+    let start = offset;
+
+    for (;;) {
+      let i = start - 1;
+      while (i >= 0 && /\s/.test(masked[i] ?? '')) i--;
+      const end = i + 1;
+
+      if (masked[i] === ')') {
+        const open = matchingOpen(masked, i);
+        if (open === -1) break;
+        i = open - 1;
+      }
+      const nameEnd = i + 1;
+      while (i >= 0 && /[\w$.]/.test(masked[i] ?? '')) i--;
+      if (i + 1 === nameEnd || masked[i] !== '@') break;
+
+      annotations.unshift(collapse(source.content.slice(i, end)));
+      start = i;
+    }
+
+    return { annotations, start };
+  }
+
+  /**
+   * Javadoc (/** ... *\/) directly in front of a declaration or its
+   * annotations; plain comments are not documentation
+   */
+  private javadocBefore(source: SourceText, offset: number): string | undefined {
+    for (let i = source.comments.length - 1; i >= 0; i--) {
+      const comment = source.comments[i];
+      if (!comment || comment.end > offset) continue;
+      // Comments are blanked in masked text, so only whitespace may separate them
+      if (source.masked.slice(comment.end, offset).trim() !== '') return undefined;
+      if (comment.text.startsWith('/**')) return cleanComment(comment) || undefined;
+    }
+    return undefined;
+  }
+}
+
+function* matches(source: SourceText, pattern: RegExp): Generator<RegExpExecArray> {
+  pattern.lastIndex = 0;
+  let match;
+  while ((match = pattern.exec(source.masked)) !== null) {
+    checkDeadline();
+    yield match;
+  }
+}
+
+/**
+ * Members of interfaces and annotation types are public unless marked
+ * private; otherwise public and protected members are visible to other packages
+ */
+function isVisible(modifiers: string[], owner: TypeBlock | null | undefined): boolean {
+  if (owner && (owner.keyword === 'interface' || owner.keyword === '@interface')) {
+    return !modifiers.includes('private');
+  }
+  return modifiers.includes('public') || modifiers.includes('protected');
+}
+
+function splitModifiers(text: string | undefined): string[] {
+  return text?.trim() ? text.trim().split(/\s+/) : [];
+}
+
+/**
+ * Offset of the ( matching the ) at closeOffset, in masked text
+ */
+function matchingOpen(masked: string, closeOffset: number): number {
+  let depth = 0;
+  for (let i = closeOffset; i >= 0; i--) {
+    if (masked[i] === ')') depth++;
+    else if (masked[i] === '(' && --depth === 0) return i;
+  }
+  return -1;
+}
+
+/**
+ * Offset of the first non-blank character of a match
+ */
+function itemStart(match: RegExpExecArray): number {
+  return match.index + Math.max(0, match[0].search(/\S/));
+}
+
+function collapse(text: string): string {
+  return text.replace(/\s+/g, ' ').trim();
+}
@@ -0,0 +1,378 @@
+/**
+ * Kotlin Parser
+ * Extracts the package, classes, interfaces, objects, enum classes, and
+ * functions with their modifiers, annotations, and KDoc; extension functions
+ * attach to their receiver type
+ */
+
+import type { SymbolEntry, SymbolKind } from '../types/index.js';
+import { checkDeadline } from '../core/deadline.js';
+import { SourceText, KOTLIN_SYNTAX, cleanComment } from './source.js';
+import type { SymbolParser } from './index.js';
+
+// Backticked names are common for test functions: fun `parses empty input`()
+const IDENT = '(?:[A-Za-z_]\\w*|`[^`\\n]+`)';
+const MODIFIERS =
+  '((?:(?:public|protected|private|internal|abstract|open|final|sealed|data|enum|annotation|inner|value|inline|companion|expect|actual|external|override|operator|infix|tailrec|suspend)\\s+)*)';
+// Type arguments nested up to three deep: Map<String, List<Set<Long>>>
+const GENERICS = '<(?:[^<>;{}()]|<(?:[^<>;{}()]|<[^<>;{}()]*>)*>)*>';
+const RECEIVER = `[\\w.]+(?:\\s*${GENERICS})?\\??`;
+
+const PACKAGE_PATTERN = /^[ \t]*package\s+([\w.]+)/m;
+// Not Foo::class; an object without a name is only a declaration when it's a companion
+const TYPE_PATTERN = new RegExp(
+  `(?<![\\w@.:])${MODIFIERS}(class|interface|object|fun\\s+interface)\\b(?:\\s+(${IDENT}))?`,
+  'g'
+);
+const FUN_PATTERN = new RegExp(
+  `(?<![\\w@.:])${MODIFIERS}fun\\s+(?:${GENERICS}\\s*)?(?:(${RECEIVER})\\s*\\.\\s*)?(${IDENT})\\s*\\(`,
+  'g'
+);
+// Secondary constructors; a primary constructor is part of the class header
+const CONSTRUCTOR_PATTERN = new RegExp(`^[ \\t]*${MODIFIERS}constructor\\s*\\(`, 'gm');
+
+interface TypeBlock {
+  name: string; // Qualified by enclosing types: Outer.Inner
+  open: number;
+  close: number;
+  exported: boolean;
+}
+
+interface Header {
+  open?: number | undefined; // The body's opening brace
+  end: number; // The brace, or the end of a declaration without a body
+}
+
+export class KotlinParser implements SymbolParser {
+  readonly language = 'kotlin';
+  readonly version = 1;
+  readonly syntax = KOTLIN_SYNTAX;
+  readonly extensions = ['.kt', '.kts'];
+
+  parse(content: string, file: string): SymbolEntry[] {
+    const source = new SourceText(content, this.syntax);
+    const symbols: SymbolEntry[] = [];
+
+    const pkg = PACKAGE_PATTERN.exec(source.masked);
+    if (pkg) {
+      const start = itemStart(pkg);
+      symbols.push(
+        this.createSymbol(source, start, {
+          name: pkg[1] ?? '',
+          kind: 'package',
+          file,
+          endLine: source.lineOf(start),
+          signature: collapse(source.content.slice(start, pkg.index + pkg[0].length)),
+          exported: true,
+        })
+      );
+    }
+
+    const blocks = this.findTypes(source, file, symbols);
+
+    for (const match of matches(source, FUN_PATTERN)) {
+      const start = itemStart(match);
+      const owner = this.enclosingType(source, blocks, start);
+      // Local functions inside bodies are skipped
+      if (!owner && source.depthAt(start) !== 0) continue;
+
+      const paramsOpen = match.index + match[0].length - 1;
+      const paramsClose = source.findMatching(paramsOpen);
+      if (paramsClose === -1) continue;
+      const [bodyOpen, end] = this.memberBody(source, paramsClose + 1);
+      const modifiers = splitModifiers(match[1]);
+      // fun <T> List<T>.second() belongs to List
+      const receiver = match[2]?.replace(new RegExp(`\\s*${GENERICS}`), '').replace(/\?$/, '');
+      const parent = owner?.name ?? receiver;
+
+      symbols.push(
+        this.createSymbol(source, start, {
+          name: unquote(match[3] ?? ''),
+          kind: parent ? 'method' : 'function',
+          file,
+          endLine: source.lineOf(end),
+          signature: collapse(source.content.slice(start, bodyOpen ?? end)),
+          exported: (owner?.exported ?? true) && isVisible(modifiers),
+          parent,
+          modifiers,
+        })
+      );
+    }
+
+    for (const match of matches(source, CONSTRUCTOR_PATTERN)) {
+      const start = itemStart(match);
+      const owner = this.enclosingType(source, blocks, start);
+      if (!owner) continue;
+
+      const paramsOpen = match.index + match[0].length - 1;
+      const paramsClose = source.findMatching(paramsOpen);
+      if (paramsClose === -1) continue;
+      const [bodyOpen, end] = this.memberBody(source, paramsClose + 1);
+      const modifiers = splitModifiers(match[1]);
+
+      symbols.push(
+        this.createSymbol(source, start, {
+          name: 'constructor',
+          kind: 'method',
+          file,
+          endLine: source.lineOf(end),
+          signature: collapse(source.content.slice(start, bodyOpen ?? end)),
+          exported: owner.exported && isVisible(modifiers),
+          parent: owner.name,
+          modifiers,
+        })
+      );
+    }
+
+    return symbols.sort((a, b) => a.line - b.line);
+  }
+
+  /**
+   * Type declarations at file level or directly inside another type, added
+   * to symbols; local classes and object expressions are skipped
+   */
+  private findTypes(source: SourceText, file: string, symbols: SymbolEntry[]): TypeBlock[] {
+    const blocks: TypeBlock[] = [];
+
+    // Outer types come first in the source, so their blocks exist by the time
+    // a nested type looks for its parent
+    for (const match of matches(source, TYPE_PATTERN)) {
+      const start = itemStart(match);
+      const owner = source.depthAt(start) === 0 ? null : this.enclosingType(source, blocks, start);
+      if (owner === undefined) continue;
+
+      const keyword = (match[2] ?? '').replace(/\s+/g, ' ');
+      const modifiers = splitModifiers(match[1]);
+      const companion = keyword === 'object' && modifiers.includes('companion');
+      if (!match[3] && !companion) continue;
+
+      const simpleName = match[3] ? unquote(match[3]) : 'Companion';
+      const header = this.header(source, match.index + match[0].length);
+      const close = header.open === undefined ? -1 : source.findMatching(header.open);
+      const exported = (owner?.exported ?? true) && isVisible(modifiers);
+      const name = owner ? `${owner.name}.${simpleName}` : simpleName;
+      if (header.open !== undefined && close !== -1) {
+        blocks.push({ name, open: header.open, close, exported });
+      }
+
+      symbols.push(
+        this.createSymbol(source, start, {
+          name: simpleName,
+          kind: typeKind(keyword, modifiers),
+          file,
+          endLine: source.lineOf(close === -1 ? header.end : close),
+          signature: collapse(source.content.slice(start, header.end)),
+          exported,
+          parent: owner?.name,
+          modifiers,
+        })
+      );
+    }
+
+    return blocks;
+  }
+
+  /**
+   * The type whose body directly contains an offset, or undefined when the
+   * offset is at file level or nested inside a function or initializer
+   */
+  private enclosingType(
+    source: SourceText,
+    blocks: TypeBlock[],
+    offset: number
+  ): TypeBlock | undefined {
+    let innermost: TypeBlock | undefined;
+    for (const block of blocks) {
+      if (block.open < offset && offset < block.close) {
+        if (!innermost || block.open > innermost.open) innermost = block;
+      }
+    }
+
+    if (innermost && source.depthAt(innermost.open) + 1 === source.depthAt(offset)) {
+      return innermost;
+    }
+    return undefined;
+  }
+
+  /**
+   * Where a class header ends, skipping the primary constructor, type
+   * parameters, and supertypes: at the body's brace, or at the end of the
+   * line for a class without a body, e.g. data class Point(val x: Int)
+   */
+  private header(source: SourceText, from: number): Header {
+    const masked = source.masked;
+    for (let i = from; i < masked.length; i++) {
+      const ch = masked[i];
+      if (ch === '(' || ch === '<') {
+        const close = source.findMatching(i);
+        if (close === -1) return { end: i };
+        i = close;
+      } else if (ch === '{') {
+        return { open: i, end: i };
+      } else if (ch === ';' || ch === '}' || (ch === '\n' && !continues(masked, from, i))) {
+        return { end: i };
+      }
+    }
+    return { end: masked.length };
+  }
+
+  /**
+   * The rest of a function after its parameter list: [signatureEnd, end],
+   * where the signature ends at a block body's brace or an expression body's
+   * `=`, or runs to the end for abstract and interface functions
+   */
+  private memberBody(source: SourceText, from: number): [number | undefined, number] {
+    const masked = source.masked;
+    for (let i = from; i < masked.length; i++) {
+      const ch = masked[i];
+      if (ch === '(' || ch === '[' || ch === '<') {
+        // Function types and generics in the return type
+        const close = source.findMatching(i);
+        if (close === -1) break;
+        i = close;
+      } else if (ch === '{') {
+        const close = source.findMatching(i);
+        return [i, close === -1 ? i : close];
+      } else if (ch === '=') {
+        return [i, source.statementEnd(i + 1)];
+      } else if (ch === ';' || ch === '}' || (ch === '\n' && !continues(masked, from, i))) {
+        return [undefined, i];
+      }
+    }
+    return [undefined, masked.length - 1];
+  }
+
+  private createSymbol(
+    source: SourceText,
+    start: number,
+    fields: Omit<SymbolEntry, 'language' | 'line' | 'doc' | 'decorators'>
+  ): SymbolEntry {
+    const line = source.lineOf(start);
+    const symbol: SymbolEntry = { ...fields, language: this.language, line };
+    if (fields.parent === undefined) delete symbol.parent;
+    if (fields.modifiers?.length === 0) delete symbol.modifiers;
+
+    const { annotations, start: declarationStart } = this.annotationsBefore(source, start);
+    if (annotations.length > 0) symbol.decorators = annotations;
+
+    const doc = this.kdocBefore(source, declarationStart);
+    if (doc) symbol.doc = doc;
+    return symbol;
+  }
+
+  /**
+   * Annotations in front of a declaration, e.g. @Composable or
+   * @field:Json(name = "id"), and the offset of the first one
+   */
+  private annotationsBefore(
+    source: SourceText,
+    offset: number
+  ): { annotations: string[]; start: number } {
+    const masked = source.masked;
+    const annotations: string[] = [];
+    let start = offset;
+
+    for (;;) {
+      let i = start - 1;
+      while (i >= 0 && /\s/.test(masked[i] ?? '')) i--;
+      const end = i + 1;
+
+      if (masked[i] === ')') {
+        const open = matchingOpen(masked, i);
+        if (open === -1) break;
+        i = open - 1;
+      }
+      const nameEnd = i + 1;
+      while (i >= 0 && /[\w.:]/.test(masked[i] ?? '')) i--;
+      if (i + 1 === nameEnd || masked[i] !== '@') break;
+
+      annotations.unshift(collapse(source.content.slice(i, end)));
+      start = i;
+    }
+
+    return { annotations, start };
+  }
+
+  /**
+   * KDoc (/** ... *\/) directly in front of a declaration or its
+   * annotations; plain comments are not documentation
+   */
+  private kdocBefore(source: SourceText, offset: number): string | undefined {
+    for (let i = source.comments.length - 1; i >= 0; i--) {
+      const comment = source.comments[i];
+      if (!comment || comment.end > offset) continue;
+      // Comments are blanked in masked text, so only whitespace may separate them
+      if (source.masked.slice(comment.end, offset).trim() !== '') return undefined;
+      if (comment.text.startsWith('/**')) return cleanComment(comment) || undefined;
+    }
+    return undefined;
+  }
+}
+
+function* matches(source: SourceText, pattern: RegExp): Generator<RegExpExecArray> {
+  pattern.lastIndex = 0;
+  let match;
+  while ((match = pattern.exec(source.masked)) !== null) {
+    checkDeadline();
+    yield match;
+  }
+}
+
+function typeKind(keyword: string, modifiers: string[]): SymbolKind {
+  if (keyword !== 'class') return keyword === 'object' ? 'class' : 'interface';
+  if (modifiers.includes('enum')) return 'enum';
+  // Annotation classes, like Java's annotation types
+  return modifiers.includes('annotation') ? 'interface' : 'class';
+}
+
+/**
+ * Declarations are public unless marked private or internal
+ */
+function isVisible(modifiers: string[]): boolean {
+  return !modifiers.includes('private') && !modifiers.includes('internal');
+}
+
+/**
+ * Whether a declaration goes on past the line break at newline: the line
+ * ends mid-expression or the next one starts with a supertype list, body,
+ * or where clause
+ */
+function continues(masked: string, from: number, newline: number): boolean {
+  let next = newline + 1;
+  while (next < masked.length && /\s/.test(masked[next] ?? '')) next++;
+  return (
+    /(?:[,:.=(<]|->)$/.test(masked.slice(from, newline).trimEnd()) ||
+    /^(?:[:{,.=]|where\b)/.test(masked.slice(next, next + 6))
+  );
+}
+
+function splitModifiers(text: string | undefined): string[] {
+  return text?.trim() ? text.trim().split(/\s+/) : [];
+}
+
+function unquote(name: string): string {
+  return name.replace(/^`(.*)`$/, '$1');
+}
+
+/**
+ * Offset of the ( matching the ) at closeOffset, in masked text
+ */
+function matchingOpen(masked: string, closeOffset: number): number {
+  let depth = 0;
+  for (let i = closeOffset; i >= 0; i--) {
+    if (masked[i] === ')') depth++;
+    else if (masked[i] === '(' && --depth === 0) return i;
+  }
+  return -1;
+}
+
+/**
+ * Offset of the first non-blank character of a match
+ */
+function itemStart(match: RegExpExecArray): number {
+  return match.index + Math.max(0, match[0].search(/\S/));
+}
+
+function collapse(text: string): string {
+  return text.replace(/\s+/g, ' ').trim();
+}
@@ -0,0 +1,206 @@
+/**
+ * Python Parser
+ * Extracts functions, classes, and methods using indentation to resolve nesting
+ */
+
+import type { SymbolEntry } from '../types/index.js';
+import { checkDeadline } from '../core/deadline.js';
+import { SourceText, PYTHON_SYNTAX } from './source.js';
+import type { SymbolParser } from './index.js';
+
+const DEF_PATTERN = /^([ \t]*)((?:async\s+)?def\s+([A-Za-z_]\w*)\s*(?:\[[^\]]*\]\s*)?\()/;
+const CLASS_PATTERN = /^([ \t]*)(class\s+([A-Za-z_]\w*))/;
+const DECORATOR_PATTERN = /^([ \t]*)@\s*([A-Za-z_][\w.]*)/;
+
+interface Scope {
+  indent: number;
+  name: string;
+  kind: 'class' | 'function';
+  exported: boolean;
+}
+
+export class PythonParser implements SymbolParser {
+  readonly language = 'python';
+  readonly version = 1;
+  readonly syntax = PYTHON_SYNTAX;
+  readonly extensions = ['.py', '.pyi'];
+
+  parse(content: string, file: string): SymbolEntry[] {
+    const source = new SourceText(content, this.syntax);
+    const symbols: SymbolEntry[] = [];
+    const scopes: Scope[] = [];
+    const continuations = continuationLines(source);
+    let decorators: string[] = [];
+    let decoratorLine = 0;
+
+    for (let line = 1; line <= source.lineCount; line++) {
+      checkDeadline();
+      const text = source.lineText(line, true);
+      if (text.trim() === '' || continuations.has(line)) continue;
+
+      const indent = indentOf(text);
+      while (scopes.length > 0 && (scopes[scopes.length - 1]?.indent ?? 0) >= indent) {
+        scopes.pop();
+      }
+
+      const decorator = DECORATOR_PATTERN.exec(text);
+      if (decorator) {
+        if (decorators.length === 0) decoratorLine = line;
+        decorators.push(`@${decorator[2] ?? ''}`);
+        continue;
+      }
+
+      const def = DEF_PATTERN.exec(text);
+      const cls = def ? null : CLASS_PATTERN.exec(text);
+      const match = def ?? cls;
+      if (!match) {
+        decorators = [];
+        continue;
+      }
+
+      const name = match[3] ?? '';
+      const enclosing = scopes[scopes.length - 1];
+      const start = source.lineStart(line) + (match[1] ?? '').length;
+      const headerEnd = this.findHeaderEnd(source, start + (match[2] ?? '').length - 1);
+      const isPublic = !name.startsWith('_') || /^__\w+__$/.test(name);
+      const exported = isPublic && (enclosing?.exported ?? true);
+
+      // Functions nested inside functions are local, not part of the module API
+      if (enclosing?.kind !== 'function') {
+        const symbol: SymbolEntry = {
+          name,
+          kind: cls ? 'class' : enclosing ? 'method' : 'function',
+          language: this.language,
+          file,
+          line,
+          endLine: this.findBlockEnd(source, line, indent, headerEnd, continuations),
+          signature: collapse(source.content.slice(start, headerEnd)),
+          exported,
+        };
+        if (enclosing) symbol.parent = enclosing.name;
+
+        const doc =
+          this.docstring(source, headerEnd) ??
+          source.docCommentBefore(decorators.length > 0 ? decoratorLine : line)?.text;
+        if (doc) symbol.doc = doc;
+        if (decorators.length > 0) symbol.decorators = decorators;
+
+        symbols.push(symbol);
+      }
+
+      const qualified = enclosing?.kind === 'class' ? `${enclosing.name}.${name}` : name;
+      scopes.push({ indent, name: qualified, kind: cls ? 'class' : 'function', exported });
+      decorators = [];
+
+      // Single-line bodies ("def f(): pass") don't open a nested scope
+      const afterColon = source.masked.slice(headerEnd + 1, source.lineStart(line + 1));
+      if (afterColon.trim() !== '') scopes.pop();
+      line = source.lineOf(headerEnd);
+    }
+
+    return symbols;
+  }
+
+  /**
+   * Offset of the ':' ending a def/class header, skipping brackets in
+   * parameters, base classes, and return annotations
+   */
+  private findHeaderEnd(source: SourceText, from: number): number {
+    const masked = source.masked;
+    let depth = 0;
+
+    for (let i = from; i < masked.length; i++) {
+      const ch = masked[i] ?? '';
+      if ('([{'.includes(ch)) depth++;
+      else if (')]}'.includes(ch)) depth--;
+      else if (ch === ':' && depth === 0) return i;
+    }
+
+    return masked.length;
+  }
+
+  /**
+   * Last line of an indented block: the final non-blank line indented
+   * deeper than the header
+   */
+  private findBlockEnd(
+    source: SourceText,
+    line: number,
+    indent: number,
+    headerEnd: number,
+    continuations: Set<number>
+  ): number {
+    let endLine = source.lineOf(headerEnd);
+
+    for (let l = endLine + 1; l <= source.lineCount; l++) {
+      const text = source.lineText(l, true);
+      if (text.trim() === '') continue;
+      if (indentOf(text) <= indent && !continuations.has(l)) break;
+      endLine = l;
+    }
+
+    return Math.max(endLine, line);
+  }
+
+  /**
+   * Docstring: a string literal as the first statement of the body
+   */
+  private docstring(source: SourceText, headerEnd: number): string | undefined {
+    const rest = source.content.slice(headerEnd + 1);
+    const offset = headerEnd + 1 + rest.search(/\S|$/);
+    const prefix = /^[rRuU]?/.exec(source.content.slice(offset))?.[0].length ?? 0;
+    const literal = source.strings.find(s => s.start === offset + prefix);
+    if (!literal) return undefined;
+
+    const raw = source.content.slice(literal.start, literal.end);
+    const quote = raw.startsWith('"""') || raw.startsWith("'''") ? 3 : 1;
+    return cleanDocstring(raw.slice(quote, raw.length - quote));
+  }
+}
+
+/**
+ * Lines that continue a multi-line string or bracketed expression, whose
+ * indentation says nothing about block structure
+ */
+function continuationLines(source: SourceText): Set<number> {
+  const lines = new Set<number>();
+
+  for (const literal of source.strings) {
+    const last = source.lineOf(literal.end - 1);
+    for (let l = source.lineOf(literal.start) + 1; l <= last; l++) lines.add(l);
+  }
+
+  let depth = 0;
+  for (let i = 0; i < source.masked.length; i++) {
+    const ch = source.masked[i] ?? '';
+    if ('([{'.includes(ch)) depth++;
+    else if (')]}'.includes(ch)) depth = Math.max(0, depth - 1);
+    else if (ch === '\n' && depth > 0) lines.add(source.lineOf(i + 1));
+  }
+
+  return lines;
+}
+
+function indentOf(text: string): number {
+  return text.replace(/\t/g, '    ').search(/\S|$/);
+}
+
+/**
+ * Dedent a docstring the way inspect.cleandoc does
+ */
+function cleanDocstring(text: string): string | undefined {
+  const lines = text.replace(/\r/g, '').split('\n');
+  const rest = lines.slice(1).filter(l => l.trim() !== '');
+  const margin = rest.length > 0 ? Math.min(...rest.map(indentOf)) : 0;
+  const cleaned = [
+    (lines[0] ?? '').trim(),
+    ...lines.slice(1).map(l => l.replace(/\t/g, '    ').slice(margin).trimEnd()),
+  ]
+    .join('\n')
+    .trim();
+  return cleaned === '' ? undefined : cleaned;
+}
+
+function collapse(text: string): string {
+  return text.replace(/\s+/g, ' ').trim();
+}
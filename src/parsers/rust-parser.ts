@@ -0,0 +1,353 @@
+/**
+ * Rust Parser
+ * Extracts fn items, structs, enums, traits, and impl methods from Rust source
+ */
+
+import type { SymbolEntry, SymbolKind } from '../types/index.js';
+import { checkDeadline } from '../core/deadline.js';
+import { SourceText, RUST_SYNTAX, cleanComment } from './source.js';
+import type { SymbolParser } from './index.js';
+
+const IDENT = '[A-Za-z_]\\w*';
+const VISIBILITY = '(pub(?:\\s*\\([^)]*\\))?\\s+)?';
+
+const FN_PATTERN = new RegExp(
+  `^[ \\t]*${VISIBILITY}(?:default\\s+)?(?:(?:const|async|unsafe|extern(?:\\s+"[^"\\n]*")?)\\s+)*fn\\s+(${IDENT})`,
+  'gm'
+);
+const TYPE_PATTERN = new RegExp(
+  `^[ \\t]*${VISIBILITY}(struct|enum|union|type)\\s+(${IDENT})`,
+  'gm'
+);
+const TRAIT_PATTERN = new RegExp(
+  `^[ \\t]*${VISIBILITY}(?:unsafe\\s+)?(?:auto\\s+)?trait\\s+(${IDENT})`,
+  'gm'
+);
+const VALUE_PATTERN = new RegExp(
+  `^[ \\t]*${VISIBILITY}(const|static)\\s+(?:mut\\s+)?(${IDENT})\\s*:`,
+  'gm'
+);
+const IMPL_PATTERN = /^[ \t]*(?:unsafe\s+)?impl\b/gm;
+const MOD_PATTERN = new RegExp(`^[ \\t]*${VISIBILITY}mod\\s+(${IDENT})\\s*\\{`, 'gm');
+
+type BlockKind = 'impl' | 'trait' | 'mod';
+
+interface Block {
+  kind: BlockKind;
+  open: number;
+  close: number;
+  name: string; // Self type for impl blocks, else the trait/module name
+  exported: boolean; // Trait impls and pub traits expose their methods
+}
+
+const TYPE_KINDS: Record<string, SymbolKind> = {
+  struct: 'struct',
+  union: 'struct',
+  enum: 'enum',
+  type: 'type',
+};
+
+export class RustParser implements SymbolParser {
+  readonly language = 'rust';
+  readonly version = 1;
+  readonly syntax = RUST_SYNTAX;
+  readonly extensions = ['.rs'];
+
+  parse(content: string, file: string): SymbolEntry[] {
+    const source = new SourceText(content, this.syntax);
+    const symbols: SymbolEntry[] = [];
+    const blocks = this.findBlocks(source);
+
+    for (const match of matches(source, FN_PATTERN)) {
+      const block = this.itemScope(source, blocks, match.index);
+      if (block === undefined) continue;
+
+      const member = block && block.kind !== 'mod' ? block : null;
+      const [bodyOpen, end] = this.itemBody(source, match.index + match[0].length);
+      symbols.push(
+        this.createSymbol(source, match, {
+          name: match[2] ?? '',
+          kind: member ? 'method' : 'function',
+          file,
+          endLine: source.lineOf(end),
+          signature: collapse(source.content.slice(itemStart(match), bodyOpen ?? end)),
+          exported: member ? match[1] !== undefined || member.exported : match[1] !== undefined,
+          parent: member?.name,
+        })
+      );
+    }
+
+    for (const match of matches(source, TYPE_PATTERN)) {
+      if (!this.isModuleLevel(source, blocks, match.index)) continue;
+
+      const keyword = match[2] ?? '';
+      const [bodyOpen, end] =
+        keyword === 'type'
+          ? [undefined, this.statementEnd(source, match.index + match[0].length)]
+          : this.itemBody(source, match.index + match[0].length);
+      symbols.push(
+        this.createSymbol(source, match, {
+          name: match[3] ?? '',
+          kind: TYPE_KINDS[keyword] ?? 'type',
+          file,
+          endLine: source.lineOf(end),
+          signature: collapse(source.content.slice(itemStart(match), bodyOpen ?? end)),
+          exported: match[1] !== undefined,
+          parent: undefined,
+        })
+      );
+    }
+
+    for (const match of matches(source, TRAIT_PATTERN)) {
+      if (!this.isModuleLevel(source, blocks, match.index)) continue;
+
+      const [bodyOpen, end] = this.itemBody(source, match.index + match[0].length);
+      symbols.push(
+        this.createSymbol(source, match, {
+          name: match[2] ?? '',
+          kind: 'interface',
+          file,
+          endLine: source.lineOf(end),
+          signature: collapse(source.content.slice(itemStart(match), bodyOpen ?? end)),
+          exported: match[1] !== undefined,
+          parent: undefined,
+        })
+      );
+    }
+
+    for (const match of matches(source, VALUE_PATTERN)) {
+      const block = this.itemScope(source, blocks, match.index);
+      if (block === undefined) continue;
+
+      // Associated consts belong to their impl or trait
+      const member = block && block.kind !== 'mod' ? block : null;
+      const end = this.statementEnd(source, match.index + match[0].length);
+      symbols.push(
+        this.createSymbol(source, match, {
+          name: match[3] ?? '',
+          kind: match[2] === 'static' ? 'var' : 'const',
+          file,
+          endLine: source.lineOf(end),
+          signature: collapse(source.content.slice(itemStart(match), end)),
+          exported: member ? match[1] !== undefined || member.exported : match[1] !== undefined,
+          parent: member?.name,
+        })
+      );
+    }
+
+    return symbols.sort((a, b) => a.line - b.line);
+  }
+
+  /**
+   * impl, trait, and inline mod blocks; items directly inside them are
+   * methods (impl, trait) or module-level declarations (mod)
+   */
+  private findBlocks(source: SourceText): Block[] {
+    const blocks: Block[] = [];
+
+    for (const match of matches(source, IMPL_PATTERN)) {
+      const [open] = this.itemBody(source, match.index + match[0].length);
+      if (open === undefined) continue;
+
+      const header = this.stripGenerics(source.masked.slice(match.index + match[0].length, open));
+      const forClause = /\bfor\s+(.+)$/s.exec(header);
+      const selfType = typeName(forClause ? (forClause[1] ?? '') : header);
+      if (!selfType) continue;
+
+      blocks.push({
+        kind: 'impl',
+        open,
+        close: source.findMatching(open),
+        name: selfType,
+        exported: forClause !== null, // Trait methods are as visible as the trait
+      });
+    }
+
+    for (const match of matches(source, TRAIT_PATTERN)) {
+      const [open] = this.itemBody(source, match.index + match[0].length);
+      if (open === undefined) continue;
+      blocks.push({
+        kind: 'trait',
+        open,
+        close: source.findMatching(open),
+        name: match[2] ?? '',
+        exported: match[1] !== undefined,
+      });
+    }
+
+    for (const match of matches(source, MOD_PATTERN)) {
+      const open = match.index + match[0].length - 1;
+      blocks.push({
+        kind: 'mod',
+        open,
+        close: source.findMatching(open),
+        name: match[2] ?? '',
+        exported: match[1] !== undefined,
+      });
+    }
+
+    return blocks.filter(b => b.close !== -1);
+  }
+
+  /**
+   * The block an item sits directly inside: null at file level, undefined
+   * when the item is nested somewhere else (e.g. a function body)
+   */
+  private itemScope(source: SourceText, blocks: Block[], offset: number): Block | null | undefined {
+    const depth = source.depthAt(offset);
+    if (depth === 0) return null;
+
+    let innermost: Block | undefined;
+    for (const block of blocks) {
+      if (block.open < offset && offset < block.close) {
+        if (!innermost || block.open > innermost.open) innermost = block;
+      }
+    }
+
+    if (innermost && source.depthAt(innermost.open) + 1 === depth) {
+      return innermost;
+    }
+    return undefined;
+  }
+
+  private isModuleLevel(source: SourceText, blocks: Block[], offset: number): boolean {
+    const block = this.itemScope(source, blocks, offset);
+    return block === null || block?.kind === 'mod';
+  }
+
+  /**
+   * Find an item's body: the first `{` outside brackets, or the `;` ending a
+   * declaration without one. Returns [bodyOpen, end].
+   */
+  private itemBody(source: SourceText, from: number): [number | undefined, number] {
+    const masked = source.masked;
+
+    for (let i = from; i < masked.length; i++) {
+      const ch = masked[i];
+      if (ch === '(' || ch === '[') {
+        const close = source.findMatching(i);
+        if (close === -1) break;
+        i = close;
+      } else if (ch === '{') {
+        const close = source.findMatching(i);
+        return [i, close === -1 ? i : close];
+      } else if (ch === ';') {
+        return [undefined, i];
+      }
+    }
+
+    return [undefined, masked.length - 1];
+  }
+
+  /**
+   * End of a const/static/type item: the `;` outside any brackets
+   */
+  private statementEnd(source: SourceText, from: number): number {
+    let depth = 0;
+    for (let i = from; i < source.masked.length; i++) {
+      const ch = source.masked[i] ?? '';
+      if ('([{'.includes(ch)) depth++;
+      else if (')]}'.includes(ch)) depth--;
+      else if (ch === ';' && depth <= 0) return i;
+    }
+    return source.masked.length - 1;
+  }
+
+  /**
+   * Drop a leading generic parameter list: "<T: Display> Foo<T>" -> "Foo<T>"
+   */
+  private stripGenerics(header: string): string {
+    const text = header.trimStart();
+    if (!text.startsWith('<')) return text.trim();
+
+    let depth = 0;
+    for (let i = 0; i < text.length; i++) {
+      if (text[i] === '<') depth++;
+      else if (text[i] === '>' && text[i - 1] !== '-' && --depth === 0) {
+        return text.slice(i + 1).trim();
+      }
+    }
+    return text.trim();
+  }
+
+  private createSymbol(
+    source: SourceText,
+    match: RegExpExecArray,
+    fields: Omit<SymbolEntry, 'language' | 'line' | 'doc' | 'decorators'>
+  ): SymbolEntry {
+    const line = source.lineOf(itemStart(match));
+    const symbol: SymbolEntry = { ...fields, language: this.language, line };
+    if (fields.parent === undefined) delete symbol.parent;
+
+    const { doc, attributes } = this.leadingDocs(source, line);
+    if (doc) symbol.doc = doc;
+    if (attributes.length > 0) symbol.decorators = attributes;
+    return symbol;
+  }
+
+  /**
+   * `///` and `/** *\/` doc comments above an item, and the #[attributes]
+   * between them and the item
+   */
+  private leadingDocs(source: SourceText, line: number): { doc?: string; attributes: string[] } {
+    const docs: string[] = [];
+    const attributes: string[] = [];
+
+    for (let l = line - 1; l >= 1; l--) {
+      const text = source.lineText(l).trim();
+      if (text.startsWith('#[')) {
+        if (docs.length === 0) attributes.unshift(text.replace(/\s*(?:\/\/.*)?$/, ''));
+        continue;
+      }
+      if (text.startsWith('///') && !text.startsWith('////')) {
+        docs.unshift(text.replace(/^\/\/\/\s?/, ''));
+        continue;
+      }
+      if (text.endsWith('*/') && docs.length === 0) {
+        const comment = source.comments.find(c => c.endLine === l && c.text.startsWith('/**'));
+        if (comment) {
+          docs.unshift(cleanComment(comment));
+          l = comment.line;
+          continue;
+        }
+      }
+      break;
+    }
+
+    const doc = docs.join('\n').trim();
+    return doc ? { doc, attributes } : { attributes };
+  }
+}
+
+function* matches(source: SourceText, pattern: RegExp): Generator<RegExpExecArray> {
+  pattern.lastIndex = 0;
+  let match;
+  while ((match = pattern.exec(source.masked)) !== null) {
+    checkDeadline();
+    yield match;
+  }
+}
+
+/**
+ * Offset of the first non-blank character of a line-anchored match
+ */
+function itemStart(match: RegExpExecArray): number {
+  return match.index + match[0].search(/\S/);
+}
+
+/**
+ * Last path segment of a type, without references or generics:
+ * "&'a mut crate::shapes::Circle<T>" -> "Circle"
+ */
+function typeName(text: string): string | undefined {
+  const cleaned = text
+    .replace(/\bwhere\b[\s\S]*$/, '')
+    .replace(/^[\s&]*(?:'\w+\s+)?(?:mut\s+|dyn\s+)?/, '')
+    .replace(/<[\s\S]*$/, '')
+    .trim();
+  return /(?:^|::)([A-Za-z_]\w*)$/.exec(cleaned)?.[1];
+}
+
+function collapse(text: string): string {
+  return text.replace(/\s+/g, ' ').trim();
+}
@@ -0,0 +1,487 @@
+/**
+ * Source Text
+ * Masks comments and string literals so parsers can match code structure safely
+ */
+
+export interface LexicalSyntax {
+  lineComments: string[];
+  blockComment?: [string, string];
+  quotes: string[];
+  tripleQuotes?: boolean; // Python/GraphQL """ strings
+  rawBackticks?: boolean; // Go raw strings: no escapes, may span lines
+  templateLiterals?: boolean; // JS/TS `...${expr}...`
+  charLiterals?: boolean; // Treat 'x' as a char literal (Go, Rust, C)
+  rawHashStrings?: boolean; // Rust r"..." and r#"..."# strings: no escapes, may span lines
+  dollarQuotes?: boolean; // PostgreSQL $$...$$ and $tag$...$tag$ bodies
+}
+
+export interface CommentRange {
+  start: number;
+  end: number; // exclusive
+  line: number;
+  endLine: number;
+  text: string;
+  block: boolean;
+}
+
+export interface StringRange {
+  start: number;
+  end: number; // exclusive, includes delimiters
+}
+
+const CHAR_LITERAL = /'(?:\\'|\\[^'\n]{1,10}|[^\\'\n]{1,2})'/y;
+const RAW_HASH_STRING = /b?r(#*)"/y;
+const DOLLAR_QUOTE = /\$(?:[A-Za-z_]\w*)?\$/y;
+
+type LexMode = { kind: 'code'; depth: number } | { kind: 'template' };
+
+export class SourceText {
+  readonly content: string;
+  readonly masked: string;
+  readonly comments: CommentRange[] = [];
+  readonly strings: StringRange[] = [];
+  private lineStarts: number[] = [0];
+  private lineDepths: number[] | null = null;
+  private commentsByEndLine = new Map<number, CommentRange>();
+
+  constructor(content: string, syntax: LexicalSyntax) {
+    this.content = content;
+
+    for (let i = 0; i < content.length; i++) {
+      if (content[i] === '\n') {
+        this.lineStarts.push(i + 1);
+      }
+    }
+
+    this.masked = this.mask(syntax);
+
+    for (const comment of this.comments) {
+      this.commentsByEndLine.set(comment.endLine, comment);
+    }
+  }
+
+  get lineCount(): number {
+    return this.lineStarts.length;
+  }
+
+  /**
+   * 1-based line number of an offset
+   */
+  lineOf(offset: number): number {
+    let low = 0;
+    let high = this.lineStarts.length - 1;
+    while (low < high) {
+      const mid = (low + high + 1) >> 1;
+      if ((this.lineStarts[mid] ?? 0) <= offset) {
+        low = mid;
+      } else {
+        high = mid - 1;
+      }
+    }
+    return low + 1;
+  }
+
+  /**
+   * Offset of the first character of a 1-based line
+   */
+  lineStart(line: number): number {
+    return this.lineStarts[Math.max(0, line - 1)] ?? this.content.length;
+  }
+
+  lineText(line: number, masked = false): string {
+    const source = masked ? this.masked : this.content;
+    const start = this.lineStart(line);
+    const next = this.lineStarts[line];
+    const end = next === undefined ? source.length : next - 1;
+    return source.slice(start, end).replace(/\r$/, '');
+  }
+
+  /**
+   * Source between two offsets without comments, with whitespace collapsed
+   * outside string literals: an expression as written
+   */
+  codeText(start: number, end: number): string {
+    const strings = this.strings.filter(s => s.start < end && s.end > start);
+    let text = '';
+    for (let i = start; i < end; i++) {
+      const literal = strings.find(s => s.start <= i && i < s.end);
+      if (literal) {
+        text += this.content.slice(i, Math.min(literal.end, end));
+        i = literal.end - 1;
+      } else if (/\s/.test(this.masked[i] ?? '')) {
+        if (!text.endsWith(' ')) text += ' ';
+      } else {
+        text += this.content[i];
+      }
+    }
+    return text.trim();
+  }
+
+  /**
+   * Find the bracket closing the one at openOffset (in masked text)
+   * Returns -1 when unbalanced
+   */
+  findMatching(openOffset: number): number {
+    const open = this.masked[openOffset];
+    const close = open === '(' ? ')' : open === '[' ? ']' : open === '<' ? '>' : '}';
+    let depth = 0;
+
+    for (let i = openOffset; i < this.masked.length; i++) {
+      const ch = this.masked[i];
+      if (ch === open) {
+        depth++;
+      } else if (ch === close) {
+        depth--;
+        if (depth === 0) return i;
+      }
+    }
+
+    return -1;
+  }
+
+  /**
+   * Curly-brace nesting depth at an offset
+   */
+  depthAt(offset: number): number {
+    if (!this.lineDepths) {
+      this.lineDepths = [];
+      let depth = 0;
+      for (let line = 0; line < this.lineStarts.length; line++) {
+        this.lineDepths.push(depth);
+        const start = this.lineStarts[line] ?? 0;
+        const end = this.lineStarts[line + 1] ?? this.masked.length;
+        depth += this.countBraces(start, end);
+      }
+    }
+
+    const line = this.lineOf(offset);
+    return (this.lineDepths[line - 1] ?? 0) + this.countBraces(this.lineStart(line), offset);
+  }
+
+  /**
+   * Find where a statement starting at offset ends: a ';' or a line break at
+   * bracket depth 0 that doesn't look like a continuation
+   */
+  statementEnd(offset: number): number {
+    let depth = 0;
+
+    for (let i = offset; i < this.masked.length; i++) {
+      const ch = this.masked[i] ?? '';
+      if ('({['.includes(ch)) depth++;
+      else if (')}]'.includes(ch)) depth--;
+
+      if (depth < 0) return i;
+      if (depth === 0 && ch === ';') return i;
+      if (depth === 0 && ch === '\n') {
+        const before = this.masked.slice(offset, i).trimEnd();
+        const after = this.masked.slice(i + 1).trimStart();
+        if (!/[=,(+\-*/&|?:.>]$/.test(before) && !/^[.?:)|&]/.test(after)) {
+          return i;
+        }
+      }
+    }
+
+    return this.masked.length;
+  }
+
+  /**
+   * The comment block directly above a line (no blank line in between)
+   * Returns cleaned text with comment markers removed
+   */
+  docCommentBefore(line: number): { text: string; startLine: number } | undefined {
+    const parts: string[] = [];
+    let current = line - 1;
+    let startLine = line;
+
+    while (current >= 1) {
+      const comment = this.commentsByEndLine.get(current);
+      if (!comment || !this.isOwnLine(comment)) break;
+
+      parts.unshift(cleanComment(comment));
+      startLine = comment.line;
+      if (comment.block) break;
+      current = comment.line - 1;
+    }
+
+    if (parts.length === 0) return undefined;
+    return { text: parts.join('\n').trim(), startLine };
+  }
+
+  private isOwnLine(comment: CommentRange): boolean {
+    return this.masked.slice(this.lineStart(comment.line), comment.start).trim() === '';
+  }
+
+  private countBraces(start: number, end: number): number {
+    let count = 0;
+    for (let i = start; i < end; i++) {
+      const ch = this.masked[i];
+      if (ch === '{') count++;
+      else if (ch === '}') count--;
+    }
+    return count;
+  }
+
+  private mask(syntax: LexicalSyntax): string {
+    const content = this.content;
+    const out = content.split('');
+    const modes: LexMode[] = [{ kind: 'code', depth: 0 }];
+    const [blockOpen, blockClose] = syntax.blockComment ?? ['', ''];
+
+    const blank = (start: number, end: number): void => {
+      for (let k = start; k < end; k++) {
+        if (out[k] !== '\n') out[k] = ' ';
+      }
+    };
+
+    let i = 0;
+    while (i < content.length) {
+      const mode = modes[modes.length - 1] ?? { kind: 'code', depth: 0 };
+      const ch = content[i] ?? '';
+
+      // Inside a template literal: blank text until `${` or the closing backtick
+      if (mode.kind === 'template') {
+        const start = i;
+        while (i < content.length && content[i] !== '`' && !content.startsWith('${', i)) {
+          i += content[i] === '\\' ? 2 : 1;
+        }
+        blank(start, Math.min(i, content.length));
+        if (content[i] === '`') {
+          modes.pop();
+          this.closeString(i + 1);
+          i++;
+        } else if (i < content.length) {
+          modes.push({ kind: 'code', depth: 0 });
+          i += 2;
+        }
+        continue;
+      }
+
+      // Line comments
+      const lineToken = syntax.lineComments.find(token => content.startsWith(token, i));
+      if (lineToken) {
+        const newline = content.indexOf('\n', i);
+        const end = newline === -1 ? content.length : newline;
+        this.addComment(i, end, false);
+        blank(i, end);
+        i = end;
+        continue;
+      }
+
+      // Block comments
+      if (blockOpen && content.startsWith(blockOpen, i)) {
+        const closeAt = content.indexOf(blockClose, i + blockOpen.length);
+        const end = closeAt === -1 ? content.length : closeAt + blockClose.length;
+        this.addComment(i, end, true);
+        blank(i, end);
+        i = end;
+        continue;
+      }
+
+      // Triple-quoted strings
+      if (syntax.tripleQuotes && (content.startsWith('"""', i) || content.startsWith("'''", i))) {
+        const delimiter = content.slice(i, i + 3);
+        const closeAt = content.indexOf(delimiter, i + 3);
+        const end = closeAt === -1 ? content.length : closeAt + 3;
+        blank(i + 3, end - 3);
+        this.strings.push({ start: i, end });
+        i = end;
+        continue;
+      }
+
+      // Char literals ('a', '\n') - a lone quote (Rust lifetime) is left alone
+      if (syntax.charLiterals && ch === "'") {
+        CHAR_LITERAL.lastIndex = i;
+        const match = CHAR_LITERAL.exec(content);
+        if (match) {
+          const end = i + match[0].length;
+          blank(i + 1, end - 1);
+          this.strings.push({ start: i, end });
+          i = end;
+        } else {
+          i++;
+        }
+        continue;
+      }
+
+      // Raw strings close at a quote followed by the same number of hashes
+      if (
+        syntax.rawHashStrings &&
+        (ch === 'r' || ch === 'b') &&
+        !/[\w$]/.test(content[i - 1] ?? '')
+      ) {
+        RAW_HASH_STRING.lastIndex = i;
+        const match = RAW_HASH_STRING.exec(content);
+        if (match) {
+          const closer = `"${match[1] ?? ''}`;
+          const bodyStart = i + match[0].length;
+          const closeAt = content.indexOf(closer, bodyStart);
+          const end = closeAt === -1 ? content.length : closeAt + closer.length;
+          blank(bodyStart, closeAt === -1 ? end : closeAt);
+          this.strings.push({ start: i, end });
+          i = end;
+          continue;
+        }
+      }
+
+      // Dollar-quoted bodies close at the same $tag$ that opened them
+      if (syntax.dollarQuotes && ch === '$' && !/[\w$]/.test(content[i - 1] ?? '')) {
+        DOLLAR_QUOTE.lastIndex = i;
+        const match = DOLLAR_QUOTE.exec(content);
+        if (match) {
+          const bodyStart = i + match[0].length;
+          const closeAt = content.indexOf(match[0], bodyStart);
+          const end = closeAt === -1 ? content.length : closeAt + match[0].length;
+          blank(bodyStart, closeAt === -1 ? end : closeAt);
+          this.strings.push({ start: i, end });
+          i = end;
+          continue;
+        }
+      }
+
+      if (ch === '`' && syntax.templateLiterals) {
+        this.strings.push({ start: i, end: -1 });
+        modes.push({ kind: 'template' });
+        i++;
+        continue;
+      }
+
+      if (syntax.quotes.includes(ch)) {
+        const raw = ch === '`' && syntax.rawBackticks === true;
+        let j = i + 1;
+        while (j < content.length) {
+          const c = content[j];
+          if (c === '\\' && !raw) {
+            j += 2;
+            continue;
+          }
+          if (c === ch || (c === '\n' && !raw)) break;
+          j++;
+        }
+        const end = Math.min(j + 1, content.length);
+        blank(i + 1, Math.min(j, content.length));
+        this.strings.push({ start: i, end });
+        i = end;
+        continue;
+      }
+
+      // Track braces of `${...}` expressions inside template literals
+      if (mode.kind === 'code' && modes.length > 1) {
+        if (ch === '{') {
+          mode.depth++;
+        } else if (ch === '}') {
+          if (mode.depth === 0) {
+            modes.pop();
+            i++;
+            continue;
+          }
+          mode.depth--;
+        }
+      }
+
+      i++;
+    }
+
+    return out.join('');
+  }
+
+  private closeString(end: number): void {
+    for (let k = this.strings.length - 1; k >= 0; k--) {
+      const range = this.strings[k];
+      if (range && range.end === -1) {
+        range.end = end;
+        return;
+      }
+    }
+  }
+
+  private addComment(start: number, end: number, block: boolean): void {
+    this.comments.push({
+      start,
+      end,
+      line: this.lineOf(start),
+      endLine: this.lineOf(Math.max(start, end - 1)),
+      text: this.content.slice(start, end),
+      block,
+    });
+  }
+}
+
+/**
+ * Strip comment markers (//, ///, #, --, /* *\/, leading *) from a comment
+ */
+export function cleanComment(comment: CommentRange): string {
+  if (comment.block) {
+    return comment.text
+      .replace(/^\/\*+!?/, '')
+      .replace(/\*+\/$/, '')
+      .split('\n')
+      .map(line => line.replace(/^\s*\*?\s?/, '').trimEnd())
+      .join('\n')
+      .trim();
+  }
+
+  return comment.text.replace(/^(?:\/\/[/!]?|#+|--)\s?/, '').trimEnd();
+}
+
+// Lexical syntax per language family
+export const C_STYLE_SYNTAX: LexicalSyntax = {
+  lineComments: ['//'],
+  blockComment: ['/*', '*/'],
+  quotes: ['"'],
+  charLiterals: true,
+};
+
+export const GO_SYNTAX: LexicalSyntax = {
+  ...C_STYLE_SYNTAX,
+  quotes: ['"', '`'],
+  rawBackticks: true,
+};
+
+export const RUST_SYNTAX: LexicalSyntax = {
+  ...C_STYLE_SYNTAX,
+  rawHashStrings: true,
+};
+
+export const JAVA_SYNTAX: LexicalSyntax = {
+  ...C_STYLE_SYNTAX,
+  tripleQuotes: true, // Text blocks
+};
+
+export const KOTLIN_SYNTAX: LexicalSyntax = {
+  ...C_STYLE_SYNTAX,
+  tripleQuotes: true, // Raw strings
+};
+
+export const SWIFT_SYNTAX: LexicalSyntax = {
+  lineComments: ['//'],
+  blockComment: ['/*', '*/'],
+  quotes: ['"'],
+  tripleQuotes: true, // Multiline string literals
+};
+
+export const JS_SYNTAX: LexicalSyntax = {
+  lineComments: ['//'],
+  blockComment: ['/*', '*/'],
+  quotes: ['"', "'"],
+  templateLiterals: true,
+};
+
+export const PYTHON_SYNTAX: LexicalSyntax = {
+  lineComments: ['#'],
+  quotes: ['"', "'"],
+  tripleQuotes: true,
+};
+
+export const GRAPHQL_SYNTAX: LexicalSyntax = {
+  lineComments: ['#'],
+  quotes: ['"'],
+  tripleQuotes: true, // Block string descriptions
+};
+
+// "quoted" and `quoted` identifiers stay in the code so their names can be read
+export const SQL_SYNTAX: LexicalSyntax = {
+  lineComments: ['--'],
+  blockComment: ['/*', '*/'],
+  quotes: ["'"],
+  dollarQuotes: true,
+};
@@ -0,0 +1,594 @@
+/**
+ * SQL Parser
+ * Extracts schema objects from DDL: tables with their columns and
+ * constraints, indexes attached to their table, views, and functions.
+ * Queries and other statements are skipped.
+ */
+
+import type {
+  Parameter,
+  SqlColumn,
+  SqlConstraint,
+  SymbolEntry,
+  SymbolKind,
+} from '../types/index.js';
+import { checkDeadline } from '../core/deadline.js';
+import { SourceText, SQL_SYNTAX } from './source.js';
+import type { SymbolParser } from './index.js';
+
+// Plain, "double-quoted", `backquoted`, or [bracketed] identifier
+const IDENT = '(?:"[^"\\n]+"|`[^`\\n]+`|\\[[^\\]\\n]+\\]|[A-Za-z_][\\w$]*)';
+const QUALIFIED = `${IDENT}(?:\\s*\\.\\s*${IDENT})*`;
+const IF_NOT_EXISTS = '(?:if\\s+not\\s+exists\\s+)?';
+
+const TABLE_PATTERN = new RegExp(
+  `^create\\s+(?:or\\s+replace\\s+)?(?:(?:global|local)\\s+)?(?:(?:temporary|temp|unlogged)\\s+)?table\\s+${IF_NOT_EXISTS}(${QUALIFIED})`,
+  'i'
+);
+const VIEW_PATTERN = new RegExp(
+  `^create\\s+(?:or\\s+replace\\s+)?(?:(?:temporary|temp)\\s+)?(?:materialized\\s+)?(?:recursive\\s+)?view\\s+${IF_NOT_EXISTS}(${QUALIFIED})`,
+  'i'
+);
+const INDEX_PATTERN = new RegExp(
+  `^create\\s+(unique\\s+)?(?:(?:clustered|nonclustered)\\s+)?index\\s+(?:concurrently\\s+)?${IF_NOT_EXISTS}(?:(${QUALIFIED})\\s+)?on\\s+(?:only\\s+)?(${QUALIFIED})`,
+  'i'
+);
+const FUNCTION_PATTERN = new RegExp(
+  `^create\\s+(?:or\\s+replace\\s+)?(?:definer\\s*=\\s*\\S+\\s+)?(?:function|procedure)\\s+${IF_NOT_EXISTS}(${QUALIFIED})\\s*\\(`,
+  'i'
+);
+const INLINE_INDEX = new RegExp(
+  `^(?:(?:unique|fulltext|spatial)\\s+)?(?:key|index)\\s+(${IDENT})\\s*\\(`,
+  'i'
+);
+// PRIMARY KEY (a), UNIQUE [KEY name] (a, b), FOREIGN KEY (a)
+const KEYED_CONSTRAINT =
+  /^(primary\s+key|unique(?:\s+(?:key|index))?|foreign\s+key)\s*(?:\w+\s*)?(\([^)]*\))/i;
+const REFERENCES = new RegExp(`\\breferences\\s+(${QUALIFIED})\\s*(\\([^)]*\\))?`, 'i');
+const ADD_COLUMN = new RegExp(`^column\\s+${IF_NOT_EXISTS}`, 'i');
+const ALTER_PATTERN = new RegExp(
+  `^alter\\s+table\\s+(?:if\\s+exists\\s+)?(?:only\\s+)?(${QUALIFIED})\\s+`,
+  'i'
+);
+
+// Words that end a column's type and start its constraints
+const COLUMN_KEYWORDS = new Set([
+  'not',
+  'null',
+  'primary',
+  'unique',
+  'references',
+  'default',
+  'check',
+  'constraint',
+  'collate',
+  'generated',
+  'auto_increment',
+  'autoincrement',
+  'identity',
+  'comment',
+  'on',
+  'as',
+]);
+
+// Words that end a RETURNS clause
+const RETURNS_END = new RegExp(
+  `^(?:${[
+    'as',
+    'language',
+    'immutable',
+    'stable',
+    'volatile',
+    'strict',
+    'security',
+    'cost',
+    'rows',
+    'parallel',
+    'set',
+    'begin',
+    'return',
+    'deterministic',
+    'no\\s+sql',
+    'reads',
+    'modifies',
+    'contains',
+    'called',
+    'leakproof',
+    'window',
+    'with',
+    'comment',
+  ].join('|')})\\b`,
+  'i'
+);
+
+const PARAMETER_MODES = /^(?:in|out|inout|variadic)\s+/i;
+
+// Migration tool annotations, left out of docs: -- +goose Up, -- +migrate Down, -- migrate:up
+const MIGRATION_DIRECTIVE = /^(?:\+(?:goose|migrate)\b|migrate:).*$/gm;
+
+interface Statement {
+  start: number; // First character of code
+  end: number; // Exclusive: the semicolon, or the end of the file
+}
+
+interface TableBody {
+  columns: SqlColumn[];
+  constraints: SqlConstraint[];
+  indexes: Array<[Statement, string]>; // Inline MySQL indexes and their names
+}
+
+export class SqlParser implements SymbolParser {
+  readonly language = 'sql';
+  readonly version = 1;
+  readonly syntax = SQL_SYNTAX;
+  readonly extensions = ['.sql'];
+
+  parse(content: string, file: string): SymbolEntry[] {
+    const source = new SourceText(content, this.syntax);
+    const symbols: SymbolEntry[] = [];
+    // Tables of this file by qualified name, for ALTER TABLE
+    const tables = new Map<string, SymbolEntry>();
+    const add = (
+      statement: Statement,
+      name: string,
+      kind: SymbolKind,
+      headerEnd: number,
+      parent?: string
+    ): SymbolEntry => {
+      const symbol = createSymbol(source, file, statement, name, kind, headerEnd, parent);
+      symbols.push(symbol);
+      return symbol;
+    };
+
+    for (const statement of splitStatements(source)) {
+      checkDeadline();
+      const text = source.masked.slice(statement.start, statement.end);
+
+      let match;
+      if ((match = TABLE_PATTERN.exec(text))) {
+        const nameEnd = statement.start + match[0].length;
+        const open = source.masked.indexOf('(', nameEnd);
+        const close = open === -1 || open >= statement.end ? -1 : source.findMatching(open);
+        const headerEnd = close === -1 ? nameEnd : open;
+        const table = add(statement, unquote(match[1] ?? ''), 'table', headerEnd);
+        if (close !== -1) {
+          const body = parseTableBody(source, open, close);
+          if (body.columns.length > 0) table.columns = body.columns;
+          if (body.constraints.length > 0) table.constraints = body.constraints;
+          for (const [index, name] of body.indexes) {
+            add(index, name, 'index', index.end, qualifiedName(table));
+          }
+        }
+        tables.set(qualifiedName(table), table);
+      } else if ((match = VIEW_PATTERN.exec(text))) {
+        const nameEnd = statement.start + match[0].length;
+        const as = /\bas\b/i.exec(source.masked.slice(nameEnd, statement.end));
+        const headerEnd = as ? lastCode(source, nameEnd, nameEnd + as.index) : statement.end;
+        const view = add(statement, unquote(match[1] ?? ''), 'view', headerEnd);
+        const columns = viewColumns(source, nameEnd, headerEnd, statement.end);
+        if (columns.length > 0) view.columns = columns;
+      } else if ((match = INDEX_PATTERN.exec(text))) {
+        const table = unquote(match[3] ?? '');
+        // Unnamed indexes get the name PostgreSQL would give them: users_email_idx
+        const columns = /^\s*(?:using\s+\w+\s*)?(\([^)]*\))/i.exec(
+          source.masked.slice(statement.start + match[0].length, statement.end)
+        );
+        const name = match[2]
+          ? splitName(unquote(match[2]))[1]
+          : [splitName(table)[1], ...splitColumns(columns?.[1]), 'idx'].join('_');
+        add(statement, name, 'index', statement.end, table);
+      } else if ((match = FUNCTION_PATTERN.exec(text))) {
+        const open = statement.start + match[0].length - 1;
+        const close = source.findMatching(open);
+        if (close === -1 || close > statement.end) continue;
+        const returns = returnType(source, close + 1, statement.end);
+        const fn = add(statement, unquote(match[1] ?? ''), 'function', returns?.end ?? close + 1);
+        fn.params = parseParameters(source, open, close);
+        if (returns) fn.returns = [{ type: returns.type }];
+      } else if ((match = ALTER_PATTERN.exec(text))) {
+        const table = tables.get(unquote(match[1] ?? ''));
+        if (table) alterTable(source, table, statement.start + match[0].length, statement.end);
+      }
+    }
+
+    return symbols.sort((a, b) => a.line - b.line);
+  }
+}
+
+/**
+ * Symbol for a statement, or a part of one; a schema-qualified name is
+ * split into the name and its schema as parent, unless a parent is given
+ */
+function createSymbol(
+  source: SourceText,
+  file: string,
+  span: Statement,
+  name: string,
+  kind: SymbolKind,
+  headerEnd: number,
+  parent: string | undefined
+): SymbolEntry {
+  const [qualifier, base] = splitName(name);
+  const symbol: SymbolEntry = {
+    name: base,
+    kind,
+    language: 'sql',
+    file,
+    line: source.lineOf(span.start),
+    endLine: source.lineOf(Math.max(span.start, span.end - 1)),
+    signature: source.codeText(span.start, headerEnd),
+    exported: true,
+  };
+  const owner = parent ?? qualifier;
+  if (owner) symbol.parent = owner;
+  const doc = source.docCommentBefore(symbol.line)?.text.replace(MIGRATION_DIRECTIVE, '').trim();
+  if (doc) symbol.doc = doc;
+  return symbol;
+}
+
+/**
+ * Statements between semicolons outside brackets, strings, and comments
+ */
+function splitStatements(source: SourceText): Statement[] {
+  const text = source.masked;
+  const statements: Statement[] = [];
+  let start = 0;
+  let depth = 0;
+  for (let i = 0; i <= text.length; i++) {
+    const ch = text[i];
+    if (ch === '(') depth++;
+    else if (ch === ')') depth = Math.max(0, depth - 1);
+    else if ((ch === ';' && depth === 0) || i === text.length) {
+      const codeStart = skipSpace(text, start);
+      if (codeStart < i) statements.push({ start: codeStart, end: lastCode(source, codeStart, i) });
+      start = i + 1;
+    }
+  }
+  return statements;
+}
+
+/**
+ * Columns, table constraints, and inline MySQL indexes (KEY name (cols))
+ * between the parentheses of CREATE TABLE
+ */
+function parseTableBody(
+  source: SourceText,
+  open: number,
+  close: number
+): TableBody {
+  const columns: SqlColumn[] = [];
+  const constraints: SqlConstraint[] = [];
+  const indexes: TableBody['indexes'] = [];
+
+  const elements = splitList(source, open + 1, close);
+  elements.forEach((element, i) => {
+    const next = elements[i + 1]?.start ?? close;
+    const constraint = parseConstraint(source, element.start, element.end);
+    if (constraint) {
+      constraints.push(constraint);
+      return;
+    }
+
+    const text = source.masked.slice(element.start, element.end);
+    const key = INLINE_INDEX.exec(text);
+    if (key) {
+      indexes.push([element, unquote(key[1] ?? '')]);
+      return;
+    }
+    if (/^like\b/i.test(text)) return;
+
+    const column = parseColumn(source, element.start, element.end);
+    if (!column) return;
+    const doc = columnDoc(source, element.start, next);
+    if (doc) column.doc = doc;
+    columns.push(column);
+  });
+
+  // PRIMARY KEY (a, b) makes its columns primary key columns too
+  const keyColumns = constraints.filter(c => c.kind === 'primary key').flatMap(c => c.columns);
+  for (const column of columns.filter(c => keyColumns.includes(c.name))) {
+    column.primaryKey = true;
+    column.nullable = false;
+  }
+
+  return { columns, constraints, indexes };
+}
+
+/**
+ * A column definition: name, type up to the first constraint keyword, then
+ * NOT NULL, PRIMARY KEY, UNIQUE, DEFAULT, and REFERENCES
+ */
+function parseColumn(source: SourceText, start: number, end: number): SqlColumn | undefined {
+  const text = source.masked.slice(start, end);
+  const name = new RegExp(`^${IDENT}`).exec(text);
+  if (!name) return undefined;
+
+  const words = tokenize(text, name[0].length);
+  const typeEnd = words.find(word => COLUMN_KEYWORDS.has(word.text.toLowerCase()));
+  const typeEndOffset = typeEnd ? typeEnd.start : text.length;
+  const type = source.codeText(start + name[0].length, start + typeEndOffset).trim();
+  const rest = text.slice(typeEndOffset);
+
+  const column: SqlColumn = { name: unquote(name[0]), line: source.lineOf(start) };
+  if (type) column.type = type;
+  const primaryKey = /\bprimary\s+key\b/i.test(rest);
+  column.nullable = !primaryKey && !/\bnot\s+null\b/i.test(rest);
+  if (primaryKey) column.primaryKey = true;
+  if (/\bunique\b/i.test(rest)) column.unique = true;
+
+  const defaultAt = /\bdefault\s+/i.exec(rest);
+  if (defaultAt) {
+    const valueStart = typeEndOffset + defaultAt.index + defaultAt[0].length;
+    const valueEnd = tokenize(text, valueStart).find(
+      (word, i) => i > 0 && COLUMN_KEYWORDS.has(word.text.toLowerCase())
+    );
+    const value = source.codeText(start + valueStart, start + (valueEnd?.start ?? text.length));
+    if (value.trim()) column.default = value.trim();
+  }
+
+  const references = REFERENCES.exec(rest);
+  if (references) {
+    column.references = `${unquote(references[1] ?? '')}${columnList(references[2])}`;
+  }
+
+  return column;
+}
+
+/**
+ * [CONSTRAINT name] PRIMARY KEY (...), UNIQUE (...), FOREIGN KEY (...)
+ * REFERENCES t (...), or CHECK (...); undefined for anything else
+ */
+function parseConstraint(
+  source: SourceText,
+  start: number,
+  end: number
+): SqlConstraint | undefined {
+  const text = source.masked.slice(start, end);
+  const named = new RegExp(`^constraint\\s+(${IDENT})\\s+`, 'i').exec(text);
+  const body = named ? text.slice(named[0].length) : text;
+  const offset = start + (named ? named[0].length : 0);
+  const line = source.lineOf(start);
+  const name = named?.[1] ? unquote(named[1]) : undefined;
+  const base = name === undefined ? { line } : { name, line };
+
+  const keyed = KEYED_CONSTRAINT.exec(body);
+  if (keyed) {
+    const keyword = (keyed[1] ?? '').toLowerCase();
+    const kind = keyword.startsWith('primary')
+      ? 'primary key'
+      : keyword.startsWith('foreign')
+        ? 'foreign key'
+        : 'unique';
+    const constraint: SqlConstraint = {
+      ...base,
+      kind,
+      columns: splitColumns(keyed[2]),
+    };
+    if (kind === 'foreign key') {
+      const references = REFERENCES.exec(
+        body.slice(keyed[0].length)
+      );
+      if (references) {
+        constraint.references = `${unquote(references[1] ?? '')}${columnList(references[2])}`;
+      }
+    }
+    return constraint;
+  }
+
+  const check = /^check\s*\(/i.exec(body);
+  if (check) {
+    const open = offset + check[0].length - 1;
+    const close = source.findMatching(open);
+    const constraint: SqlConstraint = { ...base, kind: 'check', columns: [] };
+    if (close !== -1 && close < end) constraint.expression = source.codeText(open + 1, close);
+    return constraint;
+  }
+
+  return undefined;
+}
+
+/**
+ * ALTER TABLE actions on a table of the same file: ADD [COLUMN] and
+ * ADD [CONSTRAINT] extend it; other actions are ignored
+ */
+function alterTable(source: SourceText, table: SymbolEntry, start: number, end: number): void {
+  for (const action of splitList(source, start, end)) {
+    const text = source.masked.slice(action.start, action.end);
+    const add = /^add\s+/i.exec(text);
+    if (!add) continue;
+    const itemStart = action.start + add[0].length;
+
+    const constraint = parseConstraint(source, itemStart, action.end);
+    if (constraint) {
+      table.constraints = [...(table.constraints ?? []), constraint];
+      continue;
+    }
+    const keyword = ADD_COLUMN.exec(text.slice(add[0].length))?.[0] ?? '';
+    const column = parseColumn(source, itemStart + keyword.length, action.end);
+    if (column) table.columns = [...(table.columns ?? []), column];
+  }
+}
+
+/**
+ * View columns: the explicit list in CREATE VIEW v (a, b), or else the
+ * names the select list gives its items (alias, or the last part of a
+ * column reference); expressions without an alias and * are skipped
+ */
+function viewColumns(
+  source: SourceText,
+  nameEnd: number,
+  headerEnd: number,
+  end: number
+): SqlColumn[] {
+  const open = source.masked.indexOf('(', nameEnd);
+  if (open !== -1 && open < headerEnd) {
+    const close = source.findMatching(open);
+    return splitList(source, open + 1, close === -1 ? headerEnd : close).map(item => ({
+      name: unquote(source.masked.slice(item.start, item.end)),
+      line: source.lineOf(item.start),
+    }));
+  }
+
+  const select = /\bselect\s+(?:distinct\s+(?:on\s*\([^)]*\)\s*)?)?/i.exec(
+    source.masked.slice(headerEnd, end)
+  );
+  if (!select) return [];
+  const listStart = headerEnd + select.index + select[0].length;
+  const from = findKeyword(source, listStart, end, /^from\b/i);
+
+  const columns: SqlColumn[] = [];
+  for (const item of splitList(source, listStart, from)) {
+    const text = source.masked.slice(item.start, item.end);
+    const alias = new RegExp(`(?:\\bas\\s+|\\s)(${IDENT})$`, 'i').exec(text);
+    const reference = new RegExp(`^(?:${IDENT}\\s*\\.\\s*)*(${IDENT})$`).exec(text);
+    const name = alias?.[1] ?? reference?.[1];
+    if (name) columns.push({ name: unquote(name), line: source.lineOf(item.start) });
+  }
+  return columns;
+}
+
+/**
+ * Function parameters: [IN|OUT|INOUT|VARIADIC] [name] type [DEFAULT value]
+ */
+function parseParameters(source: SourceText, open: number, close: number): Parameter[] {
+  return splitList(source, open + 1, close).map(item => {
+    const text = source
+      .codeText(item.start, item.end)
+      .replace(PARAMETER_MODES, '')
+      .replace(/\s+(?:default\b|=).*$/is, '');
+    const variadic = PARAMETER_MODES.exec(source.masked.slice(item.start, item.end))?.[0];
+    const named = new RegExp(`^(${IDENT})\\s+(.+)$`, 's').exec(text);
+    const parameter: Parameter = named
+      ? { name: unquote(named[1] ?? ''), type: (named[2] ?? '').trim() }
+      : { type: text.trim() };
+    if (variadic?.trim().toLowerCase() === 'variadic') parameter.variadic = true;
+    return parameter;
+  });
+}
+
+/**
+ * RETURNS clause after a function's parameters, up to its first option
+ */
+function returnType(
+  source: SourceText,
+  offset: number,
+  end: number
+): { type: string; end: number } | undefined {
+  const returns = /^\s*returns\s+/i.exec(source.masked.slice(offset, end));
+  if (!returns) return undefined;
+  const typeStart = offset + returns[0].length;
+  const typeEnd = findKeyword(source, typeStart, end, RETURNS_END);
+  const type = source.codeText(typeStart, lastCode(source, typeStart, typeEnd));
+  return type ? { type, end: lastCode(source, typeStart, typeEnd) } : undefined;
+}
+
+/**
+ * Offset of the first word at bracket depth 0 matching pattern, or end
+ */
+function findKeyword(source: SourceText, start: number, end: number, pattern: RegExp): number {
+  const text = source.masked;
+  let depth = 0;
+  for (let i = start; i < end; i++) {
+    const ch = text[i] ?? '';
+    if (ch === '(') depth++;
+    else if (ch === ')') depth--;
+    else if (depth === 0 && /[A-Za-z]/.test(ch) && !/[\w$]/.test(text[i - 1] ?? '')) {
+      if (pattern.test(text.slice(i, Math.min(end, i + 20)))) return i;
+    }
+  }
+  return end;
+}
+
+/**
+ * Comma-separated items between two offsets, outside nested brackets
+ */
+function splitList(source: SourceText, start: number, end: number): Statement[] {
+  const text = source.masked;
+  const items: Statement[] = [];
+  let itemStart = start;
+  let depth = 0;
+  for (let i = start; i <= end; i++) {
+    const ch = text[i];
+    if (ch === '(') depth++;
+    else if (ch === ')') depth--;
+    if ((ch === ',' && depth === 0) || i === end) {
+      const codeStart = skipSpace(text, itemStart);
+      if (codeStart < i) items.push({ start: codeStart, end: lastCode(source, codeStart, i) });
+      itemStart = i + 1;
+    }
+  }
+  return items;
+}
+
+/**
+ * Words and bracketed groups from an offset, with their offsets
+ */
+function tokenize(text: string, offset: number): Array<{ text: string; start: number }> {
+  const words: Array<{ text: string; start: number }> = [];
+  const pattern = /"[^"]*"|`[^`]*`|\[[^\]]*\]|[\w$]+|\S/g;
+  pattern.lastIndex = offset;
+  let match;
+  while ((match = pattern.exec(text)) !== null) {
+    words.push({ text: match[0], start: match.index });
+  }
+  return words;
+}
+
+/**
+ * The comment above a column, or after it on the same line (after its comma)
+ */
+function columnDoc(source: SourceText, start: number, next: number): string | undefined {
+  const line = source.lineOf(start);
+  const trailing = source.comments.find(c => c.line === line && c.start > start && c.start < next);
+  if (trailing) return trailing.text.replace(/^--\s?|^\/\*+|\*+\/$/g, '').trim() || undefined;
+  return source.docCommentBefore(line)?.text;
+}
+
+function columnList(list: string | undefined): string {
+  return list ? `(${splitColumns(list).join(', ')})` : '';
+}
+
+function splitColumns(list: string | undefined): string[] {
+  return (list ?? '')
+    .replace(/^\(|\)$/g, '')
+    .split(',')
+    .map(column => unquote(column.trim()))
+    .filter(column => column !== '');
+}
+
+/**
+ * Identifier without quotes or brackets: "public"."users" -> public.users
+ */
+function unquote(name: string): string {
+  return name
+    .split(/\s*\.\s*/)
+    .map(part => part.replace(/^["`[]|["`\]]$/g, ''))
+    .join('.');
+}
+
+/**
+ * Schema qualifier and base name: public.users -> [public, users]
+ */
+function splitName(name: string): [string | undefined, string] {
+  const dot = name.lastIndexOf('.');
+  return dot === -1 ? [undefined, name] : [name.slice(0, dot), name.slice(dot + 1)];
+}
+
+function qualifiedName(symbol: SymbolEntry): string {
+  return symbol.parent ? `${symbol.parent}.${symbol.name}` : symbol.name;
+}
+
+function skipSpace(text: string, offset: number): number {
+  let i = offset;
+  while (i < text.length && /\s/.test(text[i] ?? '')) i++;
+  return i;
+}
+
+/**
+ * Just past the last character of code before end
+ */
+function lastCode(source: SourceText, start: number, end: number): number {
+  let i = end;
+  while (i > start && /\s/.test(source.masked[i - 1] ?? '')) i--;
+  return i;
+}
@@ -0,0 +1,352 @@
+/**
+ * Swift Parser
+ * Extracts classes, structs, enums, actors, protocols, and functions with
+ * their modifiers, attributes, and doc comments; members of an extension
+ * attach to the type it extends
+ */
+
+import type { SymbolEntry, SymbolKind } from '../types/index.js';
+import { checkDeadline } from '../core/deadline.js';
+import { SourceText, SWIFT_SYNTAX, cleanComment } from './source.js';
+import type { SymbolParser } from './index.js';
+
+const IDENT = '(?:[A-Za-z_]\\w*|`[^`\\n]+`)';
+const OPERATOR = '[-+*/%=<>!&|^~?.]+';
+// private(set) only limits a property's setter, but is allowed anywhere a modifier is
+const MODIFIERS =
+  '((?:(?:public|open|internal|fileprivate|private|static|class|final|override|required|convenience|mutating|nonmutating|dynamic|optional|indirect|nonisolated|distributed|prefix|postfix|infix)(?:\\s*\\(\\s*set\\s*\\))?\\s+)*)';
+// Generic parameters nested up to three deep: <T: Collection<Array<Int>>>
+const GENERICS = '<(?:[^<>;{}()]|<(?:[^<>;{}()]|<[^<>;{}()]*>)*>)*>';
+
+// class is also a modifier: class func, class var
+const TYPE_PATTERN = new RegExp(
+  `(?<![\\w@.])${MODIFIERS}(class|struct|enum|actor|protocol|extension)\\s+(?!(?:func|var|let|subscript|init|deinit|override|final)\\b)(${IDENT}(?:\\.${IDENT})*)`,
+  'g'
+);
+// Not self.init( or super.init(, which are calls
+const FUNC_PATTERN = new RegExp(
+  `(?<![\\w@.])${MODIFIERS}(?:func\\s+(${IDENT}|${OPERATOR})|(init[?!]?|deinit|subscript))\\s*(?:${GENERICS}\\s*)?([({])`,
+  'g'
+);
+
+const TYPE_KINDS: Record<string, SymbolKind> = {
+  class: 'class',
+  actor: 'class',
+  struct: 'struct',
+  enum: 'enum',
+  protocol: 'interface',
+};
+
+interface TypeBlock {
+  name: string; // Qualified by enclosing types: Outer.Inner; the extended type for extensions
+  open: number;
+  close: number;
+  exported: boolean;
+  publicMembers: boolean; // Members are public without saying so: protocols, public extensions
+}
+
+export class SwiftParser implements SymbolParser {
+  readonly language = 'swift';
+  readonly version = 1;
+  readonly syntax = SWIFT_SYNTAX;
+  readonly extensions = ['.swift'];
+
+  parse(content: string, file: string): SymbolEntry[] {
+    const source = new SourceText(content, this.syntax);
+    const symbols: SymbolEntry[] = [];
+    const blocks = this.findTypes(source, file, symbols);
+
+    for (const match of matches(source, FUNC_PATTERN)) {
+      const start = itemStart(match);
+      const owner = this.enclosingType(source, blocks, start);
+      // Nested functions inside bodies are skipped, and initializers need a type
+      if (!owner && (source.depthAt(start) !== 0 || match[3])) continue;
+
+      const open = match.index + match[0].length - 1;
+      let bodyOpen: number | undefined = open;
+      let end = source.findMatching(open);
+      if (end === -1) continue;
+      // deinit { ... } has no parameter list
+      if (match[4] === '(') [bodyOpen, end] = this.memberBody(source, end + 1);
+      const modifiers = splitModifiers(match[1]);
+
+      symbols.push(
+        this.createSymbol(source, start, {
+          name: unquote(match[2] ?? (match[3] ?? '').replace(/[?!]$/, '')),
+          kind: owner ? 'method' : 'function',
+          file,
+          endLine: source.lineOf(end),
+          signature: collapse(source.content.slice(start, bodyOpen ?? end)),
+          exported: (owner?.exported ?? true) && isVisible(modifiers, owner),
+          parent: owner?.name,
+          modifiers,
+        })
+      );
+    }
+
+    return symbols.sort((a, b) => a.line - b.line);
+  }
+
+  /**
+   * Type declarations at file level or directly inside another type or an
+   * extension, added to symbols, and the blocks of extensions, which
+   * declare no symbol of their own; local types inside functions are skipped
+   */
+  private findTypes(source: SourceText, file: string, symbols: SymbolEntry[]): TypeBlock[] {
+    const blocks: TypeBlock[] = [];
+
+    // Outer types come first in the source, so their blocks exist by the time
+    // a nested type looks for its parent
+    for (const match of matches(source, TYPE_PATTERN)) {
+      const start = itemStart(match);
+      const owner = source.depthAt(start) === 0 ? null : this.enclosingType(source, blocks, start);
+      if (owner === undefined) continue;
+
+      const open = this.typeBody(source, match.index + match[0].length);
+      const close = open === -1 ? -1 : source.findMatching(open);
+      if (close === -1) continue;
+
+      const keyword = match[2] ?? '';
+      const simpleName = unquote(match[3] ?? '');
+      const modifiers = splitModifiers(match[1]);
+
+      // extension Array where Element: Equatable { ... } adds to Array
+      if (keyword === 'extension') {
+        blocks.push({
+          name: simpleName,
+          open,
+          close,
+          exported: !modifiers.includes('private') && !modifiers.includes('fileprivate'),
+          publicMembers: modifiers.includes('public') || modifiers.includes('open'),
+        });
+        continue;
+      }
+
+      const exported = (owner?.exported ?? true) && isVisible(modifiers, owner);
+      blocks.push({
+        name: owner ? `${owner.name}.${simpleName}` : simpleName,
+        open,
+        close,
+        exported,
+        publicMembers: keyword === 'protocol' && exported,
+      });
+      symbols.push(
+        this.createSymbol(source, start, {
+          name: simpleName,
+          kind: TYPE_KINDS[keyword] ?? 'class',
+          file,
+          endLine: source.lineOf(close),
+          signature: collapse(source.content.slice(start, open)),
+          exported,
+          parent: owner?.name,
+          modifiers,
+        })
+      );
+    }
+
+    return blocks;
+  }
+
+  /**
+   * The type or extension whose body directly contains an offset, or
+   * undefined when the offset is at file level or nested inside a function
+   */
+  private enclosingType(
+    source: SourceText,
+    blocks: TypeBlock[],
+    offset: number
+  ): TypeBlock | undefined {
+    let innermost: TypeBlock | undefined;
+    for (const block of blocks) {
+      if (block.open < offset && offset < block.close) {
+        if (!innermost || block.open > innermost.open) innermost = block;
+      }
+    }
+
+    if (innermost && source.depthAt(innermost.open) + 1 === source.depthAt(offset)) {
+      return innermost;
+    }
+    return undefined;
+  }
+
+  /**
+   * Opening brace of a type body, skipping generic parameters, inherited
+   * types, and where clauses; -1 if a `;` comes first
+   */
+  private typeBody(source: SourceText, from: number): number {
+    for (let i = from; i < source.masked.length; i++) {
+      const ch = source.masked[i];
+      if (ch === '(' || ch === '<') {
+        const close = source.findMatching(i);
+        if (close === -1) return -1;
+        i = close;
+      } else if (ch === '{') {
+        return i;
+      } else if (ch === ';' || ch === '}') {
+        return -1;
+      }
+    }
+    return -1;
+  }
+
+  /**
+   * The rest of a function after its parameter list: [bodyOpen, end], or no
+   * body for protocol requirements, which end with their line
+   */
+  private memberBody(source: SourceText, from: number): [number | undefined, number] {
+    const masked = source.masked;
+    for (let i = from; i < masked.length; i++) {
+      const ch = masked[i];
+      if (ch === '(' || ch === '[' || ch === '<') {
+        // Function types, tuples, and generics in the result type
+        const close = source.findMatching(i);
+        if (close === -1) break;
+        i = close;
+      } else if (ch === '{') {
+        const close = source.findMatching(i);
+        return [i, close === -1 ? i : close];
+      } else if (ch === ';' || ch === '}' || (ch === '\n' && !continues(masked, from, i))) {
+        return [undefined, i];
+      }
+    }
+    return [undefined, masked.length - 1];
+  }
+
+  private createSymbol(
+    source: SourceText,
+    start: number,
+    fields: Omit<SymbolEntry, 'language' | 'line' | 'doc' | 'decorators'>
+  ): SymbolEntry {
+    const line = source.lineOf(start);
+    const symbol: SymbolEntry = { ...fields, language: this.language, line };
+    if (fields.parent === undefined) delete symbol.parent;
+    if (fields.modifiers?.length === 0) delete symbol.modifiers;
+
+    const { attributes, start: declarationStart } = this.attributesBefore(source, start);
+    if (attributes.length > 0) symbol.decorators = attributes;
+
+    const doc = this.docBefore(source, declarationStart);
+    if (doc) symbol.doc = doc;
+    return symbol;
+  }
+
+  /**
+   * Attributes in front of a declaration, e.g. @MainActor or
+   * @available(iOS 15, *), and the offset of the first one
+   */
+  private attributesBefore(
+    source: SourceText,
+    offset: number
+  ): { attributes: string[]; start: number } {
+    const masked = source.masked;
+    const attributes: string[] = [];
+    let start = offset;
+
+    for (;;) {
+      let i = start - 1;
+      while (i >= 0 && /\s/.test(masked[i] ?? '')) i--;
+      const end = i + 1;
+
+      if (masked[i] === ')') {
+        const open = matchingOpen(masked, i);
+        if (open === -1) break;
+        i = open - 1;
+      }
+      const nameEnd = i + 1;
+      while (i >= 0 && /[\w.]/.test(masked[i] ?? '')) i--;
+      if (i + 1 === nameEnd || masked[i] !== '@') break;
+
+      attributes.unshift(collapse(source.content.slice(i, end)));
+      start = i;
+    }
+
+    return { attributes, start };
+  }
+
+  /**
+   * The run of /// comments or the /** ... *\/ comment directly in front of
+   * a declaration or its attributes; plain comments are not documentation
+   */
+  private docBefore(source: SourceText, offset: number): string | undefined {
+    const lines: string[] = [];
+    let end = offset;
+    for (let i = source.comments.length - 1; i >= 0; i--) {
+      const comment = source.comments[i];
+      if (!comment || comment.end > end) continue;
+      // Comments are blanked in masked text, so only whitespace may separate them
+      if (source.masked.slice(comment.end, end).trim() !== '') break;
+      if (comment.block) {
+        if (lines.length === 0 && comment.text.startsWith('/**')) lines.push(cleanComment(comment));
+        break;
+      }
+      if (!/^\/\/\/(?!\/)/.test(comment.text)) break;
+      lines.unshift(cleanComment(comment));
+      end = comment.start;
+    }
+    return lines.join('\n').trim() || undefined;
+  }
+}
+
+function* matches(source: SourceText, pattern: RegExp): Generator<RegExpExecArray> {
+  pattern.lastIndex = 0;
+  let match;
+  while ((match = pattern.exec(source.masked)) !== null) {
+    checkDeadline();
+    yield match;
+  }
+}
+
+/**
+ * Declarations are internal unless marked public or open; protocol members
+ * share the protocol's access, and a public extension makes its members public
+ */
+function isVisible(modifiers: string[], owner: TypeBlock | null | undefined): boolean {
+  if (['private', 'fileprivate', 'internal'].some(m => modifiers.includes(m))) return false;
+  if (modifiers.includes('public') || modifiers.includes('open')) return true;
+  return owner?.publicMembers ?? false;
+}
+
+/**
+ * Whether a declaration goes on past the line break at newline: the line
+ * ends mid-signature or the next one starts with effects, a result type, a
+ * where clause, or the body
+ */
+function continues(masked: string, from: number, newline: number): boolean {
+  let next = newline + 1;
+  while (next < masked.length && /\s/.test(masked[next] ?? '')) next++;
+  return (
+    /(?:[,:.(<]|->)$/.test(masked.slice(from, newline).trimEnd()) ||
+    /^(?:[{,.]|->|(?:where|throws|rethrows|async)\b)/.test(masked.slice(next, next + 9))
+  );
+}
+
+function splitModifiers(text: string | undefined): string[] {
+  return text?.trim() ? text.trim().split(/\s+/) : [];
+}
+
+function unquote(name: string): string {
+  return name.replace(/^`(.*)`$/, '$1');
+}
+
+/**
+ * Offset of the ( matching the ) at closeOffset, in masked text
+ */
+function matchingOpen(masked: string, closeOffset: number): number {
+  let depth = 0;
+  for (let i = closeOffset; i >= 0; i--) {
+    if (masked[i] === ')') depth++;
+    else if (masked[i] === '(' && --depth === 0) return i;
+  }
+  return -1;
+}
+
+/**
+ * Offset of the first non-blank character of a match
+ */
+function itemStart(match: RegExpExecArray): number {
+  return match.index + Math.max(0, match[0].search(/\S/));
+}
+
+function collapse(text: string): string {
+  return text.replace(/\s+/g, ' ').trim();
+}
@@ -0,0 +1,378 @@
+/**
+ * TypeScript/JavaScript Parser
+ * Extracts functions, classes with methods, interfaces, type aliases, enums, and constants
+ */
+
+import type { SymbolEntry, SymbolKind } from '../types/index.js';
+import { checkDeadline } from '../core/deadline.js';
+import { SourceText, JS_SYNTAX } from './source.js';
+import type { SymbolParser } from './index.js';
+
+const IDENT = '[A-Za-z_$][\\w$]*';
+
+const FUNCTION_PATTERN = new RegExp(
+  `^[ \\t]*(export\\s+)?(?:default\\s+)?(?:declare\\s+)?(?:async\\s+)?function\\s*\\*?\\s*(${IDENT})`,
+  'gm'
+);
+const CLASS_PATTERN = new RegExp(
+  `^[ \\t]*(export\\s+)?(?:default\\s+)?(?:declare\\s+)?(?:abstract\\s+)?class\\s+(${IDENT})`,
+  'gm'
+);
+const INTERFACE_PATTERN = new RegExp(
+  `^[ \\t]*(export\\s+)?(?:declare\\s+)?interface\\s+(${IDENT})`,
+  'gm'
+);
+const TYPE_PATTERN = new RegExp(`^[ \\t]*(export\\s+)?(?:declare\\s+)?type\\s+(${IDENT})\\b`, 'gm');
+const ENUM_PATTERN = new RegExp(
+  `^[ \\t]*(export\\s+)?(?:declare\\s+)?(?:const\\s+)?enum\\s+(${IDENT})`,
+  'gm'
+);
+const VARIABLE_PATTERN = new RegExp(
+  `^[ \\t]*(export\\s+)?(?:declare\\s+)?(const|let|var)\\s+(${IDENT})\\s*(?::[^=\\n]+)?=`,
+  'gm'
+);
+
+const METHOD_PATTERN = new RegExp(
+  `^\\s*((?:(?:public|private|protected|static|readonly|async|override|abstract|declare|accessor)\\s+)*)(?:(?:get|set)\\s+)?(?:\\*\\s*)?(#?${IDENT})\\s*\\??\\s*(?:<[^>]*>)?\\s*\\(`
+);
+const PROPERTY_FUNCTION_PATTERN = new RegExp(
+  `^\\s*((?:(?:public|private|protected|static|readonly|override)\\s+)*)(#?${IDENT})\\s*(?::[^=]+)?=\\s*(?=(?:async\\s+)?(?:function\\b|\\(|${IDENT}\\s*=>))`
+);
+
+const NOT_METHODS = new Set(['if', 'for', 'while', 'switch', 'catch', 'return', 'function', 'with']);
+
+export class TypeScriptParser implements SymbolParser {
+  readonly language = 'typescript';
+  readonly version = 1;
+  readonly aliases = ['javascript'];
+  readonly syntax = JS_SYNTAX;
+  readonly extensions = ['.ts', '.tsx', '.mts', '.cts', '.js', '.jsx', '.mjs', '.cjs'];
+
+  parse(content: string, file: string): SymbolEntry[] {
+    const source = new SourceText(content, this.syntax);
+    const symbols: SymbolEntry[] = [];
+
+    for (const match of this.topLevel(source, FUNCTION_PATTERN)) {
+      const open = source.masked.indexOf('(', match.index + match[0].length);
+      symbols.push(
+        this.createFunction(source, file, matchStart(match), open, 'function', {
+          name: match[2] ?? '',
+          exported: match[1] !== undefined,
+        })
+      );
+    }
+
+    for (const match of this.topLevel(source, CLASS_PATTERN)) {
+      this.parseClass(source, file, match, symbols);
+    }
+
+    for (const [pattern, kind] of [
+      [INTERFACE_PATTERN, 'interface'],
+      [ENUM_PATTERN, 'enum'],
+    ] as const) {
+      for (const match of this.topLevel(source, pattern)) {
+        const open = source.masked.indexOf('{', match.index);
+        const close = open === -1 ? -1 : source.findMatching(open);
+        symbols.push(
+          this.createSymbol(source, matchStart(match), {
+            name: match[2] ?? '',
+            kind,
+            file,
+            endLine: close === -1 ? source.lineOf(match.index) : source.lineOf(close),
+            signature: collapse(source.content.slice(matchStart(match), open)),
+            exported: match[1] !== undefined,
+          })
+        );
+      }
+    }
+
+    for (const match of this.topLevel(source, TYPE_PATTERN)) {
+      const end = source.statementEnd(match.index + match[0].length);
+      symbols.push(
+        this.createSymbol(source, matchStart(match), {
+          name: match[2] ?? '',
+          kind: 'type',
+          file,
+          endLine: source.lineOf(end),
+          signature: firstLine(source.content.slice(matchStart(match), end)),
+          exported: match[1] !== undefined,
+        })
+      );
+    }
+
+    for (const match of this.topLevel(source, VARIABLE_PATTERN)) {
+      this.parseVariable(source, file, match, symbols);
+    }
+
+    return symbols.sort((a, b) => a.line - b.line);
+  }
+
+  private *topLevel(source: SourceText, pattern: RegExp): Generator<RegExpExecArray> {
+    pattern.lastIndex = 0;
+    let match;
+    while ((match = pattern.exec(source.masked)) !== null) {
+      checkDeadline();
+      if (source.depthAt(matchStart(match)) === 0) {
+        yield match;
+      }
+    }
+  }
+
+  private parseVariable(
+    source: SourceText,
+    file: string,
+    match: RegExpExecArray,
+    symbols: SymbolEntry[]
+  ): void {
+    const name = match[3] ?? '';
+    const exported = match[1] !== undefined;
+    const valueStart = match.index + match[0].length;
+    const valueOffset = valueStart + source.masked.slice(valueStart).search(/\S|$/);
+
+    const arrowOpen = this.arrowParamsOpen(source, valueOffset);
+    if (arrowOpen !== -1) {
+      symbols.push(
+        this.createFunction(source, file, matchStart(match), arrowOpen, 'function', {
+          name,
+          exported,
+        })
+      );
+      return;
+    }
+
+    const end = source.statementEnd(valueStart);
+    symbols.push(
+      this.createSymbol(source, matchStart(match), {
+        name,
+        kind: match[2] === 'const' ? 'const' : 'var',
+        file,
+        endLine: source.lineOf(end),
+        signature: firstLine(source.content.slice(matchStart(match), end)),
+        exported,
+      })
+    );
+  }
+
+  /**
+   * If the value at offset is a function expression, return the offset of
+   * its parameter list (or of the single bare parameter), otherwise -1
+   */
+  private arrowParamsOpen(source: SourceText, offset: number): number {
+    const rest = source.masked.slice(offset, offset + 400);
+    const prefix = /^(?:async\s+)?/.exec(rest)?.[0].length ?? 0;
+    const start = offset + prefix;
+
+    if (/^function\b/.test(rest.slice(prefix))) {
+      return source.masked.indexOf('(', start);
+    }
+    if (source.masked[start] === '(') {
+      const close = source.findMatching(start);
+      if (close === -1) return -1;
+      return this.arrowFollows(source.masked, close + 1) ? start : -1;
+    }
+    if (new RegExp(`^${IDENT}\\s*=>`).test(rest.slice(prefix))) {
+      return start;
+    }
+    return -1;
+  }
+
+  /**
+   * Whether an arrow (=>) follows at offset, skipping an optional return type annotation
+   */
+  private arrowFollows(masked: string, offset: number): boolean {
+    let depth = 0;
+
+    for (let i = offset; i < Math.min(masked.length, offset + 300); i++) {
+      const ch = masked[i] ?? '';
+      if (ch === '=' && masked[i + 1] === '>') {
+        if (depth === 0) return true;
+        i++;
+      } else if ('([{<'.includes(ch)) {
+        depth++;
+      } else if (')]}>'.includes(ch)) {
+        depth--;
+      } else if (depth === 0 && (ch === ';' || ch === '=')) {
+        return false;
+      }
+    }
+
+    return false;
+  }
+
+  private parseClass(
+    source: SourceText,
+    file: string,
+    match: RegExpExecArray,
+    symbols: SymbolEntry[]
+  ): void {
+    const className = match[2] ?? '';
+    const exported = match[1] !== undefined;
+    const open = this.findClassBody(source, match.index + match[0].length);
+    const close = open === -1 ? -1 : source.findMatching(open);
+
+    symbols.push(
+      this.createSymbol(source, matchStart(match), {
+        name: className,
+        kind: 'class',
+        file,
+        endLine: close === -1 ? source.lineOf(match.index) : source.lineOf(close),
+        signature: collapse(source.content.slice(matchStart(match), open === -1 ? undefined : open)),
+        exported,
+      })
+    );
+
+    if (open === -1 || close === -1) return;
+
+    const bodyDepth = source.depthAt(open) + 1;
+    const firstBodyLine = source.lineOf(open);
+    const lastBodyLine = source.lineOf(close);
+
+    for (let line = firstBodyLine; line <= lastBodyLine; line++) {
+      const lineStart = line === firstBodyLine ? open + 1 : source.lineStart(line);
+      const text = source.masked.slice(lineStart, source.lineStart(line + 1));
+      const indent = text.length - text.trimStart().length;
+      if (!text.trim() || source.depthAt(lineStart + indent) !== bodyDepth) continue;
+
+      const methodMatch = METHOD_PATTERN.exec(text);
+      const propertyMatch = methodMatch ? null : PROPERTY_FUNCTION_PATTERN.exec(text);
+      const member = methodMatch ?? propertyMatch;
+      if (!member) continue;
+
+      const name = member[2] ?? '';
+      if (NOT_METHODS.has(name)) continue;
+
+      const modifiers = member[1] ?? '';
+      const isPublic = !/\b(?:private|protected)\b/.test(modifiers) && !name.startsWith('#');
+      const memberStart = lineStart + indent;
+      const paramsOpen = methodMatch
+        ? lineStart + member[0].length - 1
+        : this.arrowParamsOpen(source, lineStart + member[0].length);
+
+      const method = this.createFunction(source, file, memberStart, paramsOpen, 'method', {
+        name,
+        exported: exported && isPublic,
+      });
+      method.parent = className;
+      symbols.push(method);
+    }
+  }
+
+  private findClassBody(source: SourceText, from: number): number {
+    for (let i = from; i < source.masked.length; i++) {
+      const ch = source.masked[i];
+      if (ch === '<' || ch === '(') {
+        const close = source.findMatching(i);
+        if (close === -1) return -1;
+        i = close;
+      } else if (ch === '{') {
+        return i;
+      } else if (ch === ';') {
+        return -1;
+      }
+    }
+    return -1;
+  }
+
+  private createFunction(
+    source: SourceText,
+    file: string,
+    start: number,
+    paramsOpen: number,
+    kind: SymbolKind,
+    identity: { name: string; exported: boolean }
+  ): SymbolEntry {
+    const paramsClose =
+      paramsOpen !== -1 && source.masked[paramsOpen] === '(' ? source.findMatching(paramsOpen) : -1;
+    const bodyOpen = this.findBodyOpen(source, paramsClose === -1 ? paramsOpen : paramsClose + 1);
+
+    let end: number;
+    let signatureEnd: number;
+    if (bodyOpen !== -1) {
+      const bodyClose = source.findMatching(bodyOpen);
+      end = bodyClose === -1 ? bodyOpen : bodyClose;
+      signatureEnd = bodyOpen;
+    } else {
+      end = source.statementEnd(paramsClose !== -1 ? paramsClose + 1 : Math.max(paramsOpen, start));
+      signatureEnd = end;
+    }
+
+    let signature = collapse(source.content.slice(start, signatureEnd));
+    if (signature.endsWith('=>')) signature = signature.slice(0, -2).trimEnd() + ' =>';
+
+    return this.createSymbol(source, start, {
+      ...identity,
+      kind,
+      file,
+      endLine: source.lineOf(end),
+      signature: signature.replace(/[;,]$/, ''),
+    });
+  }
+
+  /**
+   * Find a function body brace after the parameter list, treating braces
+   * that follow ':', '|', '&', '<' or ',' as object types in the return type
+   */
+  private findBodyOpen(source: SourceText, from: number): number {
+    if (from < 0) return -1;
+    const masked = source.masked;
+    let expectType = false;
+
+    for (let i = from; i < masked.length; i++) {
+      const ch = masked[i] ?? '';
+      if (ch === '{') {
+        if (!expectType) return i;
+        const close = source.findMatching(i);
+        if (close === -1) return -1;
+        i = close;
+        expectType = false;
+      } else if (ch === '(' || ch === '[' || ch === '<') {
+        const close = ch === '<' ? masked.indexOf('>', i) : source.findMatching(i);
+        if (close === -1) return -1;
+        i = close;
+        expectType = false;
+      } else if (':|&,'.includes(ch)) {
+        expectType = true;
+      } else if (ch === '=' && masked[i + 1] === '>') {
+        i++;
+        expectType = false;
+        // Expression-bodied arrow function: braces here start the body
+        const next = masked.slice(i + 1).search(/\S/);
+        const nextOffset = next === -1 ? -1 : i + 1 + next;
+        return masked[nextOffset] === '{' ? nextOffset : -1;
+      } else if (ch === ';' || ch === '}') {
+        return -1;
+      } else if (/\w/.test(ch)) {
+        expectType = false;
+      }
+    }
+
+    return -1;
+  }
+
+  private createSymbol(
+    source: SourceText,
+    start: number,
+    fields: Omit<SymbolEntry, 'language' | 'line' | 'doc'>
+  ): SymbolEntry {
+    const line = source.lineOf(start);
+    const doc = source.docCommentBefore(line);
+    const symbol: SymbolEntry = { ...fields, language: this.language, line };
+    if (doc) symbol.doc = doc.text;
+    return symbol;
+  }
+}
+
+/**
+ * Offset of the first non-whitespace character of a match
+ */
+function matchStart(match: RegExpExecArray): number {
+  return match.index + (match[0].length - match[0].trimStart().length);
+}
+
+function collapse(text: string): string {
+  return text.replace(/\s+/g, ' ').trim();
+}
+
+function firstLine(text: string): string {
+  return (text.split('\n')[0] ?? '').trim();
+}
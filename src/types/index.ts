@@ -170,3 +170,276 @@ export const HardwareConfigSchema = z.object({
     })
   ),
 });
+
+// Symbol extraction (shared across all language parsers)
+export const SymbolKindSchema = z.enum([
+  'function',
+  'method',
+  'class',
+  'struct',
+  'interface',
+  'type',
+  'enum',
+  'const',
+  'var',
+  'package', // Java package declarations
+  'namespace', // C++ namespaces
+  'table', // SQL DDL
+  'view',
+  'index',
+]);
+
+export type SymbolKind = z.infer<typeof SymbolKindSchema>;
+
+// Go function parameter or result; names are omitted in func(int) (string, error)
+export const ParameterSchema = z.object({
+  name: z.string().optional(),
+  type: z.string(), // Element type for variadic parameters: ...string -> string
+  variadic: z.boolean().optional(),
+});
+
+export type Parameter = z.infer<typeof ParameterSchema>;
+
+export const TypeParameterSchema = z.object({
+  name: z.string(),
+  constraint: z.string(), // e.g. any, comparable, ~int | ~float64
+});
+
+export type TypeParameter = z.infer<typeof TypeParameterSchema>;
+
+// GraphQL field, argument-taking field, or enum value
+export const GraphQLFieldSchema = z.object({
+  name: z.string(),
+  line: z.number(),
+  type: z.string().optional(), // As written without spaces: [ID!]!; unset for enum values
+  args: z.array(ParameterSchema).optional(),
+  deprecated: z.boolean().optional(), // @deprecated
+  doc: z.string().optional(), // Description string or # comment
+});
+
+export type GraphQLField = z.infer<typeof GraphQLFieldSchema>;
+
+// SQL table column, or a view column named in its column list or select list
+export const SqlColumnSchema = z.object({
+  name: z.string(),
+  line: z.number(),
+  type: z.string().optional(), // As written, whitespace collapsed: varchar(255); unset for views
+  nullable: z.boolean().optional(), // False for NOT NULL and PRIMARY KEY columns
+  primaryKey: z.boolean().optional(),
+  unique: z.boolean().optional(),
+  default: z.string().optional(), // DEFAULT expression as written
+  references: z.string().optional(), // Inline foreign key: users(id)
+  doc: z.string().optional(), // -- comment above or after the column
+});
+
+// Table-level constraint, from CREATE TABLE or ALTER TABLE ... ADD in the same file
+export const SqlConstraintSchema = z.object({
+  name: z.string().optional(), // CONSTRAINT name
+  kind: z.enum(['primary key', 'unique', 'foreign key', 'check']),
+  line: z.number(),
+  columns: z.array(z.string()), // Empty for check constraints
+  references: z.string().optional(), // Foreign keys: orders(id, region)
+  expression: z.string().optional(), // Check constraints: the condition
+});
+
+export type SqlColumn = z.infer<typeof SqlColumnSchema>;
+export type SqlConstraint = z.infer<typeof SqlConstraintSchema>;
+
+export const SymbolEntrySchema = z.object({
+  name: z.string(),
+  kind: SymbolKindSchema,
+  language: z.string(),
+  file: z.string(),
+  line: z.number(),
+  endLine: z.number(),
+  startByte: z.number().optional(), // UTF-8 byte offsets of the declaration in the file
+  endByte: z.number().optional(), // Exclusive; end of the last line, newline excluded
+  signature: z.string(),
+  exported: z.boolean(),
+  parent: z.string().optional(), // Enclosing type for methods
+  doc: z.string().optional(),
+  deprecated: z.boolean().optional(), // Go doc comment has a Deprecated: paragraph
+  deprecation: z.string().optional(), // Text of that paragraph, e.g. "Use NewClient instead."
+  decorators: z.array(z.string()).optional(), // e.g. @app.route, @staticmethod, @Override
+  modifiers: z.array(z.string()).optional(), // Java: public, static, final, abstract, ...
+  params: z.array(ParameterSchema).optional(), // Go functions and methods, GraphQL operations, SQL
+  returns: z.array(ParameterSchema).optional(),
+  typeParams: z.array(TypeParameterSchema).optional(), // Generic Go functions
+  valueType: z.string().optional(), // Go consts and vars: declared, or inferred from the value
+  value: z.string().optional(), // Initializer as written; repeated from above in a const group
+  constValue: z.string().optional(), // Computed integer constant in decimal: 1 << 10 -> "1024"
+  fields: z.array(GraphQLFieldSchema).optional(), // GraphQL definitions: fields and enum values
+  columns: z.array(SqlColumnSchema).optional(), // SQL tables and views
+  constraints: z.array(SqlConstraintSchema).optional(), // SQL tables
+  complexity: z.number().optional(), // Cyclomatic, of functions and methods, when requested
+  blame: z
+    .object({ author: z.string(), commit: z.string(), date: z.string() })
+    .optional(), // Last commit touching the definition line, when requested
+  root: z.string().optional(), // Project root name, when several roots are registered
+});
+
+export type SymbolEntry = z.infer<typeof SymbolEntrySchema>;
+
+// Declaration-only view of a symbol, for mapping out an API cheaply
+export const SymbolSignatureSchema = SymbolEntrySchema.pick({
+  name: true,
+  kind: true,
+  file: true,
+  line: true,
+  signature: true,
+  parent: true,
+  doc: true,
+  complexity: true,
+  blame: true,
+});
+
+export type SymbolSignature = z.infer<typeof SymbolSignatureSchema>;
+
+// References
+export const ReferenceKindSchema = z.enum(['definition', 'reference']);
+
+export type ReferenceKind = z.infer<typeof ReferenceKindSchema>;
+
+export const ReferenceEntrySchema = z.object({
+  symbol: z.string(),
+  kind: ReferenceKindSchema,
+  file: z.string(),
+  line: z.number(),
+  column: z.number(),
+  startByte: z.number(), // UTF-8 byte range of the name itself
+  endByte: z.number(),
+  snippet: z.string(), // Matched line with its context (default: one line either side)
+  snippetLine: z.number(), // Line number of the snippet's first line
+  root: z.string().optional(),
+});
+
+export type ReferenceEntry = z.infer<typeof ReferenceEntrySchema>;
+
+// Edits
+export const TextEditSchema = z.object({
+  file: z.string(),
+  line: z.number(),
+  column: z.number(), // 1-based start of oldText
+  startByte: z.number(), // UTF-8 byte range of oldText, before any edit is applied
+  endByte: z.number(),
+  oldText: z.string(),
+  newText: z.string(),
+});
+
+export type TextEdit = z.infer<typeof TextEditSchema>;
+
+// Complexity
+export const ComplexityEntrySchema = z.object({
+  function: z.string(), // Qualified name, e.g. Calculator.Add
+  file: z.string(),
+  line: z.number(),
+  complexity: z.number(),
+});
+
+export type ComplexityEntry = z.infer<typeof ComplexityEntrySchema>;
+
+// Call Graph
+export const CallEdgeSchema = z.object({
+  caller: z.string(),
+  callee: z.string(),
+  external: z.boolean(), // Callee lives outside the package (e.g. http.Get)
+});
+
+export type CallEdge = z.infer<typeof CallEdgeSchema>;
+
+export const CallGraphSchema = z.object({
+  package: z.string(),
+  nodes: z.array(z.string()),
+  edges: z.array(CallEdgeSchema),
+});
+
+export type CallGraph = z.infer<typeof CallGraphSchema>;
+
+// Imports
+export const FileImportsSchema = z.object({
+  file: z.string(),
+  module: z.string().optional(), // Module path from the nearest go.mod
+  stdlib: z.array(z.string()),
+  thirdParty: z.array(z.string()),
+  internal: z.array(z.string()), // Packages inside the file's own module
+});
+
+export type FileImports = z.infer<typeof FileImportsSchema>;
+
+export const ImportReportSchema = z.object({
+  files: z.array(FileImportsSchema),
+  external: z.array(z.object({ package: z.string(), files: z.number() })),
+  stdlib: z.array(z.object({ package: z.string(), files: z.number() })),
+});
+
+export type ImportReport = z.infer<typeof ImportReportSchema>;
+
+// Import hygiene
+export const ImportIssueSchema = z.object({
+  line: z.number(), // Import spec; for a missing import, the first use
+  path: z.string(), // For a missing import, the package the name most likely stands for
+  name: z.string(), // Name the file refers to the package by
+  // blank (_) and dot (.) imports are intentional and never counted as unused
+  status: z.enum(['unused', 'missing', 'blank', 'dot']),
+  usage: z.string().optional(), // First selector of a missing import, e.g. "strings.ToUpper"
+});
+
+export const FileImportIssuesSchema = z.object({
+  file: z.string(),
+  issues: z.array(ImportIssueSchema),
+});
+
+export const ImportHygieneReportSchema = z.object({
+  files: z.number(), // Go files checked
+  unused: z.number(),
+  missing: z.number(),
+  results: z.array(FileImportIssuesSchema), // Files with at least one issue
+});
+
+export type ImportIssue = z.infer<typeof ImportIssueSchema>;
+export type FileImportIssues = z.infer<typeof FileImportIssuesSchema>;
+export type ImportHygieneReport = z.infer<typeof ImportHygieneReportSchema>;
+
+// Name resolution
+export const UnresolvedReferenceSchema = z.object({
+  name: z.string(),
+  file: z.string(),
+  line: z.number(),
+  column: z.number(), // 1-based
+  qualifier: z.boolean(), // Used as name.X, so most likely a missing import
+  importPath: z.string().optional(), // Standard library package a qualifier likely means
+});
+
+export const ReferenceValidationSchema = z.object({
+  packages: z.number(), // Go packages checked; a directory's _test package counts apart
+  files: z.number(),
+  references: z.number(), // Identifier uses checked
+  unresolved: z.array(UnresolvedReferenceSchema),
+});
+
+export type UnresolvedReference = z.infer<typeof UnresolvedReferenceSchema>;
+export type ReferenceValidation = z.infer<typeof ReferenceValidationSchema>;
+
+// Package graph
+export const PackageNodeSchema = z.object({
+  id: z.string(), // Import path; "external" for collapsed stdlib and third-party packages
+  kind: z.enum(['internal', 'stdlib', 'external']),
+  dir: z.string().optional(), // Directory of an internal package under the analyzed path
+  files: z.number().optional(), // Non-test Go files in that directory
+});
+
+export const PackageEdgeSchema = z.object({
+  from: z.string(),
+  to: z.string(),
+  files: z.number(), // Files of the importing package with this import
+});
+
+export const PackageGraphSchema = z.object({
+  nodes: z.array(PackageNodeSchema),
+  edges: z.array(PackageEdgeSchema),
+  cycles: z.array(z.array(z.string())), // Each closes on its first package: a -> b -> a
+});
+
+export type PackageNode = z.infer<typeof PackageNodeSchema>;
+export type PackageEdge = z.infer<typeof PackageEdgeSchema>;
+export type PackageGraph = z.infer<typeof PackageGraphSchema>;
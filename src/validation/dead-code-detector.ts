@@ -0,0 +1,171 @@
+/**
+ * Dead Code Detector
+ * Reports unexported Go functions, types, and methods nothing in their package uses
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { SymbolEntry } from '../types/index.js';
+import { GO_SYNTAX, SourceText } from '../parsers/source.js';
+import { SymbolIndexer, qualifiedName } from '../indexes/symbol-indexer.js';
+
+export interface DeadSymbol {
+  file: string;
+  line: number;
+  symbol: string;
+  kind: string;
+}
+
+export interface DeadCodeReport {
+  dead: DeadSymbol[];
+  checked: number; // Unexported symbols considered
+}
+
+interface PackageFile {
+  file: string;
+  source: SourceText;
+  code: string; // Masked text with method receivers blanked out
+}
+
+const CANDIDATE_KINDS = new Set(['function', 'method', 'struct', 'interface', 'type']);
+
+// Called by the runtime, never by name
+const ENTRY_POINTS = new Set(['main', 'init', '_']);
+
+export class DeadCodeDetector {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  async detect(target = '.'): Promise<DeadCodeReport> {
+    const symbols = (await this.symbolIndexer.scan(target)).filter(s => s.language === 'go');
+
+    const byPackage = new Map<string, SymbolEntry[]>();
+    for (const symbol of symbols) {
+      const dir = path.posix.dirname(symbol.file);
+      byPackage.set(dir, [...(byPackage.get(dir) ?? []), symbol]);
+    }
+
+    const report: DeadCodeReport = { dead: [], checked: 0 };
+    for (const [dir, packageSymbols] of byPackage) {
+      const candidates = packageSymbols.filter(
+        s =>
+          !s.exported &&
+          CANDIDATE_KINDS.has(s.kind) &&
+          !(s.kind === 'function' && ENTRY_POINTS.has(s.name))
+      );
+      if (candidates.length === 0) continue;
+      report.checked += candidates.length;
+
+      const files = await this.readPackage(dir);
+      // cgo's //export makes a function callable from C
+      const cgoExports = new Set(
+        files.flatMap(f =>
+          [...f.source.content.matchAll(/^\/\/export\s+(\w+)/gm)].map(m => m[1] ?? '')
+        )
+      );
+
+      for (const symbol of candidates) {
+        if (cgoExports.has(symbol.name)) continue;
+        if (this.isUsed(symbol, packageSymbols, files)) continue;
+
+        report.dead.push({
+          file: symbol.file,
+          line: symbol.line,
+          symbol: qualifiedName(symbol),
+          kind: symbol.kind,
+        });
+      }
+    }
+
+    report.dead.sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+    return report;
+  }
+
+  /**
+   * Any mention of the name in package code counts as a use: calls, and also
+   * functions passed as values like http.HandleFunc("/", healthHandler).
+   * Mentions inside a declaration of the same name (its own body, or a
+   * same-named method on another type) don't.
+   */
+  private isUsed(
+    symbol: SymbolEntry,
+    packageSymbols: SymbolEntry[],
+    files: PackageFile[]
+  ): boolean {
+    const sameName = packageSymbols.filter(s => s.name === symbol.name);
+    const pattern = new RegExp(`(?<![\\w$])${symbol.name}(?![\\w$])`, 'g');
+
+    for (const { file, source, code } of files) {
+      for (const match of code.matchAll(pattern)) {
+        const line = source.lineOf(match.index);
+        const inDeclaration = sameName.some(
+          s => s.file === file && s.line <= line && line <= s.endLine
+        );
+        if (!inDeclaration) return true;
+      }
+    }
+    return false;
+  }
+
+  /**
+   * Every .go file in the directory, tests included: a helper only tests call
+   * is still in use
+   */
+  private async readPackage(dir: string): Promise<PackageFile[]> {
+    const fullDir = path.join(this.symbolIndexer.getWorkingDir(), dir);
+    let entries: string[];
+    try {
+      entries = await fs.readdir(fullDir);
+    } catch {
+      return [];
+    }
+
+    const files: PackageFile[] = [];
+    for (const entry of entries.filter(e => e.endsWith('.go')).sort()) {
+      try {
+        const content = await fs.readFile(path.join(fullDir, entry), 'utf-8');
+        const source = new SourceText(content, GO_SYNTAX);
+        files.push({
+          file: path.posix.join(dir, entry),
+          source,
+          // A method's own receiver doesn't make its type used
+          code: source.masked.replace(/^func\s*\([^)]*\)/gm, m => m.replace(/[^\n]/g, ' ')),
+        });
+      } catch {
+        // Skip files that can't be read
+      }
+    }
+    return files;
+  }
+
+  formatReport(report: DeadCodeReport): string {
+    if (report.checked === 0) {
+      return 'No unexported Go functions, types, or methods found.';
+    }
+    if (report.dead.length === 0) {
+      return `All ${report.checked} unexported Go symbol(s) are used within their package.`;
+    }
+
+    let output = `${report.dead.length} of ${report.checked} unexported symbol(s) `;
+    output += 'are never used in their package:\n\n';
+    let currentFile = '';
+
+    for (const entry of report.dead) {
+      if (entry.file !== currentFile) {
+        if (currentFile) output += '\n';
+        output += `${entry.file}:\n`;
+        currentFile = entry.file;
+      }
+      output += `  ${String(entry.line).padStart(4)}  ${entry.kind} ${entry.symbol}\n`;
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
@@ -0,0 +1,73 @@
+/**
+ * Deprecation Finder
+ * Lists Go symbols whose doc comment has a "Deprecated:" paragraph, for
+ * steering callers away from them and tracking their removal
+ */
+
+import type { ScopeOptions } from '../core/file-walker.js';
+import { SymbolIndexer, qualifiedName } from '../indexes/symbol-indexer.js';
+
+export interface DeprecatedSymbol {
+  file: string;
+  line: number;
+  symbol: string;
+  kind: string;
+  exported: boolean;
+  message?: string | undefined; // Text after "Deprecated:", e.g. "Use NewClient instead."
+}
+
+export class DeprecationFinder {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  async find(
+    target = '.',
+    only?: string[],
+    scope: ScopeOptions = {}
+  ): Promise<DeprecatedSymbol[]> {
+    const symbols = await this.symbolIndexer.scan(target, { ...scope, only });
+
+    return symbols
+      .filter(s => s.deprecated)
+      .map(s => {
+        const entry: DeprecatedSymbol = {
+          file: s.file,
+          line: s.line,
+          symbol: qualifiedName(s),
+          kind: s.kind,
+          exported: s.exported,
+        };
+        if (s.deprecation) entry.message = s.deprecation;
+        return entry;
+      })
+      .sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+  }
+
+  formatResult(deprecated: DeprecatedSymbol[]): string {
+    if (deprecated.length === 0) {
+      return 'No deprecated symbols found.';
+    }
+
+    let output = `Found ${deprecated.length} deprecated symbol(s):\n\n`;
+    let currentFile = '';
+
+    for (const d of deprecated) {
+      if (d.file !== currentFile) {
+        if (currentFile) output += '\n';
+        output += `${d.file}:\n`;
+        currentFile = d.file;
+      }
+      const message = d.message ? `: ${d.message}` : '';
+      output += `  ${String(d.line).padStart(4)}  ${d.kind} ${d.symbol}${message}\n`;
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
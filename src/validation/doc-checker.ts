@@ -0,0 +1,147 @@
+/**
+ * Doc Comment Checker
+ * Reports exported Go symbols without a doc comment that starts with their name
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { SymbolEntry } from '../types/index.js';
+import { GO_SYNTAX, SourceText } from '../parsers/source.js';
+import type { ScopeOptions } from '../core/file-walker.js';
+import { SymbolIndexer, qualifiedName } from '../indexes/symbol-indexer.js';
+
+export interface DocViolation {
+  file: string;
+  line: number;
+  symbol: string;
+  kind: string;
+  reason: string;
+}
+
+const GROUP_OPEN = /^(?:const|var|type)\s*\(/;
+const TYPE_KINDS = new Set(['struct', 'interface', 'type']);
+
+export class DocCommentChecker {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  async check(target = '.', only?: string[], scope: ScopeOptions = {}): Promise<DocViolation[]> {
+    const symbols = (await this.symbolIndexer.scan(target, { ...scope, only })).filter(
+      s => s.language === 'go' && s.exported && !this.onUnexportedType(s)
+    );
+
+    const byFile = new Map<string, SymbolEntry[]>();
+    for (const symbol of symbols) {
+      byFile.set(symbol.file, [...(byFile.get(symbol.file) ?? []), symbol]);
+    }
+
+    const violations: DocViolation[] = [];
+    for (const [file, fileSymbols] of byFile) {
+      const source = await this.loadSource(file);
+
+      for (const symbol of fileSymbols) {
+        const reason = this.checkDoc(symbol, source);
+        if (reason) {
+          violations.push({
+            file,
+            line: symbol.line,
+            symbol: qualifiedName(symbol),
+            kind: symbol.kind,
+            reason,
+          });
+        }
+      }
+    }
+
+    return violations.sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+  }
+
+  /**
+   * Reason the symbol's doc comment fails the Go convention, or null if it passes
+   */
+  private checkDoc(symbol: SymbolEntry, source: SourceText | null): string | null {
+    const doc = symbol.doc?.trim();
+
+    if (!doc) {
+      // Members of a documented const/var/type ( ... ) block are covered by the block comment
+      if (source && this.hasGroupDoc(source, symbol.line)) {
+        return null;
+      }
+      return `exported ${symbol.kind} ${symbol.name} should have a doc comment`;
+    }
+
+    const firstWord = doc.split(/\s+/)[0] ?? '';
+    if (firstWord === symbol.name || firstWord.startsWith('Deprecated:')) {
+      return null;
+    }
+
+    // Types may be introduced with an article: "A Calculator performs ..."
+    const article = new RegExp(`^(?:A|An|The)\\s+${symbol.name}\\b`);
+    if (TYPE_KINDS.has(symbol.kind) && article.test(doc)) {
+      return null;
+    }
+
+    return `doc comment should start with "${symbol.name}" (found "${firstWord}")`;
+  }
+
+  private hasGroupDoc(source: SourceText, line: number): boolean {
+    for (let l = line - 1; l >= 1; l--) {
+      const text = source.lineText(l, true);
+      if (GROUP_OPEN.test(text)) {
+        return source.docCommentBefore(l) !== undefined;
+      }
+      // Reached another top-level declaration: not inside a group
+      if (/^\S/.test(text)) {
+        return false;
+      }
+    }
+    return false;
+  }
+
+  /**
+   * Methods on unexported types aren't part of the package API
+   */
+  private onUnexportedType(symbol: SymbolEntry): boolean {
+    return symbol.kind === 'method' && !/^[A-Z]/.test(symbol.parent ?? '');
+  }
+
+  private async loadSource(file: string): Promise<SourceText | null> {
+    try {
+      const content = await fs.readFile(
+        path.join(this.symbolIndexer.getWorkingDir(), file),
+        'utf-8'
+      );
+      return new SourceText(content, GO_SYNTAX);
+    } catch {
+      // Skip files that can't be read
+      return null;
+    }
+  }
+
+  formatResult(violations: DocViolation[]): string {
+    if (violations.length === 0) {
+      return 'All exported Go symbols are documented.';
+    }
+
+    let output = `Found ${violations.length} doc comment issue(s):\n\n`;
+    let currentFile = '';
+
+    for (const v of violations) {
+      if (v.file !== currentFile) {
+        if (currentFile) output += '\n';
+        output += `${v.file}:\n`;
+        currentFile = v.file;
+      }
+      output += `  ${String(v.line).padStart(4)}  ${v.symbol}: ${v.reason}\n`;
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
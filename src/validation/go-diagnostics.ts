@@ -0,0 +1,220 @@
+/**
+ * Go Diagnostics
+ * Runs go vet (and optionally the shadow analyzer) and returns structured findings
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { ToolError } from '../core/errors.js';
+import { resolveProjectPath } from '../core/file-reader.js';
+import { runProcess } from '../core/process.js';
+
+// Whole-module vets type-check every package, which takes a while on large trees
+const VET_TIMEOUT_MS = 120_000;
+
+export type DiagnosticSeverity = 'error' | 'warning' | 'info';
+
+export interface Diagnostic {
+  severity: DiagnosticSeverity;
+  analyzer: string; // e.g. printf, unreachable, shadow; "compile" for type errors
+  file: string;
+  line: number;
+  column: number;
+  message: string;
+}
+
+export interface DiagnosticsOptions {
+  path?: string | undefined; // Go file, package directory, or pattern such as ./... (default)
+  shadow?: boolean | undefined; // Also report shadowed variables
+  severity?: DiagnosticSeverity | undefined; // Minimum severity to report
+}
+
+export interface DiagnosticsReport {
+  target: string; // Package pattern passed to go vet
+  diagnostics: Diagnostic[];
+}
+
+const SEVERITY_RANK: Record<DiagnosticSeverity, number> = { error: 0, warning: 1, info: 2 };
+
+// Opt-in analyzers whose findings are often intentional
+const INFO_ANALYZERS = new Set(['shadow']);
+
+// go vet -json output: package -> analyzer -> findings
+type VetJson = Record<string, Record<string, Array<{ posn: string; message: string }>>>;
+
+export class GoDiagnostics {
+  private workingDir: string;
+
+  constructor(workingDir?: string) {
+    this.workingDir = workingDir ?? process.cwd();
+  }
+
+  async run(options: DiagnosticsOptions = {}): Promise<DiagnosticsReport> {
+    const { pattern, file } = await this.resolveTarget(options.path ?? './...');
+
+    const diagnostics = await this.vet('go', ['vet', '-json', pattern]);
+    if (options.shadow) {
+      const shadowed = await this.vet('shadow', ['-json', pattern]);
+      // Type errors are already reported by go vet
+      diagnostics.push(...shadowed.filter(d => d.analyzer !== 'compile'));
+    }
+
+    const minimum = SEVERITY_RANK[options.severity ?? 'info'];
+    return {
+      target: pattern,
+      diagnostics: diagnostics
+        .filter(d => SEVERITY_RANK[d.severity] <= minimum && (!file || d.file === file))
+        .sort(
+          (a, b) =>
+            a.file.localeCompare(b.file) ||
+            a.line - b.line ||
+            a.column - b.column ||
+            a.analyzer.localeCompare(b.analyzer)
+        ),
+    };
+  }
+
+  /**
+   * go vet works on packages: a file vets its directory and keeps only its
+   * own findings; a directory vets that package
+   */
+  private async resolveTarget(target: string): Promise<{ pattern: string; file?: string }> {
+    // ./pkg/... takes the packages under pkg, which must be in the project too;
+    // ./pkg... also matches siblings named like pkgutil
+    if (target.endsWith('...')) {
+      const before = target.slice(0, -'...'.length);
+      const prefix = resolveProjectPath(this.workingDir, before || '.');
+      const separator = before === '' || /[\\/]$/.test(before) ? '/' : '';
+      return { pattern: prefix ? `./${prefix}${separator}...` : './...' };
+    }
+
+    const relative = resolveProjectPath(this.workingDir, target);
+    const stat = await fs.stat(path.join(this.workingDir, relative));
+    if (stat.isFile()) {
+      const dir = path.posix.dirname(relative);
+      return { pattern: dir === '.' ? '.' : `./${dir}`, file: relative };
+    }
+    return { pattern: relative ? `./${relative}` : '.' };
+  }
+
+  private async vet(command: string, args: string[]): Promise<Diagnostic[]> {
+    const result = await runProcess(command, args, {
+      cwd: this.workingDir,
+      timeoutMs: VET_TIMEOUT_MS,
+    });
+    if (!result) {
+      throw new ToolError(
+        'FAILED_PRECONDITION',
+        command === 'shadow'
+          ? 'shadow analyzer not installed: go install golang.org/x/tools/go/analysis/passes/shadow/cmd/shadow@latest'
+          : 'go not found on PATH'
+      );
+    }
+
+    // Depending on the Go version, JSON goes to stdout or stderr
+    const output = `${result.stdout}\n${result.stderr}`;
+    const diagnostics = [...this.parseJson(output), ...this.parseErrors(output)];
+    if (result.code !== 0 && diagnostics.length === 0) {
+      throw new Error(`${command} ${args.join(' ')} failed: ${result.stderr.trim()}`);
+    }
+    return diagnostics;
+  }
+
+  /**
+   * Findings from each top-level JSON object in the output
+   */
+  private parseJson(output: string): Diagnostic[] {
+    const diagnostics: Diagnostic[] = [];
+    const documents = output.match(/^\{\n[\s\S]*?\n\}$/gm) ?? [];
+
+    for (const document of documents) {
+      let parsed: VetJson;
+      try {
+        parsed = JSON.parse(document) as VetJson;
+      } catch {
+        // Skip anything that only looks like a JSON object
+        continue;
+      }
+
+      for (const analyzers of Object.values(parsed)) {
+        for (const [analyzer, findings] of Object.entries(analyzers)) {
+          if (!Array.isArray(findings)) continue; // e.g. {"error": "..."} for broken packages
+          for (const finding of findings) {
+            const position = this.parsePosition(finding.posn);
+            if (!position) continue;
+            diagnostics.push({
+              severity: INFO_ANALYZERS.has(analyzer) ? 'info' : 'warning',
+              analyzer,
+              ...position,
+              message: finding.message,
+            });
+          }
+        }
+      }
+    }
+
+    return diagnostics;
+  }
+
+  /**
+   * Type-check errors, printed as "vet: file.go:2:23: message" instead of JSON
+   */
+  private parseErrors(output: string): Diagnostic[] {
+    const diagnostics: Diagnostic[] = [];
+    const pattern = /^(?:vet: )?(\S+\.go:\d+:\d+): (.+)$/gm;
+
+    let match;
+    while ((match = pattern.exec(output)) !== null) {
+      const position = this.parsePosition(match[1] ?? '');
+      if (!position) continue;
+      diagnostics.push({
+        severity: 'error',
+        analyzer: 'compile',
+        ...position,
+        message: match[2] ?? '',
+      });
+    }
+
+    return diagnostics;
+  }
+
+  /**
+   * "/abs/pkg/a.go:6:14" -> pkg/a.go, 6, 14
+   */
+  private parsePosition(
+    posn: string
+  ): { file: string; line: number; column: number } | undefined {
+    const match = /^(.+):(\d+):(\d+)$/.exec(posn);
+    if (!match?.[1]) return undefined;
+
+    const file = path.relative(this.workingDir, path.resolve(this.workingDir, match[1]));
+    return {
+      file: file.replace(/\\/g, '/'),
+      line: Number(match[2]),
+      column: Number(match[3]),
+    };
+  }
+
+  formatReport(report: DiagnosticsReport): string {
+    if (report.diagnostics.length === 0) {
+      return `No diagnostics for ${report.target}.`;
+    }
+
+    const counts = (['error', 'warning', 'info'] as const)
+      .map(severity => [severity, report.diagnostics.filter(d => d.severity === severity).length])
+      .filter(([, count]) => count !== 0)
+      .map(([severity, count]) => `${count} ${severity}(s)`)
+      .join(', ');
+
+    let output = `Diagnostics for ${report.target}: ${counts}\n\n`;
+    for (const d of report.diagnostics) {
+      output += `  ${d.file}:${d.line}:${d.column}  ${d.severity}  [${d.analyzer}] ${d.message}\n`;
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.workingDir = dir;
+  }
+}
@@ -0,0 +1,372 @@
+/**
+ * Naming Checker
+ * Checks Go symbol names against naming rules per kind: MixedCaps by default,
+ * overridable per project in .engineering/naming.yaml or per call
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { parse } from 'yaml';
+import type { SymbolEntry } from '../types/index.js';
+import { SymbolKindSchema } from '../types/index.js';
+import { ToolError } from '../core/errors.js';
+import type { ScopeOptions } from '../core/file-walker.js';
+import { SymbolIndexer, qualifiedName } from '../indexes/symbol-indexer.js';
+
+export const NAMING_STYLES = ['PascalCase', 'camelCase', 'SCREAMING_SNAKE', 'snake_case'] as const;
+
+export type NamingStyle = (typeof NAMING_STYLES)[number];
+
+// Where several style rules apply to a symbol, those listing its kind win
+export interface NamingRule {
+  id: string; // Rules with the id of a default replace its fields
+  description?: string | undefined;
+  kinds?: string[] | undefined; // Symbol kinds the rule applies to; all when unset
+  exported?: boolean | undefined; // Only exported (true) or unexported (false) names
+  style?: NamingStyle | undefined;
+  pattern?: string | undefined; // Regular expression the whole name must match
+  suffix?: string | undefined; // e.g. "er" for interfaces
+  initialisms?: boolean | undefined; // ID, URL, HTTP, ... keep a single case: UserID, not UserId
+  enabled?: boolean | undefined;
+}
+
+export interface NamingViolation {
+  file: string;
+  line: number;
+  symbol: string; // Qualified: Client.getUrl
+  kind: string;
+  name: string;
+  rule: string;
+  message: string;
+  suggestion?: string | undefined;
+}
+
+// Idiomatic Go: MixedCaps with the case of the first letter deciding visibility
+export const DEFAULT_NAMING_RULES: NamingRule[] = [
+  {
+    id: 'exported-mixed-caps',
+    description: 'Exported names are MixedCaps with no underscores',
+    exported: true,
+    style: 'PascalCase',
+  },
+  {
+    id: 'unexported-mixed-caps',
+    description: 'Unexported names are mixedCaps with no underscores',
+    exported: false,
+    style: 'camelCase',
+  },
+  {
+    id: 'initialisms',
+    description: 'Initialisms keep a consistent case: ServeHTTP, userID',
+    initialisms: true,
+  },
+  {
+    id: 'const-screaming-snake',
+    description: 'Constants are SCREAMING_SNAKE (not idiomatic Go; for projects that want it)',
+    kinds: ['const'],
+    style: 'SCREAMING_SNAKE',
+    enabled: false,
+  },
+  {
+    id: 'interface-er-suffix',
+    description: 'Interface names end in -er (Reader, Stringer)',
+    kinds: ['interface'],
+    suffix: 'er',
+    enabled: false,
+  },
+];
+
+const PROJECT_RULES_FILE = path.join('.engineering', 'naming.yaml');
+
+const STYLE_PATTERNS: Record<NamingStyle, RegExp> = {
+  PascalCase: /^[A-Z][A-Za-z0-9]*$/,
+  camelCase: /^[a-z][A-Za-z0-9]*$/,
+  SCREAMING_SNAKE: /^[A-Z][A-Z0-9]*(?:_[A-Z0-9]+)*$/,
+  snake_case: /^[a-z][a-z0-9]*(?:_[a-z0-9]+)*$/,
+};
+
+// The initialisms golint knows
+const INITIALISMS = new Set([
+  'ACL',
+  'API',
+  'ASCII',
+  'CPU',
+  'CSS',
+  'DNS',
+  'EOF',
+  'GUID',
+  'HTML',
+  'HTTP',
+  'HTTPS',
+  'ID',
+  'IP',
+  'JSON',
+  'LHS',
+  'QPS',
+  'RAM',
+  'RHS',
+  'RPC',
+  'SLA',
+  'SMTP',
+  'SQL',
+  'SSH',
+  'TCP',
+  'TLS',
+  'TTL',
+  'UDP',
+  'UI',
+  'UID',
+  'UUID',
+  'URI',
+  'URL',
+  'UTF8',
+  'VM',
+  'XML',
+  'XMPP',
+  'XSRF',
+  'XSS',
+]);
+
+// Go test, benchmark, example, and fuzz functions may use underscores
+const TEST_FUNCTION = /^(?:Test|Benchmark|Example|Fuzz)/;
+
+export class NamingChecker {
+  private symbolIndexer: SymbolIndexer;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+  }
+
+  /**
+   * Violations of the naming rules: the defaults, overridden by the project's
+   * .engineering/naming.yaml and then by rules passed in
+   */
+  async check(
+    target = '.',
+    only?: string[],
+    scope: ScopeOptions = {},
+    overrides: NamingRule[] = []
+  ): Promise<NamingViolation[]> {
+    const rules = mergeRules(DEFAULT_NAMING_RULES, [
+      ...(await this.projectRules()),
+      ...validateRules(overrides, 'rules argument'),
+    ]).filter(rule => rule.enabled !== false);
+    const patterns = new Map(
+      rules.map(rule => [rule.id, rule.pattern ? new RegExp(`^(?:${rule.pattern})$`) : undefined])
+    );
+
+    const symbols = (await this.symbolIndexer.scan(target, { ...scope, only })).filter(
+      s => s.language === 'go' && s.name !== '_'
+    );
+
+    const violations: NamingViolation[] = [];
+    for (const symbol of symbols) {
+      if (this.symbolIndexer.isGenerated(symbol.file)) continue;
+      if (isTestFunction(symbol)) continue;
+
+      const applicable = rules.filter(
+        rule =>
+          (!rule.kinds || rule.kinds.includes(symbol.kind)) &&
+          (rule.exported === undefined || rule.exported === symbol.exported)
+      );
+      // A style rule for the symbol's kind overrides the general ones
+      const kindStyled = applicable.some(rule => rule.style && rule.kinds);
+      for (const rule of applicable) {
+        if (kindStyled && rule.style && !rule.kinds) continue;
+        const broken = breaks(symbol.name, rule, patterns.get(rule.id));
+        if (!broken) continue;
+
+        const violation: NamingViolation = {
+          file: symbol.file,
+          line: symbol.line,
+          symbol: qualifiedName(symbol),
+          kind: symbol.kind,
+          name: symbol.name,
+          rule: rule.id,
+          message: broken.message,
+        };
+        if (broken.suggestion && broken.suggestion !== symbol.name) {
+          violation.suggestion = broken.suggestion;
+        }
+        violations.push(violation);
+      }
+    }
+
+    return violations.sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+  }
+
+  /**
+   * Rules from .engineering/naming.yaml, if the project has one
+   */
+  private async projectRules(): Promise<NamingRule[]> {
+    let content: string;
+    try {
+      content = await fs.readFile(
+        path.join(this.symbolIndexer.getWorkingDir(), PROJECT_RULES_FILE),
+        'utf-8'
+      );
+    } catch {
+      // No project rules
+      return [];
+    }
+    const parsed = parse(content) as { rules?: unknown } | null;
+    if (!Array.isArray(parsed?.rules)) {
+      throw new ToolError(
+        'INVALID_ARGUMENT',
+        `${PROJECT_RULES_FILE} must have a rules list`,
+        PROJECT_RULES_FILE
+      );
+    }
+    return validateRules(parsed.rules as NamingRule[], PROJECT_RULES_FILE);
+  }
+
+  formatResult(violations: NamingViolation[]): string {
+    if (violations.length === 0) {
+      return 'All Go symbol names follow the naming rules.';
+    }
+
+    let output = `Found ${violations.length} naming issue(s):\n\n`;
+    let currentFile = '';
+
+    for (const v of violations) {
+      if (v.file !== currentFile) {
+        if (currentFile) output += '\n';
+        output += `${v.file}:\n`;
+        currentFile = v.file;
+      }
+      const suggestion = v.suggestion ? ` (rename to ${v.suggestion})` : '';
+      output += `  ${String(v.line).padStart(4)}  ${v.symbol}: ${v.message}${suggestion}`;
+      output += ` [${v.rule}]\n`;
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+/**
+ * Apply overrides in order: a rule with a known id updates that rule's
+ * fields, a new id adds a rule
+ */
+function mergeRules(defaults: NamingRule[], overrides: NamingRule[]): NamingRule[] {
+  const merged = defaults.map(rule => ({ ...rule }));
+  for (const override of overrides) {
+    const index = merged.findIndex(rule => rule.id === override.id);
+    const existing = merged[index];
+    if (existing) merged[index] = { ...existing, ...override };
+    else merged.push({ ...override });
+  }
+  return merged;
+}
+
+function validateRules(rules: NamingRule[], source: string): NamingRule[] {
+  const kinds: readonly string[] = SymbolKindSchema.options;
+  const styles: readonly string[] = NAMING_STYLES;
+  for (const rule of rules) {
+    const where = `${source}: rule ${rule.id ?? '(no id)'}`;
+    if (typeof rule.id !== 'string' || rule.id === '') {
+      throw new ToolError('INVALID_ARGUMENT', `${source}: every naming rule needs an id`);
+    }
+    const unknown = (rule.kinds ?? []).filter(k => !kinds.includes(k));
+    if (unknown.length > 0) {
+      throw new ToolError(
+        'INVALID_ARGUMENT',
+        `${where}: unknown kind(s) ${unknown.join(', ')}. Valid kinds: ${kinds.join(', ')}`
+      );
+    }
+    if (rule.style !== undefined && !styles.includes(rule.style)) {
+      throw new ToolError(
+        'INVALID_ARGUMENT',
+        `${where}: unknown style ${String(rule.style)}. Valid styles: ${styles.join(', ')}`
+      );
+    }
+    if (rule.pattern !== undefined) {
+      try {
+        new RegExp(rule.pattern);
+      } catch (error) {
+        throw new ToolError('INVALID_ARGUMENT', `${where}: ${String(error)}`);
+      }
+    }
+  }
+  return rules;
+}
+
+/**
+ * How a name breaks a rule, with a compliant name when one can be derived;
+ * undefined when it complies
+ */
+function breaks(
+  name: string,
+  rule: NamingRule,
+  pattern: RegExp | undefined
+): { message: string; suggestion?: string | undefined } | undefined {
+  if (rule.style && !STYLE_PATTERNS[rule.style].test(name)) {
+    return { message: `should be ${rule.style}`, suggestion: toStyle(name, rule.style) };
+  }
+  if (pattern && !pattern.test(name)) {
+    return { message: `should match /${rule.pattern ?? ''}/` };
+  }
+  if (rule.suffix && !name.endsWith(rule.suffix)) {
+    return { message: `should end in ${rule.suffix}` };
+  }
+  if (rule.initialisms) {
+    const fixed = fixInitialisms(name);
+    if (fixed !== name) return { message: 'initialisms should keep one case', suggestion: fixed };
+  }
+  return undefined;
+}
+
+/**
+ * Words of a name across underscores and case changes: HTTPServer_v2 -> HTTP, Server, v2
+ */
+function words(name: string): string[] {
+  return name
+    .split(/_+/)
+    .flatMap(part => part.match(/[A-Z]+(?![a-z])|[A-Z][a-z0-9]*|[a-z0-9]+/g) ?? []);
+}
+
+function toStyle(name: string, style: NamingStyle): string {
+  const parts = words(name);
+  const capitalized = (word: string): string =>
+    INITIALISMS.has(word.toUpperCase())
+      ? word.toUpperCase()
+      : word.charAt(0).toUpperCase() + word.slice(1).toLowerCase();
+
+  switch (style) {
+    case 'PascalCase':
+      return parts.map(capitalized).join('');
+    case 'camelCase':
+      return parts.map((word, i) => (i === 0 ? word.toLowerCase() : capitalized(word))).join('');
+    case 'SCREAMING_SNAKE':
+      return parts.map(word => word.toUpperCase()).join('_');
+    case 'snake_case':
+      return parts.map(word => word.toLowerCase()).join('_');
+  }
+}
+
+/**
+ * The name with mixed-case initialisms upper-cased: UserId -> UserID. An
+ * initialism that starts an unexported name stays lower case: urlPath.
+ */
+function fixInitialisms(name: string): string {
+  return name.replace(/[A-Z][a-z0-9]*|[a-z0-9]+/g, (word, offset: number) => {
+    const upper = word.toUpperCase();
+    if (!INITIALISMS.has(upper)) return word;
+    // Lower case is fine at the start of a name, upper case anywhere
+    if (word === upper || (offset === 0 && word === word.toLowerCase())) return word;
+    // Only whole words: the "Id" in "Idle" isn't one
+    const next = name.charAt(offset + word.length);
+    return next === '' || !/[a-z]/.test(next) ? upper : word;
+  });
+}
+
+function isTestFunction(symbol: SymbolEntry): boolean {
+  return (
+    symbol.file.endsWith('_test.go') &&
+    symbol.kind === 'function' &&
+    TEST_FUNCTION.test(symbol.name)
+  );
+}
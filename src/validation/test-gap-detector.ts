@@ -0,0 +1,183 @@
+/**
+ * Test Gap Detector
+ * Reports exported Go symbols that no test in their package references
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import type { SymbolEntry } from '../types/index.js';
+import { GO_SYNTAX, SourceText } from '../parsers/source.js';
+import { CallGraphBuilder } from '../indexes/call-graph.js';
+import { SymbolIndexer, qualifiedName } from '../indexes/symbol-indexer.js';
+
+export interface TestGap {
+  file: string;
+  line: number;
+  symbol: string;
+  kind: string;
+  indirect: boolean; // Reached through functions the tests do call
+}
+
+export interface TestGapReport {
+  gaps: TestGap[];
+  checked: number; // Exported symbols considered
+  packagesWithoutTests: string[];
+}
+
+export class TestGapDetector {
+  private symbolIndexer: SymbolIndexer;
+  private callGraphBuilder: CallGraphBuilder;
+
+  constructor(symbolIndexer?: SymbolIndexer) {
+    this.symbolIndexer = symbolIndexer ?? new SymbolIndexer();
+    this.callGraphBuilder = new CallGraphBuilder(this.symbolIndexer);
+  }
+
+  async detect(target = '.'): Promise<TestGapReport> {
+    const symbols = (await this.symbolIndexer.scan(target)).filter(s => s.language === 'go');
+
+    const byPackage = new Map<string, SymbolEntry[]>();
+    for (const symbol of symbols) {
+      const dir = path.posix.dirname(symbol.file);
+      byPackage.set(dir, [...(byPackage.get(dir) ?? []), symbol]);
+    }
+
+    const report: TestGapReport = { gaps: [], checked: 0, packagesWithoutTests: [] };
+    for (const [dir, packageSymbols] of byPackage) {
+      const exported = packageSymbols.filter(
+        s => !isTestFile(s.file) && s.exported && !this.onUnexportedType(s)
+      );
+      if (exported.length === 0) continue;
+      report.checked += exported.length;
+
+      const testFiles = [...new Set(packageSymbols.map(s => s.file).filter(isTestFile))];
+      if (testFiles.length === 0) {
+        report.packagesWithoutTests.push(dir);
+      }
+
+      const testText = await this.readMasked(testFiles);
+      const direct = exported.filter(s =>
+        new RegExp(`(?<![\\w$])${s.name}(?![\\w$])`).test(testText)
+      );
+      const untested = exported.filter(s => !direct.includes(s));
+      if (untested.length === 0) continue;
+
+      const reachable =
+        testFiles.length > 0
+          ? await this.reachableFromTests(dir, packageSymbols, direct)
+          : new Set<string>();
+
+      for (const symbol of untested) {
+        const name = qualifiedName(symbol);
+        report.gaps.push({
+          file: symbol.file,
+          line: symbol.line,
+          symbol: name,
+          kind: symbol.kind,
+          // A type counts as indirectly tested when any of its methods is reached
+          indirect:
+            reachable.has(name) ||
+            packageSymbols.some(s => s.parent === symbol.name && reachable.has(qualifiedName(s))),
+        });
+      }
+    }
+
+    report.gaps.sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+    report.packagesWithoutTests.sort();
+    return report;
+  }
+
+  /**
+   * Functions reachable in the package call graph from test functions and
+   * from symbols the tests reference directly
+   */
+  private async reachableFromTests(
+    dir: string,
+    packageSymbols: SymbolEntry[],
+    direct: SymbolEntry[]
+  ): Promise<Set<string>> {
+    const graph = await this.callGraphBuilder.build(dir);
+    const callees = new Map<string, string[]>();
+    for (const edge of graph.edges) {
+      callees.set(edge.caller, [...(callees.get(edge.caller) ?? []), edge.callee]);
+    }
+
+    const queue = [
+      ...packageSymbols.filter(s => isTestFile(s.file)).map(qualifiedName),
+      ...direct.map(qualifiedName),
+    ];
+    const reachable = new Set<string>(queue);
+    while (queue.length > 0) {
+      const next = queue.pop() ?? '';
+      for (const callee of callees.get(next) ?? []) {
+        if (!reachable.has(callee)) {
+          reachable.add(callee);
+          queue.push(callee);
+        }
+      }
+    }
+
+    return reachable;
+  }
+
+  private async readMasked(files: string[]): Promise<string> {
+    const parts: string[] = [];
+    for (const file of files) {
+      try {
+        const content = await fs.readFile(
+          path.join(this.symbolIndexer.getWorkingDir(), file),
+          'utf-8'
+        );
+        // Names inside comments and strings don't count as test coverage
+        parts.push(new SourceText(content, GO_SYNTAX).masked);
+      } catch {
+        // Skip files that can't be read
+      }
+    }
+    return parts.join('\n');
+  }
+
+  /**
+   * Methods on unexported types aren't part of the package API
+   */
+  private onUnexportedType(symbol: SymbolEntry): boolean {
+    return symbol.kind === 'method' && !/^[A-Z]/.test(symbol.parent ?? '');
+  }
+
+  formatReport(report: TestGapReport): string {
+    if (report.checked === 0) {
+      return 'No exported Go symbols found.';
+    }
+    if (report.gaps.length === 0) {
+      return `All ${report.checked} exported Go symbol(s) are referenced by tests.`;
+    }
+
+    let output = `${report.gaps.length} of ${report.checked} exported symbol(s) `;
+    output += 'never appear in a test:\n\n';
+    let currentFile = '';
+
+    for (const gap of report.gaps) {
+      if (gap.file !== currentFile) {
+        if (currentFile) output += '\n';
+        output += `${gap.file}:\n`;
+        currentFile = gap.file;
+      }
+      const note = gap.indirect ? ' (reached indirectly)' : '';
+      output += `  ${String(gap.line).padStart(4)}  ${gap.kind} ${gap.symbol}${note}\n`;
+    }
+
+    if (report.packagesWithoutTests.length > 0) {
+      output += `\nPackages without any _test.go file: ${report.packagesWithoutTests.join(', ')}\n`;
+    }
+
+    return output.trimEnd();
+  }
+
+  setWorkingDir(dir: string): void {
+    this.symbolIndexer.setWorkingDir(dir);
+  }
+}
+
+function isTestFile(file: string): boolean {
+  return file.endsWith('_test.go');
+}